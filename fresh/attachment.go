@@ -1,5 +1,16 @@
 package fresh
 
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+)
+
+// ErrNotReplayable the attachment reader can not be read again (e.g. for a retry), since it is not an io.Seeker
+var ErrNotReplayable = errors.New("fresh: attachment reader is not replayable")
+
 type Attachment struct {
 	ID int64 `json:"id,omitempty"`
 
@@ -22,6 +33,15 @@ type Attachment struct {
 
 	// file attachment file
 	file string
+
+	// fsys the file system of the file
+	fsys fs.FS
+
+	// reader attachment reader
+	reader io.Reader
+
+	// opened the reader is opened
+	opened bool
 }
 
 func (a *Attachment) String() string {
@@ -50,6 +70,84 @@ func NewAttachment(file string, data ...[]byte) *Attachment {
 	return a
 }
 
+// NewAttachmentFS returns an Attachment of the file path in the file system fsys,
+// the file is opened when the request is sent (and reopened for a retry).
+func NewAttachmentFS(fsys fs.FS, path string) *Attachment {
+	return &Attachment{file: path, fsys: fsys}
+}
+
+// NewAttachmentReader returns an Attachment of the reader r, the name is the file name of the attachment.
+// The reader is read when the request is sent, it is seeked to the start for a retry if it is an io.Seeker,
+// otherwise the retry fails with ErrNotReplayable.
+func NewAttachmentReader(name string, r io.Reader) *Attachment {
+	return &Attachment{file: name, reader: r}
+}
+
+// Open opens the attachment content for reading.
+func (a *Attachment) Open() (io.ReadCloser, error) {
+	switch {
+	case a.data != nil:
+		return io.NopCloser(bytes.NewReader(a.data)), nil
+	case a.fsys != nil:
+		return a.fsys.Open(a.file)
+	case a.reader != nil:
+		if a.opened {
+			sk, ok := a.reader.(io.Seeker)
+			if !ok {
+				return nil, ErrNotReplayable
+			}
+			if _, err := sk.Seek(0, io.SeekStart); err != nil {
+				return nil, err
+			}
+		}
+		a.opened = true
+		return io.NopCloser(a.reader), nil
+	default:
+		return os.Open(a.file)
+	}
+}
+
+// FileSize returns the size of the attachment content, returns -1 if the size is unknown
+// (the reader is not an io.Seeker).
+func (a *Attachment) FileSize() (int64, error) {
+	switch {
+	case a.data != nil:
+		return int64(len(a.data)), nil
+	case a.fsys != nil:
+		fi, err := fs.Stat(a.fsys, a.file)
+		if err != nil {
+			return 0, err
+		}
+		return fi.Size(), nil
+	case a.reader != nil:
+		sk, ok := a.reader.(io.Seeker)
+		if !ok {
+			return -1, nil
+		}
+		pos, err := sk.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return 0, err
+		}
+		end, err := sk.Seek(0, io.SeekEnd)
+		if err != nil {
+			return 0, err
+		}
+		if _, err := sk.Seek(pos, io.SeekStart); err != nil {
+			return 0, err
+		}
+		if a.opened {
+			pos = 0 // it is seeked to the start when reopened
+		}
+		return end - pos, nil
+	default:
+		fi, err := os.Stat(a.file)
+		if err != nil {
+			return 0, err
+		}
+		return fi.Size(), nil
+	}
+}
+
 type Attachments []*Attachment
 
 func (as Attachments) Files() Files {
@@ -0,0 +1,397 @@
+// Package cassette provides a http.RoundTripper which records the http exchanges to a file,
+// and replays them later without the network.
+//
+// The Authorization header is never recorded, and the configured emails/phones are replaced
+// with the placeholders in the recorded urls, headers and bodies.
+//
+// A cassette replays a run recorded against a live account, it does not replace the emulators (fdtest/fstest)
+// which the offline tests run against. The other data of the account (names, subjects, descriptions, etc.)
+// is recorded as it is, so the cassette files should be reviewed before they are shared.
+//
+// Example:
+//
+//	rec, err := cassette.New("testdata/tickets.json", cassette.ModeAuto)
+//	if err != nil {
+//		t.Fatal(err)
+//	}
+//	rec.Emails = []string{"someone@example.co.jp"}
+//	defer rec.Save()
+//
+//	fd := &freshdesk.Client{Domain: "example.freshdesk.com", APIKey: apikey, Transport: rec}
+package cassette
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// ErrNotFound the error returned by RoundTrip if no recorded interaction matches the request in replay mode
+var ErrNotFound = errors.New("cassette: interaction not found")
+
+// Redacted the value of the redacted headers
+const Redacted = "[REDACTED]"
+
+// Mode the mode of the Recorder
+type Mode int
+
+const (
+	// ModeReplay replays the recorded interactions, the requests are never sent to the network.
+	ModeReplay Mode = iota
+
+	// ModeRecord sends the requests to the network and records the interactions.
+	ModeRecord
+
+	// ModeAuto replays if the cassette file exists, otherwise records.
+	ModeAuto
+)
+
+// String returns the name of the mode.
+func (m Mode) String() string {
+	switch m {
+	case ModeReplay:
+		return "replay"
+	case ModeRecord:
+		return "record"
+	case ModeAuto:
+		return "auto"
+	default:
+		return strconv.Itoa(int(m))
+	}
+}
+
+// Request the recorded http request
+type Request struct {
+	Method       string      `json:"method"`
+	URL          string      `json:"url"`
+	Header       http.Header `json:"header,omitempty"`
+	Body         string      `json:"body,omitempty"`
+	BodyEncoding string      `json:"body_encoding,omitempty"` // "base64" for the binary body
+}
+
+// Response the recorded http response
+type Response struct {
+	StatusCode   int         `json:"status_code"`
+	Header       http.Header `json:"header,omitempty"`
+	Body         string      `json:"body,omitempty"`
+	BodyEncoding string      `json:"body_encoding,omitempty"` // "base64" for the binary body
+}
+
+// Interaction a recorded http exchange
+type Interaction struct {
+	Request  *Request  `json:"request"`
+	Response *Response `json:"response"`
+}
+
+// Cassette the content of the cassette file
+type Cassette struct {
+	Interactions []*Interaction `json:"interactions"`
+}
+
+// Recorder a http.RoundTripper which records or replays the http exchanges.
+// It is safe for concurrent use, but the concurrent identical requests may be replayed in any order.
+type Recorder struct {
+	// Path the path of the cassette file
+	Path string
+
+	// Mode the mode of the Recorder, ModeAuto is resolved by New.
+	Mode Mode
+
+	// Transport the transport to send the requests in record mode, default is http.DefaultTransport.
+	Transport http.RoundTripper
+
+	// Emails the emails to redact, they are replaced with "email1@example.com", "email2@example.com", ... (case-insensitive).
+	Emails []string
+
+	// Phones the phone numbers to redact, they are replaced with "0000000001", "0000000002", ...
+	Phones []string
+
+	// Headers the additional headers to redact, the Authorization header is always redacted.
+	Headers []string
+
+	// Match reports whether the request matches the recorded (redacted) request.
+	// nil means matching by the method, the url path and the url query, so the cassette can be
+	// replayed with another domain or BaseURL.
+	Match func(req *http.Request, rr *Request) bool
+
+	mu       sync.Mutex
+	cassette Cassette
+	used     []bool
+}
+
+// New returns a Recorder of the cassette file path.
+// The cassette file is loaded in ModeReplay, or in ModeAuto if it exists (the mode is resolved to ModeReplay).
+// In ModeAuto, the mode is resolved to ModeRecord if the cassette file does not exist.
+func New(path string, mode Mode) (*Recorder, error) {
+	r := &Recorder{Path: path, Mode: mode}
+
+	if mode == ModeAuto {
+		r.Mode = ModeRecord
+		if _, err := os.Stat(path); err == nil {
+			r.Mode = ModeReplay
+		}
+	}
+
+	if r.Mode == ModeReplay {
+		if err := r.load(); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+func (r *Recorder) load() error {
+	data, err := os.ReadFile(r.Path)
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(data, &r.cassette); err != nil {
+		return fmt.Errorf("cassette: invalid cassette file %q: %w", r.Path, err)
+	}
+	r.used = make([]bool, len(r.cassette.Interactions))
+	return nil
+}
+
+// Interactions returns the recorded interactions.
+func (r *Recorder) Interactions() []*Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]*Interaction(nil), r.cassette.Interactions...)
+}
+
+// Save writes the recorded interactions to the cassette file in ModeRecord, it does nothing in ModeReplay.
+func (r *Recorder) Save() error {
+	if r.Mode != ModeRecord {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	data, err := json.MarshalIndent(&r.cassette, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(r.Path), 0o770); err != nil {
+		return err
+	}
+	return os.WriteFile(r.Path, data, fs.FileMode(0o660))
+}
+
+// RoundTrip implements the http.RoundTripper interface.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	if r.Mode == ModeRecord {
+		return r.record(req)
+	}
+	return r.replay(req)
+}
+
+func (r *Recorder) record(req *http.Request) (*http.Response, error) {
+	body, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	rt := r.Transport
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+
+	res, err := rt.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := io.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	res.Body = io.NopCloser(bytes.NewReader(data))
+
+	it := &Interaction{
+		Request: &Request{
+			Method: req.Method,
+			URL:    r.redact(req.URL.String()),
+			Header: r.redactHeader(req.Header),
+		},
+		Response: &Response{
+			StatusCode: res.StatusCode,
+			Header:     r.redactHeader(res.Header),
+		},
+	}
+	it.Request.Body, it.Request.BodyEncoding = r.encodeBody(body)
+	it.Response.Body, it.Response.BodyEncoding = r.encodeBody(data)
+
+	r.mu.Lock()
+	r.cassette.Interactions = append(r.cassette.Interactions, it)
+	r.mu.Unlock()
+
+	return res, nil
+}
+
+func (r *Recorder) replay(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, it := range r.cassette.Interactions {
+		if r.used[i] || !r.match(req, it.Request) {
+			continue
+		}
+		r.used[i] = true
+
+		data, err := decodeBody(it.Response.Body, it.Response.BodyEncoding)
+		if err != nil {
+			return nil, err
+		}
+
+		res := &http.Response{
+			Status:        fmt.Sprintf("%d %s", it.Response.StatusCode, http.StatusText(it.Response.StatusCode)),
+			StatusCode:    it.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        it.Response.Header.Clone(),
+			Body:          io.NopCloser(bytes.NewReader(data)),
+			ContentLength: int64(len(data)),
+			Request:       req,
+		}
+		if res.Header == nil {
+			res.Header = http.Header{}
+		}
+		return res, nil
+	}
+
+	return nil, fmt.Errorf("%w: %s %s", ErrNotFound, req.Method, r.redact(req.URL.String()))
+}
+
+func (r *Recorder) match(req *http.Request, rr *Request) bool {
+	if r.Match != nil {
+		return r.Match(req, rr)
+	}
+	if req.Method != rr.Method {
+		return false
+	}
+
+	u, err := url.Parse(rr.URL)
+	if err != nil {
+		return false
+	}
+	return r.redact(req.URL.RequestURI()) == u.RequestURI()
+}
+
+func readRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+func (r *Recorder) encodeBody(data []byte) (string, string) {
+	if utf8.Valid(data) {
+		return r.redact(string(data)), ""
+	}
+	return base64.StdEncoding.EncodeToString(data), "base64"
+}
+
+func decodeBody(body, encoding string) ([]byte, error) {
+	if encoding == "base64" {
+		return base64.StdEncoding.DecodeString(body)
+	}
+	return []byte(body), nil
+}
+
+func (r *Recorder) redactHeader(h http.Header) http.Header {
+	rh := http.Header{}
+	for k, vs := range h {
+		if strings.EqualFold(k, "Authorization") || r.isRedactedHeader(k) {
+			rh[k] = []string{Redacted}
+			continue
+		}
+
+		rvs := make([]string, len(vs))
+		for i, v := range vs {
+			rvs[i] = r.redact(v)
+		}
+		rh[k] = rvs
+	}
+	return rh
+}
+
+func (r *Recorder) isRedactedHeader(k string) bool {
+	for _, h := range r.Headers {
+		if strings.EqualFold(h, k) {
+			return true
+		}
+	}
+	return false
+}
+
+// redact replaces the emails and phones of s with the placeholders.
+// The email "@" is also matched in the url encoded form "%40".
+func (r *Recorder) redact(s string) string {
+	for i, e := range r.Emails {
+		if e == "" {
+			continue
+		}
+
+		p := fmt.Sprintf("email%d@example.com", i+1)
+		s = replaceFold(s, e, p)
+		s = replaceFold(s, strings.ReplaceAll(e, "@", "%40"), strings.ReplaceAll(p, "@", "%40"))
+	}
+
+	for i, p := range r.Phones {
+		if p != "" {
+			s = strings.ReplaceAll(s, p, fmt.Sprintf("%010d", i+1))
+		}
+	}
+	return s
+}
+
+// replaceFold replaces all the case-insensitive occurrences of old in s with new.
+func replaceFold(s, old, new string) string {
+	ls, lo := strings.ToLower(s), strings.ToLower(old)
+	if len(ls) != len(s) || !strings.Contains(ls, lo) {
+		return strings.ReplaceAll(s, old, new)
+	}
+
+	sb := &strings.Builder{}
+	for {
+		i := strings.Index(ls, lo)
+		if i < 0 {
+			break
+		}
+		sb.WriteString(s[:i])
+		sb.WriteString(new)
+		s, ls = s[i+len(old):], ls[i+len(lo):]
+	}
+	sb.WriteString(s)
+	return sb.String()
+}
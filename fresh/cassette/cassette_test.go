@@ -0,0 +1,159 @@
+package cassette
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/askasoft/gofresh/fresh"
+)
+
+type testContact struct {
+	ID    int64  `json:"id"`
+	Email string `json:"email"`
+	Phone string `json:"phone"`
+}
+
+func TestRecordReplay(t *testing.T) {
+	seq := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seq++
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v2/contacts":
+			w.Write([]byte(`[{"id":1,"email":"` + r.URL.Query().Get("email") + `","phone":"+81-90-1234-5678"}]`))
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v2/contacts":
+			body, _ := io.ReadAll(r.Body)
+			w.WriteHeader(http.StatusCreated)
+			w.Write(body)
+		case r.URL.Path == "/files/1":
+			w.Write([]byte{0xff, 0x00, byte(seq)})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer ts.Close()
+
+	path := filepath.Join(t.TempDir(), "cassette.json")
+
+	rec, err := New(path, ModeAuto)
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	if rec.Mode != ModeRecord {
+		t.Fatalf("Mode = %v, want %v", rec.Mode, ModeRecord)
+	}
+	rec.Emails = []string{"John.Doe@example.co.jp"}
+	rec.Phones = []string{"+81-90-1234-5678"}
+
+	ctx := context.Background()
+	fc := &fresh.Client{Domain: "example.freshdesk.com", APIKey: "secret-apikey", BaseURL: ts.URL, Transport: rec}
+
+	var cs []*testContact
+	if err = fc.DoGet(ctx, fc.Endpoint("/contacts?email=john.doe%%40example.co.jp"), &cs); err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	if len(cs) != 1 || cs[0].Email != "john.doe@example.co.jp" {
+		t.Fatalf("DoGet() = %v", cs)
+	}
+
+	c := &testContact{}
+	if err = fc.DoPost(ctx, fc.Endpoint("/contacts"), &testContact{Email: "john.doe@example.co.jp"}, c); err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+
+	bs1, err := fc.DoReadFile(ctx, ts.URL+"/files/1")
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	bs2, err := fc.DoReadFile(ctx, ts.URL+"/files/1")
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+
+	if err = rec.Save(); err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	for _, s := range []string{"secret-apikey", "c2VjcmV0LWFwaWtl", "example.co.jp", "1234-5678"} {
+		if strings.Contains(string(data), s) {
+			t.Errorf("cassette contains %q", s)
+		}
+	}
+
+	ts.Close()
+
+	// replay with another domain
+	rep, err := New(path, ModeAuto)
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	if rep.Mode != ModeReplay {
+		t.Fatalf("Mode = %v, want %v", rep.Mode, ModeReplay)
+	}
+	rep.Emails = rec.Emails
+	rep.Phones = rec.Phones
+
+	fc = &fresh.Client{Domain: "replay.freshdesk.com", APIKey: "replay", Transport: rep}
+
+	cs = nil
+	if err = fc.DoGet(ctx, fc.Endpoint("/contacts?email=john.doe%%40example.co.jp"), &cs); err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	if len(cs) != 1 || cs[0].Email != "email1@example.com" || cs[0].Phone != "0000000001" {
+		t.Fatalf("DoGet() = %v", cs[0])
+	}
+
+	c = &testContact{}
+	if err = fc.DoPost(ctx, fc.Endpoint("/contacts"), &testContact{}, c); err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	if c.Email != "email1@example.com" {
+		t.Fatalf("DoPost() = %v", c)
+	}
+
+	for i, w := range [][]byte{bs1, bs2} {
+		bs, err := fc.DoReadFile(ctx, "https://example.freshdesk.com/files/1")
+		if err != nil {
+			t.Fatalf("ERROR: %v", err)
+		}
+		if string(bs) != string(w) {
+			t.Errorf("[%d] DoReadFile() = %v, want %v", i, bs, w)
+		}
+	}
+
+	if err = fc.DoDelete(ctx, fc.Endpoint("/contacts/1")); !errors.Is(err, ErrNotFound) {
+		t.Errorf("DoDelete() = %v, want %v", err, ErrNotFound)
+	}
+}
+
+func TestReplayMissing(t *testing.T) {
+	if _, err := New(filepath.Join(t.TempDir(), "missing.json"), ModeReplay); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("New() = %v", err)
+	}
+}
+
+func TestReplaceFold(t *testing.T) {
+	cs := []struct {
+		s, o, n, w string
+	}{
+		{"a@B.com, A@b.COM", "a@b.com", "x", "x, x"},
+		{"none", "a@b.com", "x", "none"},
+		{"İ a@b.com", "a@b.com", "x", "İ x"},
+	}
+
+	for i, c := range cs {
+		if a := replaceFold(c.s, c.o, c.n); a != c.w {
+			t.Errorf("[%d] replaceFold(%q, %q) = %q, want %q", i, c.s, c.o, a, c.w)
+		}
+	}
+}
@@ -0,0 +1,204 @@
+package fresh
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"time"
+)
+
+// ErrMaxPageExceeded is matched by a PageLimitError with errors.Is.
+var ErrMaxPageExceeded = errors.New("fresh: maximum page exceeded")
+
+// PageLimitError is returned when the results of a query exceed the maximum page that the api allows,
+// and the query can not be narrowed any further to guarantee the full coverage of the results.
+type PageLimitError struct {
+	MaxPage int    // the maximum page number that the api allows
+	Query   string // the description of the query
+}
+
+func (e *PageLimitError) Error() string {
+	if e.Query == "" {
+		return fmt.Sprintf("fresh: the results exceed the maximum page %d", e.MaxPage)
+	}
+	return fmt.Sprintf("fresh: the results of %s exceed the maximum page %d", e.Query, e.MaxPage)
+}
+
+// Is reports whether the target is ErrMaxPageExceeded.
+func (e *PageLimitError) Is(target error) bool {
+	return target == ErrMaxPageExceeded //nolint: errorlint
+}
+
+// Window a half-open time range [Start, End), zero End means unbounded.
+type Window struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Contains reports whether the time t is in the window.
+func (w Window) Contains(t time.Time) bool {
+	return !t.Before(w.Start) && (w.End.IsZero() || t.Before(w.End))
+}
+
+// SplitWindows splits the time range [start, end) into windows of the size d.
+// The End of the last window is zero (unbounded), so that the items updated after end are not missed.
+func SplitWindows(start, end time.Time, d time.Duration) []Window {
+	ws := []Window{}
+	if d > 0 {
+		for s := start; s.Add(d).Before(end); s = s.Add(d) {
+			ws = append(ws, Window{Start: s, End: s.Add(d)})
+		}
+	}
+
+	if n := len(ws); n > 0 {
+		start = ws[n-1].End
+	}
+	return append(ws, Window{Start: start})
+}
+
+// ConcurrentOption the option of the concurrent list iterations
+type ConcurrentOption struct {
+	// Workers the maximum number of concurrent page requests, default is 4.
+	Workers int
+
+	// Window the size of the updated_since time windows, 0 means no splitting.
+	// Only used by the apis that support ordering by updated_at.
+	Window time.Duration
+
+	// Until the end time of the time windows, default is now.
+	Until time.Time
+}
+
+// ErrWindowStart is returned by ConcurrentOption.Windows if the Window is set but the start time is zero,
+// since splitting the time range from 0001-01-01 would issue millions of queries.
+var ErrWindowStart = errors.New("fresh: the start time of the time windows is required")
+
+// Windows splits the time range [start, Until) by the Window size.
+// If the Window is set, the start must not be zero, otherwise ErrWindowStart is returned.
+func (co *ConcurrentOption) Windows(start time.Time) ([]Window, error) {
+	if co == nil || co.Window <= 0 {
+		return []Window{{Start: start}}, nil
+	}
+	if start.IsZero() {
+		return nil, ErrWindowStart
+	}
+
+	end := co.Until
+	if end.IsZero() {
+		end = time.Now()
+	}
+	return SplitWindows(start, end, co.Window), nil
+}
+
+// ClipWindow returns the items in the window w, the items must be sorted by the updated time in ascending order.
+// If any item is updated after the window, next is set to false.
+func ClipWindow[T any](w Window, items []T, next bool, updatedAt func(T) time.Time) ([]T, bool) {
+	clipped := make([]T, 0, len(items))
+	for _, it := range items {
+		t := updatedAt(it)
+		if !w.End.IsZero() && !t.Before(w.End) {
+			return clipped, false
+		}
+		if w.Contains(t) {
+			clipped = append(clipped, it)
+		}
+	}
+	return clipped, next
+}
+
+// QueryPageFunc lists the page (starts from 1) of a query, returns the items and whether there is a next page.
+type QueryPageFunc[T any] func(ctx context.Context, page int) ([]T, bool, error)
+
+// Concurrent lists the pages of a sequence of queries by a bounded pool of concurrent requests,
+// and delivers the items in order (query by query, page by page).
+// The pages of a query are fetched ahead speculatively, the pages after the last page are discarded.
+// The requests are throttled by the Client.RateLimiter, so it should be set to share the rate limit budget.
+type Concurrent[T any] struct {
+	// Workers the maximum number of concurrent page requests, default is 4.
+	Workers int
+
+	// MaxPage the maximum page number that the api allows for a query, 0 means no limit.
+	MaxPage int
+}
+
+// Iter calls fn for each item of all queries in order, stops if fn returns an error.
+func (cc *Concurrent[T]) Iter(ctx context.Context, queries []QueryPageFunc[T], fn func(T) error) error {
+	return iterItems(cc.All(ctx, queries), fn)
+}
+
+// All returns an iterator over the items of all queries in order.
+// If a page request fails, the error is yielded with a zero item and the iteration stops.
+func (cc *Concurrent[T]) All(ctx context.Context, queries []QueryPageFunc[T]) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for _, qp := range queries {
+			if err := cc.query(ctx, qp, yield); err != nil {
+				if !errors.Is(err, errStopYield) {
+					var zero T
+					yield(zero, err)
+				}
+				return
+			}
+		}
+	}
+}
+
+var errStopYield = errors.New("stop")
+
+type queryPageResult[T any] struct {
+	items []T
+	next  bool
+	err   error
+}
+
+func (cc *Concurrent[T]) query(ctx context.Context, qp QueryPageFunc[T], yield func(T, error) bool) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel() // cancel the speculative requests
+
+	workers := cc.Workers
+	if workers < 1 {
+		workers = 4
+	}
+
+	fetch := func(page int) chan queryPageResult[T] {
+		ch := make(chan queryPageResult[T], 1)
+		go func() {
+			items, next, err := qp(ctx, page)
+			ch <- queryPageResult[T]{items, next, err}
+		}()
+		return ch
+	}
+
+	pending := []chan queryPageResult[T]{}
+	for page, issued := 1, 0; ; page++ {
+		for len(pending) < workers && (cc.MaxPage <= 0 || issued < cc.MaxPage) {
+			issued++
+			pending = append(pending, fetch(issued))
+		}
+
+		var r queryPageResult[T]
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case r = <-pending[0]:
+			pending = pending[1:]
+		}
+
+		if r.err != nil {
+			return r.err
+		}
+
+		for _, it := range r.items {
+			if !yield(it, nil) {
+				return errStopYield
+			}
+		}
+
+		if !r.next {
+			return nil
+		}
+		if cc.MaxPage > 0 && page >= cc.MaxPage {
+			return &PageLimitError{MaxPage: cc.MaxPage}
+		}
+	}
+}
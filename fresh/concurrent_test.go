@@ -0,0 +1,138 @@
+package fresh
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func testQueryPage(start, total, perPage int, calls *int32) QueryPageFunc[int] {
+	return func(ctx context.Context, page int) ([]int, bool, error) {
+		atomic.AddInt32(calls, 1)
+		time.Sleep(time.Millisecond * time.Duration(rand.Intn(5)))
+
+		items := []int{}
+		for i := (page - 1) * perPage; i < page*perPage && i < total; i++ {
+			items = append(items, start+i)
+		}
+		return items, page*perPage < total, nil
+	}
+}
+
+func TestConcurrentOrder(t *testing.T) {
+	var calls int32
+
+	cc := &Concurrent[int]{Workers: 3}
+	qps := []QueryPageFunc[int]{
+		testQueryPage(0, 95, 10, &calls),
+		testQueryPage(95, 5, 10, &calls),
+		testQueryPage(100, 40, 10, &calls),
+	}
+
+	n := 0
+	err := cc.Iter(context.Background(), qps, func(it int) error {
+		if it != n {
+			t.Fatalf("item = %d, want %d", it, n)
+		}
+		n++
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	if n != 140 {
+		t.Errorf("iterated %d, want %d", n, 140)
+	}
+}
+
+func TestConcurrentMaxPage(t *testing.T) {
+	var calls int32
+
+	cc := &Concurrent[int]{Workers: 4, MaxPage: 3}
+	qps := []QueryPageFunc[int]{testQueryPage(0, 100, 10, &calls)}
+
+	n := 0
+	for _, err := range cc.All(context.Background(), qps) {
+		if err != nil {
+			var ple *PageLimitError
+			if !errors.Is(err, ErrMaxPageExceeded) || !errors.As(err, &ple) || ple.MaxPage != 3 {
+				t.Fatalf("ERROR: %v", err)
+			}
+			break
+		}
+		n++
+	}
+	if n != 30 {
+		t.Errorf("iterated %d, want %d", n, 30)
+	}
+	if n := atomic.LoadInt32(&calls); n > 3 {
+		t.Errorf("calls = %d, want <= %d", n, 3)
+	}
+}
+
+func TestConcurrentStop(t *testing.T) {
+	var calls int32
+
+	cc := &Concurrent[int]{Workers: 2}
+	qps := []QueryPageFunc[int]{testQueryPage(0, 1000, 10, &calls)}
+
+	n := 0
+	for _, err := range cc.All(context.Background(), qps) {
+		if err != nil {
+			t.Fatalf("ERROR: %v", err)
+		}
+		if n++; n == 25 {
+			break
+		}
+	}
+	if n := atomic.LoadInt32(&calls); n > 5 {
+		t.Errorf("calls = %d, want <= %d", n, 5)
+	}
+}
+
+func TestSplitWindows(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(time.Hour * 60)
+
+	ws := SplitWindows(start, end, time.Hour*24)
+	if len(ws) != 3 {
+		t.Fatalf("SplitWindows() = %v", ws)
+	}
+	if !ws[0].Start.Equal(start) || !ws[1].Start.Equal(ws[0].End) || !ws[2].Start.Equal(ws[1].End) || !ws[2].End.IsZero() {
+		t.Errorf("SplitWindows() = %v", ws)
+	}
+}
+
+func TestConcurrentOptionWindows(t *testing.T) {
+	co := &ConcurrentOption{Window: time.Hour * 24}
+	if _, err := co.Windows(time.Time{}); !errors.Is(err, ErrWindowStart) {
+		t.Errorf("Windows(zero) = %v, want %v", err, ErrWindowStart)
+	}
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	co.Until = start.Add(time.Hour * 60)
+	ws, err := co.Windows(start)
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	if len(ws) != 3 {
+		t.Errorf("Windows() = %v", ws)
+	}
+
+	if ws, err := (*ConcurrentOption)(nil).Windows(time.Time{}); err != nil || len(ws) != 1 {
+		t.Errorf("nil.Windows() = %v, %v", ws, err)
+	}
+}
+
+func TestClipWindow(t *testing.T) {
+	w := Window{Start: time.Unix(10, 0), End: time.Unix(20, 0)}
+	ts := []int64{10, 15, 19, 20, 21}
+
+	a, next := ClipWindow(w, ts, true, func(n int64) time.Time { return time.Unix(n, 0) })
+	if len(a) != 3 || a[2] != 19 || next {
+		t.Errorf("ClipWindow() = %v, %v", a, next)
+	}
+}
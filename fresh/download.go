@@ -0,0 +1,402 @@
+package fresh
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/askasoft/pango/iox"
+)
+
+// PartFileExt the extension of the temporary file of a download.
+// The file is renamed to the target path when the download completes,
+// and an interrupted download is resumed from it by a Range request.
+const PartFileExt = ".part"
+
+// ValidatorFileExt the extension of the file which keeps the validator (the strong ETag or the Last-Modified)
+// of the part file, it is sent as the If-Range header to resume the download, so that the part file is not
+// appended with the content of a modified file. A part file without the validator is downloaded again.
+const ValidatorFileExt = ".validator"
+
+var (
+	// ErrSizeMismatch the size of the downloaded file does not match the expected size
+	ErrSizeMismatch = errors.New("fresh: download size mismatch")
+
+	// ErrChecksumMismatch the SHA-256 checksum of the downloaded file does not match the expected checksum
+	ErrChecksumMismatch = errors.New("fresh: download checksum mismatch")
+)
+
+type DownloadOption struct {
+	// Size the expected size of the file, the size is checked if it is greater than 0.
+	Size int64
+
+	// SHA256 the expected SHA-256 checksum (hex) of the file, the checksum is verified if it is not empty.
+	SHA256 string
+
+	// Checksum computes the SHA-256 checksum of the file to the DownloadResult.SHA256.
+	Checksum bool
+}
+
+type DownloadResult struct {
+	// Path the path of the downloaded file
+	Path string
+
+	// Size the size of the downloaded file
+	Size int64
+
+	// SHA256 the SHA-256 checksum (hex) of the file, computed if the DownloadOption.Checksum or DownloadOption.SHA256 is set.
+	SHA256 string
+
+	// ContentType the Content-Type of the response,
+	// it is sniffed from the content if the response has no Content-Type or it is "application/octet-stream".
+	ContentType string
+
+	// Resumed the download is resumed from a partial file
+	Resumed bool
+}
+
+func (dr *DownloadResult) String() string {
+	return toString(dr)
+}
+
+// DoDownloadFile downloads the url to the path atomically, the content is written to the path + PartFileExt
+// and renamed to the path after the size and checksum are verified.
+// A partial file left by a failed download (or a retry) is resumed by a Range request with the If-Range
+// of its validator (see ValidatorFileExt), the partial file without a validator is downloaded again.
+func (c *Client) DoDownloadFile(ctx context.Context, url, path string, do *DownloadOption) (*DownloadResult, error) {
+	return c.download(ctx, url, path, do, c.authAndCall)
+}
+
+// DoDownloadFileNoAuth downloads the url to the path atomically without the authorization, see DoDownloadFile.
+func (c *Client) DoDownloadFileNoAuth(ctx context.Context, url, path string, do *DownloadOption) (*DownloadResult, error) {
+	return c.download(ctx, url, path, do, c.call)
+}
+
+func (c *Client) download(ctx context.Context, url, path string, do *DownloadOption, call func(*http.Request) (*http.Response, error)) (*DownloadResult, error) {
+	if do == nil {
+		do = &DownloadOption{}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0770); err != nil {
+		return nil, err
+	}
+
+	part := path + PartFileExt
+	dr := &DownloadResult{Path: path}
+
+	err := c.RetryForError(ctx, func() error {
+		return downloadPart(ctx, url, part, do.Size, dr, call)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := dr.verify(part, do); err != nil {
+		// the part file is broken, do not resume from it
+		_ = os.Remove(part)
+		_ = os.Remove(part + ValidatorFileExt)
+		return nil, err
+	}
+
+	if err := os.Rename(part, path); err != nil {
+		return nil, err
+	}
+	_ = os.Remove(part + ValidatorFileExt)
+	return dr, nil
+}
+
+// downloadPart downloads the url to the part file, it is resumed by a Range request if the part file exists.
+// The Range request is sent with the If-Range of the validator of the part file,
+// the server sends the whole content (200) instead of the range if the file is modified.
+func downloadPart(ctx context.Context, url, part string, size int64, dr *DownloadResult, call func(*http.Request) (*http.Response, error)) error {
+	var offset int64
+	if fi, err := os.Stat(part); err == nil {
+		offset = fi.Size()
+	}
+
+	if size > 0 && offset >= size {
+		if offset == size {
+			return nil
+		}
+		offset = 0
+	}
+
+	validator := readValidator(part)
+	if validator == "" {
+		offset = 0
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+		req.Header.Set("If-Range", validator)
+	}
+
+	res, err := call(req)
+	if err != nil {
+		return err
+	}
+	defer iox.DrainAndClose(res.Body)
+
+	flag := os.O_WRONLY | os.O_CREATE
+	switch res.StatusCode {
+	case http.StatusOK:
+		flag |= os.O_TRUNC
+	case http.StatusPartialContent:
+		if start, _ := parseContentRange(res.Header.Get("Content-Range")); start != offset {
+			_ = os.Remove(part)
+			return fmt.Errorf("fresh: unexpected Content-Range %q for the offset %d", res.Header.Get("Content-Range"), offset)
+		}
+		if v := responseValidator(res); v != "" && v != validator {
+			// the server ignored the If-Range and the file is modified, download again
+			if err := os.Remove(part); err != nil {
+				return err
+			}
+			return downloadPart(ctx, url, part, size, dr, call)
+		}
+		flag |= os.O_APPEND
+		dr.Resumed = true
+	case http.StatusRequestedRangeNotSatisfiable:
+		if offset > 0 {
+			if _, total := parseContentRange(res.Header.Get("Content-Range")); total == offset {
+				// the part file is completed
+				return nil
+			}
+
+			// the part file is stale, download again
+			if err := os.Remove(part); err != nil {
+				return err
+			}
+			return downloadPart(ctx, url, part, size, dr, call)
+		}
+		return newResultError(res)
+	default:
+		return newResultError(res)
+	}
+
+	dr.ContentType = res.Header.Get("Content-Type")
+
+	if res.StatusCode == http.StatusOK {
+		if err := writeValidator(part, responseValidator(res)); err != nil {
+			return err
+		}
+	}
+
+	f, err := os.OpenFile(part, flag, 0660)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(f, res.Body)
+	if err1 := f.Close(); err1 != nil && err == nil {
+		err = err1
+	}
+	return err
+}
+
+// responseValidator returns the strong ETag or the Last-Modified of the response,
+// a weak ETag can not be used by the If-Range.
+func responseValidator(res *http.Response) string {
+	if etag := res.Header.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+		return etag
+	}
+	return res.Header.Get("Last-Modified")
+}
+
+func readValidator(part string) string {
+	bs, err := os.ReadFile(part + ValidatorFileExt)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(bs))
+}
+
+// writeValidator writes the validator of the part file, the validator file is removed if the validator is empty.
+func writeValidator(part, validator string) error {
+	vf := part + ValidatorFileExt
+	if validator == "" {
+		if err := os.Remove(vf); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+	return os.WriteFile(vf, []byte(validator), 0660)
+}
+
+// parseContentRange parses the Content-Range header "bytes <start>-<end>/<total>" or "bytes */<total>",
+// returns -1 for the unknown value.
+func parseContentRange(s string) (start, total int64) {
+	start, total = -1, -1
+
+	s, ok := strings.CutPrefix(s, "bytes ")
+	if !ok {
+		return
+	}
+
+	r, t, _ := strings.Cut(s, "/")
+	if n, err := strconv.ParseInt(t, 10, 64); err == nil {
+		total = n
+	}
+
+	r, _, _ = strings.Cut(r, "-")
+	if n, err := strconv.ParseInt(r, 10, 64); err == nil {
+		start = n
+	}
+	return
+}
+
+// verify checks the size and checksum of the part file, sniffs the content type if unknown.
+func (dr *DownloadResult) verify(part string, do *DownloadOption) error {
+	f, err := os.Open(part)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	dr.Size = fi.Size()
+	if do.Size > 0 && dr.Size != do.Size {
+		return fmt.Errorf("%w: %s (%d bytes, want %d bytes)", ErrSizeMismatch, dr.Path, dr.Size, do.Size)
+	}
+
+	if mt, _, _ := mime.ParseMediaType(dr.ContentType); mt == "" || mt == "application/octet-stream" {
+		head := make([]byte, 512)
+		n, err := io.ReadFull(f, head)
+		if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+			return err
+		}
+		dr.ContentType = http.DetectContentType(head[:n])
+
+		if _, err := f.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+	}
+
+	if do.Checksum || do.SHA256 != "" {
+		h := sha256.New()
+		if _, err := io.Copy(h, f); err != nil {
+			return err
+		}
+
+		dr.SHA256 = hex.EncodeToString(h.Sum(nil))
+		if do.SHA256 != "" && !strings.EqualFold(dr.SHA256, do.SHA256) {
+			return fmt.Errorf("%w: %s (%s, want %s)", ErrChecksumMismatch, dr.Path, dr.SHA256, do.SHA256)
+		}
+	}
+	return nil
+}
+
+// AttachmentFile an attachment and the path to download it
+type AttachmentFile struct {
+	Attachment *Attachment
+	Path       string
+}
+
+// AttachmentFileName returns the file name "<attachment id>_<name>" of the attachment.
+func AttachmentFileName(a *Attachment) string {
+	return strconv.FormatInt(a.ID, 10) + "_" + SanitizeName(a.Name)
+}
+
+// AttachmentFiles returns the attachment files of the attachments in the directory dir, named by AttachmentFileName.
+func AttachmentFiles(dir string, as []*Attachment) []*AttachmentFile {
+	afs := make([]*AttachmentFile, 0, len(as))
+	for _, a := range as {
+		afs = append(afs, &AttachmentFile{Attachment: a, Path: filepath.Join(dir, AttachmentFileName(a))})
+	}
+	return afs
+}
+
+// SanitizeName returns a file name which is safe for the file systems.
+func SanitizeName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if r < 0x20 || strings.ContainsRune(`/\:*?"<>|`, r) {
+			return '_'
+		}
+		return r
+	}, name)
+
+	name = strings.Trim(name, " .")
+	if name == "" {
+		return "_"
+	}
+	return name
+}
+
+// DownloadFunc downloads the attachment to the path
+type DownloadFunc func(ctx context.Context, a *Attachment, path string) (*DownloadResult, error)
+
+// DownloadAttachments downloads the attachment files by the download function df concurrently,
+// workers is the maximum number of concurrent downloads, default is 4.
+// The remaining downloads are canceled on the first error,
+// returns the results in the order of the attachment files.
+func DownloadAttachments(ctx context.Context, afs []*AttachmentFile, workers int, df DownloadFunc) ([]*DownloadResult, error) {
+	if workers < 1 {
+		workers = 4
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg   sync.WaitGroup
+		once sync.Once
+		derr error
+	)
+
+	drs := make([]*DownloadResult, len(afs))
+	sem := make(chan struct{}, workers)
+
+	for i, af := range afs {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			dr, err := df(ctx, af.Attachment, af.Path)
+			if err != nil {
+				once.Do(func() {
+					derr = fmt.Errorf("attachment #%d: %w", af.Attachment.ID, err)
+					cancel()
+				})
+				return
+			}
+			drs[i] = dr
+		}()
+	}
+	wg.Wait()
+
+	if derr != nil {
+		return nil, derr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return drs, nil
+}
@@ -0,0 +1,201 @@
+package fresh
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func testDownloadServer(data *[]byte, ranges *[]string) *httptest.Server {
+	var mu sync.Mutex
+	aborted := false
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		*ranges = append(*ranges, r.Header.Get("Range"))
+		abort := !aborted && r.URL.Path == "/abort"
+		aborted = aborted || abort
+		content := *data
+		mu.Unlock()
+
+		sum := sha256.Sum256(content)
+		w.Header().Set("ETag", `"`+hex.EncodeToString(sum[:8])+`"`)
+		w.Header().Set("Content-Type", "application/octet-stream")
+		if abort {
+			// send the half of the content, then break the connection
+			w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+			w.Write(content[:len(content)/2])
+			w.(http.Flusher).Flush()
+			panic(http.ErrAbortHandler)
+		}
+		http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(content))
+	}))
+}
+
+func TestDownloadFile(t *testing.T) {
+	data := []byte("<html><body>" + strings.Repeat("0123456789", 100) + "</body></html>")
+	sum := sha256.Sum256(data)
+
+	var ranges []string
+	ts := testDownloadServer(&data, &ranges)
+	defer ts.Close()
+
+	c := &Client{Domain: "example.freshdesk.com", APIKey: "k", BaseURL: ts.URL, Retryer: NewRetryer(time.Millisecond, 1, nil)}
+	dir := t.TempDir()
+
+	// resume the broken download
+	path := filepath.Join(dir, "a", "abort.html")
+	dr, err := c.DoDownloadFile(context.Background(), ts.URL+"/abort", path, &DownloadOption{Size: int64(len(data)), SHA256: hex.EncodeToString(sum[:])})
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	if !dr.Resumed || dr.Size != int64(len(data)) || dr.SHA256 != hex.EncodeToString(sum[:]) || dr.ContentType != "text/html; charset=utf-8" {
+		t.Errorf("DoDownloadFile() = %v", dr)
+	}
+	if want := []string{"", fmt.Sprintf("bytes=%d-", len(data)/2)}; fmt.Sprint(ranges) != fmt.Sprint(want) {
+		t.Errorf("ranges = %q, want %q", ranges, want)
+	}
+	if bs, _ := os.ReadFile(path); !bytes.Equal(bs, data) {
+		t.Errorf("%s = %q", path, bs)
+	}
+	if _, err := os.Stat(path + PartFileExt); !os.IsNotExist(err) {
+		t.Errorf("%s exists: %v", path+PartFileExt, err)
+	}
+
+	// a stale part file (larger than the content)
+	ranges = nil
+	path = filepath.Join(dir, "stale.html")
+	if err := os.WriteFile(path+PartFileExt, bytes.Repeat([]byte("x"), len(data)+10), 0o660); err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	if err := os.WriteFile(path+PartFileExt+ValidatorFileExt, []byte(`"`+hex.EncodeToString(sum[:8])+`"`), 0o660); err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	if dr, err = c.DoDownloadFile(context.Background(), ts.URL+"/stale", path, &DownloadOption{Checksum: true}); err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	if dr.Resumed || dr.SHA256 != hex.EncodeToString(sum[:]) || len(ranges) != 2 || ranges[1] != "" {
+		t.Errorf("DoDownloadFile() = %v, ranges = %q", dr, ranges)
+	}
+
+	// a part file without the validator is downloaded again
+	ranges = nil
+	path = filepath.Join(dir, "novalidator.html")
+	if err := os.WriteFile(path+PartFileExt, data[:10], 0o660); err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	if dr, err = c.DoDownloadFile(context.Background(), ts.URL+"/novalidator", path, nil); err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	if dr.Resumed || dr.Size != int64(len(data)) || len(ranges) != 1 || ranges[0] != "" {
+		t.Errorf("DoDownloadFile() = %v, ranges = %q", dr, ranges)
+	}
+
+	// the file is modified after the part file is downloaded: the If-Range does not match
+	ranges = nil
+	path = filepath.Join(dir, "modified.html")
+	if err := os.WriteFile(path+PartFileExt, bytes.Repeat([]byte("x"), 10), 0o660); err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	if err := os.WriteFile(path+PartFileExt+ValidatorFileExt, []byte(`"modified"`), 0o660); err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	if dr, err = c.DoDownloadFile(context.Background(), ts.URL+"/modified", path, nil); err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	if dr.Resumed || len(ranges) != 1 || ranges[0] != "bytes=10-" {
+		t.Errorf("DoDownloadFile() = %v, ranges = %q", dr, ranges)
+	}
+	if bs, _ := os.ReadFile(path); !bytes.Equal(bs, data) {
+		t.Errorf("%s = %q", path, bs)
+	}
+	if _, err := os.Stat(path + PartFileExt + ValidatorFileExt); !os.IsNotExist(err) {
+		t.Errorf("%s exists: %v", path+PartFileExt+ValidatorFileExt, err)
+	}
+
+	// size mismatch
+	path = filepath.Join(dir, "size.html")
+	if _, err = c.DoDownloadFile(context.Background(), ts.URL+"/size", path, &DownloadOption{Size: int64(len(data)) - 1}); !errors.Is(err, ErrSizeMismatch) {
+		t.Errorf("DoDownloadFile() = %v, want %v", err, ErrSizeMismatch)
+	}
+
+	// checksum mismatch
+	path = filepath.Join(dir, "sum.html")
+	if _, err = c.DoDownloadFile(context.Background(), ts.URL+"/sum", path, &DownloadOption{SHA256: "00"}); !errors.Is(err, ErrChecksumMismatch) {
+		t.Errorf("DoDownloadFile() = %v, want %v", err, ErrChecksumMismatch)
+	}
+	for _, p := range []string{path, path + PartFileExt} {
+		if _, err := os.Stat(p); !os.IsNotExist(err) {
+			t.Errorf("%s exists: %v", p, err)
+		}
+	}
+}
+
+func TestDownloadAttachments(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/404" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(r.URL.Path))
+	}))
+	defer ts.Close()
+
+	c := &Client{Domain: "example.freshdesk.com", BaseURL: ts.URL}
+	dir := t.TempDir()
+
+	var afs []*AttachmentFile
+	for i := range 10 {
+		a := &Attachment{ID: int64(i + 1), AttachmentURL: fmt.Sprintf("%s/%d", ts.URL, i+1)}
+		afs = append(afs, &AttachmentFile{Attachment: a, Path: filepath.Join(dir, strconv.Itoa(i+1))})
+	}
+
+	download := func(ctx context.Context, a *Attachment, path string) (*DownloadResult, error) {
+		return c.DoDownloadFileNoAuth(ctx, a.AttachmentURL, path, nil)
+	}
+
+	drs, err := DownloadAttachments(context.Background(), afs, 3, download)
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	for i, dr := range drs {
+		bs, _ := os.ReadFile(dr.Path)
+		if dr.Path != afs[i].Path || string(bs) != "/"+strconv.Itoa(i+1) {
+			t.Errorf("[%d] %v = %q", i, dr, bs)
+		}
+	}
+
+	afs[5].Attachment = &Attachment{ID: 404, AttachmentURL: ts.URL + "/404"}
+	if _, err = DownloadAttachments(context.Background(), afs, 3, download); !IsResultError(err) || !strings.HasPrefix(err.Error(), "attachment #404: ") {
+		t.Errorf("DownloadAttachments() = %v", err)
+	}
+}
+
+func TestSanitizeName(t *testing.T) {
+	cs := []struct {
+		s, w string
+	}{
+		{"a.txt", "a.txt"},
+		{`a/b\c:d*e?.txt`, "a_b_c_d_e_.txt"},
+		{"..", "_"},
+		{"", "_"},
+	}
+
+	for i, c := range cs {
+		if a := SanitizeName(c.s); a != c.w {
+			t.Errorf("[%d] SanitizeName(%q) = %q, want %q", i, c.s, a, c.w)
+		}
+	}
+}
@@ -5,12 +5,44 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"reflect"
 	"strings"
 	"time"
 
 	"github.com/askasoft/pango/str"
 )
 
+// The sentinel errors to classify a *ResultError by errors.Is(err, ErrXXX).
+var (
+	// ErrNotFound the resource is not found (404 Not Found)
+	ErrNotFound = errors.New("fresh: not found")
+
+	// ErrRateLimited the api rate limit is exceeded (429 Too Many Requests)
+	ErrRateLimited = errors.New("fresh: rate limited")
+
+	// ErrUnauthorized the credentials are invalid (401 Unauthorized)
+	ErrUnauthorized = errors.New("fresh: unauthorized")
+
+	// ErrAccessDenied the agent is not allowed to perform the action (403 Forbidden),
+	// except the feature is not supported by the plan (ErrFeatureNotSupported).
+	ErrAccessDenied = errors.New("fresh: access denied")
+
+	// ErrFeatureNotSupported the feature is not supported (or not enabled) by the plan of the account ("require_feature")
+	ErrFeatureNotSupported = errors.New("fresh: feature not supported")
+
+	// ErrDuplicate the value is used by another record ("duplicate_value"), e.g. the email of a contact
+	ErrDuplicate = errors.New("fresh: duplicate value")
+
+	// ErrValidation the request is invalid (400 Bad Request, or a 4xx response with field errors),
+	// a duplicate value is a validation error too.
+	ErrValidation = errors.New("fresh: validation failed")
+)
+
+const (
+	codeDuplicateValue = "duplicate_value"
+	codeRequireFeature = "require_feature"
+)
+
 type FieldError struct {
 	Code    string `json:"code,omitempty"`
 	Field   string `json:"field,omitempty"`
@@ -33,6 +65,123 @@ type ResultError struct {
 	RetryAfter  time.Duration `json:"-"`
 }
 
+// Is reports whether the result error matches the sentinel error target,
+// which is one of ErrNotFound, ErrRateLimited, ErrUnauthorized, ErrAccessDenied,
+// ErrFeatureNotSupported, ErrDuplicate and ErrValidation.
+func (re *ResultError) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return re.StatusCode == http.StatusNotFound
+	case ErrRateLimited:
+		return re.StatusCode == http.StatusTooManyRequests
+	case ErrUnauthorized:
+		return re.StatusCode == http.StatusUnauthorized
+	case ErrAccessDenied:
+		return re.StatusCode == http.StatusForbidden && !re.HasCode(codeRequireFeature)
+	case ErrFeatureNotSupported:
+		return re.HasCode(codeRequireFeature)
+	case ErrDuplicate:
+		return re.HasCode(codeDuplicateValue)
+	case ErrValidation:
+		return re.StatusCode == http.StatusBadRequest || (re.StatusCode >= 400 && re.StatusCode < 500 && len(re.Errors) > 0)
+	default:
+		return false
+	}
+}
+
+// HasCode reports whether the code of the result error or one of its field errors is the code.
+func (re *ResultError) HasCode(code string) bool {
+	if re.Code == code {
+		return true
+	}
+	for _, fe := range re.Errors {
+		if fe.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
+// FieldErrors returns the field errors grouped by the Go struct field names of v (see FieldError.GoField),
+// the errors of the fields which are not found in v are grouped by their api field names.
+func (re *ResultError) FieldErrors(v any) map[string][]FieldError {
+	fem := make(map[string][]FieldError, len(re.Errors))
+	for _, fe := range re.Errors {
+		name, ok := fe.GoField(v)
+		if !ok {
+			name = fe.Field
+		}
+		fem[name] = append(fem[name], fe)
+	}
+	return fem
+}
+
+// GoField returns the Go struct field name of the v (a struct or a pointer to struct) which is encoded as the fe.Field in json,
+// e.g. "requester_id" -> "RequesterID". The nested field "a.b" is returned as "A.B",
+// and the custom field "cf_xxx" (or "custom_fields.cf_xxx") is returned as "CustomFields[cf_xxx]".
+// Returns false if the field is not found.
+func (fe FieldError) GoField(v any) (string, bool) {
+	t := reflect.TypeOf(v)
+	if fe.Field == "" || t == nil {
+		return "", false
+	}
+
+	var names []string
+	for seg := range strings.SplitSeq(fe.Field, ".") {
+		for t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+			t = t.Elem()
+		}
+
+		switch t.Kind() {
+		case reflect.Map:
+			if len(names) == 0 {
+				return "", false
+			}
+			names[len(names)-1] += "[" + seg + "]"
+			t = t.Elem()
+		case reflect.Struct:
+			sf, ok := jsonField(t, seg)
+			if !ok {
+				// the custom field error of Freshdesk is reported by the name of the custom field
+				if !strings.HasPrefix(seg, "cf_") {
+					return "", false
+				}
+				if sf, ok = jsonField(t, "custom_fields"); !ok || sf.Type.Kind() != reflect.Map {
+					return "", false
+				}
+				names = append(names, sf.Name+"["+seg+"]")
+				t = sf.Type.Elem()
+				continue
+			}
+			names = append(names, sf.Name)
+			t = sf.Type
+		default:
+			return "", false
+		}
+	}
+	return strings.Join(names, "."), true
+}
+
+// jsonField returns the struct field of the type t which is encoded as the name in json.
+func jsonField(t reflect.Type, name string) (reflect.StructField, bool) {
+	return t.FieldByNameFunc(func(fn string) bool {
+		sf, _ := t.FieldByName(fn)
+		if !sf.IsExported() {
+			return false
+		}
+
+		tag, _, _ := strings.Cut(sf.Tag.Get("json"), ",")
+		switch tag {
+		case "-":
+			return false
+		case "":
+			return fn == name
+		default:
+			return tag == name
+		}
+	})
+}
+
 func AsResultError(err error) (re *ResultError, ok bool) {
 	ok = errors.As(err, &re)
 	return
@@ -0,0 +1,115 @@
+package fresh
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestResultErrorIs(t *testing.T) {
+	sentinels := []error{ErrNotFound, ErrRateLimited, ErrUnauthorized, ErrAccessDenied, ErrFeatureNotSupported, ErrDuplicate, ErrValidation}
+
+	cs := []struct {
+		re   *ResultError
+		want []error
+	}{
+		{&ResultError{StatusCode: http.StatusNotFound}, []error{ErrNotFound}},
+		{&ResultError{StatusCode: http.StatusTooManyRequests}, []error{ErrRateLimited}},
+		{&ResultError{StatusCode: http.StatusUnauthorized, Code: "invalid_credentials"}, []error{ErrUnauthorized}},
+		{&ResultError{StatusCode: http.StatusForbidden, Code: "access_denied"}, []error{ErrAccessDenied}},
+		{&ResultError{StatusCode: http.StatusForbidden, Code: "require_feature"}, []error{ErrFeatureNotSupported}},
+		{&ResultError{StatusCode: http.StatusConflict, Errors: []FieldError{{Code: "duplicate_value", Field: "email"}}}, []error{ErrDuplicate, ErrValidation}},
+		{&ResultError{StatusCode: http.StatusBadRequest, Errors: []FieldError{{Code: "missing_field", Field: "email"}}}, []error{ErrValidation}},
+		{&ResultError{StatusCode: http.StatusInternalServerError}, nil},
+	}
+
+	for i, c := range cs {
+		err := fmt.Errorf("wrapped: %w", c.re)
+		for _, s := range sentinels {
+			want := false
+			for _, w := range c.want {
+				want = want || w == s
+			}
+			if a := errors.Is(err, s); a != want {
+				t.Errorf("[%d] errors.Is(%v, %v) = %v, want %v", i, c.re.StatusCode, s, a, want)
+			}
+		}
+	}
+}
+
+type testFieldErrorItem struct {
+	Name string `json:"name"`
+}
+
+type testFieldErrorBase struct {
+	Email string `json:"email,omitempty"`
+}
+
+type testFieldErrorSource struct {
+	testFieldErrorBase
+
+	RequesterID  int64                 `json:"requester_id,omitempty"`
+	Priority     int                   `json:"priority,omitempty"`
+	Ignored      string                `json:"-"`
+	Items        []*testFieldErrorItem `json:"items,omitempty"`
+	CustomFields map[string]any        `json:"custom_fields,omitempty"`
+}
+
+func TestFieldErrorGoField(t *testing.T) {
+	cs := []struct {
+		f string
+		w string
+	}{
+		{"requester_id", "RequesterID"},
+		{"email", "Email"},
+		{"items.name", "Items.Name"},
+		{"cf_category", "CustomFields[cf_category]"},
+		{"custom_fields.cf_category", "CustomFields[cf_category]"},
+		{"Priority", ""},
+		{"-", ""},
+		{"items.unknown", ""},
+	}
+
+	for i, c := range cs {
+		a, ok := FieldError{Field: c.f}.GoField(&testFieldErrorSource{})
+		if a != c.w || ok != (c.w != "") {
+			t.Errorf("[%d] GoField(%q) = %q, %v, want %q", i, c.f, a, ok, c.w)
+		}
+	}
+
+	re := &ResultError{Errors: []FieldError{{Field: "requester_id"}, {Field: "cf_category"}, {Field: "unknown"}}}
+	fem := re.FieldErrors(testFieldErrorSource{})
+	for _, k := range []string{"RequesterID", "CustomFields[cf_category]", "unknown"} {
+		if len(fem[k]) != 1 {
+			t.Errorf("FieldErrors()[%q] = %v", k, fem[k])
+		}
+	}
+}
+
+func TestRetryerValidation(t *testing.T) {
+	status := http.StatusBadRequest
+	count := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		count++
+		w.WriteHeader(status)
+		w.Write([]byte(`{"description":"Validation failed","errors":[{"field":"email","message":"It is invalid","code":"invalid_value"}]}`))
+	}))
+	defer ts.Close()
+
+	c := &Client{Domain: "example.freshdesk.com", APIKey: "k", BaseURL: ts.URL, Retryer: NewRetryer(time.Millisecond, 3, nil)}
+
+	err := c.DoPost(context.Background(), c.Endpoint("/contacts"), map[string]any{"email": "x"}, nil)
+	if !errors.Is(err, ErrValidation) || count != 1 {
+		t.Fatalf("DoPost() = %v, requests = %d", err, count)
+	}
+
+	status, count = http.StatusServiceUnavailable, 0
+	err = c.DoPost(context.Background(), c.Endpoint("/contacts"), map[string]any{"email": "x"}, nil)
+	if errors.Is(err, ErrValidation) || count != 4 {
+		t.Fatalf("DoPost() = %v, requests = %d", err, count)
+	}
+}
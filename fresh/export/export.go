@@ -0,0 +1,486 @@
+// Package export exports the tickets with their conversations and attachments to a self-contained archive.
+//
+// The archive layout of the Exporter.Dir:
+//
+//	tickets.jsonl (or tickets.csv)              the ticket rows
+//	conversations.jsonl (or conversations.csv)  the conversation rows
+//	attachments/<ticket id>/<attachment id>_<name>                    the ticket attachments
+//	attachments/<ticket id>/<conversation id>/<attachment id>_<name>  the conversation attachments
+//	progress.jsonl                              the exported tickets (for resuming)
+//
+// The rows of a ticket are written after all of its attachments are downloaded,
+// and the ticket is recorded to the progress file after the rows are written,
+// so an interrupted export can be resumed by running the Exporter again with the same Dir and Format.
+//
+// Example:
+//
+//	ex := fd.NewTicketExporter("archive", export.FormatCSV, nil)
+//	ex.CustomFields = []string{"cf_region"}
+//	err := ex.Export(ctx)
+package export
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/askasoft/gofresh/fresh"
+)
+
+// Format the format of the ticket and conversation rows
+type Format string
+
+const (
+	FormatJSONL Format = "jsonl"
+	FormatCSV   Format = "csv"
+)
+
+const (
+	TicketsFile       = "tickets"
+	ConversationsFile = "conversations"
+	AttachmentsDir    = "attachments"
+	ProgressFile      = "progress.jsonl"
+)
+
+// ErrInvalidFormat the format is not supported
+var ErrInvalidFormat = errors.New("export: invalid format")
+
+// Column a CSV column
+type Column[T any] struct {
+	Name  string
+	Value func(T) any
+}
+
+// Source the source of the tickets and conversations to export
+type Source[T, C any] struct {
+	// Tickets returns an iterator over the tickets to export.
+	Tickets func(ctx context.Context) iter.Seq2[T, error]
+
+	// Conversations returns an iterator over the conversations of the ticket, nil means no conversations.
+	Conversations func(ctx context.Context, t T) iter.Seq2[C, error]
+
+	// TicketID returns the id of the ticket.
+	TicketID func(T) int64
+
+	// ConversationID returns the id of the conversation.
+	ConversationID func(C) int64
+
+	// TicketAttachments returns the attachments of the ticket.
+	TicketAttachments func(T) []*fresh.Attachment
+
+	// ConversationAttachments returns the attachments of the conversation.
+	ConversationAttachments func(C) []*fresh.Attachment
+
+	// TicketCustomFields returns the custom fields of the ticket.
+	TicketCustomFields func(T) map[string]any
+
+	// TicketColumns the CSV columns of the ticket.
+	TicketColumns []*Column[T]
+
+	// ConversationColumns the CSV columns of the conversation.
+	ConversationColumns []*Column[C]
+
+	// Download saves the attachment to the path.
+	Download func(ctx context.Context, a *fresh.Attachment, path string) error
+}
+
+// Exporter exports the tickets of the Source to the Dir.
+// An Exporter must not be run concurrently with the same Dir.
+type Exporter[T, C any] struct {
+	Source *Source[T, C]
+
+	// Dir the archive directory
+	Dir string
+
+	// Format the format of the rows, default is FormatJSONL.
+	Format Format
+
+	// CustomFields the custom fields to export.
+	// For FormatCSV, a "custom_fields.<name>" column is added for each custom field, nil means no custom field columns.
+	// For FormatJSONL, only the specified custom fields are kept in the "custom_fields" object, nil means all custom fields.
+	CustomFields []string
+
+	// SkipAttachments do not download the attachments.
+	SkipAttachments bool
+}
+
+// NewExporter returns an Exporter of the source.
+func NewExporter[T, C any](src *Source[T, C], dir string, format Format) *Exporter[T, C] {
+	return &Exporter[T, C]{
+		Source: src,
+		Dir:    dir,
+		Format: format,
+	}
+}
+
+// progress a line of the progress file
+type progress struct {
+	TicketID      int64 `json:"ticket_id"`
+	Tickets       int64 `json:"tickets"`       // size of the tickets file
+	Conversations int64 `json:"conversations"` // size of the conversations file
+}
+
+// Export exports the tickets which are not exported yet (recorded in the progress file).
+// The tickets exported by the previous runs are not exported again, even if they are updated.
+func (ex *Exporter[T, C]) Export(ctx context.Context) error {
+	format := ex.Format
+	if format == "" {
+		format = FormatJSONL
+	}
+	if format != FormatJSONL && format != FormatCSV {
+		return fmt.Errorf("%w: %q", ErrInvalidFormat, format)
+	}
+
+	if err := os.MkdirAll(ex.Dir, 0o770); err != nil {
+		return err
+	}
+
+	pf, done, last, err := ex.loadProgress()
+	if err != nil {
+		return err
+	}
+	defer pf.Close()
+
+	tf, err := ex.openRows(TicketsFile+"."+string(format), last.Tickets)
+	if err != nil {
+		return err
+	}
+	defer tf.Close()
+
+	cf, err := ex.openRows(ConversationsFile+"."+string(format), last.Conversations)
+	if err != nil {
+		return err
+	}
+	defer cf.Close()
+
+	if format == FormatCSV {
+		if err := ex.writeHeaders(tf, cf); err != nil {
+			return err
+		}
+
+		// the sizes of the header lines
+		if last.Tickets, err = tf.Seek(0, io.SeekCurrent); err != nil {
+			return err
+		}
+		if last.Conversations, err = cf.Seek(0, io.SeekCurrent); err != nil {
+			return err
+		}
+	}
+
+	src := ex.Source
+	for t, err := range src.Tickets(ctx) {
+		if err != nil {
+			return err
+		}
+
+		tid := src.TicketID(t)
+		if done[tid] {
+			continue
+		}
+
+		tb, cb, err := ex.exportTicket(ctx, format, t)
+		if err != nil {
+			return fmt.Errorf("export: ticket #%d: %w", tid, err)
+		}
+
+		if _, err := tf.Write(tb); err != nil {
+			return err
+		}
+		if _, err := cf.Write(cb); err != nil {
+			return err
+		}
+		if err := tf.Sync(); err != nil {
+			return err
+		}
+		if err := cf.Sync(); err != nil {
+			return err
+		}
+
+		last.TicketID = tid
+		last.Tickets += int64(len(tb))
+		last.Conversations += int64(len(cb))
+		if err := writeJSONLine(pf, last); err != nil {
+			return err
+		}
+		done[tid] = true
+	}
+
+	return nil
+}
+
+// loadProgress loads the exported ticket ids and the last progress from the progress file,
+// and opens the progress file for appending (a broken line written by an interruption is truncated).
+func (ex *Exporter[T, C]) loadProgress() (*os.File, map[int64]bool, *progress, error) {
+	f, err := os.OpenFile(filepath.Join(ex.Dir, ProgressFile), os.O_RDWR|os.O_CREATE, 0o660)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	done, last, size := map[int64]bool{}, &progress{}, int64(0)
+
+	br := bufio.NewReader(f)
+	for {
+		bs, err := br.ReadBytes('\n')
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			f.Close()
+			return nil, nil, nil, err
+		}
+
+		p := &progress{}
+		if err := json.Unmarshal(bs, p); err != nil {
+			break
+		}
+		done[p.TicketID] = true
+		last = p
+		size += int64(len(bs))
+	}
+
+	if err := f.Truncate(size); err != nil {
+		f.Close()
+		return nil, nil, nil, err
+	}
+	if _, err := f.Seek(size, io.SeekStart); err != nil {
+		f.Close()
+		return nil, nil, nil, err
+	}
+	return f, done, last, nil
+}
+
+// openRows opens the rows file and truncates the rows which are not recorded in the progress file.
+func (ex *Exporter[T, C]) openRows(name string, size int64) (*os.File, error) {
+	f, err := os.OpenFile(filepath.Join(ex.Dir, name), os.O_RDWR|os.O_CREATE, 0o660)
+	if err != nil {
+		return nil, err
+	}
+	if err := f.Truncate(size); err != nil {
+		f.Close()
+		return nil, err
+	}
+	if _, err := f.Seek(size, io.SeekStart); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return f, nil
+}
+
+func (ex *Exporter[T, C]) writeHeaders(tf, cf *os.File) error {
+	if fi, err := tf.Stat(); err != nil {
+		return err
+	} else if fi.Size() == 0 {
+		var hs []string
+		for _, c := range ex.Source.TicketColumns {
+			hs = append(hs, c.Name)
+		}
+		for _, n := range ex.CustomFields {
+			hs = append(hs, "custom_fields."+n)
+		}
+		hs = append(hs, AttachmentsDir)
+		if err := writeCSVLine(tf, hs); err != nil {
+			return err
+		}
+	}
+
+	if fi, err := cf.Stat(); err != nil {
+		return err
+	} else if fi.Size() == 0 {
+		var hs []string
+		for _, c := range ex.Source.ConversationColumns {
+			hs = append(hs, c.Name)
+		}
+		hs = append(hs, AttachmentsDir)
+		if err := writeCSVLine(cf, hs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// exportTicket downloads the attachments of the ticket and its conversations,
+// returns the rows of the ticket and the conversations.
+func (ex *Exporter[T, C]) exportTicket(ctx context.Context, format Format, t T) ([]byte, []byte, error) {
+	src := ex.Source
+	tid := src.TicketID(t)
+	tdir := filepath.Join(AttachmentsDir, strconv.FormatInt(tid, 10))
+
+	var err error
+
+	var tas []string
+	if src.TicketAttachments != nil {
+		tas, err = ex.download(ctx, tdir, src.TicketAttachments(t))
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tb := &bytes.Buffer{}
+	if format == FormatCSV {
+		err = writeCSVLine(tb, ex.ticketRecord(t, tas))
+	} else {
+		err = ex.writeTicketJSON(tb, t)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cb := &bytes.Buffer{}
+	if src.Conversations == nil {
+		return tb.Bytes(), cb.Bytes(), nil
+	}
+
+	for c, err := range src.Conversations(ctx, t) {
+		if err != nil {
+			return nil, nil, err
+		}
+
+		cdir := filepath.Join(tdir, strconv.FormatInt(src.ConversationID(c), 10))
+		var cas []string
+		if src.ConversationAttachments != nil {
+			cas, err = ex.download(ctx, cdir, src.ConversationAttachments(c))
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if format == FormatCSV {
+			err = writeCSVLine(cb, conversationRecord(src.ConversationColumns, c, cas))
+		} else {
+			err = writeJSONLine(cb, c)
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return tb.Bytes(), cb.Bytes(), nil
+}
+
+// download downloads the attachments to the dir (relative to ex.Dir),
+// returns the relative paths (slash separated) of the attachments.
+func (ex *Exporter[T, C]) download(ctx context.Context, dir string, as []*fresh.Attachment) ([]string, error) {
+	var paths []string
+	for _, a := range as {
+		path := filepath.Join(dir, fresh.AttachmentFileName(a))
+		if !ex.SkipAttachments {
+			if err := ex.Source.Download(ctx, a, filepath.Join(ex.Dir, path)); err != nil {
+				return nil, fmt.Errorf("attachment #%d: %w", a.ID, err)
+			}
+		}
+		paths = append(paths, filepath.ToSlash(path))
+	}
+	return paths, nil
+}
+
+func (ex *Exporter[T, C]) ticketRecord(t T, paths []string) []string {
+	var rec []string
+	for _, c := range ex.Source.TicketColumns {
+		rec = append(rec, Text(c.Value(t)))
+	}
+	if len(ex.CustomFields) > 0 {
+		var cfs map[string]any
+		if ex.Source.TicketCustomFields != nil {
+			cfs = ex.Source.TicketCustomFields(t)
+		}
+		for _, n := range ex.CustomFields {
+			rec = append(rec, Text(cfs[n]))
+		}
+	}
+	return append(rec, strings.Join(paths, "\n"))
+}
+
+func conversationRecord[C any](cols []*Column[C], c C, paths []string) []string {
+	var rec []string
+	for _, col := range cols {
+		rec = append(rec, Text(col.Value(c)))
+	}
+	return append(rec, strings.Join(paths, "\n"))
+}
+
+// writeTicketJSON writes the ticket as a JSON line, only the selected custom fields are kept if ex.CustomFields is not nil.
+func (ex *Exporter[T, C]) writeTicketJSON(w io.Writer, t T) error {
+	if ex.CustomFields == nil || ex.Source.TicketCustomFields == nil {
+		return writeJSONLine(w, t)
+	}
+
+	bs, err := json.Marshal(t)
+	if err != nil {
+		return err
+	}
+
+	m := map[string]json.RawMessage{}
+	if err := json.Unmarshal(bs, &m); err != nil {
+		return err
+	}
+
+	cfs := ex.Source.TicketCustomFields(t)
+	sel := make(map[string]any, len(ex.CustomFields))
+	for _, n := range ex.CustomFields {
+		if v, ok := cfs[n]; ok {
+			sel[n] = v
+		}
+	}
+	if bs, err = json.Marshal(sel); err != nil {
+		return err
+	}
+	m["custom_fields"] = bs
+
+	return writeJSONLine(w, m)
+}
+
+func writeJSONLine(w io.Writer, v any) error {
+	bs, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(bs, '\n'))
+	return err
+}
+
+func writeCSVLine(w io.Writer, rec []string) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(rec); err != nil {
+		return err
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// Text returns the CSV text of the value.
+// A string is returned as is, nil is converted to "", and the other values are converted to JSON
+// (the quotes of a JSON string are removed).
+func Text(v any) string {
+	switch s := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return s
+	}
+
+	bs, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+
+	s := string(bs)
+	if s == "null" {
+		return ""
+	}
+	if len(s) > 1 && s[0] == '"' {
+		if u, err := strconv.Unquote(s); err == nil {
+			return u
+		}
+	}
+	return s
+}
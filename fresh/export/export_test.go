@@ -0,0 +1,205 @@
+package export
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"iter"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/askasoft/gofresh/fresh"
+)
+
+type testTicket struct {
+	ID           int64               `json:"id"`
+	Attachments  []*fresh.Attachment `json:"attachments,omitempty"`
+	CustomFields map[string]any      `json:"custom_fields,omitempty"`
+}
+
+type testConversation struct {
+	ID          int64               `json:"id"`
+	TicketID    int64               `json:"ticket_id"`
+	Attachments []*fresh.Attachment `json:"attachments,omitempty"`
+}
+
+func testSource(fail map[int64]bool) *Source[*testTicket, *testConversation] {
+	tickets := []*testTicket{
+		{ID: 1, CustomFields: map[string]any{"cf_a": "a1", "cf_b": 1}},
+		{ID: 2, Attachments: []*fresh.Attachment{{ID: 20, Name: "a/b.txt"}}, CustomFields: map[string]any{"cf_a": "a2"}},
+		{ID: 3},
+	}
+
+	return &Source[*testTicket, *testConversation]{
+		Tickets: func(ctx context.Context) iter.Seq2[*testTicket, error] {
+			return func(yield func(*testTicket, error) bool) {
+				for _, t := range tickets {
+					if !yield(t, nil) {
+						return
+					}
+				}
+			}
+		},
+		Conversations: func(ctx context.Context, t *testTicket) iter.Seq2[*testConversation, error] {
+			return func(yield func(*testConversation, error) bool) {
+				c := &testConversation{ID: t.ID * 100, TicketID: t.ID}
+				if t.ID == 3 {
+					c.Attachments = []*fresh.Attachment{{ID: 30, Name: "c.txt"}}
+				}
+				yield(c, nil)
+			}
+		},
+		TicketID:                func(t *testTicket) int64 { return t.ID },
+		ConversationID:          func(c *testConversation) int64 { return c.ID },
+		TicketAttachments:       func(t *testTicket) []*fresh.Attachment { return t.Attachments },
+		ConversationAttachments: func(c *testConversation) []*fresh.Attachment { return c.Attachments },
+		TicketCustomFields:      func(t *testTicket) map[string]any { return t.CustomFields },
+		TicketColumns: []*Column[*testTicket]{
+			{Name: "id", Value: func(t *testTicket) any { return t.ID }},
+		},
+		ConversationColumns: []*Column[*testConversation]{
+			{Name: "id", Value: func(c *testConversation) any { return c.ID }},
+			{Name: "ticket_id", Value: func(c *testConversation) any { return c.TicketID }},
+		},
+		Download: func(ctx context.Context, a *fresh.Attachment, path string) error {
+			if fail[a.ID] {
+				return errors.New("failed")
+			}
+			if err := os.MkdirAll(filepath.Dir(path), 0o770); err != nil {
+				return err
+			}
+			return os.WriteFile(path, []byte(a.Name), 0o660)
+		},
+	}
+}
+
+func testReadCSV(t *testing.T, path string) [][]string {
+	t.Helper()
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	defer f.Close()
+
+	recs, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	return recs
+}
+
+func TestExportCSVResume(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	fail := map[int64]bool{30: true}
+	ex := NewExporter(testSource(fail), dir, FormatCSV)
+	ex.CustomFields = []string{"cf_a", "cf_b"}
+
+	if err := ex.Export(ctx); err == nil {
+		t.Fatal("Export() should fail")
+	}
+
+	// simulate an interruption after the rows are written
+	f, err := os.OpenFile(filepath.Join(dir, "tickets.csv"), os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	f.WriteString("3,broken\n")
+	f.Close()
+
+	delete(fail, 30)
+	if err := ex.Export(ctx); err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+
+	want := [][]string{
+		{"id", "custom_fields.cf_a", "custom_fields.cf_b", "attachments"},
+		{"1", "a1", "1", ""},
+		{"2", "a2", "", "attachments/2/20_a_b.txt"},
+		{"3", "", "", ""},
+	}
+	if recs := testReadCSV(t, filepath.Join(dir, "tickets.csv")); !slices.EqualFunc(recs, want, slices.Equal) {
+		t.Errorf("tickets.csv = %q, want %q", recs, want)
+	}
+
+	want = [][]string{
+		{"id", "ticket_id", "attachments"},
+		{"100", "1", ""},
+		{"200", "2", ""},
+		{"300", "3", "attachments/3/300/30_c.txt"},
+	}
+	if recs := testReadCSV(t, filepath.Join(dir, "conversations.csv")); !slices.EqualFunc(recs, want, slices.Equal) {
+		t.Errorf("conversations.csv = %q, want %q", recs, want)
+	}
+
+	for _, p := range []string{"attachments/2/20_a_b.txt", "attachments/3/300/30_c.txt"} {
+		if _, err := os.Stat(filepath.Join(dir, p)); err != nil {
+			t.Errorf("ERROR: %v", err)
+		}
+	}
+
+	// all exported
+	if err := ex.Export(ctx); err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	if recs := testReadCSV(t, filepath.Join(dir, "tickets.csv")); len(recs) != 4 {
+		t.Errorf("tickets.csv = %q", recs)
+	}
+}
+
+func TestExportJSONL(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	ex := NewExporter(testSource(nil), dir, FormatJSONL)
+	ex.CustomFields = []string{"cf_b"}
+	ex.SkipAttachments = true
+
+	if err := ex.Export(ctx); err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+
+	bs, err := os.ReadFile(filepath.Join(dir, "tickets.jsonl"))
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+
+	want := strings.Join([]string{
+		`{"custom_fields":{"cf_b":1},"id":1}`,
+		`{"attachments":[{"id":20,"name":"a/b.txt"}],"custom_fields":{},"id":2}`,
+		`{"custom_fields":{},"id":3}`,
+		``,
+	}, "\n")
+	if string(bs) != want {
+		t.Errorf("tickets.jsonl = %s, want %s", bs, want)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "attachments")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("attachments should not be downloaded: %v", err)
+	}
+}
+
+func TestText(t *testing.T) {
+	cs := []struct {
+		v    any
+		want string
+	}{
+		{nil, ""},
+		{"a\"b", "a\"b"},
+		{int64(12), "12"},
+		{true, "true"},
+		{[]string{"a", "b"}, `["a","b"]`},
+		{(*fresh.Time)(nil), ""},
+	}
+
+	for i, c := range cs {
+		if a := Text(c.v); a != c.want {
+			t.Errorf("[%d] Text(%v) = %q, want %q", i, c.v, a, c.want)
+		}
+	}
+}
@@ -9,17 +9,14 @@ import (
 	"io"
 	"net/http"
 	"net/url"
-	"os"
-	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/askasoft/pango/doc/jsonx"
-	"github.com/askasoft/pango/fsu"
 	"github.com/askasoft/pango/gog"
 	"github.com/askasoft/pango/iox"
 	"github.com/askasoft/pango/log"
 	"github.com/askasoft/pango/net/httpx"
-	"github.com/askasoft/pango/num"
 	"github.com/askasoft/pango/ret"
 )
 
@@ -32,14 +29,15 @@ type BodyMarshaler interface {
 	MarshalBody() (io.Reader, string, error)
 }
 
-// default retry on not canceled error or (status = 429 || (status >= 500 && status <= 599))
+// default retry on not canceled error or (status = 429 || (status >= 500 && status <= 599)),
+// the validation errors (ErrValidation) are never retried.
 func NewRetryer(retryAfter time.Duration, maxRetries int, logger log.Logger) *ret.Retryer {
 	return &ret.Retryer{
 		Logger:     logger,
 		MaxRetries: maxRetries,
 		ShouldRetry: func(err error) time.Duration {
 			var ute *json.UnmarshalTypeError
-			if errors.As(err, &ute) {
+			if errors.As(err, &ute) || errors.Is(err, ErrNotReplayable) || errors.Is(err, ErrValidation) {
 				return 0
 			}
 			if re, ok := AsResultError(err); ok {
@@ -53,20 +51,73 @@ func NewRetryer(retryAfter time.Duration, maxRetries int, logger log.Logger) *re
 	}
 }
 
+// DefaultAPIPath the default path prefix of the api endpoints
+const DefaultAPIPath = "/api/v2"
+
 type Client struct {
 	Domain   string
 	APIKey   string
 	Username string
 	Password string
 
-	Transport http.RoundTripper
-	Timeout   time.Duration
-	Retryer   *ret.Retryer
+	// BaseURL overrides the scheme and host of the urls (default: "https://" + Domain),
+	// e.g. "http://localhost:8080" or "https://proxy.example.com/freshdesk".
+	// The requests to the Domain (e.g. the Link header next URL, the attachment URL) are sent to the BaseURL too.
+	BaseURL string
+
+	// APIPath overrides the path prefix of the api endpoints (default: "/api/v2"),
+	// e.g. "/api/_" or "/api/channel/v2".
+	APIPath string
+
+	Transport   http.RoundTripper
+	Timeout     time.Duration
+	Retryer     *ret.Retryer
+	RateLimiter *RateLimiter
+}
+
+// SiteURL returns the base url of the site, which is the BaseURL or "https://" + Domain.
+func (c *Client) SiteURL() string {
+	if c.BaseURL != "" {
+		return strings.TrimSuffix(c.BaseURL, "/")
+	}
+	return "https://" + c.Domain
+}
+
+// Permalink formats the url of the site page, e.g. Permalink("/a/tickets/%d", tid).
+func (c *Client) Permalink(format string, a ...any) string {
+	return c.SiteURL() + fmt.Sprintf(format, a...)
 }
 
 // Endpoint formats endpoint url
 func (c *Client) Endpoint(format string, a ...any) string {
-	return "https://" + c.Domain + "/api/v2" + fmt.Sprintf(format, a...)
+	ap := c.APIPath
+	if ap == "" {
+		ap = DefaultAPIPath
+	}
+	return c.SiteURL() + ap + fmt.Sprintf(format, a...)
+}
+
+// rewrite redirects the request to the Domain to the BaseURL.
+// The url which already starts with the SiteURL (e.g. the BaseURL host is the Domain) is not rewritten.
+func (c *Client) rewrite(req *http.Request) {
+	if c.BaseURL == "" || c.Domain == "" || !strings.EqualFold(req.URL.Host, c.Domain) {
+		return
+	}
+
+	if su := c.SiteURL(); strings.HasPrefix(req.URL.String(), su+"/") {
+		return
+	}
+
+	bu, err := url.Parse(c.BaseURL)
+	if err != nil {
+		return
+	}
+
+	req.URL.Scheme = bu.Scheme
+	req.URL.Host = bu.Host
+	req.URL.Path = strings.TrimSuffix(bu.Path, "/") + req.URL.Path
+	req.URL.RawPath = ""
+	req.Host = bu.Host
 }
 
 func (c *Client) RetryForError(ctx context.Context, api func() error) (err error) {
@@ -89,6 +140,8 @@ func (c *Client) authenticate(req *http.Request) {
 }
 
 func (c *Client) call(req *http.Request) (*http.Response, error) {
+	c.rewrite(req)
+
 	hc := http.Client{
 		Transport: c.Transport,
 		Timeout:   c.Timeout,
@@ -98,7 +151,28 @@ func (c *Client) call(req *http.Request) (*http.Response, error) {
 
 func (c *Client) authAndCall(req *http.Request) (*http.Response, error) {
 	c.authenticate(req)
-	return c.call(req)
+
+	rl := c.RateLimiter
+	if rl != nil {
+		if err := rl.Wait(req.Context()); err != nil {
+			if req.Body != nil {
+				req.Body.Close()
+			}
+			return nil, err
+		}
+	}
+
+	res, err := c.call(req)
+	if err != nil {
+		return res, err
+	}
+
+	if rl != nil {
+		rl.Update(res)
+	}
+	collectResponse(res)
+
+	return res, nil
 }
 
 func (c *Client) DoCall(req *http.Request, result any) error {
@@ -127,11 +201,7 @@ func (c *Client) doCall(req *http.Request, result any) (*http.Response, error) {
 		_ = decoder.Decode(re)
 	}
 
-	s := res.Header.Get("Retry-After")
-	n := num.Atoi(s)
-	if n > 0 {
-		re.RetryAfter = time.Second * time.Duration(n)
-	}
+	re.RetryAfter = parseRetryAfter(res.Header)
 
 	return res, re
 }
@@ -152,6 +222,12 @@ func (c *Client) doGet(ctx context.Context, url string, result any) error {
 }
 
 func (c *Client) DoList(ctx context.Context, url string, lo ListOption, ap any) (next bool, err error) {
+	link, err := c.DoListLink(ctx, url, lo, ap)
+	return link != "", err
+}
+
+// DoListLink lists the url with the list option, returns the rel="next" URL of the Link header.
+func (c *Client) DoListLink(ctx context.Context, url string, lo ListOption, ap any) (next string, err error) {
 	err = c.RetryForError(ctx, func() error {
 		next, err = c.doList(ctx, url, lo, ap)
 		return err
@@ -159,10 +235,10 @@ func (c *Client) DoList(ctx context.Context, url string, lo ListOption, ap any)
 	return
 }
 
-func (c *Client) doList(ctx context.Context, url string, lo ListOption, result any) (bool, error) {
+func (c *Client) doList(ctx context.Context, url string, lo ListOption, result any) (string, error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
-		return false, err
+		return "", err
 	}
 
 	if lo != nil && !lo.IsNil() {
@@ -172,11 +248,10 @@ func (c *Client) doList(ctx context.Context, url string, lo ListOption, result a
 
 	res, err := c.doCall(req, result)
 	if err != nil {
-		return false, err
+		return "", err
 	}
 
-	next := res.Header.Get("Link") != ""
-	return next, nil
+	return ParseLinkNext(res.Header.Get(HeaderLink)), nil
 }
 
 func (c *Client) DoPost(ctx context.Context, url string, source, result any) error {
@@ -186,19 +261,11 @@ func (c *Client) DoPost(ctx context.Context, url string, source, result any) err
 }
 
 func (c *Client) doPost(ctx context.Context, url string, source, result any) error {
-	buf, ct, err := buildRequest(source)
+	req, err := newRequest(ctx, http.MethodPost, url, source)
 	if err != nil {
 		return err
 	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, buf)
-	if err != nil {
-		return err
-	}
-	if ct != "" {
-		req.Header.Set("Content-Type", ct)
-	}
-
 	return c.DoCall(req, result)
 }
 
@@ -209,19 +276,11 @@ func (c *Client) DoPut(ctx context.Context, url string, source, result any) erro
 }
 
 func (c *Client) doPut(ctx context.Context, url string, source, result any) error {
-	buf, ct, err := buildRequest(source)
+	req, err := newRequest(ctx, http.MethodPut, url, source)
 	if err != nil {
 		return err
 	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPut, url, buf)
-	if err != nil {
-		return err
-	}
-	if ct != "" {
-		req.Header.Set("Content-Type", ct)
-	}
-
 	return c.DoCall(req, result)
 }
 
@@ -282,24 +341,10 @@ func (c *Client) doReadFile(ctx context.Context, url string) ([]byte, error) {
 	return readResponse(res)
 }
 
+// DoSaveFile downloads the url to the path atomically, see DoDownloadFile.
 func (c *Client) DoSaveFile(ctx context.Context, url string, path string) error {
-	return c.RetryForError(ctx, func() error {
-		return c.doSaveFile(ctx, url, path)
-	})
-}
-
-func (c *Client) doSaveFile(ctx context.Context, url string, path string) error {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
-	if err != nil {
-		return err
-	}
-
-	res, err := c.authAndCall(req)
-	if err != nil {
-		return err
-	}
-
-	return saveResponse(res, path)
+	_, err := c.DoDownloadFile(ctx, url, path, nil)
+	return err
 }
 
 func (c *Client) DoCopyFileNoAuth(ctx context.Context, url string, w io.Writer) error {
@@ -344,24 +389,10 @@ func (c *Client) doReadFileNoAuth(ctx context.Context, url string) ([]byte, erro
 	return readResponse(res)
 }
 
+// DoSaveFileNoAuth downloads the url to the path atomically, see DoDownloadFileNoAuth.
 func (c *Client) DoSaveFileNoAuth(ctx context.Context, url string, path string) error {
-	return c.RetryForError(ctx, func() error {
-		return c.doSaveFileNoAuth(ctx, url, path)
-	})
-}
-
-func (c *Client) doSaveFileNoAuth(ctx context.Context, url string, path string) error {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
-	if err != nil {
-		return err
-	}
-
-	res, err := c.call(req)
-	if err != nil {
-		return err
-	}
-
-	return saveResponse(res, path)
+	_, err := c.DoDownloadFileNoAuth(ctx, url, path, nil)
+	return err
 }
 
 func toString(o any) string {
@@ -372,18 +403,39 @@ func addMultipartValues(mw *httpx.MultipartWriter, vs Values) error {
 	return mw.WriteFields(url.Values(vs))
 }
 
-func addMultipartFiles(mw *httpx.MultipartWriter, fs Files) (err error) {
+func addMultipartFiles(mw *httpx.MultipartWriter, fs Files) error {
 	for _, f := range fs {
-		if f.Data() == nil {
-			err = mw.WriteFile(f.Field(), f.File())
-		} else {
-			err = mw.WriteFileData(f.Field(), f.File(), f.Data())
+		if err := writeMultipartFile(mw, f); err != nil {
+			return err
 		}
-		if err != nil {
-			return
+	}
+	return nil
+}
+
+// newRequest build a request of the source,
+// a source with files is sent as a streaming MultipartBody.
+func newRequest(ctx context.Context, method, url string, source any) (*http.Request, error) {
+	if _, ok := source.(BodyMarshaler); !ok {
+		if wf, ok := source.(WithFiles); ok {
+			if fs := wf.Files(); len(fs) > 0 {
+				return NewMultipartBody(wf.Values(), fs).NewRequest(ctx, method, url)
+			}
 		}
 	}
-	return
+
+	buf, ct, err := buildRequest(source)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, buf)
+	if err != nil {
+		return nil, err
+	}
+	if ct != "" {
+		req.Header.Set("Content-Type", ct)
+	}
+	return req, nil
 }
 
 // buildRequest build a request, returns buffer, contentType, error
@@ -407,6 +459,8 @@ func buildRequest(a any) (io.Reader, string, error) {
 	return BuildJSONRequest(a)
 }
 
+// BuildMultipartRequest builds the multipart body in memory, returns buffer, contentType, error.
+// Use NewMultipartBody to stream a large body.
 func BuildMultipartRequest(vs Values, fs Files) (io.Reader, string, error) {
 	buf := &bytes.Buffer{}
 	mw := httpx.NewMultipartWriter(buf)
@@ -456,18 +510,3 @@ func readResponse(res *http.Response) ([]byte, error) {
 
 	return io.ReadAll(res.Body)
 }
-
-func saveResponse(res *http.Response, path string) error {
-	defer iox.DrainAndClose(res.Body)
-
-	if res.StatusCode != http.StatusOK {
-		return newResultError(res)
-	}
-
-	dir := filepath.Dir(path)
-	if err := os.MkdirAll(dir, 0770); err != nil {
-		return err
-	}
-
-	return fsu.WriteReader(path, res.Body, 0660)
-}
@@ -0,0 +1,99 @@
+package fresh
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestClientEndpoint(t *testing.T) {
+	cs := []struct {
+		c *Client
+		e string
+		p string
+	}{
+		{&Client{Domain: "example.freshdesk.com"}, "https://example.freshdesk.com/api/v2/tickets/1", "https://example.freshdesk.com/a/tickets/1"},
+		{&Client{Domain: "example.freshdesk.com", BaseURL: "http://localhost:8080/"}, "http://localhost:8080/api/v2/tickets/1", "http://localhost:8080/a/tickets/1"},
+		{&Client{BaseURL: "https://proxy.example.com/fd", APIPath: "/api/_"}, "https://proxy.example.com/fd/api/_/tickets/1", "https://proxy.example.com/fd/a/tickets/1"},
+		{&Client{Domain: "example.freshdesk.com", APIPath: "/api/channel/v2"}, "https://example.freshdesk.com/api/channel/v2/tickets/1", "https://example.freshdesk.com/a/tickets/1"},
+	}
+
+	for i, c := range cs {
+		if a := c.c.Endpoint("/tickets/%d", 1); a != c.e {
+			t.Errorf("[%d] Endpoint() = %q, want %q", i, a, c.e)
+		}
+		if a := c.c.Permalink("/a/tickets/%d", 1); a != c.p {
+			t.Errorf("[%d] Permalink() = %q, want %q", i, a, c.p)
+		}
+	}
+}
+
+func TestClientBaseURL(t *testing.T) {
+	var paths []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.RequestURI())
+		if r.URL.Query().Get("page") == "" {
+			w.Header().Set(HeaderLink, `<https://example.freshdesk.com/api/v2/items?page=2>; rel="next"`)
+		}
+		w.Write([]byte(`[]`))
+	}))
+	defer ts.Close()
+
+	c := &Client{Domain: "example.freshdesk.com", APIKey: "k", BaseURL: ts.URL + "/proxy"}
+
+	var items []any
+	next, err := c.DoListLink(context.Background(), c.Endpoint("/items"), nil, &items)
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+
+	if _, err = c.DoListLink(context.Background(), next, nil, &items); err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+
+	if _, err = c.DoReadFileNoAuth(context.Background(), "https://EXAMPLE.freshdesk.com/helpdesk/attachments/1"); err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+
+	want := []string{"/proxy/api/v2/items", "/proxy/api/v2/items?page=2", "/proxy/helpdesk/attachments/1"}
+	if len(paths) != len(want) {
+		t.Fatalf("requests = %v, want %v", paths, want)
+	}
+	for i, w := range want {
+		if paths[i] != w {
+			t.Errorf("[%d] request = %q, want %q", i, paths[i], w)
+		}
+	}
+}
+
+func TestClientBaseURLSameHost(t *testing.T) {
+	var paths []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.RequestURI())
+		w.Write([]byte(`[]`))
+	}))
+	defer ts.Close()
+
+	u, _ := url.Parse(ts.URL)
+	c := &Client{Domain: u.Host, APIKey: "k", BaseURL: ts.URL + "/proxy"}
+
+	var items []any
+	if _, err := c.DoListLink(context.Background(), c.Endpoint("/items"), nil, &items); err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	if _, err := c.DoListLink(context.Background(), ts.URL+"/api/v2/items?page=2", nil, &items); err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+
+	want := []string{"/proxy/api/v2/items", "/proxy/api/v2/items?page=2"}
+	if len(paths) != len(want) {
+		t.Fatalf("requests = %v, want %v", paths, want)
+	}
+	for i, w := range want {
+		if paths[i] != w {
+			t.Errorf("[%d] request = %q, want %q", i, paths[i], w)
+		}
+	}
+}
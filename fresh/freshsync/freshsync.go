@@ -0,0 +1,196 @@
+// Package freshsync provides an incremental change sync engine on top of the "updated_since" list apis.
+//
+// A Syncer polls its Sources for the records updated since the checkpoint (high-water mark) of each Source,
+// emits the created/updated/deleted Changes, and saves the checkpoint to a pluggable Store.
+// The checkpoint remembers the records which have been delivered at the high-water mark (and in the Overlap window),
+// so the records with the same timestamp as the boundary are neither dropped nor duplicated.
+//
+// Example:
+//
+//	s := fd.NewTicketSyncer(freshsync.NewFileStore("checkpoints"), nil)
+//	err := s.Run(ctx, func(ctx context.Context, c *freshsync.Change[*freshdesk.Ticket]) error {
+//		...
+//	})
+package freshsync
+
+import (
+	"context"
+	"iter"
+	"time"
+)
+
+// ChangeType the type of the Change
+type ChangeType string
+
+const (
+	ChangeCreated ChangeType = "created"
+	ChangeUpdated ChangeType = "updated"
+	ChangeDeleted ChangeType = "deleted"
+)
+
+// Change a change of a record
+type Change[T any] struct {
+	Type      ChangeType
+	Source    string // the name of the Source
+	ID        int64
+	UpdatedAt time.Time
+	Item      T
+}
+
+// Handler handles a Change, the sync is stopped if it returns an error.
+type Handler[T any] func(ctx context.Context, c *Change[T]) error
+
+// Source a source of the records to sync
+type Source[T any] struct {
+	// Name the name of the source, it is a part of the checkpoint key.
+	Name string
+
+	// List returns an iterator over the records updated since the time (inclusive).
+	List func(ctx context.Context, since time.Time) iter.Seq2[T, error]
+
+	// Ordered reports whether the records are listed in ascending order of the updated time.
+	// If true, the checkpoint of the delivered records is saved when the Handler fails,
+	// otherwise the checkpoint is saved only when all the records are delivered.
+	Ordered bool
+
+	// ID returns the id of the record.
+	ID func(T) int64
+
+	// UpdatedAt returns the updated time of the record.
+	UpdatedAt func(T) time.Time
+
+	// CreatedAt returns the created time of the record, nil means the changes are never ChangeCreated.
+	CreatedAt func(T) time.Time
+
+	// Deleted reports whether the record is deleted, nil means the changes are never ChangeDeleted.
+	Deleted func(T) bool
+}
+
+// Syncer polls the Sources and emits the Changes.
+// A Syncer must not be run concurrently with the same Store and Name.
+type Syncer[T any] struct {
+	// Name the name of the syncer, the checkpoint key of a Source is Name + "/" + Source.Name.
+	Name string
+
+	// Sources the sources to sync
+	Sources []*Source[T]
+
+	// Store the checkpoint store, default is a MemoryStore
+	Store Store
+
+	// Start the start time of the first sync (no checkpoint), zero means all the records.
+	Start time.Time
+
+	// Overlap the records updated in [checkpoint - Overlap, checkpoint) are listed again,
+	// to catch the records which are indexed late by the list api. The delivered ones are not emitted again.
+	Overlap time.Duration
+
+	// Interval the polling interval of Run, default is 1 minute.
+	Interval time.Duration
+}
+
+// NewSyncer returns a Syncer of the sources with the Store.
+func NewSyncer[T any](name string, store Store, sources ...*Source[T]) *Syncer[T] {
+	return &Syncer[T]{
+		Name:    name,
+		Store:   store,
+		Sources: sources,
+	}
+}
+
+// Run syncs repeatedly with the Interval, until the ctx is done or an error occurred.
+func (s *Syncer[T]) Run(ctx context.Context, h Handler[T]) error {
+	interval := s.Interval
+	if interval <= 0 {
+		interval = time.Minute
+	}
+
+	for {
+		if err := s.Sync(ctx, h); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+// Sync polls all the Sources once, and emits the Changes since the last checkpoints.
+func (s *Syncer[T]) Sync(ctx context.Context, h Handler[T]) error {
+	if s.Store == nil {
+		s.Store = NewMemoryStore()
+	}
+
+	for _, src := range s.Sources {
+		if err := s.sync(ctx, src, h); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Syncer[T]) key(src *Source[T]) string {
+	return s.Name + "/" + src.Name
+}
+
+func (s *Syncer[T]) sync(ctx context.Context, src *Source[T], h Handler[T]) (err error) {
+	key := s.key(src)
+
+	cp, err := s.Store.Load(ctx, key)
+	if err != nil {
+		return err
+	}
+	if cp == nil {
+		cp = &Checkpoint{Since: s.Start, Base: s.Start}
+	}
+
+	from := cp.Since.Add(-s.Overlap)
+	if cp.Since.IsZero() {
+		from = cp.Since
+	}
+
+	delivered := map[int64]time.Time{}
+	defer func() {
+		if err == nil || src.Ordered {
+			if len(delivered) > 0 {
+				if serr := s.Store.Save(ctx, key, cp.advance(delivered, s.Overlap, err == nil)); err == nil {
+					err = serr
+				}
+			}
+		}
+	}()
+
+	for item, err := range src.List(ctx, from) {
+		if err != nil {
+			return err
+		}
+
+		id, ut := src.ID(item), src.UpdatedAt(item)
+		if ut.Before(from) {
+			continue
+		}
+		if t, ok := cp.Seen[id]; ok && !ut.After(t) {
+			continue
+		}
+		if t, ok := delivered[id]; ok && !ut.After(t) {
+			continue
+		}
+
+		c := &Change[T]{Type: ChangeUpdated, Source: src.Name, ID: id, UpdatedAt: ut, Item: item}
+		if src.Deleted != nil && src.Deleted(item) {
+			c.Type = ChangeDeleted
+		} else if _, ok := cp.Seen[id]; !ok && src.CreatedAt != nil && !src.CreatedAt(item).Before(cp.Base) {
+			c.Type = ChangeCreated
+		}
+
+		if err := h(ctx, c); err != nil {
+			return err
+		}
+		delivered[id] = ut
+	}
+
+	return nil
+}
@@ -0,0 +1,204 @@
+package freshsync
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"slices"
+	"testing"
+	"time"
+)
+
+type testRecord struct {
+	ID        int64
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	Deleted   bool
+}
+
+type testSource struct {
+	records []*testRecord
+	lag     map[int64]bool // the records not indexed yet
+}
+
+func (ts *testSource) put(id int64, created, updated time.Time) {
+	for _, r := range ts.records {
+		if r.ID == id {
+			r.UpdatedAt = updated
+			return
+		}
+	}
+	ts.records = append(ts.records, &testRecord{ID: id, CreatedAt: created, UpdatedAt: updated})
+}
+
+func (ts *testSource) source(ordered bool) *Source[*testRecord] {
+	return &Source[*testRecord]{
+		Name:    "test",
+		Ordered: ordered,
+		List: func(ctx context.Context, since time.Time) iter.Seq2[*testRecord, error] {
+			return func(yield func(*testRecord, error) bool) {
+				rs := slices.Clone(ts.records)
+				if ordered {
+					slices.SortStableFunc(rs, func(a, b *testRecord) int { return a.UpdatedAt.Compare(b.UpdatedAt) })
+				}
+				for _, r := range rs {
+					if r.UpdatedAt.Before(since) || ts.lag[r.ID] {
+						continue
+					}
+					c := *r
+					if !yield(&c, nil) {
+						return
+					}
+				}
+			}
+		},
+		ID:        func(r *testRecord) int64 { return r.ID },
+		UpdatedAt: func(r *testRecord) time.Time { return r.UpdatedAt },
+		CreatedAt: func(r *testRecord) time.Time { return r.CreatedAt },
+		Deleted:   func(r *testRecord) bool { return r.Deleted },
+	}
+}
+
+type testChanges []string
+
+func (tcs *testChanges) handler(fail int64) Handler[*testRecord] {
+	return func(ctx context.Context, c *Change[*testRecord]) error {
+		if c.ID == fail {
+			return errors.New("failed")
+		}
+		*tcs = append(*tcs, string(c.Type[0])+string(rune('0'+c.ID)))
+		return nil
+	}
+}
+
+func (tcs *testChanges) check(t *testing.T, step string, want ...string) {
+	t.Helper()
+
+	if !slices.Equal(*tcs, want) {
+		t.Errorf("%s: changes = %v, want %v", step, *tcs, want)
+	}
+	*tcs = nil
+}
+
+func TestSyncBoundary(t *testing.T) {
+	ctx := context.Background()
+	t0 := time.Date(2024, 5, 6, 10, 0, 0, 0, time.UTC)
+	t1, t2 := t0.Add(time.Second), t0.Add(2*time.Second)
+
+	for _, ordered := range []bool{true, false} {
+		ts := &testSource{}
+		ts.put(1, t0, t0)
+		ts.put(2, t0, t1)
+
+		s := NewSyncer("sync", nil, ts.source(ordered))
+		s.Start = t0
+
+		tcs := &testChanges{}
+		if err := s.Sync(ctx, tcs.handler(0)); err != nil {
+			t.Fatalf("ERROR: %v", err)
+		}
+		tcs.check(t, "first", "c1", "c2")
+
+		// same timestamp as the checkpoint
+		ts.put(3, t1, t1)
+		if err := s.Sync(ctx, tcs.handler(0)); err != nil {
+			t.Fatalf("ERROR: %v", err)
+		}
+		tcs.check(t, "same timestamp", "c3")
+
+		// no changes
+		if err := s.Sync(ctx, tcs.handler(0)); err != nil {
+			t.Fatalf("ERROR: %v", err)
+		}
+		tcs.check(t, "no changes")
+
+		// updated and deleted
+		ts.put(1, t0, t2)
+		ts.put(2, t0, t2)
+		ts.records[1].Deleted = true
+		if err := s.Sync(ctx, tcs.handler(0)); err != nil {
+			t.Fatalf("ERROR: %v", err)
+		}
+		tcs.check(t, "updated", "u1", "d2")
+
+		cp, _ := s.Store.Load(ctx, "sync/test")
+		if !cp.Since.Equal(t2) || len(cp.Seen) != 2 {
+			t.Errorf("checkpoint = %v", cp)
+		}
+	}
+}
+
+func TestSyncOrderedFailure(t *testing.T) {
+	ctx := context.Background()
+	t0 := time.Date(2024, 5, 6, 10, 0, 0, 0, time.UTC)
+
+	for _, ordered := range []bool{true, false} {
+		ts := &testSource{}
+		for i := int64(1); i <= 4; i++ {
+			ts.put(i, t0, t0.Add(time.Duration(i/2)*time.Second))
+		}
+
+		s := NewSyncer("sync", NewMemoryStore(), ts.source(ordered))
+
+		tcs := &testChanges{}
+		if err := s.Sync(ctx, tcs.handler(3)); err == nil {
+			t.Fatal("Sync() should fail")
+		}
+		tcs.check(t, "failed", "c1", "c2")
+
+		if err := s.Sync(ctx, tcs.handler(0)); err != nil {
+			t.Fatalf("ERROR: %v", err)
+		}
+		if ordered {
+			tcs.check(t, "ordered resumed", "c3", "c4")
+		} else {
+			tcs.check(t, "unordered restarted", "c1", "c2", "c3", "c4")
+		}
+	}
+}
+
+func TestSyncOverlap(t *testing.T) {
+	ctx := context.Background()
+	t0 := time.Date(2024, 5, 6, 10, 0, 0, 0, time.UTC)
+
+	ts := &testSource{lag: map[int64]bool{1: true}}
+	ts.put(1, t0, t0)
+	ts.put(2, t0.Add(time.Second), t0.Add(time.Second))
+
+	s := NewSyncer("sync", nil, ts.source(true))
+	s.Start = t0
+	s.Overlap = time.Minute
+
+	tcs := &testChanges{}
+	if err := s.Sync(ctx, tcs.handler(0)); err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	tcs.check(t, "first", "c2")
+
+	// record 1 is indexed late
+	delete(ts.lag, 1)
+	if err := s.Sync(ctx, tcs.handler(0)); err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	tcs.check(t, "late", "u1")
+}
+
+func TestFileStore(t *testing.T) {
+	ctx := context.Background()
+	fs := NewFileStore(t.TempDir())
+
+	cp, err := fs.Load(ctx, "a/b")
+	if err != nil || cp != nil {
+		t.Fatalf("Load() = %v, %v", cp, err)
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	if err = fs.Save(ctx, "a/b", &Checkpoint{Since: now, Seen: map[int64]time.Time{1: now}}); err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+
+	cp, err = fs.Load(ctx, "a/b")
+	if err != nil || !cp.Since.Equal(now) || !cp.Seen[1].Equal(now) {
+		t.Fatalf("Load() = %v, %v", cp, err)
+	}
+}
@@ -0,0 +1,158 @@
+package freshsync
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"maps"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Checkpoint the sync checkpoint of a Source
+type Checkpoint struct {
+	// Since the high-water mark, the maximum updated time of the delivered records.
+	Since time.Time `json:"since"`
+
+	// Base the high-water mark of the last completed sync, the records created since it are ChangeCreated.
+	Base time.Time `json:"base"`
+
+	// Seen the updated time of the delivered records in [Since - Overlap, Since].
+	Seen map[int64]time.Time `json:"seen,omitempty"`
+}
+
+// advance returns a new checkpoint advanced by the delivered records,
+// the Base is advanced only if all the records have been delivered (complete).
+func (cp *Checkpoint) advance(delivered map[int64]time.Time, overlap time.Duration, complete bool) *Checkpoint {
+	seen := maps.Clone(cp.Seen)
+	if seen == nil {
+		seen = map[int64]time.Time{}
+	}
+	maps.Copy(seen, delivered)
+
+	ncp := &Checkpoint{Since: cp.Since, Base: cp.Base, Seen: map[int64]time.Time{}}
+	for _, t := range delivered {
+		if t.After(ncp.Since) {
+			ncp.Since = t
+		}
+	}
+	if complete {
+		ncp.Base = ncp.Since
+	}
+
+	low := ncp.Since.Add(-overlap)
+	for id, t := range seen {
+		if !t.Before(low) {
+			ncp.Seen[id] = t
+		}
+	}
+	return ncp
+}
+
+// Store the checkpoint store
+type Store interface {
+	// Load loads the checkpoint of the key, returns nil if not found.
+	Load(ctx context.Context, key string) (*Checkpoint, error)
+
+	// Save saves the checkpoint of the key.
+	Save(ctx context.Context, key string, cp *Checkpoint) error
+}
+
+// MemoryStore an in-memory Store
+type MemoryStore struct {
+	mu  sync.Mutex
+	cps map[string]*Checkpoint
+}
+
+// NewMemoryStore returns a MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{cps: map[string]*Checkpoint{}}
+}
+
+// Load implements the Store interface.
+func (ms *MemoryStore) Load(ctx context.Context, key string) (*Checkpoint, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	if cp, ok := ms.cps[key]; ok {
+		return &Checkpoint{Since: cp.Since, Base: cp.Base, Seen: maps.Clone(cp.Seen)}, nil
+	}
+	return nil, nil
+}
+
+// Save implements the Store interface.
+func (ms *MemoryStore) Save(ctx context.Context, key string, cp *Checkpoint) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	if ms.cps == nil {
+		ms.cps = map[string]*Checkpoint{}
+	}
+	ms.cps[key] = &Checkpoint{Since: cp.Since, Base: cp.Base, Seen: maps.Clone(cp.Seen)}
+	return nil
+}
+
+// FileStore a Store which saves the checkpoints as the json files in the directory.
+// The file is written to a temporary file and renamed, so a checkpoint file is never partially written.
+type FileStore struct {
+	Dir string
+}
+
+// NewFileStore returns a FileStore of the directory.
+func NewFileStore(dir string) *FileStore {
+	return &FileStore{Dir: dir}
+}
+
+func (fs *FileStore) path(key string) string {
+	return filepath.Join(fs.Dir, url.PathEscape(key)+".json")
+}
+
+// Load implements the Store interface.
+func (fs *FileStore) Load(ctx context.Context, key string) (*Checkpoint, error) {
+	data, err := os.ReadFile(fs.path(key))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	cp := &Checkpoint{}
+	if err := json.Unmarshal(data, cp); err != nil {
+		return nil, err
+	}
+	return cp, nil
+}
+
+// Save implements the Store interface.
+func (fs *FileStore) Save(ctx context.Context, key string, cp *Checkpoint) error {
+	data, err := json.Marshal(cp)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(fs.Dir, 0o770); err != nil {
+		return err
+	}
+
+	f, err := os.CreateTemp(fs.Dir, ".checkpoint-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(f.Name(), 0o660); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), fs.path(key))
+}
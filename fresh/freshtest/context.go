@@ -0,0 +1,277 @@
+package freshtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/askasoft/gofresh/fresh"
+)
+
+// Context the context of an api request
+type Context struct {
+	Server  *Server
+	Writer  http.ResponseWriter
+	Request *http.Request
+	Query   url.Values
+	IDs     []int64 // the ":id" segments of the path
+}
+
+// Store returns the Store of the server.
+func (c *Context) Store() *Store {
+	return c.Server.Store
+}
+
+// ID returns the i-th ":id" segment of the path.
+func (c *Context) ID(i int) int64 {
+	if i < len(c.IDs) {
+		return c.IDs[i]
+	}
+	return 0
+}
+
+// JSON writes the value v as the json response body.
+func (c *Context) JSON(status int, v any) {
+	writeJSON(c.Writer, status, v)
+}
+
+// NoContent writes the 204 No Content response.
+func (c *Context) NoContent() {
+	c.Writer.WriteHeader(http.StatusNoContent)
+}
+
+// Error writes the ResultError as the response body.
+func (c *Context) Error(status int, re *fresh.ResultError) {
+	c.JSON(status, re)
+}
+
+// NotFound writes the 404 Not Found response without body.
+func (c *Context) NotFound() {
+	c.Writer.WriteHeader(http.StatusNotFound)
+}
+
+// Invalid writes the 400 Bad Request response with the field errors.
+func (c *Context) Invalid(fes ...fresh.FieldError) {
+	c.Error(http.StatusBadRequest, &fresh.ResultError{Description: "Validation failed", Errors: fes})
+}
+
+// Require checks that at least one of the fields of the record is not empty,
+// writes the 400 Bad Request response and returns false if all of them are empty.
+func (c *Context) Require(r Record, fields ...string) bool {
+	for _, f := range fields {
+		if !r.IsEmpty(f) {
+			return true
+		}
+	}
+
+	msg := "It should be a/an String"
+	if len(fields) > 1 {
+		msg = "Please fill at least 1 of " + strings.Join(fields, ", ") + " attributes"
+	}
+	c.Invalid(fresh.FieldError{Field: fields[0], Message: msg, Code: "missing_field"})
+	return false
+}
+
+// Bind decodes the json or multipart request body to a record.
+// The files of a multipart request are added to the Store, and set to the record as the attachments.
+// Writes the 400 Bad Request response and returns false if the body is invalid.
+func (c *Context) Bind() (Record, bool) {
+	ct, _, _ := mime.ParseMediaType(c.Request.Header.Get("Content-Type"))
+	if ct == "multipart/form-data" {
+		return c.bindMultipart()
+	}
+
+	r := Record{}
+
+	body, err := io.ReadAll(c.Request.Body)
+	if err == nil && len(bytes.TrimSpace(body)) > 0 {
+		dec := json.NewDecoder(bytes.NewReader(body))
+		dec.UseNumber()
+		err = dec.Decode(&r)
+	}
+	if err != nil {
+		c.Error(http.StatusBadRequest, &fresh.ResultError{Code: "invalid_json", Message: "Request body has invalid json format"})
+		return nil, false
+	}
+	return r, true
+}
+
+func (c *Context) bindMultipart() (Record, bool) {
+	if err := c.Request.ParseMultipartForm(32 << 20); err != nil {
+		c.Error(http.StatusBadRequest, &fresh.ResultError{Code: "invalid_multipart", Message: err.Error()})
+		return nil, false
+	}
+
+	r := Record{}
+
+	form := c.Request.MultipartForm
+	for k, vs := range form.Value {
+		for _, v := range vs {
+			setFormValue(r, k, formValue(v))
+		}
+	}
+
+	for k, fhs := range form.File {
+		for _, fh := range fhs {
+			f, err := fh.Open()
+			if err != nil {
+				c.Error(http.StatusBadRequest, &fresh.ResultError{Code: "invalid_multipart", Message: err.Error()})
+				return nil, false
+			}
+
+			data, err := io.ReadAll(f)
+			f.Close()
+			if err != nil {
+				c.Error(http.StatusBadRequest, &fresh.ResultError{Code: "invalid_multipart", Message: err.Error()})
+				return nil, false
+			}
+
+			ct := fh.Header.Get("Content-Type")
+			if ct == "" {
+				ct = http.DetectContentType(data)
+			}
+
+			file := c.Store().AddFile(fh.Filename, ct, data)
+			setFormValue(r, k, c.Attachment(file))
+		}
+	}
+	return r, true
+}
+
+// formValue converts the form value to a json value.
+func formValue(v string) any {
+	if n, err := strconv.ParseInt(v, 10, 64); err == nil {
+		return n
+	}
+	if b, err := strconv.ParseBool(v); err == nil {
+		return b
+	}
+	return v
+}
+
+// setFormValue sets the form value to the record, "a[]" is set as an array, "a[b]" is set as an object.
+func setFormValue(r Record, key string, v any) {
+	if k, ok := strings.CutSuffix(key, "[]"); ok {
+		a, _ := r[k].([]any)
+		if v == "" {
+			// an empty "name[]" value clears the array
+			if a == nil {
+				a = []any{}
+			}
+			r[k] = a
+			return
+		}
+		r[k] = append(a, v)
+		return
+	}
+
+	if i := strings.IndexByte(key, '['); i > 0 && strings.HasSuffix(key, "]") {
+		k, sk := key[:i], key[i+1:len(key)-1]
+		m, ok := r[k].(map[string]any)
+		if !ok {
+			m = map[string]any{}
+			r[k] = m
+		}
+		m[sk] = v
+		return
+	}
+
+	r[key] = v
+}
+
+// Attachment returns the attachment record of the file.
+func (c *Context) Attachment(f *File) Record {
+	now := c.Store().now()
+	return Record{
+		"id":             f.ID,
+		"name":           f.Name,
+		"content_type":   f.ContentType,
+		"size":           len(f.Data),
+		"attachment_url": c.Server.URL + "/files/" + strconv.FormatInt(f.ID, 10),
+		"created_at":     now,
+		"updated_at":     now,
+	}
+}
+
+// ServeFile writes the content of the file (Range requests are supported), writes 404 if not found.
+func (c *Context) ServeFile(fid int64) {
+	f := c.Store().GetFile(fid)
+	if f == nil {
+		c.NotFound()
+		return
+	}
+
+	c.Writer.Header().Set("Content-Type", f.ContentType)
+	http.ServeContent(c.Writer, c.Request, f.Name, time.Time{}, bytes.NewReader(f.Data))
+}
+
+// FilterQuery parses the "query" parameter, a nil Matcher is returned if the parameter is absent.
+// Writes the 400 Bad Request response and returns false if the query is invalid.
+func (c *Context) FilterQuery() (Matcher, bool) {
+	q := c.Query.Get("query")
+	if q == "" {
+		return nil, true
+	}
+
+	m, err := ParseQuery(q)
+	if err != nil {
+		c.Invalid(fresh.FieldError{Field: "query", Message: err.Error(), Code: "invalid_value"})
+		return nil, false
+	}
+	return m, true
+}
+
+// PageNumber returns the "page" parameter, default is 1.
+func (c *Context) PageNumber() int {
+	if n, err := strconv.Atoi(c.Query.Get("page")); err == nil && n > 0 {
+		return n
+	}
+	return 1
+}
+
+// Paginate returns the records of the requested page, and sets the Link header if there is a next page.
+// Writes the 400 Bad Request response and returns false if the page parameters are invalid.
+func (c *Context) Paginate(rs []Record) ([]Record, bool) {
+	s := c.Server
+
+	perPage := s.PerPage
+	if pp := c.Query.Get("per_page"); pp != "" {
+		n, err := strconv.Atoi(pp)
+		if err != nil || n < 1 || n > s.MaxPerPage {
+			c.Invalid(fresh.FieldError{
+				Field:   "per_page",
+				Message: "It should be a Positive Integer less than or equal to " + strconv.Itoa(s.MaxPerPage),
+				Code:    "invalid_value",
+			})
+			return nil, false
+		}
+		perPage = n
+	}
+
+	if pg := c.Query.Get("page"); pg != "" {
+		if n, err := strconv.Atoi(pg); err != nil || n < 1 {
+			c.Invalid(fresh.FieldError{Field: "page", Message: "It should be a Positive Integer", Code: "invalid_value"})
+			return nil, false
+		}
+	}
+
+	page := c.PageNumber()
+
+	start := min((page-1)*perPage, len(rs))
+	end := min(start+perPage, len(rs))
+	if end < len(rs) {
+		q := c.Request.URL.Query()
+		q.Set("page", strconv.Itoa(page+1))
+		q.Set("per_page", strconv.Itoa(perPage))
+
+		next := "https://" + s.Domain + c.Request.URL.Path + "?" + q.Encode()
+		c.Writer.Header().Set(fresh.HeaderLink, "<"+next+">; rel=\"next\"")
+	}
+	return rs[start:end], true
+}
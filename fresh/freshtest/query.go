@@ -0,0 +1,331 @@
+package freshtest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidQuery the error of an invalid filter query
+var ErrInvalidQuery = errors.New("freshtest: invalid query")
+
+// Matcher reports whether the record matches a filter query.
+type Matcher func(r Record) bool
+
+// ParseQuery parses the filter query of the Freshdesk/Freshservice filter apis, e.g. "priority:3 AND (status:2 OR status:3)".
+// The query may be enclosed in double quotes. Supported:
+//
+//   - field:value, field:>value (greater than or equal), field:<value (less than or equal)
+//   - ~[field1|field2]:'prefix' (starts with)
+//   - 'string', number, true/false, null and 'yyyy-mm-dd' values
+//   - AND, OR and parentheses, AND binds tighter than OR
+//
+// The field is looked up in the record first, then in the "custom_fields" of the record.
+func ParseQuery(query string) (Matcher, error) {
+	query = strings.TrimSpace(query)
+	if s, ok := strings.CutPrefix(query, `"`); ok {
+		if query, ok = strings.CutSuffix(s, `"`); !ok {
+			return nil, fmt.Errorf("%w: unterminated double quote", ErrInvalidQuery)
+		}
+	}
+
+	toks, err := lexQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	if len(toks) == 0 {
+		return nil, fmt.Errorf("%w: empty query", ErrInvalidQuery)
+	}
+
+	qp := &queryParser{toks: toks}
+	m, err := qp.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if qp.pos < len(qp.toks) {
+		return nil, fmt.Errorf("%w: unexpected %q", ErrInvalidQuery, qp.toks[qp.pos].text)
+	}
+	return m, nil
+}
+
+type tokenKind int
+
+const (
+	tokWord tokenKind = iota
+	tokString
+	tokColon
+	tokLParen
+	tokRParen
+)
+
+type token struct {
+	kind tokenKind
+	text string
+}
+
+func lexQuery(q string) ([]token, error) {
+	var toks []token
+
+	for i := 0; i < len(q); {
+		switch ch := q[i]; ch {
+		case ' ', '\t', '\r', '\n':
+			i++
+		case '(':
+			toks = append(toks, token{tokLParen, "("})
+			i++
+		case ')':
+			toks = append(toks, token{tokRParen, ")"})
+			i++
+		case ':':
+			op := ":"
+			if i+1 < len(q) && (q[i+1] == '>' || q[i+1] == '<') {
+				op += q[i+1 : i+2]
+			}
+			toks = append(toks, token{tokColon, op})
+			i += len(op)
+		case '\'':
+			sb := &strings.Builder{}
+			j := i + 1
+			for ; j < len(q) && q[j] != '\''; j++ {
+				if q[j] == '\\' && j+1 < len(q) {
+					j++
+				}
+				sb.WriteByte(q[j])
+			}
+			if j >= len(q) {
+				return nil, fmt.Errorf("%w: unterminated string", ErrInvalidQuery)
+			}
+			toks = append(toks, token{tokString, sb.String()})
+			i = j + 1
+		default:
+			j := i
+			for j < len(q) && !strings.ContainsRune(" \t\r\n():'", rune(q[j])) {
+				j++
+			}
+			toks = append(toks, token{tokWord, q[i:j]})
+			i = j
+		}
+	}
+	return toks, nil
+}
+
+type queryParser struct {
+	toks []token
+	pos  int
+}
+
+func (qp *queryParser) next() (token, bool) {
+	if qp.pos < len(qp.toks) {
+		t := qp.toks[qp.pos]
+		qp.pos++
+		return t, true
+	}
+	return token{}, false
+}
+
+func (qp *queryParser) keyword(kw string) bool {
+	if qp.pos < len(qp.toks) && qp.toks[qp.pos].kind == tokWord && qp.toks[qp.pos].text == kw {
+		qp.pos++
+		return true
+	}
+	return false
+}
+
+func (qp *queryParser) parseOr() (Matcher, error) {
+	m, err := qp.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for qp.keyword("OR") {
+		l := m
+		r, err := qp.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		m = func(rec Record) bool { return l(rec) || r(rec) }
+	}
+	return m, nil
+}
+
+func (qp *queryParser) parseAnd() (Matcher, error) {
+	m, err := qp.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+
+	for qp.keyword("AND") {
+		l := m
+		r, err := qp.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		m = func(rec Record) bool { return l(rec) && r(rec) }
+	}
+	return m, nil
+}
+
+func (qp *queryParser) parseTerm() (Matcher, error) {
+	t, ok := qp.next()
+	if !ok {
+		return nil, fmt.Errorf("%w: unexpected end of query", ErrInvalidQuery)
+	}
+
+	if t.kind == tokLParen {
+		m, err := qp.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if t, ok = qp.next(); !ok || t.kind != tokRParen {
+			return nil, fmt.Errorf("%w: missing ')'", ErrInvalidQuery)
+		}
+		return m, nil
+	}
+
+	if t.kind != tokWord {
+		return nil, fmt.Errorf("%w: unexpected %q", ErrInvalidQuery, t.text)
+	}
+	field := t.text
+
+	op, ok := qp.next()
+	if !ok || op.kind != tokColon {
+		return nil, fmt.Errorf("%w: missing ':' after %q", ErrInvalidQuery, field)
+	}
+
+	val, ok := qp.next()
+	if !ok || (val.kind != tokWord && val.kind != tokString) {
+		return nil, fmt.Errorf("%w: missing value of %q", ErrInvalidQuery, field)
+	}
+
+	if fs, ok := strings.CutPrefix(field, "~["); ok {
+		fs, ok = strings.CutSuffix(fs, "]")
+		if !ok || op.text != ":" || val.kind != tokString {
+			return nil, fmt.Errorf("%w: invalid starts with condition %q", ErrInvalidQuery, field)
+		}
+		return startsWith(strings.Split(fs, "|"), val.text), nil
+	}
+
+	if val.kind == tokWord && val.text != "null" && val.text != "true" && val.text != "false" {
+		if _, err := strconv.ParseFloat(val.text, 64); err != nil {
+			return nil, fmt.Errorf("%w: invalid value %q of %q", ErrInvalidQuery, val.text, field)
+		}
+	}
+
+	return compare(field, op.text, val), nil
+}
+
+func startsWith(fields []string, prefix string) Matcher {
+	prefix = strings.ToLower(prefix)
+	return func(r Record) bool {
+		for _, f := range fields {
+			if s, ok := lookup(r, f).(string); ok && strings.HasPrefix(strings.ToLower(s), prefix) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func compare(field, op string, val token) Matcher {
+	return func(r Record) bool {
+		v := lookup(r, field)
+
+		if val.kind == tokWord && val.text == "null" {
+			return op == ":" && Record{"v": v}.IsEmpty("v")
+		}
+
+		if vs, ok := v.([]any); ok {
+			for _, e := range vs {
+				if compareValue(e, op, val) {
+					return true
+				}
+			}
+			return false
+		}
+		return compareValue(v, op, val)
+	}
+}
+
+func compareValue(v any, op string, val token) bool {
+	if v == nil {
+		return false
+	}
+
+	if val.kind == tokString {
+		s := toString(v)
+		if d, err := time.Parse(time.DateOnly, val.text); err == nil {
+			if t, err := time.Parse(time.RFC3339, s); err == nil {
+				return compareInt(t.UTC().Truncate(24*time.Hour).Compare(d), op)
+			}
+		}
+		return op == ":" && strings.EqualFold(s, val.text)
+	}
+
+	if val.text == "true" || val.text == "false" {
+		b, ok := v.(bool)
+		return ok && op == ":" && strconv.FormatBool(b) == val.text
+	}
+
+	a, err1 := strconv.ParseFloat(toString(v), 64)
+	b, err2 := strconv.ParseFloat(val.text, 64)
+	if err1 != nil || err2 != nil {
+		return false
+	}
+
+	n := 0
+	if a < b {
+		n = -1
+	} else if a > b {
+		n = 1
+	}
+	return compareInt(n, op)
+}
+
+func compareInt(n int, op string) bool {
+	switch op {
+	case ":>":
+		return n >= 0
+	case ":<":
+		return n <= 0
+	default:
+		return n == 0
+	}
+}
+
+func lookup(r Record, field string) any {
+	if v, ok := r[field]; ok {
+		return v
+	}
+
+	if cfs, ok := r["custom_fields"].(map[string]any); ok {
+		if v, ok := cfs[field]; ok {
+			return v
+		}
+		if v, ok := cfs["cf_"+field]; ok {
+			return v
+		}
+	}
+	return nil
+}
+
+func toString(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case int:
+		return strconv.Itoa(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,88 @@
+package freshtest
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestParseQuery(t *testing.T) {
+	r := Record{
+		"status":     json.Number("2"),
+		"priority":   int64(3),
+		"first_name": "John",
+		"last_name":  "O'Neil",
+		"tags":       []any{"foo", "bar"},
+		"spam":       false,
+		"group_id":   nil,
+		"created_at": "2024-05-06T10:20:30Z",
+		"custom_fields": map[string]any{
+			"cf_team": "Sales",
+			"level":   json.Number("5"),
+		},
+	}
+
+	cs := []struct {
+		q string
+		w bool
+	}{
+		{`status:2`, true},
+		{`"status:2"`, true},
+		{`status: 3`, false},
+		{`priority:>3 AND priority:<3`, true},
+		{`priority:>4`, false},
+		{`status:2 AND priority:1`, false},
+		{`status:1 OR priority:3`, true},
+		{`status:1 AND priority:1 OR priority:3`, true},
+		{`status:1 AND (priority:1 OR priority:3)`, false},
+		{`first_name:'john'`, true},
+		{`last_name:'O\'Neil'`, true},
+		{`~[first_name|last_name]:'o'`, true},
+		{`~[first_name]:'o'`, false},
+		{`tags:'bar'`, true},
+		{`tags:'baz'`, false},
+		{`spam:false`, true},
+		{`spam:true`, false},
+		{`group_id:null`, true},
+		{`status:null`, false},
+		{`created_at:'2024-05-06'`, true},
+		{`created_at:>'2024-05-07'`, false},
+		{`created_at:<'2024-05-07' AND created_at:>'2024-05-01'`, true},
+		{`team:'sales'`, true},
+		{`cf_team:'sales'`, true},
+		{`level:>5`, true},
+		{`unknown:1`, false},
+	}
+
+	for i, c := range cs {
+		m, err := ParseQuery(c.q)
+		if err != nil {
+			t.Errorf("#%d ParseQuery(%q) = %v", i, c.q, err)
+			continue
+		}
+		if a := m(r); a != c.w {
+			t.Errorf("#%d ParseQuery(%q)(r) = %v, want %v", i, c.q, a, c.w)
+		}
+	}
+}
+
+func TestParseQueryErrors(t *testing.T) {
+	cs := []string{
+		``,
+		`"status:2`,
+		`status`,
+		`status:`,
+		`status:abc`,
+		`status:2 AND`,
+		`(status:2`,
+		`status:2)`,
+		`name:'abc`,
+		`~[name:'a'`,
+	}
+
+	for i, c := range cs {
+		if _, err := ParseQuery(c); !errors.Is(err, ErrInvalidQuery) {
+			t.Errorf("#%d ParseQuery(%q) = %v", i, c, err)
+		}
+	}
+}
@@ -0,0 +1,301 @@
+package freshtest
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/askasoft/gofresh/fresh"
+)
+
+// Parent the parent of a nested resource, e.g. the ticket of the conversations.
+type Parent struct {
+	// Collection the collection of the parent records
+	Collection string
+
+	// Key the field of the child record which refers to the parent id, e.g. "ticket_id"
+	Key string
+}
+
+// Resource the generic CRUD handlers of a collection.
+type Resource struct {
+	// Collection the collection name in the Store
+	Collection string
+
+	// Parent the parent of the nested list/create routes, the parent id is the first ":id" of the path.
+	Parent *Parent
+
+	// Required the required fields on creation, each entry requires at least one of the fields.
+	Required [][]string
+
+	// Defaults the default values on creation
+	Defaults Record
+
+	// Single the envelope name of a single record (e.g. "ticket"), empty means no envelope.
+	Single string
+
+	// Plural the envelope name of the list (e.g. "tickets"), empty means no envelope.
+	Plural string
+
+	// Match filters the records to list, nil matches all.
+	Match func(c *Context, r Record) bool
+
+	// Query enables filtering the records to list by the "query" parameter, see ParseQuery.
+	Query bool
+
+	// Sort sorts the records to list, nil means sorting by id in ascending order.
+	Sort func(c *Context, rs []Record)
+
+	// Brief converts the record to the brief form in the list (e.g. without the description), nil means no conversion.
+	Brief func(r Record) Record
+
+	// Validate validates the record to create or the patch to update, returns false if the response was written.
+	Validate func(c *Context, r Record, create bool) bool
+
+	// Removed reports whether the record is removed (e.g. soft deleted), the removed records are not found by Get/Update/Delete.
+	Removed func(r Record) bool
+}
+
+// HandleResource registers the list/create handlers at the path, and the get/update/delete handlers at the path + "/:id".
+func (s *Server) HandleResource(path string, res *Resource) {
+	s.Handle(http.MethodGet, path, res.List)
+	s.Handle(http.MethodPost, path, res.Create)
+	s.Handle(http.MethodGet, path+"/:id", res.Get)
+	s.Handle(http.MethodPut, path+"/:id", res.Update)
+	s.Handle(http.MethodDelete, path+"/:id", res.Delete)
+}
+
+func (res *Resource) single(r Record) any {
+	if res.Single != "" {
+		return map[string]any{res.Single: r}
+	}
+	return r
+}
+
+func (res *Resource) plural(rs []Record) any {
+	if res.Plural != "" {
+		return map[string]any{res.Plural: rs}
+	}
+	return rs
+}
+
+// parent returns the parent id and true if the parent exists, writes 404 and returns false if not found.
+func (res *Resource) parent(c *Context) (int64, bool) {
+	if res.Parent == nil || len(c.IDs) == 0 {
+		return 0, true
+	}
+
+	pid := c.ID(0)
+	if c.Store().Get(res.Parent.Collection, pid) == nil {
+		c.NotFound()
+		return 0, false
+	}
+	return pid, true
+}
+
+// Find returns the record of the last ":id" of the path, writes 404 and returns nil if not found.
+func (res *Resource) Find(c *Context) Record {
+	r := c.Store().Get(res.Collection, c.ID(len(c.IDs)-1))
+	if r == nil || (res.Removed != nil && res.Removed(r)) {
+		c.NotFound()
+		return nil
+	}
+	return r
+}
+
+// List writes the requested page of the records.
+func (res *Resource) List(c *Context) {
+	pid, ok := res.parent(c)
+	if !ok {
+		return
+	}
+
+	var qm Matcher
+	if res.Query {
+		if qm, ok = c.FilterQuery(); !ok {
+			return
+		}
+	}
+
+	rs := c.Store().Find(res.Collection, func(r Record) bool {
+		if pid != 0 && r.Int64(res.Parent.Key) != pid {
+			return false
+		}
+		if qm != nil && !qm(r) {
+			return false
+		}
+		return res.Match == nil || res.Match(c, r)
+	})
+
+	if res.Sort != nil {
+		res.Sort(c, rs)
+	}
+
+	if rs, ok = c.Paginate(rs); ok {
+		if res.Brief != nil {
+			bs := make([]Record, len(rs))
+			for i, r := range rs {
+				bs[i] = res.Brief(r)
+			}
+			rs = bs
+		}
+		c.JSON(http.StatusOK, res.plural(rs))
+	}
+}
+
+// Create creates a record by the request body, writes 201 and the created record.
+func (res *Resource) Create(c *Context) {
+	pid, ok := res.parent(c)
+	if !ok {
+		return
+	}
+
+	r, ok := c.Bind()
+	if !ok {
+		return
+	}
+
+	if pid != 0 {
+		r[res.Parent.Key] = pid
+	}
+
+	for k, v := range res.Defaults {
+		if _, ok := r[k]; !ok {
+			r[k] = v
+		}
+	}
+
+	for _, fs := range res.Required {
+		if !c.Require(r, fs...) {
+			return
+		}
+	}
+
+	if res.Validate != nil && !res.Validate(c, r, true) {
+		return
+	}
+
+	r = c.Store().Insert(res.Collection, r)
+	c.JSON(http.StatusCreated, res.single(r))
+}
+
+// Get writes the record of the last ":id" of the path.
+func (res *Resource) Get(c *Context) {
+	if r := res.Find(c); r != nil {
+		c.JSON(http.StatusOK, res.single(r))
+	}
+}
+
+// Update updates the record of the last ":id" of the path by the request body, writes the updated record.
+func (res *Resource) Update(c *Context) {
+	r := res.Find(c)
+	if r == nil {
+		return
+	}
+
+	patch, ok := c.Bind()
+	if !ok {
+		return
+	}
+
+	if res.Validate != nil && !res.Validate(c, patch, false) {
+		return
+	}
+
+	r = c.Store().Update(res.Collection, r.ID(), patch)
+	c.JSON(http.StatusOK, res.single(r))
+}
+
+// Delete deletes the record of the last ":id" of the path, writes 204.
+func (res *Resource) Delete(c *Context) {
+	if r := res.Find(c); r != nil {
+		c.Store().Delete(res.Collection, r.ID())
+		c.NoContent()
+	}
+}
+
+// SoftDelete marks the record of the last ":id" of the path as "deleted", writes 204.
+// Writes 404 if the record is already deleted.
+func (res *Resource) SoftDelete(c *Context) {
+	if r := res.Find(c); r != nil {
+		if r.Bool("deleted") {
+			c.NotFound()
+			return
+		}
+		c.Store().Update(res.Collection, r.ID(), Record{"deleted": true})
+		c.NoContent()
+	}
+}
+
+// Restore restores the soft deleted record of the last ":id" of the path, writes 204.
+func (res *Resource) Restore(c *Context) {
+	if r := res.Find(c); r != nil {
+		c.Store().Update(res.Collection, r.ID(), Record{"deleted": false})
+		c.NoContent()
+	}
+}
+
+// Unique returns a Validate function which checks the value of the key is unique in the collection.
+// Writes the 409 Conflict response with the "duplicate_value" error if the value is used by another record.
+func Unique(coll, key string) func(c *Context, r Record, create bool) bool {
+	return func(c *Context, r Record, create bool) bool {
+		v := r.String(key)
+		if v == "" {
+			return true
+		}
+
+		id := int64(0)
+		if !create {
+			id = c.ID(len(c.IDs) - 1)
+		}
+
+		rs := c.Store().Find(coll, func(o Record) bool {
+			return o.ID() != id && strings.EqualFold(o.String(key), v)
+		})
+		if len(rs) > 0 {
+			c.Error(http.StatusConflict, &fresh.ResultError{
+				Description: "Validation failed",
+				Errors: []fresh.FieldError{{
+					Field:   key,
+					Message: "It should be a unique value",
+					Code:    "duplicate_value",
+				}},
+			})
+			return false
+		}
+		return true
+	}
+}
+
+// Validators returns a Validate function which calls the functions in order, stops at the first failure.
+func Validators(vfs ...func(c *Context, r Record, create bool) bool) func(c *Context, r Record, create bool) bool {
+	return func(c *Context, r Record, create bool) bool {
+		for _, vf := range vfs {
+			if !vf(c, r, create) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// AppendAttachments is a Validate function which appends the attachments of the patch to the existing attachments of the record.
+func AppendAttachments(coll string) func(c *Context, r Record, create bool) bool {
+	return func(c *Context, r Record, create bool) bool {
+		if create {
+			return true
+		}
+
+		as, ok := r["attachments"].([]any)
+		if !ok {
+			return true
+		}
+
+		if o := c.Store().Get(coll, c.ID(len(c.IDs)-1)); o != nil {
+			if oas, ok := o["attachments"].([]any); ok {
+				r["attachments"] = append(slices.Clone(oas), as...)
+			}
+		}
+		return true
+	}
+}
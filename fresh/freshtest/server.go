@@ -0,0 +1,222 @@
+// Package freshtest provides the core of the in-memory Freshdesk/Freshservice emulators.
+// It is used by the freshdesk/fdtest and freshservice/fstest packages, which register the api routes.
+package freshtest
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/askasoft/gofresh/fresh"
+)
+
+// HandlerFunc handles an api request.
+type HandlerFunc func(c *Context)
+
+type route struct {
+	method  string
+	pattern []string
+	handler HandlerFunc
+}
+
+// Server an in-memory emulator of the Freshdesk/Freshservice api, based on httptest.Server.
+// The requests are handled one by one, so the handlers need not to lock the Store.
+type Server struct {
+	*httptest.Server
+
+	// Domain the domain of the emulated helpdesk, it is used to build the Link headers.
+	Domain string
+
+	// APIKey the api key to authenticate the requests (basic auth with the api key as the username).
+	APIKey string
+
+	// Prefix the path prefix of the api, default is "/api/v2".
+	Prefix string
+
+	// PerPage the default per_page parameter, default is 30.
+	PerPage int
+
+	// MaxPerPage the maximum per_page parameter, default is 100.
+	MaxPerPage int
+
+	// Store the in-memory data
+	Store *Store
+
+	mu         sync.Mutex
+	routes     []*route
+	throttle   int
+	retryAfter time.Duration
+}
+
+// NewServer starts and returns a new emulator Server, the caller should call Close when finished.
+func NewServer(domain, apikey string) *Server {
+	s := &Server{
+		Domain:     domain,
+		APIKey:     apikey,
+		Prefix:     "/api/v2",
+		PerPage:    30,
+		MaxPerPage: 100,
+		Store:      NewStore(),
+	}
+	s.Server = httptest.NewServer(s)
+	return s
+}
+
+// Handle registers the handler for the method and the path pattern.
+// The pattern is relative to the Prefix, a ":id" segment matches an integer, e.g. "/tickets/:id/reply",
+// a "*" segment matches any segment, e.g. "/solutions/articles/:id/*".
+func (s *Server) Handle(method, pattern string, h HandlerFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.routes = append(s.routes, &route{method, splitPath(pattern), h})
+}
+
+// Throttle makes the next n requests fail with 429 Too Many Requests and the Retry-After header.
+func (s *Server) Throttle(n int, retryAfter time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.throttle, s.retryAfter = n, retryAfter
+}
+
+// Transport returns a http.RoundTripper which sends the requests of the emulated domain to the server,
+// it is an alternative to setting the BaseURL of the client to the URL of the server.
+func (s *Server) Transport() http.RoundTripper {
+	u, _ := url.Parse(s.URL)
+	return &rewriteTransport{domain: s.Domain, target: u, rt: s.Client().Transport}
+}
+
+// ServeHTTP implements the http.Handler interface.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c := &Context{Server: s, Writer: w, Request: r, Query: r.URL.Query()}
+
+	if s.throttle > 0 {
+		s.throttle--
+
+		secs := int((s.retryAfter + time.Second - 1) / time.Second)
+		w.Header().Set(fresh.HeaderRetryAfter, strconv.Itoa(max(secs, 1)))
+		c.Error(http.StatusTooManyRequests, &fresh.ResultError{
+			Code:    "too_many_requests",
+			Message: "You have exceeded the limit of requests per minute",
+		})
+		return
+	}
+
+	if id, ok := strings.CutPrefix(r.URL.Path, "/files/"); ok {
+		fid, _ := strconv.ParseInt(id, 10, 64)
+		c.ServeFile(fid)
+		return
+	}
+
+	if !s.authorized(r) {
+		c.Error(http.StatusUnauthorized, &fresh.ResultError{
+			Code:    "invalid_credentials",
+			Message: "You have to be logged in to perform this action.",
+		})
+		return
+	}
+
+	path, ok := strings.CutPrefix(r.URL.Path, s.Prefix)
+	if !ok {
+		c.NotFound()
+		return
+	}
+
+	segs := splitPath(path)
+
+	allowed := false
+	for _, rt := range s.routes {
+		ids, ok := matchPath(rt.pattern, segs)
+		if !ok {
+			continue
+		}
+
+		if rt.method != r.Method {
+			allowed = true
+			continue
+		}
+
+		c.IDs = ids
+		rt.handler(c)
+		return
+	}
+
+	if allowed {
+		c.Error(http.StatusMethodNotAllowed, &fresh.ResultError{
+			Code:    "method_not_allowed",
+			Message: r.Method + " method is not allowed",
+		})
+		return
+	}
+	c.NotFound()
+}
+
+func (s *Server) authorized(r *http.Request) bool {
+	u, _, ok := r.BasicAuth()
+	return ok && subtle.ConstantTimeCompare([]byte(u), []byte(s.APIKey)) == 1
+}
+
+func splitPath(p string) []string {
+	p = strings.Trim(p, "/")
+	if p == "" {
+		return nil
+	}
+	return strings.Split(p, "/")
+}
+
+func matchPath(pattern, segs []string) ([]int64, bool) {
+	if len(pattern) != len(segs) {
+		return nil, false
+	}
+
+	var ids []int64
+	for i, p := range pattern {
+		if p == ":id" {
+			id, err := strconv.ParseInt(segs[i], 10, 64)
+			if err != nil {
+				return nil, false
+			}
+			ids = append(ids, id)
+			continue
+		}
+		if p != "*" && p != segs[i] {
+			return nil, false
+		}
+	}
+	return ids, true
+}
+
+type rewriteTransport struct {
+	domain string
+	target *url.URL
+	rt     http.RoundTripper
+}
+
+func (t *rewriteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.Host == t.domain {
+		req = req.Clone(req.Context())
+		req.URL.Scheme = t.target.Scheme
+		req.URL.Host = t.target.Host
+		req.Host = t.target.Host
+	}
+	return t.rt.RoundTrip(req)
+}
+
+// writeJSON writes the value v as the json response body.
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	if v != nil {
+		_ = json.NewEncoder(w).Encode(v)
+	}
+}
@@ -0,0 +1,229 @@
+package freshtest
+
+import (
+	"cmp"
+	"encoding/json"
+	"maps"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/askasoft/gofresh/fresh"
+)
+
+// Record a json object stored in the Store
+type Record map[string]any
+
+// ID returns the "id" of the record.
+func (r Record) ID() int64 {
+	return r.Int64("id")
+}
+
+// Int64 returns the integer value of the key, returns 0 if the value is not a number.
+func (r Record) Int64(key string) int64 {
+	switch v := r[key].(type) {
+	case int64:
+		return v
+	case int:
+		return int64(v)
+	case float64:
+		return int64(v)
+	case json.Number:
+		n, _ := v.Int64()
+		return n
+	case string:
+		n, _ := strconv.ParseInt(v, 10, 64)
+		return n
+	default:
+		return 0
+	}
+}
+
+// String returns the string value of the key, returns "" if the value is not a string.
+func (r Record) String(key string) string {
+	s, _ := r[key].(string)
+	return s
+}
+
+// Bool returns the boolean value of the key.
+func (r Record) Bool(key string) bool {
+	switch v := r[key].(type) {
+	case bool:
+		return v
+	case string:
+		b, _ := strconv.ParseBool(v)
+		return b
+	default:
+		return false
+	}
+}
+
+// Time returns the time value of the key, returns zero time if the value is not a time string.
+func (r Record) Time(key string) time.Time {
+	t, _ := fresh.ParseTime(r.String(key))
+	return t.Time
+}
+
+// IsEmpty reports whether the value of the key is absent, null, zero, false or empty.
+func (r Record) IsEmpty(key string) bool {
+	switch v := r[key].(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case bool:
+		return !v
+	case []any:
+		return len(v) == 0
+	case map[string]any:
+		return len(v) == 0
+	default:
+		return r.Int64(key) == 0
+	}
+}
+
+// AsRecord converts the value to a Record, returns false if the value is not a json object.
+func AsRecord(v any) (Record, bool) {
+	switch r := v.(type) {
+	case Record:
+		return r, true
+	case map[string]any:
+		return Record(r), true
+	default:
+		return nil, false
+	}
+}
+
+// Clone returns a shallow copy of the record.
+func (r Record) Clone() Record {
+	return maps.Clone(r)
+}
+
+// File a file stored in the Store, served at "/files/{id}" without authentication.
+type File struct {
+	ID          int64
+	Name        string
+	ContentType string
+	Data        []byte
+}
+
+// Store an in-memory store of the records, grouped by collections.
+// The ids are assigned sequentially per collection, starting from 1.
+type Store struct {
+	// Now returns the current time, it can be replaced to control the created_at/updated_at of the records.
+	Now func() time.Time
+
+	seqs  map[string]int64
+	colls map[string]map[int64]Record
+	files map[int64]*File
+	fseq  int64
+}
+
+// NewStore returns an empty Store.
+func NewStore() *Store {
+	st := &Store{Now: time.Now}
+	st.Reset()
+	return st
+}
+
+// Reset removes all the records and files.
+func (st *Store) Reset() {
+	st.seqs = map[string]int64{}
+	st.colls = map[string]map[int64]Record{}
+	st.files = map[int64]*File{}
+	st.fseq = 0
+}
+
+func (st *Store) now() string {
+	return st.Now().UTC().Format(fresh.TimeFormat)
+}
+
+// Insert adds the record to the collection, the id, created_at and updated_at are set if absent.
+func (st *Store) Insert(coll string, r Record) Record {
+	id := r.ID()
+	if id == 0 {
+		st.seqs[coll]++
+		id = st.seqs[coll]
+	} else if id > st.seqs[coll] {
+		st.seqs[coll] = id
+	}
+
+	r = r.Clone()
+	r["id"] = id
+
+	now := st.now()
+	if r.IsEmpty("created_at") {
+		r["created_at"] = now
+	}
+	if r.IsEmpty("updated_at") {
+		r["updated_at"] = now
+	}
+
+	rs, ok := st.colls[coll]
+	if !ok {
+		rs = map[int64]Record{}
+		st.colls[coll] = rs
+	}
+	rs[id] = r
+	return r
+}
+
+// Get returns the record of the id in the collection, returns nil if not found.
+func (st *Store) Get(coll string, id int64) Record {
+	return st.colls[coll][id]
+}
+
+// Update merges the patch into the record of the id, and sets the updated_at.
+// Returns the updated record, or nil if not found.
+func (st *Store) Update(coll string, id int64, patch Record) Record {
+	r := st.Get(coll, id)
+	if r == nil {
+		return nil
+	}
+
+	for k, v := range patch {
+		if k != "id" {
+			r[k] = v
+		}
+	}
+	r["updated_at"] = st.now()
+	return r
+}
+
+// Delete removes the record of the id, returns false if not found.
+func (st *Store) Delete(coll string, id int64) bool {
+	if st.Get(coll, id) == nil {
+		return false
+	}
+	delete(st.colls[coll], id)
+	return true
+}
+
+// Find returns the records which match the function fn (nil matches all), sorted by id in ascending order.
+func (st *Store) Find(coll string, fn func(Record) bool) []Record {
+	rs := []Record{}
+	for _, r := range st.colls[coll] {
+		if fn == nil || fn(r) {
+			rs = append(rs, r)
+		}
+	}
+
+	slices.SortFunc(rs, func(a, b Record) int {
+		return cmp.Compare(a.ID(), b.ID())
+	})
+	return rs
+}
+
+// AddFile adds the file to the store, returns the added File.
+func (st *Store) AddFile(name, contentType string, data []byte) *File {
+	st.fseq++
+
+	f := &File{ID: st.fseq, Name: name, ContentType: contentType, Data: data}
+	st.files[f.ID] = f
+	return f
+}
+
+// GetFile returns the file of the id, returns nil if not found.
+func (st *Store) GetFile(id int64) *File {
+	return st.files[id]
+}
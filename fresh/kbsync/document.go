@@ -0,0 +1,275 @@
+package kbsync
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+// Kind the kind of a Document
+type Kind string
+
+const (
+	KindCategory Kind = "category"
+	KindFolder   Kind = "folder"
+	KindArticle  Kind = "article"
+)
+
+// ErrInvalidDocument the file does not start with a front matter
+var ErrInvalidDocument = errors.New("kbsync: invalid document")
+
+const frontMatterDelim = "---"
+
+// Document a solution category, folder or article (or a translation of an article).
+// The fields except Body are written to the front matter of the file, the Body is the description,
+// which is the HTML of the Remote, or the body of the file converted by the Mirror.Converter.
+type Document struct {
+	Kind Kind `json:"kind"`
+
+	// ID the id of the category/folder/article, 0 means a new document which is not pushed yet.
+	// The ID of a translation is the id of the article.
+	ID int64 `json:"id,omitempty"`
+
+	// Language the language of a translation, empty for the primary language.
+	Language string `json:"language,omitempty"`
+
+	// Title the name of the category/folder, or the title of the article
+	Title string `json:"title"`
+
+	// Status the article status (1: draft, 2: published)
+	Status int `json:"status,omitempty"`
+
+	// Type the article type of Freshservice (1: permanent, 2: workaround)
+	Type int `json:"type,omitempty"`
+
+	// Visibility the folder visibility
+	Visibility int `json:"visibility,omitempty"`
+
+	Tags []string `json:"tags,omitempty"`
+
+	Keywords []string `json:"keywords,omitempty"`
+
+	SeoTitle string `json:"seo_title,omitempty"`
+
+	SeoDescription string `json:"seo_description,omitempty"`
+
+	// Hash the content hash of the last pull/push, empty if the document is never synchronized.
+	Hash string `json:"hash,omitempty"`
+
+	// Body the description (the HTML of the Remote, or the converted body of the file)
+	Body string `json:"-"`
+
+	// Path the path of the file relative to the Mirror.Dir
+	Path string `json:"-"`
+}
+
+func (d *Document) String() string {
+	return fmt.Sprintf("%s #%d %s", d.Kind, d.ID, d.Path)
+}
+
+// ContentHash returns the sha256 hex string of the content (all fields except the Hash and Path).
+func (d *Document) ContentHash() string {
+	c := *d
+	c.Hash = ""
+
+	bs, _ := json.Marshal(&c)
+
+	h := sha256.New()
+	h.Write(bs)
+	h.Write([]byte{'\n'})
+	h.Write([]byte(d.Body))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Modified reports whether the content is modified since the last pull/push.
+func (d *Document) Modified() bool {
+	return d.Hash != d.ContentHash()
+}
+
+// Save writes the front matter and the body to the file dir/d.Path.
+func (d *Document) Save(dir string) error {
+	var buf bytes.Buffer
+
+	buf.WriteString(frontMatterDelim + "\n")
+
+	rv := reflect.ValueOf(d).Elem()
+	rt := rv.Type()
+	for i := range rt.NumField() {
+		name, opts, _ := strings.Cut(rt.Field(i).Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+
+		fv := rv.Field(i)
+		if opts == "omitempty" && (fv.IsZero() || (fv.Kind() == reflect.Slice && fv.Len() == 0)) {
+			continue
+		}
+
+		// a json value is a valid yaml flow value
+		bs, err := marshalValue(fv.Interface())
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(&buf, "%s: %s\n", name, bs)
+	}
+
+	buf.WriteString(frontMatterDelim + "\n")
+	buf.WriteString(d.Body)
+	buf.WriteByte('\n')
+
+	path := filepath.Join(dir, d.Path)
+	if err := os.MkdirAll(filepath.Dir(path), 0o770); err != nil {
+		return err
+	}
+	return os.WriteFile(path, buf.Bytes(), 0o660)
+}
+
+func marshalValue(v any) ([]byte, error) {
+	var buf bytes.Buffer
+
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSpace(buf.Bytes()), nil
+}
+
+// ReadDocument reads the Document from the file dir/path.
+func ReadDocument(dir, path string) (*Document, error) {
+	data, err := os.ReadFile(filepath.Join(dir, path))
+	if err != nil {
+		return nil, err
+	}
+
+	d, err := ParseDocument(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	d.Path = path
+	return d, nil
+}
+
+// ParseDocument parses the front matter and the body of the data.
+// The front matter values are json values (as written by Save), the unquoted strings and the
+// flow sequences of unquoted strings (e.g. "tags: [a, b]") are accepted for the hand-written files.
+func ParseDocument(data []byte) (*Document, error) {
+	rest := data
+	next := func() (string, bool) {
+		if len(rest) == 0 {
+			return "", false
+		}
+
+		line := rest
+		if i := bytes.IndexByte(rest, '\n'); i >= 0 {
+			line, rest = rest[:i], rest[i+1:]
+		} else {
+			rest = nil
+		}
+		return strings.TrimSpace(string(line)), true
+	}
+
+	if line, ok := next(); !ok || line != frontMatterDelim {
+		return nil, ErrInvalidDocument
+	}
+
+	fm := map[string]json.RawMessage{}
+	for {
+		line, ok := next()
+		if !ok {
+			return nil, ErrInvalidDocument
+		}
+		if line == frontMatterDelim {
+			break
+		}
+		if line == "" || line[0] == '#' {
+			continue
+		}
+
+		k, v, ok := strings.Cut(line, ":")
+		if !ok {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidDocument, line)
+		}
+		if v = strings.TrimSpace(v); v != "" {
+			fm[strings.TrimSpace(k)] = parseValue(v)
+		}
+	}
+
+	bs, err := json.Marshal(fm)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &Document{}
+	if err := json.Unmarshal(bs, d); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidDocument, err)
+	}
+
+	body := string(rest)
+	body = strings.TrimSuffix(body, "\n")
+	body = strings.TrimSuffix(body, "\r")
+	d.Body = body
+	return d, nil
+}
+
+func parseValue(v string) json.RawMessage {
+	if json.Valid([]byte(v)) {
+		return json.RawMessage(v)
+	}
+
+	if strings.HasPrefix(v, "[") && strings.HasSuffix(v, "]") {
+		ss := []string{}
+		for s := range strings.SplitSeq(v[1:len(v)-1], ",") {
+			if s = unquote(strings.TrimSpace(s)); s != "" {
+				ss = append(ss, s)
+			}
+		}
+		bs, _ := json.Marshal(ss)
+		return bs
+	}
+
+	bs, _ := json.Marshal(unquote(v))
+	return bs
+}
+
+func unquote(s string) string {
+	if len(s) > 1 && s[0] == '\'' && s[len(s)-1] == '\'' {
+		return strings.ReplaceAll(s[1:len(s)-1], "''", "'")
+	}
+	return strings.Trim(s, `"`)
+}
+
+// Slug returns the lower case letters and digits of the s joined by "-", truncated to 50 letters.
+func Slug(s string) string {
+	var sb strings.Builder
+
+	n, dash := 0, false
+	for _, r := range strings.ToLower(s) {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			dash = true
+			continue
+		}
+
+		if n >= 50 {
+			break
+		}
+		if dash && n > 0 {
+			sb.WriteByte('-')
+			n++
+		}
+		dash = false
+
+		sb.WriteRune(r)
+		n++
+	}
+	return sb.String()
+}
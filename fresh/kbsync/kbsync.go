@@ -0,0 +1,537 @@
+// Package kbsync mirrors a solutions knowledge base (categories, folders, articles and their translations)
+// to a directory of files with a front matter, and pushes the local changes back.
+//
+// The directory layout of the Mirror.Dir:
+//
+//	<category id>-<slug>/_index.html                              the category
+//	<category id>-<slug>/<folder id>-<slug>/_index.html           the folder (the sub folders are nested)
+//	<category id>-<slug>/<folder id>-<slug>/<article id>-<slug>.html       the article
+//	<category id>-<slug>/<folder id>-<slug>/<article id>-<slug>.<lang>.html the translation of the article
+//
+// A file starts with a YAML front matter (id, title, status, tags, seo data, language, hash ...) between
+// the "---" lines, followed by the description. The HTML description of an article is converted to the
+// body of the file by the Mirror.Converter, and converted back to HTML when it is pushed.
+// The articles are kept as HTML by default, set the Mirror.Converter to Markdown to write them in Markdown.
+// The descriptions of the categories and folders are plain text, they are not converted.
+//
+// The "hash" of the front matter is the content hash of the last pull/push:
+//
+//   - Pull writes the remote documents whose content hash differ from the local files,
+//     the locally modified files are kept, and reported as conflicts if the remote documents are modified too.
+//   - Push sends the files whose content hash differ from the recorded hash,
+//     the files without "id" (e.g. a new folder directory with an _index file) are created.
+//
+// The deletions are not synchronized.
+//
+// Example:
+//
+//	m := fd.NewKBMirror("kb", "ja", "fr")
+//	m.Ext, m.Converter = ".md", kbsync.Markdown{}
+//	res, err := m.Pull(ctx)
+//	// edit the files ...
+//	res, err = m.Push(ctx)
+package kbsync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"iter"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// IndexName the file name (without the extension) of a category or folder in its directory
+const IndexName = "_index"
+
+// Remote the knowledge base of a helpdesk.
+type Remote interface {
+	// Categories iterates the categories.
+	Categories(ctx context.Context) iter.Seq2[*Document, error]
+
+	// Folders iterates the top level folders of the category.
+	Folders(ctx context.Context, cid int64) iter.Seq2[*Document, error]
+
+	// SubFolders iterates the sub folders of the folder.
+	SubFolders(ctx context.Context, fid int64) iter.Seq2[*Document, error]
+
+	// Articles iterates the articles (primary language) of the folder.
+	Articles(ctx context.Context, fid int64) iter.Seq2[*Document, error]
+
+	// Translation returns the translation of the article in the language, nil if it is not translated.
+	Translation(ctx context.Context, aid int64, lang string) (*Document, error)
+
+	// Save creates or updates the document in the parent (nil for a category), returns the saved document.
+	// A document should be created if the ID is 0, or if it is a translation (Language is not empty)
+	// without Hash (it is never pulled or pushed).
+	Save(ctx context.Context, doc, parent *Document) (*Document, error)
+}
+
+// Converter converts the description of an article between the HTML of the Remote and the body of the file.
+// ToFile must be deterministic, since the content hash of a pulled document is the hash of the converted body.
+type Converter interface {
+	// ToFile converts the HTML description to the body of the file.
+	ToFile(html string) (string, error)
+
+	// ToRemote converts the body of the file to the HTML description.
+	ToRemote(body string) (string, error)
+}
+
+// HTML a Converter which keeps the HTML description as is.
+type HTML struct{}
+
+// ToFile returns the html.
+func (HTML) ToFile(html string) (string, error) {
+	return html, nil
+}
+
+// ToRemote returns the body.
+func (HTML) ToRemote(body string) (string, error) {
+	return body, nil
+}
+
+// Result the result of a Pull or Push
+type Result struct {
+	// Created the paths of the created files (Pull) or documents (Push)
+	Created []string
+
+	// Updated the paths of the updated (or moved) files (Pull) or documents (Push)
+	Updated []string
+
+	// Conflicts the paths of the files which are modified both locally and remotely (Pull)
+	Conflicts []string
+
+	// Unchanged the count of the unchanged documents
+	Unchanged int
+}
+
+// Mirror mirrors the knowledge base of the Remote to the directory Dir.
+type Mirror struct {
+	Remote Remote
+
+	// Dir the directory of the files
+	Dir string
+
+	// Ext the file extension, default is ".html".
+	Ext string
+
+	// Converter converts the article descriptions, default is HTML.
+	Converter Converter
+
+	// Languages the translation languages of the articles to pull.
+	// The translation files are pushed regardless of the Languages.
+	Languages []string
+}
+
+// NewMirror returns a Mirror of the remote to the directory dir with the translations of the languages.
+func NewMirror(remote Remote, dir string, languages ...string) *Mirror {
+	return &Mirror{
+		Remote:    remote,
+		Dir:       dir,
+		Ext:       ".html",
+		Languages: languages,
+	}
+}
+
+func (m *Mirror) ext() string {
+	if m.Ext == "" {
+		return ".html"
+	}
+	return m.Ext
+}
+
+func (m *Mirror) converter() Converter {
+	if m.Converter != nil {
+		return m.Converter
+	}
+	return HTML{}
+}
+
+// toFile converts the description of the remote article to the body of the file.
+func (m *Mirror) toFile(d *Document) error {
+	if d.Kind != KindArticle {
+		return nil
+	}
+
+	body, err := m.converter().ToFile(d.Body)
+	if err != nil {
+		return fmt.Errorf("kbsync: %s: %w", d, err)
+	}
+	d.Body = body
+	return nil
+}
+
+// name returns the file (directory) name of a new document.
+func (m *Mirror) name(d *Document) string {
+	id := strconv.FormatInt(d.ID, 10)
+	if s := Slug(d.Title); s != "" {
+		return id + "-" + s
+	}
+	return id
+}
+
+// node a local document with its parent (and the article of a translation)
+type node struct {
+	doc    *Document
+	parent *node
+	base   *node
+}
+
+// Changes returns the local documents which are created or modified since the last pull/push.
+func (m *Mirror) Changes() ([]*Document, error) {
+	nodes, err := m.scan()
+	if err != nil {
+		return nil, err
+	}
+
+	var ds []*Document
+	for _, n := range nodes {
+		if n.doc.Modified() {
+			ds = append(ds, n.doc)
+		}
+	}
+	return ds, nil
+}
+
+// scan reads the local documents, the parents are placed before the children.
+func (m *Mirror) scan() ([]*node, error) {
+	var nodes []*node
+	if err := m.scanDir(".", nil, &nodes); err != nil {
+		if errors.Is(err, fs.ErrNotExist) && len(nodes) == 0 {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return nodes, nil
+}
+
+func (m *Mirror) scanDir(dir string, parent *node, nodes *[]*node) error {
+	des, err := os.ReadDir(filepath.Join(m.Dir, dir))
+	if err != nil {
+		return err
+	}
+
+	ext, index := m.ext(), IndexName+m.ext()
+
+	var articles, translations []*node
+	var subdirs []string
+	for _, de := range des {
+		name := de.Name()
+		if de.IsDir() {
+			subdirs = append(subdirs, name)
+			continue
+		}
+
+		// the articles are in the folders only
+		if parent == nil || parent.doc.Kind != KindFolder || name == index || !strings.HasSuffix(name, ext) {
+			continue
+		}
+
+		d, err := ReadDocument(m.Dir, filepath.Join(dir, name))
+		if err != nil {
+			return err
+		}
+		d.Kind = KindArticle
+
+		n := &node{doc: d, parent: parent}
+		if d.Language == "" {
+			articles = append(articles, n)
+		} else {
+			translations = append(translations, n)
+		}
+	}
+
+	// the translation file is named "<article file stem>.<lang><ext>"
+	for _, t := range translations {
+		stem := strings.TrimSuffix(filepath.Base(t.doc.Path), "."+t.doc.Language+ext)
+		for _, a := range articles {
+			if strings.TrimSuffix(filepath.Base(a.doc.Path), ext) == stem {
+				t.base = a
+				break
+			}
+		}
+		if t.base == nil {
+			return fmt.Errorf("kbsync: %s: the article of the translation is not found", t.doc.Path)
+		}
+	}
+
+	*nodes = append(*nodes, articles...)
+	*nodes = append(*nodes, translations...)
+
+	for _, sd := range subdirs {
+		path := filepath.Join(dir, sd, index)
+
+		d, err := ReadDocument(m.Dir, path)
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				continue // not a category or folder directory
+			}
+			return err
+		}
+
+		d.Kind = KindCategory
+		if parent != nil {
+			d.Kind = KindFolder
+		}
+
+		n := &node{doc: d, parent: parent}
+		*nodes = append(*nodes, n)
+
+		if err := m.scanDir(filepath.Join(dir, sd), n, nodes); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Push pushes the created and modified local documents to the Remote,
+// and writes the saved documents back to the files.
+func (m *Mirror) Push(ctx context.Context) (*Result, error) {
+	nodes, err := m.scan()
+	if err != nil {
+		return nil, err
+	}
+
+	res := &Result{}
+	for _, n := range nodes {
+		d := n.doc
+		if n.base != nil {
+			d.ID = n.base.doc.ID
+		}
+
+		if !d.Modified() {
+			res.Unchanged++
+			continue
+		}
+
+		var parent *Document
+		if n.parent != nil {
+			parent = n.parent.doc
+			if parent.ID == 0 {
+				return res, fmt.Errorf("kbsync: %s: the parent %s is not pushed", d.Path, parent.Path)
+			}
+		}
+
+		create := d.ID == 0 || (d.Language != "" && d.Hash == "")
+
+		rd := *d
+		if d.Kind == KindArticle {
+			if rd.Body, err = m.converter().ToRemote(d.Body); err != nil {
+				return res, fmt.Errorf("kbsync: push %s: %w", d.Path, err)
+			}
+		}
+
+		sd, err := m.Remote.Save(ctx, &rd, parent)
+		if err != nil {
+			return res, fmt.Errorf("kbsync: push %s: %w", d.Path, err)
+		}
+
+		sd.Kind, sd.Path = d.Kind, d.Path
+		if err := m.toFile(sd); err != nil {
+			return res, err
+		}
+		sd.Hash = sd.ContentHash()
+		if err := sd.Save(m.Dir); err != nil {
+			return res, err
+		}
+		*d = *sd
+
+		if create {
+			res.Created = append(res.Created, d.Path)
+		} else {
+			res.Updated = append(res.Updated, d.Path)
+		}
+	}
+	return res, nil
+}
+
+type docKey struct {
+	kind Kind
+	id   int64
+	lang string
+}
+
+type puller struct {
+	*Mirror
+	local map[docKey]*Document
+	res   *Result
+}
+
+// Pull writes the remote documents to the files.
+// The locally modified files are not overwritten, they are reported as the Result.Conflicts
+// if the remote documents are modified since the last pull/push too.
+func (m *Mirror) Pull(ctx context.Context) (*Result, error) {
+	nodes, err := m.scan()
+	if err != nil {
+		return nil, err
+	}
+
+	p := &puller{Mirror: m, local: map[docKey]*Document{}, res: &Result{}}
+	for _, n := range nodes {
+		if d := n.doc; d.ID != 0 {
+			p.local[docKey{d.Kind, d.ID, d.Language}] = d
+		}
+	}
+
+	for c, err := range m.Remote.Categories(ctx) {
+		if err != nil {
+			return p.res, err
+		}
+
+		c.Kind = KindCategory
+		if err := p.pull(c, "."); err != nil {
+			return p.res, err
+		}
+
+		for f, err := range m.Remote.Folders(ctx, c.ID) {
+			if err != nil {
+				return p.res, err
+			}
+			if err := p.pullFolder(ctx, f, filepath.Dir(c.Path)); err != nil {
+				return p.res, err
+			}
+		}
+	}
+	return p.res, nil
+}
+
+func (p *puller) pullFolder(ctx context.Context, f *Document, pdir string) error {
+	f.Kind = KindFolder
+	if err := p.pull(f, pdir); err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(f.Path)
+	for a, err := range p.Remote.Articles(ctx, f.ID) {
+		if err != nil {
+			return err
+		}
+
+		a.Kind, a.Language = KindArticle, ""
+		if err := p.pull(a, dir); err != nil {
+			return err
+		}
+
+		for _, lang := range p.Languages {
+			t, err := p.Remote.Translation(ctx, a.ID, lang)
+			if err != nil {
+				return err
+			}
+			if t == nil {
+				continue
+			}
+
+			t.Kind, t.ID, t.Language = KindArticle, a.ID, lang
+			if err := p.pull(t, dir, a.Path); err != nil {
+				return err
+			}
+		}
+	}
+
+	for sf, err := range p.Remote.SubFolders(ctx, f.ID) {
+		if err != nil {
+			return err
+		}
+		if err := p.pullFolder(ctx, sf, dir); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// pull writes the remote document d in the directory pdir, the base is the article path of a translation.
+func (p *puller) pull(d *Document, pdir string, base ...string) error {
+	ext := p.ext()
+	local := p.local[docKey{d.Kind, d.ID, d.Language}]
+
+	if err := p.toFile(d); err != nil {
+		return err
+	}
+
+	switch {
+	case d.Kind != KindArticle:
+		name := p.name(d)
+		if local != nil {
+			name = filepath.Base(filepath.Dir(local.Path))
+		}
+		d.Path = filepath.Join(pdir, name, IndexName+ext)
+	case local != nil:
+		d.Path = filepath.Join(pdir, filepath.Base(local.Path))
+	case d.Language != "":
+		d.Path = strings.TrimSuffix(base[0], ext) + "." + d.Language + ext
+	default:
+		d.Path = filepath.Join(pdir, p.name(d)+ext)
+	}
+	d.Hash = d.ContentHash()
+
+	if local == nil {
+		p.res.Created = append(p.res.Created, d.Path)
+		return d.Save(p.Dir)
+	}
+
+	if local.Modified() {
+		if local.Hash != d.Hash {
+			p.res.Conflicts = append(p.res.Conflicts, local.Path)
+		} else {
+			p.res.Unchanged++
+		}
+		d.Path = local.Path
+		return nil
+	}
+
+	if local.Hash == d.Hash && local.Path == d.Path {
+		p.res.Unchanged++
+		return nil
+	}
+
+	if local.Path != d.Path {
+		if err := p.move(local.Path, d); err != nil {
+			return err
+		}
+	}
+
+	p.res.Updated = append(p.res.Updated, d.Path)
+	return d.Save(p.Dir)
+}
+
+// move moves the local file (or the directory of a category/folder) of the path to the d.Path.
+func (p *puller) move(path string, d *Document) error {
+	if d.Kind == KindArticle {
+		if err := os.Remove(filepath.Join(p.Dir, path)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+
+	src := filepath.Join(p.Dir, filepath.Dir(path))
+	dst := filepath.Join(p.Dir, filepath.Dir(d.Path))
+	if _, err := os.Stat(src); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil // moved with the parent directory
+		}
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dst), 0o770); err != nil {
+		return err
+	}
+	return os.Rename(src, dst)
+}
+
+// Convert converts the sequence of the remote records to the sequence of Documents by the function fn.
+func Convert[T any](seq iter.Seq2[T, error], fn func(T) *Document) iter.Seq2[*Document, error] {
+	return func(yield func(*Document, error) bool) {
+		for v, err := range seq {
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if !yield(fn(v), nil) {
+				return
+			}
+		}
+	}
+}
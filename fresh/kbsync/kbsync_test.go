@@ -0,0 +1,386 @@
+package kbsync
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+var ctxbg = context.Background()
+
+type testRemote struct {
+	seq     int64
+	docs    []*Document         // categories, folders and articles
+	parents map[int64]int64     // document id -> parent id
+	trans   map[string]Document // "<article id>:<lang>" -> translation
+	saves   []string
+}
+
+func newTestRemote() *testRemote {
+	return &testRemote{parents: map[int64]int64{}, trans: map[string]Document{}}
+}
+
+func (tr *testRemote) add(kind Kind, pid int64, title, body string) *Document {
+	tr.seq++
+	d := &Document{Kind: kind, ID: tr.seq, Title: title, Body: body}
+	tr.docs = append(tr.docs, d)
+	tr.parents[d.ID] = pid
+	return d
+}
+
+func (tr *testRemote) children(kind Kind, pid int64) iter.Seq2[*Document, error] {
+	return func(yield func(*Document, error) bool) {
+		for _, d := range tr.docs {
+			if d.Kind == kind && tr.parents[d.ID] == pid {
+				c := *d
+				if !yield(&c, nil) {
+					return
+				}
+			}
+		}
+	}
+}
+
+func (tr *testRemote) Categories(ctx context.Context) iter.Seq2[*Document, error] {
+	return tr.children(KindCategory, 0)
+}
+
+func (tr *testRemote) Folders(ctx context.Context, cid int64) iter.Seq2[*Document, error] {
+	return tr.children(KindFolder, cid)
+}
+
+func (tr *testRemote) SubFolders(ctx context.Context, fid int64) iter.Seq2[*Document, error] {
+	return tr.children(KindFolder, fid)
+}
+
+func (tr *testRemote) Articles(ctx context.Context, fid int64) iter.Seq2[*Document, error] {
+	return tr.children(KindArticle, fid)
+}
+
+func (tr *testRemote) Translation(ctx context.Context, aid int64, lang string) (*Document, error) {
+	if t, ok := tr.trans[fmt.Sprintf("%d:%s", aid, lang)]; ok {
+		return &t, nil
+	}
+	return nil, nil
+}
+
+func (tr *testRemote) Save(ctx context.Context, doc, parent *Document) (*Document, error) {
+	d := *doc
+	d.Hash, d.Path = "", ""
+
+	if d.Language != "" {
+		tr.saves = append(tr.saves, "translation:"+d.Language)
+		tr.trans[fmt.Sprintf("%d:%s", d.ID, d.Language)] = d
+		return &d, nil
+	}
+
+	if d.ID == 0 {
+		var pid int64
+		if parent != nil {
+			pid = parent.ID
+		}
+		tr.saves = append(tr.saves, "create:"+d.Title)
+		c := tr.add(d.Kind, pid, "", "")
+		d.ID = c.ID
+		*c = d
+		return &d, nil
+	}
+
+	tr.saves = append(tr.saves, "update:"+d.Title)
+	for _, c := range tr.docs {
+		if c.ID == d.ID {
+			*c = d
+		}
+	}
+	return &d, nil
+}
+
+func readFile(t *testing.T, dir, path string) string {
+	t.Helper()
+
+	bs, err := os.ReadFile(filepath.Join(dir, path))
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	return string(bs)
+}
+
+func writeFile(t *testing.T, dir, path, data string) {
+	t.Helper()
+
+	if err := os.MkdirAll(filepath.Dir(filepath.Join(dir, path)), 0o770); err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, path), []byte(data), 0o660); err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+}
+
+func TestDocument(t *testing.T) {
+	dir := t.TempDir()
+
+	d := &Document{
+		Kind:     KindArticle,
+		ID:       1,
+		Title:    `Say "<hello>"`,
+		Status:   2,
+		Tags:     []string{"a", "b"},
+		SeoTitle: "SEO",
+		Body:     "<p>hello</p>\r\n<p>world</p>",
+		Path:     "a/b.html",
+	}
+	d.Hash = d.ContentHash()
+
+	if err := d.Save(dir); err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+
+	want := "---\nkind: \"article\"\nid: 1\ntitle: \"Say \\\"<hello>\\\"\"\nstatus: 2\ntags: [\"a\",\"b\"]\nseo_title: \"SEO\"\nhash: \"" + d.Hash + "\"\n---\n<p>hello</p>\r\n<p>world</p>\n"
+	if got := readFile(t, dir, d.Path); got != want {
+		t.Fatalf("Save() =\n%s\nwant\n%s", got, want)
+	}
+
+	r, err := ReadDocument(dir, d.Path)
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	if r.Modified() || r.Body != d.Body || !slices.Equal(r.Tags, d.Tags) {
+		t.Fatalf("ReadDocument() = %#v", r)
+	}
+
+	// hand-written front matter
+	r, err = ParseDocument([]byte("---\r\nkind: article\r\n# comment\r\ntitle: It's new\r\ntags: [x, 'y z']\r\nlanguage:\r\n---\r\nbody\r\n"))
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	if r.Title != "It's new" || !slices.Equal(r.Tags, []string{"x", "y z"}) || r.Language != "" || r.Body != "body" || !r.Modified() {
+		t.Fatalf("ParseDocument() = %#v", r)
+	}
+
+	for _, s := range []string{"", "no front matter", "---\ntitle: x\n"} {
+		if _, err := ParseDocument([]byte(s)); err == nil {
+			t.Errorf("ParseDocument(%q) should fail", s)
+		}
+	}
+}
+
+func TestSlug(t *testing.T) {
+	cs := []struct {
+		s, w string
+	}{
+		{"Hello, World!", "hello-world"},
+		{"  --How to: reset the password?  ", "how-to-reset-the-password"},
+		{"よくある質問 (FAQ)", "よくある質問-faq"},
+		{"!!!", ""},
+		{strings.Repeat("a", 60), strings.Repeat("a", 50)},
+	}
+
+	for i, c := range cs {
+		if a := Slug(c.s); a != c.w {
+			t.Errorf("[%d] Slug(%q) = %q, want %q", i, c.s, a, c.w)
+		}
+	}
+}
+
+func TestMirror(t *testing.T) {
+	dir := t.TempDir()
+
+	tr := newTestRemote()
+	cat := tr.add(KindCategory, 0, "FAQ", "")
+	fol := tr.add(KindFolder, cat.ID, "Getting Started", "")
+	sub := tr.add(KindFolder, fol.ID, "Sub", "")
+	art := tr.add(KindArticle, fol.ID, "How to", "<p>how to</p>")
+	tr.add(KindArticle, sub.ID, "Deep", "<p>deep</p>")
+	tr.trans["4:ja"] = Document{Kind: KindArticle, ID: art.ID, Language: "ja", Title: "使い方", Body: "<p>使い方</p>"}
+
+	m := NewMirror(tr, dir, "ja", "fr")
+
+	// initial pull
+	res, err := m.Pull(ctxbg)
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+
+	paths := []string{
+		"1-faq/_index.html",
+		"1-faq/2-getting-started/_index.html",
+		"1-faq/2-getting-started/4-how-to.html",
+		"1-faq/2-getting-started/4-how-to.ja.html",
+		"1-faq/2-getting-started/3-sub/_index.html",
+		"1-faq/2-getting-started/3-sub/5-deep.html",
+	}
+	if !slices.Equal(res.Created, paths) {
+		t.Fatalf("Pull().Created = %v, want %v", res.Created, paths)
+	}
+
+	// nothing changed
+	res, err = m.Pull(ctxbg)
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	if len(res.Created)+len(res.Updated)+len(res.Conflicts) != 0 || res.Unchanged != len(paths) {
+		t.Fatalf("Pull() = %+v", res)
+	}
+
+	// local changes: edit an article and a translation, add a folder with an article and a translation
+	body := readFile(t, dir, paths[2])
+	writeFile(t, dir, paths[2], strings.Replace(body, "<p>how to</p>", "<p>how to (edited)</p>", 1))
+
+	body = readFile(t, dir, paths[3])
+	writeFile(t, dir, paths[3], strings.Replace(body, "<p>使い方</p>", "<p>使い方 (編集)</p>", 1))
+
+	writeFile(t, dir, "1-faq/new/_index.html", "---\ntitle: New Folder\nvisibility: 1\n---\n")
+	writeFile(t, dir, "1-faq/new/new-article.html", "---\ntitle: New Article\ntags: [new]\n---\n<p>new</p>\n")
+	writeFile(t, dir, "1-faq/new/new-article.fr.html", "---\ntitle: Nouvel article\nlanguage: fr\n---\n<p>nouveau</p>\n")
+
+	changes, err := m.Changes()
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	if len(changes) != 5 {
+		t.Fatalf("Changes() = %v", changes)
+	}
+
+	res, err = m.Push(ctxbg)
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+
+	saves := []string{"update:How to", "translation:ja", "create:New Folder", "create:New Article", "translation:fr"}
+	if !slices.Equal(tr.saves, saves) {
+		t.Fatalf("Push() saves = %v, want %v", tr.saves, saves)
+	}
+	if len(res.Created) != 3 || len(res.Updated) != 2 || res.Unchanged != 4 {
+		t.Fatalf("Push() = %+v", res)
+	}
+	if tr.docs[1].Body != "" || tr.docs[3].Body != "<p>how to (edited)</p>" || tr.trans["4:ja"].Body != "<p>使い方 (編集)</p>" {
+		t.Fatalf("Push() remote = %v %v", tr.docs, tr.trans)
+	}
+
+	nd, err := ReadDocument(dir, "1-faq/new/new-article.fr.html")
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	if nd.ID != 7 || nd.Modified() || tr.trans["7:fr"].Title != "Nouvel article" || tr.parents[7] != 6 {
+		t.Fatalf("Push() translation = %#v", nd)
+	}
+
+	// nothing to push
+	if changes, err = m.Changes(); err != nil || len(changes) != 0 {
+		t.Fatalf("Changes() = %v, %v", changes, err)
+	}
+
+	// remote changes: update an article, move an article, and edit the same article on both sides
+	tr.docs[3].Body = "<p>how to (remote)</p>"
+	tr.parents[5] = fol.ID
+	tr.docs[4].Title = "Deep (remote)"
+
+	body = readFile(t, dir, paths[5])
+	writeFile(t, dir, paths[5], strings.Replace(body, "<p>deep</p>", "<p>deep (local)</p>", 1))
+
+	res, err = m.Pull(ctxbg)
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	if !slices.Equal(res.Updated, paths[2:3]) || !slices.Equal(res.Conflicts, paths[5:6]) {
+		t.Fatalf("Pull() = %+v", res)
+	}
+	if body = readFile(t, dir, paths[2]); !strings.Contains(body, "<p>how to (remote)</p>") {
+		t.Fatalf("Pull() %s =\n%s", paths[2], body)
+	}
+	if body = readFile(t, dir, paths[5]); !strings.Contains(body, "<p>deep (local)</p>") {
+		t.Fatalf("Pull() %s =\n%s", paths[5], body)
+	}
+
+	// resolve the conflict by discarding the local change: the article is moved
+	os.Remove(filepath.Join(dir, paths[5]))
+
+	res, err = m.Pull(ctxbg)
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	if !slices.Equal(res.Created, []string{"1-faq/2-getting-started/5-deep-remote.html"}) {
+		t.Fatalf("Pull() = %+v", res)
+	}
+
+	// move an article with its translation
+	tr.parents[art.ID] = sub.ID
+
+	res, err = m.Pull(ctxbg)
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+
+	moved := []string{"1-faq/2-getting-started/3-sub/4-how-to.html", "1-faq/2-getting-started/3-sub/4-how-to.ja.html"}
+	if !slices.Equal(res.Updated, moved) {
+		t.Fatalf("Pull() = %+v", res)
+	}
+	for _, p := range paths[2:4] {
+		if _, err := os.Stat(filepath.Join(dir, p)); !os.IsNotExist(err) {
+			t.Errorf("%s should be removed: %v", p, err)
+		}
+	}
+}
+
+func TestMirrorMarkdown(t *testing.T) {
+	dir := t.TempDir()
+
+	tr := newTestRemote()
+	cat := tr.add(KindCategory, 0, "FAQ", "a & b")
+	fol := tr.add(KindFolder, cat.ID, "Guide", "")
+	tr.add(KindArticle, fol.ID, "How to", "<h2>Steps</h2><ol><li>open</li><li><b>save</b></li></ol>")
+
+	m := NewMirror(tr, dir)
+	m.Ext = ".md"
+	if c := m.converter(); c != (HTML{}) {
+		t.Fatalf("converter() = %T, want HTML", c)
+	}
+	m.Converter = Markdown{}
+
+	res, err := m.Pull(ctxbg)
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+
+	path := "1-faq/2-guide/3-how-to.md"
+	if len(res.Created) != 3 || res.Created[2] != path {
+		t.Fatalf("Pull() = %+v", res)
+	}
+
+	body := readFile(t, dir, path)
+	if !strings.HasSuffix(body, "---\n## Steps\n\n1. open\n2. **save**\n") {
+		t.Fatalf("Pull() %s =\n%s", path, body)
+	}
+	if body = readFile(t, dir, "1-faq/_index.md"); !strings.HasSuffix(body, "---\na & b\n") {
+		t.Fatalf("Pull() category =\n%s", body)
+	}
+
+	// the converted files are not modified
+	res, err = m.Pull(ctxbg)
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	if res.Unchanged != 3 {
+		t.Fatalf("Pull() = %+v", res)
+	}
+
+	writeFile(t, dir, path, strings.Replace(readFile(t, dir, path), "2. **save**", "2. **save**\n3. close", 1))
+
+	if _, err = m.Push(ctxbg); err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+
+	want := "<h2>Steps</h2>\n<ol>\n<li>open</li>\n<li><strong>save</strong></li>\n<li>close</li>\n</ol>"
+	if a := tr.docs[2].Body; a != want {
+		t.Fatalf("Push() remote = %q, want %q", a, want)
+	}
+
+	if changes, err := m.Changes(); err != nil || len(changes) != 0 {
+		t.Fatalf("Changes() = %v, %v", changes, err)
+	}
+}
@@ -0,0 +1,608 @@
+package kbsync
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/yuin/goldmark"
+	gmhtml "github.com/yuin/goldmark/renderer/html"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// Markdown a Converter between the HTML and the Markdown (CommonMark).
+//
+// ToFile converts the headings, paragraphs, line breaks, emphasis, code, links, images, lists,
+// block quotes and horizontal rules to Markdown. The other elements (e.g. tables) and the elements
+// with the attributes which can not be written in Markdown (e.g. a styled paragraph) are kept as
+// raw HTML, which is valid in Markdown, so nothing of the description is lost.
+//
+// ToRemote converts the Markdown to HTML by goldmark, a complete CommonMark implementation,
+// the raw HTML is kept as is. The extensions (e.g. the GFM tables) are not enabled, the tables are written
+// as raw HTML by ToFile.
+//
+// Markdown is not the default Converter of a Mirror, set the Mirror.Converter to use it.
+type Markdown struct{}
+
+// blockTags the elements which are converted as blocks by ToFile, and started as raw HTML blocks by ToRemote.
+var blockTags = map[atom.Atom]bool{
+	atom.Address: true, atom.Article: true, atom.Aside: true, atom.Blockquote: true, atom.Details: true,
+	atom.Dd: true, atom.Div: true, atom.Dl: true, atom.Dt: true, atom.Fieldset: true, atom.Figcaption: true,
+	atom.Figure: true, atom.Footer: true, atom.Form: true, atom.H1: true, atom.H2: true, atom.H3: true,
+	atom.H4: true, atom.H5: true, atom.H6: true, atom.Header: true, atom.Hr: true, atom.Iframe: true,
+	atom.Li: true, atom.Main: true, atom.Nav: true, atom.Ol: true, atom.P: true, atom.Pre: true,
+	atom.Section: true, atom.Summary: true, atom.Table: true, atom.Tbody: true, atom.Td: true,
+	atom.Tfoot: true, atom.Th: true, atom.Thead: true, atom.Tr: true, atom.Ul: true, atom.Video: true,
+}
+
+// ToFile converts the HTML to Markdown.
+func (Markdown) ToFile(s string) (string, error) {
+	ns, err := html.ParseFragment(strings.NewReader(s), &html.Node{Type: html.ElementNode, DataAtom: atom.Body, Data: "body"})
+	if err != nil {
+		return "", err
+	}
+
+	bs, err := mdBlocks(ns)
+	if err != nil {
+		return "", err
+	}
+	return strings.Join(bs, "\n\n"), nil
+}
+
+func isBlock(n *html.Node) bool {
+	return n.Type == html.ElementNode && blockTags[n.DataAtom]
+}
+
+// hasAttrs reports whether the node has the attributes other than the names.
+func hasAttrs(n *html.Node, names ...string) bool {
+	for _, a := range n.Attr {
+		if a.Namespace != "" || !contains(names, a.Key) {
+			return true
+		}
+	}
+	return false
+}
+
+func contains(ss []string, s string) bool {
+	for _, v := range ss {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Namespace == "" && a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+func children(n *html.Node) []*html.Node {
+	var ns []*html.Node
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		ns = append(ns, c)
+	}
+	return ns
+}
+
+// rawHTML renders the node as a raw HTML block (without blank lines, which end a HTML block).
+func rawHTML(n *html.Node) (string, error) {
+	var sb strings.Builder
+	if err := html.Render(&sb, n); err != nil {
+		return "", err
+	}
+
+	var lines []string
+	for line := range strings.SplitSeq(sb.String(), "\n") {
+		if strings.TrimSpace(line) != "" {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// mdBlocks converts the nodes to the Markdown blocks, the inline nodes between the blocks are converted as paragraphs.
+func mdBlocks(ns []*html.Node) ([]string, error) {
+	var (
+		blocks  []string
+		inlines []*html.Node
+	)
+	flush := func() error {
+		if len(inlines) > 0 {
+			p, err := mdParagraph(inlines)
+			if err != nil {
+				return err
+			}
+			if p != "" {
+				blocks = append(blocks, p)
+			}
+			inlines = nil
+		}
+		return nil
+	}
+
+	for _, n := range ns {
+		if !isBlock(n) {
+			inlines = append(inlines, n)
+			continue
+		}
+
+		if err := flush(); err != nil {
+			return nil, err
+		}
+
+		b, err := mdBlock(n)
+		if err != nil {
+			return nil, err
+		}
+		blocks = append(blocks, b...)
+	}
+
+	if err := flush(); err != nil {
+		return nil, err
+	}
+	return blocks, nil
+}
+
+func mdBlock(n *html.Node) ([]string, error) {
+	raw := func() ([]string, error) {
+		s, err := rawHTML(n)
+		if err != nil {
+			return nil, err
+		}
+		return []string{s}, nil
+	}
+
+	switch n.DataAtom {
+	case atom.P:
+		if hasAttrs(n) {
+			return raw()
+		}
+		p, err := mdParagraph(children(n))
+		if err != nil || p == "" {
+			return nil, err
+		}
+		return []string{p}, nil
+	case atom.Div:
+		if hasAttrs(n) {
+			return raw()
+		}
+		return mdBlocks(children(n))
+	case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+		if hasAttrs(n) {
+			return raw()
+		}
+		s, err := mdInlines(children(n))
+		if err != nil {
+			return nil, err
+		}
+		s = strings.Join(strings.Fields(strings.ReplaceAll(s, "\\\n", " ")), " ")
+		return []string{strings.Repeat("#", int(n.Data[1]-'0')) + " " + s}, nil
+	case atom.Hr:
+		if hasAttrs(n) {
+			return raw()
+		}
+		return []string{"***"}, nil
+	case atom.Pre:
+		if s, ok := mdCodeBlock(n); ok {
+			return []string{s}, nil
+		}
+		return raw()
+	case atom.Blockquote:
+		if hasAttrs(n) {
+			return raw()
+		}
+		bs, err := mdBlocks(children(n))
+		if err != nil {
+			return nil, err
+		}
+		return []string{indent(strings.Join(bs, "\n\n"), "> ", ">")}, nil
+	case atom.Ul, atom.Ol:
+		s, ok, err := mdList(n)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			return []string{s}, nil
+		}
+		return raw()
+	default:
+		return raw()
+	}
+}
+
+// indent prefixes the lines of s by the prefix, the blank lines are prefixed by the blank.
+func indent(s, prefix, blank string) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		if line == "" {
+			lines[i] = blank
+		} else {
+			lines[i] = prefix + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// mdCodeBlock converts the <pre> or <pre><code class="language-xxx"> of text to a fenced code block.
+func mdCodeBlock(n *html.Node) (string, bool) {
+	if hasAttrs(n) {
+		return "", false
+	}
+
+	c, lang := n, ""
+	if f := n.FirstChild; f != nil && f == n.LastChild && f.Type == html.ElementNode && f.DataAtom == atom.Code {
+		if hasAttrs(f, "class") {
+			return "", false
+		}
+
+		cls := attr(f, "class")
+		if cls != "" {
+			if !strings.HasPrefix(cls, "language-") || strings.ContainsAny(cls, " `~") {
+				return "", false
+			}
+			lang = strings.TrimPrefix(cls, "language-")
+		}
+		c = f
+	}
+
+	var sb strings.Builder
+	for t := c.FirstChild; t != nil; t = t.NextSibling {
+		if t.Type != html.TextNode {
+			return "", false
+		}
+		sb.WriteString(t.Data)
+	}
+
+	code := strings.TrimSuffix(sb.String(), "\n")
+
+	fence := "```"
+	for strings.Contains(code, fence) {
+		fence += "`"
+	}
+	if code == "" {
+		return fence + lang + "\n" + fence, true
+	}
+	return fence + lang + "\n" + code + "\n" + fence, true
+}
+
+// mdList converts the <ul> or <ol> to a list, returns false if the list can not be written in Markdown.
+func mdList(n *html.Node) (string, bool, error) {
+	ordered, start := n.DataAtom == atom.Ol, 1
+	if ordered {
+		if hasAttrs(n, "start") {
+			return "", false, nil
+		}
+		if s := attr(n, "start"); s != "" {
+			i, err := strconv.Atoi(s)
+			if err != nil || i < 0 {
+				return "", false, nil
+			}
+			start = i
+		}
+	} else if hasAttrs(n) {
+		return "", false, nil
+	}
+
+	var items []string
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		switch {
+		case c.Type == html.TextNode && strings.TrimSpace(c.Data) == "":
+			continue
+		case c.Type == html.ElementNode && c.DataAtom == atom.Li && !hasAttrs(c):
+		default:
+			return "", false, nil
+		}
+
+		marker := "- "
+		if ordered {
+			marker = strconv.Itoa(start+len(items)) + ". "
+		}
+
+		bs, err := mdBlocks(children(c))
+		if err != nil {
+			return "", false, err
+		}
+
+		// a paragraph with the sub lists is tight, the other blocks are separated by blank lines (loose)
+		sep := "\n"
+		for i, b := range bs {
+			if i > 0 && !mdIsList(b) {
+				sep = "\n\n"
+				break
+			}
+		}
+
+		item := strings.TrimRight(marker, " ")
+		if len(bs) > 0 {
+			item = marker + indent(strings.Join(bs, sep), strings.Repeat(" ", len(marker)), "")[len(marker):]
+		}
+		items = append(items, item)
+	}
+	return strings.Join(items, "\n"), true, nil
+}
+
+// mdListMarker matches a line which starts with a list item marker.
+var mdListMarker = regexp.MustCompile(`^ {0,3}([-+*]|[0-9]{1,9}[.)])( |$)`)
+
+func mdIsList(b string) bool {
+	return mdListMarker.MatchString(b)
+}
+
+// mdParagraph converts the inline nodes to a paragraph, the line breaks are written as "\" at the end of the lines.
+func mdParagraph(ns []*html.Node) (string, error) {
+	s, err := mdInlines(ns)
+	if err != nil {
+		return "", err
+	}
+
+	var lines []string
+	for line := range strings.SplitSeq(s, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, mdEscapeLineStart(line))
+		}
+	}
+
+	// the line break at the end of the paragraph is ignored
+	p := strings.Join(lines, "\n")
+	if n := len(p) - len(strings.TrimRight(p, "\\")); n%2 == 1 {
+		p = strings.TrimRight(p[:len(p)-1], " ")
+	}
+	return p, nil
+}
+
+var mdOrderedStart = regexp.MustCompile(`^[0-9]+[.)]`)
+
+// mdEscapeLineStart escapes the characters which start a block at the start of the line.
+func mdEscapeLineStart(line string) string {
+	if line == "" {
+		return line
+	}
+
+	switch line[0] {
+	case '#', '>', '-', '+', '=', '~':
+		return "\\" + line
+	}
+
+	if m := mdOrderedStart.FindString(line); m != "" {
+		return m[:len(m)-1] + "\\" + line[len(m)-1:]
+	}
+	return line
+}
+
+func mdInlines(ns []*html.Node) (string, error) {
+	var sb strings.Builder
+	for _, n := range ns {
+		if err := mdInline(&sb, n); err != nil {
+			return "", err
+		}
+	}
+	return sb.String(), nil
+}
+
+func mdInline(sb *strings.Builder, n *html.Node) error {
+	// the raw tags of the element, the children are converted,
+	// since the content between the inline tags is parsed as Markdown.
+	raw := func() error {
+		if n.Type != html.ElementNode || n.FirstChild == nil || n.DataAtom == atom.Script || n.DataAtom == atom.Style || n.DataAtom == atom.Textarea {
+			return html.Render(sb, n)
+		}
+
+		sb.WriteString("<" + n.Data)
+		for _, a := range n.Attr {
+			k := a.Key
+			if a.Namespace != "" {
+				k = a.Namespace + ":" + k
+			}
+			sb.WriteString(" " + k + `="` + mdAttrEscaper.Replace(a.Val) + `"`)
+		}
+		sb.WriteString(">")
+
+		s, err := mdInlines(children(n))
+		if err != nil {
+			return err
+		}
+		sb.WriteString(s + "</" + n.Data + ">")
+		return nil
+	}
+
+	switch n.Type {
+	case html.TextNode:
+		sb.WriteString(mdEscapeText(strings.Join(mdSplitSpace(n.Data), " ")))
+		return nil
+	case html.ElementNode:
+	default:
+		return raw()
+	}
+
+	switch n.DataAtom {
+	case atom.Br:
+		sb.WriteString("\\\n")
+	case atom.Strong, atom.B:
+		if hasAttrs(n) {
+			return raw()
+		}
+		return mdWrap(sb, n, "**", "strong")
+	case atom.Em, atom.I:
+		if hasAttrs(n) {
+			return raw()
+		}
+		return mdWrap(sb, n, "*", "em")
+	case atom.Span:
+		if hasAttrs(n) {
+			return raw()
+		}
+		s, err := mdInlines(children(n))
+		if err != nil {
+			return err
+		}
+		sb.WriteString(s)
+	case atom.Code:
+		if hasAttrs(n) || n.FirstChild == nil {
+			return raw()
+		}
+
+		var code strings.Builder
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type != html.TextNode {
+				return raw()
+			}
+			code.WriteString(c.Data)
+		}
+		sb.WriteString(mdCodeSpan(code.String()))
+	case atom.A:
+		href := attr(n, "href")
+		if hasAttrs(n, "href", "title") || href == "" || strings.ContainsAny(href, "<>\n") {
+			return raw()
+		}
+
+		s, err := mdInlines(children(n))
+		if err != nil {
+			return err
+		}
+		if strings.Contains(s, "\n") {
+			return raw()
+		}
+		fmt.Fprintf(sb, "[%s](%s)", s, mdLinkDest(href, attr(n, "title")))
+	case atom.Img:
+		src := attr(n, "src")
+		if hasAttrs(n, "src", "alt", "title") || src == "" || strings.ContainsAny(src, "<>\n") {
+			return raw()
+		}
+		fmt.Fprintf(sb, "![%s](%s)", mdEscapeText(strings.Join(strings.Fields(attr(n, "alt")), " ")), mdLinkDest(src, attr(n, "title")))
+	default:
+		return raw()
+	}
+	return nil
+}
+
+// mdWrap writes the children of the node between the delimiters, the spaces are moved out of the delimiters.
+// If the content starts or ends with a punctuation, the delimiters may be not recognized by the neighbors,
+// so the raw tags are written.
+func mdWrap(sb *strings.Builder, n *html.Node, delim, tag string) error {
+	s, err := mdInlines(children(n))
+	if err != nil {
+		return err
+	}
+
+	t := strings.TrimSpace(s)
+	if t == "" {
+		sb.WriteString(s)
+		return nil
+	}
+
+	i := strings.Index(s, t)
+	lead, trail := s[:i], s[i+len(t):]
+
+	f, _ := utf8.DecodeRuneInString(t)
+	l, _ := utf8.DecodeLastRuneInString(t)
+	if unicode.IsPunct(f) || unicode.IsSymbol(f) || unicode.IsPunct(l) || unicode.IsSymbol(l) || strings.Contains(t, "\n") {
+		delim = "<" + tag + ">"
+		sb.WriteString(lead + delim + t + "</" + tag + ">" + trail)
+		return nil
+	}
+
+	sb.WriteString(lead + delim + t + delim + trail)
+	return nil
+}
+
+func mdCodeSpan(code string) string {
+	code = strings.ReplaceAll(code, "\n", " ")
+
+	ticks := "`"
+	for strings.Contains(code, ticks) {
+		ticks += "`"
+	}
+	if strings.HasPrefix(code, "`") || strings.HasSuffix(code, "`") || (strings.HasPrefix(code, " ") && strings.HasSuffix(code, " ") && strings.TrimSpace(code) != "") {
+		code = " " + code + " "
+	}
+	return ticks + code + ticks
+}
+
+func mdLinkDest(dest, title string) string {
+	if strings.ContainsAny(dest, " ()") {
+		dest = "<" + dest + ">"
+	}
+	if title != "" {
+		dest += ` "` + strings.ReplaceAll(title, `"`, `\"`) + `"`
+	}
+	return dest
+}
+
+// mdSplitSpace splits the text by the html white spaces, keeps the leading and trailing space as the empty strings.
+func mdSplitSpace(s string) []string {
+	ss := strings.FieldsFunc(s, mdIsSpace)
+	if len(ss) == 0 {
+		if s != "" {
+			return []string{"", ""} // a space
+		}
+		return nil
+	}
+
+	if mdIsSpace(rune(s[0])) {
+		ss = append([]string{""}, ss...)
+	}
+	if mdIsSpace(rune(s[len(s)-1])) {
+		ss = append(ss, "")
+	}
+	return ss
+}
+
+func mdIsSpace(r rune) bool {
+	return r == ' ' || r == '\t' || r == '\n' || r == '\r' || r == '\f'
+}
+
+var mdEntity = regexp.MustCompile(`^&(?:#[0-9]{1,7}|#[xX][0-9a-fA-F]{1,6}|[A-Za-z][A-Za-z0-9]{1,31});`)
+
+// mdEscapeText escapes the Markdown characters of the text.
+// The '_' between the letters or digits is not escaped, since it can not be a delimiter.
+func mdEscapeText(s string) string {
+	var sb strings.Builder
+	for i, r := range s {
+		switch r {
+		case '\\', '*', '`', '[', ']', '<':
+			sb.WriteByte('\\')
+		case '_':
+			p, _ := utf8.DecodeLastRuneInString(s[:i])
+			n, _ := utf8.DecodeRuneInString(s[i+1:])
+			if !isAlnum(p) || !isAlnum(n) {
+				sb.WriteByte('\\')
+			}
+		case '&':
+			if mdEntity.MatchString(s[i:]) {
+				sb.WriteByte('\\')
+			}
+		case '\u00a0':
+			sb.WriteString("&nbsp;")
+			continue
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+func isAlnum(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+var mdAttrEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;")
+
+// mdRenderer the CommonMark renderer of ToRemote, the raw HTML (e.g. the elements kept by ToFile) is rendered as is.
+var mdRenderer = goldmark.New(goldmark.WithRendererOptions(gmhtml.WithUnsafe()))
+
+// ToRemote converts the Markdown to HTML by the CommonMark renderer.
+func (Markdown) ToRemote(s string) (string, error) {
+	var sb strings.Builder
+	if err := mdRenderer.Convert([]byte(s), &sb); err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(sb.String(), "\n"), nil
+}
@@ -0,0 +1,86 @@
+package kbsync
+
+import (
+	"testing"
+)
+
+func TestMarkdownToFile(t *testing.T) {
+	cs := []struct {
+		h, w string
+	}{
+		{`plain text`, `plain text`},
+		{`<h2>Title</h2><p>Hello <b>world</b>, <i>it's</i> <code>a*b</code>.<br>next</p>`, "## Title\n\nHello **world**, *it's* `a*b`.\\\nnext"},
+		{`<p><a href="https://example.com/a_b" title="T">link</a> <img src="a b.png" alt="A"></p>`, `[link](https://example.com/a_b "T") ![A](<a b.png>)`},
+		{`<ul><li>one</li><li>two<ul><li>sub</li></ul></li></ul>`, "- one\n- two\n  - sub"},
+		{`<ol start="3"><li><p>a</p><p>b</p></li><li>c</li></ol>`, "3. a\n\n   b\n4. c"},
+		{"<pre><code class=\"language-go\">if a {\n\tb()\n}</code></pre>", "```go\nif a {\n\tb()\n}\n```"},
+		{`<blockquote><p>a</p><p>b</p></blockquote><hr>`, "> a\n>\n> b\n\n***"},
+		{`<p>1. # * _x_ snake_case &lt;b&gt; &amp;amp; &nbsp;</p>`, `1\. # \* \_x\_ snake_case \<b> \&amp; &nbsp;`},
+		{`<p style="color: red">red</p><table><tr><td>1</td></tr></table>`, "<p style=\"color: red\">red</p>\n\n<table><tbody><tr><td>1</td></tr></tbody></table>"},
+		{`<p><span style="color: red">a*b</span> <b>(x)</b>y</p>`, `<span style="color: red">a\*b</span> <strong>(x)</strong>y`},
+	}
+
+	for i, c := range cs {
+		a, err := Markdown{}.ToFile(c.h)
+		if err != nil {
+			t.Fatalf("[%d] ERROR: %v", i, err)
+		}
+		if a != c.w {
+			t.Errorf("[%d] ToFile(%q)\n got: %q\nwant: %q", i, c.h, a, c.w)
+		}
+	}
+}
+
+func TestMarkdownToRemote(t *testing.T) {
+	cs := []struct {
+		m, w string
+	}{
+		{"plain text", "<p>plain text</p>"},
+		{"# Title #\n\nHello **world**, _it's_ `a*b`.  \nnext", "<h1>Title</h1>\n<p>Hello <strong>world</strong>, <em>it's</em> <code>a*b</code>.<br>\nnext</p>"},
+		{`[link](<a b> "T") ![A \[1\]](p.png) <https://example.com>`, `<p><a href="a%20b" title="T">link</a> <img src="p.png" alt="A [1]"> <a href="https://example.com">https://example.com</a></p>`},
+		{"* one\n* two\n  - sub\n\n3) a\n4) b\n   c", "<ul>\n<li>one</li>\n<li>two\n<ul>\n<li>sub</li>\n</ul>\n</li>\n</ul>\n<ol start=\"3\">\n<li>a</li>\n<li>b\nc</li>\n</ol>"},
+		{"- a\n\n  b\n- c", "<ul>\n<li>\n<p>a</p>\n<p>b</p>\n</li>\n<li>\n<p>c</p>\n</li>\n</ul>"},
+		{"~~~\n<x> & y\n~~~", "<pre><code>&lt;x&gt; &amp; y\n</code></pre>"},
+		{"> a\n>\n> - b\n\n---", "<blockquote>\n<p>a</p>\n<ul>\n<li>b</li>\n</ul>\n</blockquote>\n<hr>"},
+		{"***a** b* and *a **b***", "<p><em><strong>a</strong> b</em> and <em>a <strong>b</strong></em></p>"},
+		{"<table>\n<tr><td>*x*</td></tr>\n</table>\n\n<span style=\"a\">*x*</span> a < b &amp; c & d", "<table>\n<tr><td>*x*</td></tr>\n</table>\n<p><span style=\"a\"><em>x</em></span> a &lt; b &amp; c &amp; d</p>"},
+		{"Title\n=====\n\nSub\n---", "<h1>Title</h1>\n<h2>Sub</h2>"},
+		{"text\n\n    <code>\n      indented", "<p>text</p>\n<pre><code>&lt;code&gt;\n  indented\n</code></pre>"},
+		{"[link][ref] and [ref]\n\n[ref]: https://example.com/ref \"Ref\"", `<p><a href="https://example.com/ref" title="Ref">link</a> and <a href="https://example.com/ref" title="Ref">ref</a></p>`},
+		{"> a\nlazy\n\nafter", "<blockquote>\n<p>a\nlazy</p>\n</blockquote>\n<p>after</p>"},
+	}
+
+	for i, c := range cs {
+		a, err := Markdown{}.ToRemote(c.m)
+		if err != nil {
+			t.Fatalf("[%d] ERROR: %v", i, err)
+		}
+		if a != c.w {
+			t.Errorf("[%d] ToRemote(%q)\n got: %q\nwant: %q", i, c.m, a, c.w)
+		}
+	}
+}
+
+func TestMarkdownRoundTrip(t *testing.T) {
+	h := `<h1>Title</h1><p>Hello <b>world</b> <a href="https://example.com" target="_blank">link</a><br>next</p>` +
+		`<ol><li>one<ul><li>sub <code>x</code></li></ul></li><li><p>two</p><pre><code>code</code></pre></li></ol>` +
+		`<blockquote><p>quote *</p></blockquote><div class="note"><p>note</p></div><p><em>a <strong>b</strong></em></p>`
+
+	md, err := Markdown{}.ToFile(h)
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+
+	h2, err := Markdown{}.ToRemote(md)
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+
+	md2, err := Markdown{}.ToFile(h2)
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	if md != md2 {
+		t.Errorf("ToFile(ToRemote(md)) =\n%s\nwant:\n%s", md2, md)
+	}
+}
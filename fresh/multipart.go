@@ -0,0 +1,214 @@
+package fresh
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+
+	"github.com/askasoft/pango/net/httpx"
+)
+
+// FileOpener is the interface implemented by the File which can be opened as a stream.
+type FileOpener interface {
+	Open() (io.ReadCloser, error)
+}
+
+// FileSizer is the interface implemented by the File which knows its size (-1 if unknown).
+type FileSizer interface {
+	FileSize() (int64, error)
+}
+
+// AttachmentsSizeError the total size of the attachments exceeds the limit of the api
+type AttachmentsSizeError struct {
+	Size  int64
+	Limit int64
+}
+
+func (ase *AttachmentsSizeError) Error() string {
+	return fmt.Sprintf("fresh: the total size of the attachments (%d bytes) exceeds the limit (%d bytes)", ase.Size, ase.Limit)
+}
+
+// FileSize returns the size of the file f, returns -1 if the size is unknown.
+func FileSize(f File) (int64, error) {
+	if fs, ok := f.(FileSizer); ok {
+		return fs.FileSize()
+	}
+	if data := f.Data(); data != nil {
+		return int64(len(data)), nil
+	}
+
+	fi, err := os.Stat(f.File())
+	if err != nil {
+		return 0, err
+	}
+	return fi.Size(), nil
+}
+
+// CheckFilesSize returns an *AttachmentsSizeError if the total size of the files exceeds the limit,
+// the files of unknown size are not counted.
+func CheckFilesSize(fs Files, limit int64) error {
+	var size int64
+	for _, f := range fs {
+		n, err := FileSize(f)
+		if err != nil {
+			return err
+		}
+		if n > 0 {
+			size += n
+		}
+	}
+
+	if size > limit {
+		return &AttachmentsSizeError{Size: size, Limit: limit}
+	}
+	return nil
+}
+
+func openFile(f File) (io.ReadCloser, error) {
+	if fo, ok := f.(FileOpener); ok {
+		return fo.Open()
+	}
+	if data := f.Data(); data != nil {
+		return io.NopCloser(bytes.NewReader(data)), nil
+	}
+	return os.Open(f.File())
+}
+
+func writeMultipartFile(mw *httpx.MultipartWriter, f File) error {
+	fw, err := mw.CreateFormFile(f.Field(), f.File())
+	if err != nil {
+		return err
+	}
+
+	r, err := openFile(f)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	_, err = io.Copy(fw, r)
+	return err
+}
+
+// MultipartBody a streaming multipart/form-data request body.
+// The body is written through an io.Pipe while the request is sent, the files are read from their sources
+// instead of being buffered in memory, and the body can be opened again (the files are reopened) for a retry.
+type MultipartBody struct {
+	Values Values
+	Files  Files
+
+	boundary string
+}
+
+// NewMultipartBody returns a MultipartBody of the values and files.
+func NewMultipartBody(vs Values, fs Files) *MultipartBody {
+	return &MultipartBody{
+		Values:   vs,
+		Files:    fs,
+		boundary: multipart.NewWriter(io.Discard).Boundary(),
+	}
+}
+
+func (mb *MultipartBody) newWriter(w io.Writer) *httpx.MultipartWriter {
+	mw := httpx.NewMultipartWriter(w)
+	_ = mw.SetBoundary(mb.boundary)
+	return mw
+}
+
+// ContentType returns the Content-Type of the body with the boundary.
+func (mb *MultipartBody) ContentType() string {
+	return mb.newWriter(io.Discard).FormDataContentType()
+}
+
+// ContentLength returns the length of the body, returns -1 if the size of a file is unknown.
+func (mb *MultipartBody) ContentLength() (int64, error) {
+	cw := &countWriter{}
+	mw := mb.newWriter(cw)
+
+	if err := addMultipartValues(mw, mb.Values); err != nil {
+		return 0, err
+	}
+
+	var size int64
+	for _, f := range mb.Files {
+		n, err := FileSize(f)
+		if err != nil {
+			return 0, err
+		}
+		if n < 0 {
+			return -1, nil
+		}
+		size += n
+
+		if _, err := mw.CreateFormFile(f.Field(), f.File()); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := mw.Close(); err != nil {
+		return 0, err
+	}
+	return cw.n + size, nil
+}
+
+// Open returns a reader of the body, the body is written by a goroutine.
+// The reader should be read to the end or closed.
+func (mb *MultipartBody) Open() io.ReadCloser {
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(mb.write(pw))
+	}()
+	return pr
+}
+
+func (mb *MultipartBody) write(w io.Writer) error {
+	mw := mb.newWriter(w)
+
+	if err := addMultipartValues(mw, mb.Values); err != nil {
+		return err
+	}
+	for _, f := range mb.Files {
+		if err := writeMultipartFile(mw, f); err != nil {
+			return err
+		}
+	}
+	return mw.Close()
+}
+
+// NewRequest returns a http request of the body, the GetBody of the request reopens the body.
+// The ContentLength of the request is set if the sizes of all files are known.
+func (mb *MultipartBody) NewRequest(ctx context.Context, method, url string) (*http.Request, error) {
+	size, err := mb.ContentLength()
+	if err != nil {
+		return nil, err
+	}
+
+	body := mb.Open()
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		body.Close()
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", mb.ContentType())
+	req.GetBody = func() (io.ReadCloser, error) {
+		return mb.Open(), nil
+	}
+	if size > 0 {
+		req.ContentLength = size
+	}
+	return req, nil
+}
+
+type countWriter struct {
+	n int64
+}
+
+func (cw *countWriter) Write(p []byte) (int, error) {
+	cw.n += int64(len(p))
+	return len(p), nil
+}
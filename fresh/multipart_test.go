@@ -0,0 +1,175 @@
+package fresh
+
+import (
+	"context"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+type testUpload struct {
+	Name        string
+	Attachments Attachments
+}
+
+func (tu *testUpload) Values() Values {
+	vs := Values{}
+	vs.SetString("name", tu.Name)
+	return vs
+}
+
+func (tu *testUpload) Files() Files {
+	return tu.Attachments.Files()
+}
+
+func readMultipart(t *testing.T, ct string, r io.Reader) map[string]string {
+	_, ps, err := mime.ParseMediaType(ct)
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+
+	parts := map[string]string{}
+	mr := multipart.NewReader(r, ps["boundary"])
+	for {
+		p, err := mr.NextPart()
+		if errors.Is(err, io.EOF) {
+			return parts
+		}
+		if err != nil {
+			t.Fatalf("ERROR: %v", err)
+		}
+
+		bs, err := io.ReadAll(p)
+		if err != nil {
+			t.Fatalf("ERROR: %v", err)
+		}
+
+		key := p.FormName()
+		if fn := p.FileName(); fn != "" {
+			key += ":" + fn
+		}
+		parts[key] = string(bs)
+	}
+}
+
+func TestMultipartBody(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "disk.txt")
+	if err := os.WriteFile(path, []byte("disk"), 0o660); err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+
+	fsys := fstest.MapFS{"dir/fs.txt": &fstest.MapFile{Data: []byte("file system")}}
+
+	mb := NewMultipartBody(Values{"name": {"test"}}, Files{
+		NewAttachment("data.txt", []byte("data")),
+		NewAttachment(path),
+		NewAttachmentFS(fsys, "dir/fs.txt"),
+		NewAttachmentReader("reader.txt", strings.NewReader("reader")),
+	})
+
+	size, err := mb.ContentLength()
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+
+	// the body can be read twice
+	for i := range 2 {
+		bs, err := io.ReadAll(mb.Open())
+		if err != nil {
+			t.Fatalf("ERROR: %v", err)
+		}
+		if int64(len(bs)) != size {
+			t.Errorf("[%d] len(body) = %d, ContentLength() = %d", i, len(bs), size)
+		}
+
+		parts := readMultipart(t, mb.ContentType(), strings.NewReader(string(bs)))
+		want := map[string]string{
+			"name":                     "test",
+			"attachments[]:data.txt":   "data",
+			"attachments[]:disk.txt":   "disk",
+			"attachments[]:fs.txt":     "file system",
+			"attachments[]:reader.txt": "reader",
+		}
+		if len(parts) != len(want) {
+			t.Fatalf("[%d] parts = %v, want %v", i, parts, want)
+		}
+		for k, v := range want {
+			if parts[k] != v {
+				t.Errorf("[%d] parts[%q] = %q, want %q", i, k, parts[k], v)
+			}
+		}
+	}
+
+	// unknown size
+	mb = NewMultipartBody(nil, Files{NewAttachmentReader("a.txt", io.MultiReader(strings.NewReader("a")))})
+	if size, err := mb.ContentLength(); err != nil || size != -1 {
+		t.Errorf("ContentLength() = %d, %v, want -1", size, err)
+	}
+}
+
+func TestMultipartRetry(t *testing.T) {
+	var bodies []map[string]string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		bodies = append(bodies, readMultipart(t, r.Header.Get("Content-Type"), r.Body))
+		if len(bodies) == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer ts.Close()
+
+	c := &Client{Domain: "example.freshdesk.com", APIKey: "k", BaseURL: ts.URL, Retryer: NewRetryer(time.Millisecond, 1, nil)}
+
+	tu := &testUpload{Name: "test", Attachments: Attachments{NewAttachmentReader("a.txt", strings.NewReader("seekable"))}}
+	if err := c.DoPost(context.Background(), c.Endpoint("/items"), tu, nil); err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	if len(bodies) != 2 {
+		t.Fatalf("requests = %d, want 2", len(bodies))
+	}
+	for i, b := range bodies {
+		if b["name"] != "test" || b["attachments[]:a.txt"] != "seekable" {
+			t.Errorf("[%d] body = %v", i, b)
+		}
+	}
+
+	// a non-seekable reader can not be replayed
+	bodies = nil
+	tu.Attachments = Attachments{NewAttachmentReader("a.txt", io.MultiReader(strings.NewReader("stream")))}
+	err := c.DoPost(context.Background(), c.Endpoint("/items"), tu, nil)
+	if !errors.Is(err, ErrNotReplayable) {
+		t.Fatalf("DoPost() = %v, want %v", err, ErrNotReplayable)
+	}
+	if len(bodies) != 1 || bodies[0]["attachments[]:a.txt"] != "stream" {
+		t.Errorf("bodies = %v", bodies)
+	}
+}
+
+func TestCheckFilesSize(t *testing.T) {
+	fs := Files{
+		NewAttachment("a.txt", make([]byte, 10)),
+		NewAttachmentReader("b.txt", strings.NewReader(strings.Repeat("b", 10))),
+		NewAttachmentReader("c.txt", io.MultiReader(strings.NewReader("unknown"))),
+	}
+
+	if err := CheckFilesSize(fs, 20); err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+
+	err := CheckFilesSize(fs, 19)
+	var ase *AttachmentsSizeError
+	if !errors.As(err, &ase) || ase.Size != 20 || ase.Limit != 19 {
+		t.Fatalf("CheckFilesSize() = %v", err)
+	}
+}
@@ -0,0 +1,200 @@
+package fresh
+
+import (
+	"context"
+	"iter"
+	"net/url"
+
+	"github.com/askasoft/pango/num"
+)
+
+// ListPageFunc lists a page of items by the list option,
+// returns the items and the URL of the next page (e.g. the rel="next" URL of the Link header), empty if it is the last page.
+type ListPageFunc[T any] func(ctx context.Context, lo ListOption) ([]T, string, error)
+
+// Page a page of items listed by a Paginator
+type Page[T any] struct {
+	// Number the page number
+	Number int
+
+	// Items the items of the page
+	Items []T
+
+	// Next the URL of the next page returned by the ListPageFunc, it can be saved to resume the iteration by PagesFrom/AllFrom.
+	// Empty if it is the last page.
+	Next string
+}
+
+// Paginator iterates all items of a list api page by page.
+// The list option passed to Iter/All is never modified, the page parameters are overridden on a copy of its values.
+// The next page URL returned by the ListPage is followed, the query parameters of the URL are the Values of the next list option.
+// If the MaxPage is reached and there is still a next page, a *PageLimitError is yielded, so the results are never truncated silently.
+type Paginator[T any] struct {
+	// ListPage lists a page of items, the list option should be passed to Client.DoListLink as it is.
+	ListPage ListPageFunc[T]
+
+	// PerPage the default per_page parameter if it is not specified by the list option, 0 means not to send.
+	PerPage int
+
+	// MaxPage the maximum page number that the api allows, 0 means no limit.
+	MaxPage int
+}
+
+// Iter calls fn for each item of all pages, stops if fn returns an error.
+func (p *Paginator[T]) Iter(ctx context.Context, lo ListOption, fn func(T) error) error {
+	return iterItems(p.All(ctx, lo), fn)
+}
+
+// IterFrom calls fn for each item of the pages starting from the next URL, stops if fn returns an error.
+func (p *Paginator[T]) IterFrom(ctx context.Context, next string, fn func(T) error) error {
+	return iterItems(p.AllFrom(ctx, next), fn)
+}
+
+// All returns an iterator over all items of all pages.
+// If a page request fails, the error is yielded with a zero item and the iteration stops.
+func (p *Paginator[T]) All(ctx context.Context, lo ListOption) iter.Seq2[T, error] {
+	return allItems(p.Pages(ctx, lo))
+}
+
+// AllFrom returns an iterator over the items of the pages starting from the next URL.
+func (p *Paginator[T]) AllFrom(ctx context.Context, next string) iter.Seq2[T, error] {
+	return allItems(p.PagesFrom(ctx, next))
+}
+
+// Pages returns an iterator over all pages.
+// If a page request fails, the error is yielded with a nil page and the iteration stops.
+func (p *Paginator[T]) Pages(ctx context.Context, lo ListOption) iter.Seq2[*Page[T], error] {
+	return func(yield func(*Page[T], error) bool) {
+		p.pages(ctx, newPageOption(lo, p.PerPage), yield)
+	}
+}
+
+// PagesFrom returns an iterator over the pages starting from the next URL (Page.Next).
+func (p *Paginator[T]) PagesFrom(ctx context.Context, next string) iter.Seq2[*Page[T], error] {
+	return func(yield func(*Page[T], error) bool) {
+		po, err := newLinkOption(next)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		p.pages(ctx, po, yield)
+	}
+}
+
+func (p *Paginator[T]) pages(ctx context.Context, po *pageOption, yield func(*Page[T], error) bool) {
+	for {
+		items, next, err := p.ListPage(ctx, po)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+
+		pg := &Page[T]{Number: po.page, Items: items, Next: next}
+		if !yield(pg, nil) {
+			return
+		}
+
+		if next == "" {
+			return
+		}
+
+		if p.MaxPage > 0 && po.page >= p.MaxPage {
+			yield(nil, &PageLimitError{MaxPage: p.MaxPage})
+			return
+		}
+
+		po.page++
+		po.link = next
+	}
+}
+
+func allItems[T any](pages iter.Seq2[*Page[T], error]) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for pg, err := range pages {
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+
+			for _, it := range pg.Items {
+				if !yield(it, nil) {
+					return
+				}
+			}
+		}
+	}
+}
+
+func iterItems[T any](items iter.Seq2[T, error], fn func(T) error) error {
+	for it, err := range items {
+		if err != nil {
+			return err
+		}
+		if err = fn(it); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// pageOption wraps a ListOption and overrides the page parameters.
+// If link is not empty, the Values are the query parameters of the link URL.
+type pageOption struct {
+	vs      Values
+	page    int
+	perPage int
+	link    string
+}
+
+func newPageOption(lo ListOption, perPage int) *pageOption {
+	vs := Values{}
+	if lo != nil && !lo.IsNil() {
+		for k, v := range lo.Values() {
+			vs[k] = v
+		}
+	}
+
+	po := &pageOption{vs: vs, page: num.Atoi(vs.Get("page")), perPage: perPage}
+	if po.page < 1 {
+		po.page = 1
+	}
+	return po
+}
+
+func newLinkOption(link string) (*pageOption, error) {
+	u, err := url.Parse(link)
+	if err != nil {
+		return nil, err
+	}
+
+	po := &pageOption{vs: Values{}, page: num.Atoi(u.Query().Get("page")), link: link}
+	if po.page < 1 {
+		po.page = 1
+	}
+	return po, nil
+}
+
+func (po *pageOption) IsNil() bool {
+	return po == nil
+}
+
+// Values returns the query parameters of the page.
+func (po *pageOption) Values() Values {
+	if po.link != "" {
+		if u, err := url.Parse(po.link); err == nil {
+			return Values(u.Query())
+		}
+	}
+
+	q := Values{}
+	for k, v := range po.vs {
+		q[k] = v
+	}
+
+	q.SetInt("page", po.page)
+	if !q.Has("per_page") {
+		q.SetInt("per_page", po.perPage)
+	}
+	return q
+}
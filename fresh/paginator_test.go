@@ -0,0 +1,164 @@
+package fresh
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+func testPaginatorServer(total int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		page, _ := strconv.Atoi(q.Get("page"))
+		perPage, _ := strconv.Atoi(q.Get("per_page"))
+
+		items := []int{}
+		for i := (page - 1) * perPage; i < page*perPage && i < total; i++ {
+			items = append(items, i)
+		}
+		if page*perPage < total {
+			q.Set("page", strconv.Itoa(page+1))
+			w.Header().Set(HeaderLink, `<http://`+r.Host+r.URL.Path+`?`+q.Encode()+`>; rel="next"`)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(items)
+	}))
+}
+
+func testPaginator(c *Client, url string, maxPage int) *Paginator[int] {
+	lp := func(ctx context.Context, lo ListOption) ([]int, string, error) {
+		items := []int{}
+		next, err := c.DoListLink(ctx, url, lo, &items)
+		return items, next, err
+	}
+	return &Paginator[int]{ListPage: lp, PerPage: 10, MaxPage: maxPage}
+}
+
+func TestPaginatorAll(t *testing.T) {
+	ts := testPaginatorServer(25)
+	defer ts.Close()
+
+	c := &Client{APIKey: "x"}
+	lo := &PageOption{PerPage: 3}
+	p := testPaginator(c, ts.URL, 0)
+
+	n := 0
+	for it, err := range p.All(context.Background(), lo) {
+		if err != nil {
+			t.Fatalf("ERROR: %v", err)
+		}
+		if it != n {
+			t.Fatalf("item = %d, want %d", it, n)
+		}
+		n++
+	}
+	if n != 25 {
+		t.Errorf("iterated %d, want %d", n, 25)
+	}
+	if lo.Page != 0 || lo.PerPage != 3 {
+		t.Errorf("list option modified: %v", lo)
+	}
+}
+
+func TestPaginatorIterMaxPage(t *testing.T) {
+	ts := testPaginatorServer(100)
+	defer ts.Close()
+
+	c := &Client{APIKey: "x"}
+	p := testPaginator(c, ts.URL, 3)
+
+	n := 0
+	err := p.Iter(context.Background(), nil, func(int) error {
+		n++
+		return nil
+	})
+	var ple *PageLimitError
+	if !errors.As(err, &ple) || ple.MaxPage != 3 {
+		t.Fatalf("Iter() = %v, want *PageLimitError", err)
+	}
+	if n != 30 {
+		t.Errorf("iterated %d, want %d", n, 30)
+	}
+}
+
+func TestPaginatorStop(t *testing.T) {
+	ts := testPaginatorServer(100)
+	defer ts.Close()
+
+	c := &Client{APIKey: "x"}
+	p := testPaginator(c, ts.URL, 0)
+
+	n := 0
+	for _, err := range p.All(context.Background(), nil) {
+		if err != nil {
+			t.Fatalf("ERROR: %v", err)
+		}
+		if n++; n == 15 {
+			break
+		}
+	}
+	if n != 15 {
+		t.Errorf("iterated %d, want %d", n, 15)
+	}
+
+	errStop := errors.New("stop")
+	err := p.Iter(context.Background(), nil, func(it int) error {
+		if it == 5 {
+			return errStop
+		}
+		return nil
+	})
+	if !errors.Is(err, errStop) {
+		t.Errorf("Iter() = %v, want %v", err, errStop)
+	}
+}
+
+func TestPaginatorFollowLink(t *testing.T) {
+	// a server which paginates by a cursor parameter of the Link header
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cursor, _ := strconv.Atoi(r.URL.Query().Get("cursor"))
+
+		items := []int{cursor, cursor + 1}
+		if cursor < 8 {
+			w.Header().Set(HeaderLink, `<http://`+r.Host+r.URL.Path+`?cursor=`+strconv.Itoa(cursor+2)+`>; rel="next"`)
+		}
+		_ = json.NewEncoder(w).Encode(items)
+	}))
+	defer ts.Close()
+
+	c := &Client{APIKey: "x"}
+	p := testPaginator(c, ts.URL, 0)
+
+	var pages []*Page[int]
+	for pg, err := range p.Pages(context.Background(), nil) {
+		if err != nil {
+			t.Fatalf("ERROR: %v", err)
+		}
+		pages = append(pages, pg)
+	}
+	if len(pages) != 5 {
+		t.Fatalf("pages = %d, want %d", len(pages), 5)
+	}
+	if pages[4].Next != "" || pages[4].Items[1] != 9 {
+		t.Errorf("last page = %v", pages[4])
+	}
+
+	n := 0
+	err := p.IterFrom(context.Background(), pages[1].Next, func(it int) error {
+		if it != n+4 {
+			t.Fatalf("item = %d, want %d", it, n+4)
+		}
+		n++
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	if n != 6 {
+		t.Errorf("iterated %d, want %d", n, 6)
+	}
+}
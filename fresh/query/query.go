@@ -0,0 +1,339 @@
+// Package query builds the filter queries of the Freshdesk/Freshservice search apis.
+//
+// Example:
+//
+//	q, err := query.Build(query.And(
+//		query.Or(query.Eq(query.Number("priority"), 3), query.Eq(query.Number("priority"), 4)),
+//		query.Gte(query.Date("created_at"), time.Now().AddDate(0, -1, 0)),
+//		query.IsNull(query.Number("agent_id")),
+//	))
+//	// (priority:3 OR priority:4) AND created_at:>'2024-01-01' AND agent_id:null
+package query
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/askasoft/gofresh/fresh"
+)
+
+// MaxLength the maximum length of a query string (without the enclosing double quotes)
+const MaxLength = 512
+
+var (
+	ErrEmptyQuery    = errors.New("query: empty query")
+	ErrQueryTooLong  = fmt.Errorf("query: the query exceeds %d characters", MaxLength)
+	ErrInvalidField  = errors.New("query: invalid field")
+	ErrInvalidOp     = errors.New("query: invalid operator")
+	ErrInvalidValue  = errors.New("query: invalid value")
+	ErrEmptyCombined = errors.New("query: no conditions to combine")
+)
+
+// FieldType the type of a filter field, which determines the operators and the value format.
+type FieldType int
+
+const (
+	TypeNumber  FieldType = iota + 1 // number or integer, supports :, :>, :<
+	TypeString                       // string, enclosed in single quotes, supports :
+	TypeBoolean                      // boolean, true or false, supports :
+	TypeDate                         // date (YYYY-MM-DD in UTC), enclosed in single quotes, supports :, :>, :<
+)
+
+func (ft FieldType) String() string {
+	switch ft {
+	case TypeNumber:
+		return "number"
+	case TypeString:
+		return "string"
+	case TypeBoolean:
+		return "boolean"
+	case TypeDate:
+		return "date"
+	default:
+		return strconv.Itoa(int(ft))
+	}
+}
+
+// Field a filter field with the name and type
+type Field struct {
+	Name string
+	Type FieldType
+}
+
+// Number returns a number field.
+func Number(name string) Field {
+	return Field{Name: name, Type: TypeNumber}
+}
+
+// String returns a string field.
+func String(name string) Field {
+	return Field{Name: name, Type: TypeString}
+}
+
+// Boolean returns a boolean field.
+func Boolean(name string) Field {
+	return Field{Name: name, Type: TypeBoolean}
+}
+
+// Date returns a date field.
+func Date(name string) Field {
+	return Field{Name: name, Type: TypeDate}
+}
+
+// Custom returns a custom field, the "cf_" prefix of the name is removed,
+// because the search apis use the name of the custom field without the prefix.
+func Custom(name string, ft FieldType) Field {
+	return Field{Name: strings.TrimPrefix(name, "cf_"), Type: ft}
+}
+
+func (f Field) String() string {
+	return f.Name + "(" + f.Type.String() + ")"
+}
+
+// Cond a condition of the filter query
+type Cond interface {
+	build(sb *strings.Builder) error
+}
+
+// Build validates the condition and returns the query string.
+func Build(c Cond) (string, error) {
+	if c == nil {
+		return "", ErrEmptyQuery
+	}
+
+	sb := &strings.Builder{}
+	if err := c.build(sb); err != nil {
+		return "", err
+	}
+
+	q := sb.String()
+	if q == "" {
+		return "", ErrEmptyQuery
+	}
+	if utf8.RuneCountInString(q) > MaxLength {
+		return "", ErrQueryTooLong
+	}
+	return q, nil
+}
+
+// MustBuild is like Build but panics if the condition is invalid.
+func MustBuild(c Cond) string {
+	q, err := Build(c)
+	if err != nil {
+		panic(err)
+	}
+	return q
+}
+
+type compare struct {
+	field Field
+	op    string
+	value any
+}
+
+// Eq returns the condition "field:value".
+func Eq(f Field, v any) Cond {
+	return &compare{f, ":", v}
+}
+
+// Gte returns the condition "field:>value" (greater than or equal to), only for number and date fields.
+func Gte(f Field, v any) Cond {
+	return &compare{f, ":>", v}
+}
+
+// Lte returns the condition "field:<value" (less than or equal to), only for number and date fields.
+func Lte(f Field, v any) Cond {
+	return &compare{f, ":<", v}
+}
+
+// Between returns the condition "field:>from AND field:<to", only for number and date fields.
+func Between(f Field, from, to any) Cond {
+	return And(Gte(f, from), Lte(f, to))
+}
+
+// In returns the condition "field:v1 OR field:v2 ...", matches any of the values.
+func In[T any](f Field, vs ...T) Cond {
+	cs := make([]Cond, len(vs))
+	for i, v := range vs {
+		cs[i] = Eq(f, v)
+	}
+	return Or(cs...)
+}
+
+// IsNull returns the condition "field:null", matches the field with no value assigned.
+func IsNull(f Field) Cond {
+	return &compare{f, ":", nil}
+}
+
+func (c *compare) build(sb *strings.Builder) error {
+	if !isFieldName(c.field.Name) {
+		return fmt.Errorf("%w %q", ErrInvalidField, c.field.Name)
+	}
+
+	if c.op != ":" && c.field.Type != TypeNumber && c.field.Type != TypeDate {
+		return fmt.Errorf("%w %q for the %s field %q", ErrInvalidOp, c.op, c.field.Type, c.field.Name)
+	}
+
+	v, err := formatValue(c.field.Type, c.value)
+	if err != nil {
+		return fmt.Errorf("%w for the %s field %q: %v", ErrInvalidValue, c.field.Type, c.field.Name, err)
+	}
+
+	sb.WriteString(c.field.Name)
+	sb.WriteString(c.op)
+	sb.WriteString(v)
+	return nil
+}
+
+type combine struct {
+	op    string
+	conds []Cond
+}
+
+// And returns the condition that all the conditions are matched.
+func And(cs ...Cond) Cond {
+	return &combine{" AND ", cs}
+}
+
+// Or returns the condition that any of the conditions is matched.
+func Or(cs ...Cond) Cond {
+	return &combine{" OR ", cs}
+}
+
+func (c *combine) build(sb *strings.Builder) error {
+	if len(c.conds) == 0 {
+		return ErrEmptyCombined
+	}
+
+	for i, cc := range c.conds {
+		if cc == nil {
+			return ErrEmptyCombined
+		}
+
+		if i > 0 {
+			sb.WriteString(c.op)
+		}
+
+		if cb, ok := cc.(*combine); ok && len(cb.conds) > 1 {
+			sb.WriteByte('(')
+			if err := cb.build(sb); err != nil {
+				return err
+			}
+			sb.WriteByte(')')
+			continue
+		}
+
+		if err := cc.build(sb); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func isFieldName(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, c := range s {
+		if !(c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')) {
+			return false
+		}
+	}
+	return true
+}
+
+func formatValue(ft FieldType, v any) (string, error) {
+	if v == nil {
+		return "null", nil
+	}
+
+	switch ft {
+	case TypeNumber:
+		return formatNumber(v)
+	case TypeString:
+		if s, ok := v.(string); ok {
+			return Quote(s), nil
+		}
+		if s, ok := v.(fmt.Stringer); ok {
+			return Quote(s.String()), nil
+		}
+	case TypeBoolean:
+		if b, ok := v.(bool); ok {
+			return strconv.FormatBool(b), nil
+		}
+	case TypeDate:
+		return formatDate(v)
+	default:
+		return "", fmt.Errorf("unknown field type %d", ft)
+	}
+	return "", fmt.Errorf("unsupported value %v (%T)", v, v)
+}
+
+func formatNumber(v any) (string, error) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(rv.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(rv.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(rv.Float(), 'f', -1, 64), nil
+	default:
+		return "", fmt.Errorf("unsupported value %v (%T)", v, v)
+	}
+}
+
+func formatDate(v any) (string, error) {
+	var t time.Time
+
+	switch d := v.(type) {
+	case time.Time:
+		t = d
+	case *time.Time:
+		t = *d
+	case fresh.Date:
+		t = d.Time
+	case *fresh.Date:
+		t = d.Time
+	case fresh.Time:
+		t = d.Time
+	case *fresh.Time:
+		t = d.Time
+	case string:
+		fd, err := fresh.ParseDate(d)
+		if err != nil {
+			return "", err
+		}
+		t = fd.Time
+	default:
+		return "", fmt.Errorf("unsupported value %v (%T)", v, v)
+	}
+
+	if t.IsZero() {
+		return "", errors.New("zero date")
+	}
+	return "'" + t.UTC().Format(fresh.DateFormat) + "'", nil
+}
+
+// Quote returns the single-quoted string s,
+// the backslashes, single quotes and double quotes in s are escaped by a backslash.
+func Quote(s string) string {
+	sb := &strings.Builder{}
+	sb.Grow(len(s) + 2)
+
+	sb.WriteByte('\'')
+	for _, c := range s {
+		switch c {
+		case '\\', '\'', '"':
+			sb.WriteByte('\\')
+		}
+		sb.WriteRune(c)
+	}
+	sb.WriteByte('\'')
+	return sb.String()
+}
@@ -0,0 +1,77 @@
+package query
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/askasoft/gofresh/fresh"
+)
+
+type testStatus int
+
+func TestBuild(t *testing.T) {
+	d := time.Date(2024, 1, 2, 23, 0, 0, 0, time.UTC)
+
+	cs := []struct {
+		c Cond
+		w string
+	}{
+		{Eq(Number("agent_id"), 123), "agent_id:123"},
+		{Eq(String("tag"), "it's"), `tag:'it\'s'`},
+		{Eq(Boolean("vip"), true), "vip:true"},
+		{Gte(Date("created_at"), d), "created_at:>'2024-01-02'"},
+		{Lte(Date("due_by"), fresh.Date{Time: d}), "due_by:<'2024-01-02'"},
+		{Eq(Date("updated_at"), "2024-01-02"), "updated_at:'2024-01-02'"},
+		{IsNull(Number("group_id")), "group_id:null"},
+		{Eq(Custom("cf_level", TypeNumber), 2.5), "level:2.5"},
+		{In(Number("status"), testStatus(2), testStatus(3)), "status:2 OR status:3"},
+		{In(Number("status"), testStatus(2)), "status:2"},
+		{
+			And(In(Number("priority"), 3, 4), Between(Date("created_at"), d, d), IsNull(Number("agent_id"))),
+			"(priority:3 OR priority:4) AND (created_at:>'2024-01-02' AND created_at:<'2024-01-02') AND agent_id:null",
+		},
+	}
+
+	for i, c := range cs {
+		a, err := Build(c.c)
+		if err != nil {
+			t.Errorf("[%d] Build() = %v", i, err)
+			continue
+		}
+		if a != c.w {
+			t.Errorf("[%d] Build() = %q, want %q", i, a, c.w)
+		}
+	}
+}
+
+func TestBuildErrors(t *testing.T) {
+	cs := []struct {
+		c Cond
+		w error
+	}{
+		{nil, ErrEmptyQuery},
+		{And(), ErrEmptyCombined},
+		{Eq(Number("a b"), 1), ErrInvalidField},
+		{Gte(String("tag"), "a"), ErrInvalidOp},
+		{Lte(Boolean("vip"), true), ErrInvalidOp},
+		{Eq(Number("agent_id"), "1"), ErrInvalidValue},
+		{Eq(Boolean("vip"), 1), ErrInvalidValue},
+		{Gte(Date("created_at"), "2024/01/02"), ErrInvalidValue},
+		{Gte(Date("created_at"), time.Time{}), ErrInvalidValue},
+		{Eq(String("tag"), strings.Repeat("x", MaxLength)), ErrQueryTooLong},
+	}
+
+	for i, c := range cs {
+		if _, err := Build(c.c); !errors.Is(err, c.w) {
+			t.Errorf("[%d] Build() = %v, want %v", i, err, c.w)
+		}
+	}
+}
+
+func TestQuote(t *testing.T) {
+	if a, w := Quote(`a'b"c\d`), `'a\'b\"c\\d'`; a != w {
+		t.Errorf("Quote() = %q, want %q", a, w)
+	}
+}
@@ -0,0 +1,177 @@
+package fresh
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/askasoft/pango/num"
+)
+
+const (
+	HeaderRateLimitTotal     = "X-RateLimit-Total"
+	HeaderRateLimitRemaining = "X-RateLimit-Remaining"
+	HeaderRateLimitUsed      = "X-RateLimit-Used-CurrentRequest"
+	HeaderRetryAfter         = "Retry-After"
+)
+
+// RateLimit the rate limit information of a api response
+type RateLimit struct {
+	Total     int // X-RateLimit-Total: total number of api calls allowed per minute
+	Remaining int // X-RateLimit-Remaining: the number of requests remaining in the current rate limit window
+	Used      int // X-RateLimit-Used-CurrentRequest: the number of api credits consumed by the current request
+}
+
+// ParseRateLimit parse the X-RateLimit-* headers, returns nil if the headers are not present.
+func ParseRateLimit(h http.Header) *RateLimit {
+	st := h.Get(HeaderRateLimitTotal)
+	sr := h.Get(HeaderRateLimitRemaining)
+	if st == "" && sr == "" {
+		return nil
+	}
+
+	return &RateLimit{
+		Total:     num.Atoi(st),
+		Remaining: num.Atoi(sr),
+		Used:      num.Atoi(h.Get(HeaderRateLimitUsed)),
+	}
+}
+
+// parseRetryAfter parse the Retry-After header (seconds)
+func parseRetryAfter(h http.Header) time.Duration {
+	n := num.Atoi(h.Get(HeaderRetryAfter))
+	if n > 0 {
+		return time.Second * time.Duration(n)
+	}
+	return 0
+}
+
+// RateLimiter throttles the api calls by the X-RateLimit-* headers of the responses.
+// While the remaining credits are above the reserve, the calls are not delayed.
+// Once the remaining credits fall to the reserve, the calls are paced evenly over the window (Window / Total).
+// After a 429 response, all calls are blocked until the Retry-After duration passed.
+// A RateLimiter is safe for concurrent use, and should be shared by all clients of the same domain.
+type RateLimiter struct {
+	// Window the rate limit window, default is 1 minute.
+	Window time.Duration
+
+	// Reserve the number of credits to keep in reserve, default is 10% of the total.
+	Reserve int
+
+	mu        sync.Mutex
+	total     int
+	remaining int
+	next      time.Time // the next available slot for paced calls
+	resume    time.Time // calls are blocked until this time (429)
+}
+
+func (rl *RateLimiter) window() time.Duration {
+	if rl.Window > 0 {
+		return rl.Window
+	}
+	return time.Minute
+}
+
+func (rl *RateLimiter) reserve() int {
+	if rl.Reserve > 0 {
+		return rl.Reserve
+	}
+	return rl.total / 10
+}
+
+// RateLimit returns the last known rate limit status.
+func (rl *RateLimiter) RateLimit() RateLimit {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	return RateLimit{Total: rl.total, Remaining: rl.remaining}
+}
+
+// Wait blocks until a api call is allowed, or the ctx is done.
+func (rl *RateLimiter) Wait(ctx context.Context) error {
+	now := time.Now()
+
+	d := rl.delay(now)
+	if d <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// delay reserves a call slot and returns the duration to wait.
+func (rl *RateLimiter) delay(now time.Time) time.Duration {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	at := now
+	if at.Before(rl.resume) {
+		at = rl.resume
+	}
+
+	if rl.total > 0 && rl.remaining <= rl.reserve() {
+		if at.Before(rl.next) {
+			at = rl.next
+		}
+		rl.next = at.Add(rl.window() / time.Duration(rl.total))
+	}
+
+	if rl.remaining > 0 {
+		rl.remaining--
+	}
+
+	return at.Sub(now)
+}
+
+// Update updates the rate limit status by the response.
+func (rl *RateLimiter) Update(res *http.Response) {
+	rl.update(time.Now(), res)
+}
+
+func (rl *RateLimiter) update(now time.Time, res *http.Response) {
+	rt := ParseRateLimit(res.Header)
+
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	if rt != nil {
+		rl.total, rl.remaining = rt.Total, rt.Remaining
+	}
+
+	if res.StatusCode == http.StatusTooManyRequests {
+		rl.remaining = 0
+
+		ra := parseRetryAfter(res.Header)
+		if ra <= 0 {
+			ra = rl.window()
+		}
+
+		if resume := now.Add(ra); resume.After(rl.resume) {
+			rl.resume = resume
+		}
+		if rl.next.Before(rl.resume) {
+			rl.next = rl.resume
+		}
+	}
+}
+
+var rateLimiters sync.Map
+
+// DomainRateLimiter returns the RateLimiter shared by all clients of the domain.
+func DomainRateLimiter(domain string) *RateLimiter {
+	if rl, ok := rateLimiters.Load(domain); ok {
+		return rl.(*RateLimiter)
+	}
+
+	rl, _ := rateLimiters.LoadOrStore(domain, &RateLimiter{})
+	return rl.(*RateLimiter)
+}
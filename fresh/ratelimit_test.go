@@ -0,0 +1,70 @@
+package fresh
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func testRateLimitResponse(status, total, remaining int, retryAfter string) *http.Response {
+	h := http.Header{}
+	h.Set(HeaderRateLimitTotal, strconv.Itoa(total))
+	h.Set(HeaderRateLimitRemaining, strconv.Itoa(remaining))
+	h.Set(HeaderRateLimitUsed, "1")
+	if retryAfter != "" {
+		h.Set(HeaderRetryAfter, retryAfter)
+	}
+	return &http.Response{StatusCode: status, Header: h}
+}
+
+func TestParseRateLimit(t *testing.T) {
+	if rl := ParseRateLimit(http.Header{}); rl != nil {
+		t.Errorf("ParseRateLimit(empty) = %v, want nil", rl)
+	}
+
+	res := testRateLimitResponse(http.StatusOK, 100, 50, "")
+	rl := ParseRateLimit(res.Header)
+	w := RateLimit{Total: 100, Remaining: 50, Used: 1}
+	if rl == nil || *rl != w {
+		t.Errorf("ParseRateLimit() = %v, want %v", rl, w)
+	}
+}
+
+func TestRateLimiterPacing(t *testing.T) {
+	now := time.Now()
+
+	rl := &RateLimiter{Reserve: 5}
+	if d := rl.delay(now); d != 0 {
+		t.Fatalf("delay() = %v, want 0", d)
+	}
+
+	rl.update(now, testRateLimitResponse(http.StatusOK, 60, 10, ""))
+	for i := 0; i < 5; i++ {
+		if d := rl.delay(now); d != 0 {
+			t.Fatalf("[%d] delay() = %v, want 0", i, d)
+		}
+	}
+
+	for i := 0; i < 3; i++ {
+		w := time.Second * time.Duration(i)
+		if d := rl.delay(now); d != w {
+			t.Fatalf("[%d] delay() = %v, want %v", i, d, w)
+		}
+	}
+}
+
+func TestRateLimiterTooManyRequests(t *testing.T) {
+	now := time.Now()
+
+	rl := &RateLimiter{}
+	rl.update(now, testRateLimitResponse(http.StatusTooManyRequests, 60, 0, "30"))
+
+	w := time.Second * 30
+	if d := rl.delay(now); d != w {
+		t.Fatalf("delay() = %v, want %v", d, w)
+	}
+	if d := rl.delay(now); d != w+time.Second {
+		t.Fatalf("delay() = %v, want %v", d, w+time.Second)
+	}
+}
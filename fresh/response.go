@@ -0,0 +1,152 @@
+package fresh
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+const (
+	HeaderLink      = "Link"
+	HeaderRequestID = "X-Request-Id"
+)
+
+// ResponseInfo the metadata of a api response
+type ResponseInfo struct {
+	Method     string     // http request method
+	URL        string     // http request URL
+	StatusCode int        // http status code
+	Status     string     // http status
+	RequestID  string     // X-Request-Id header
+	RateLimit  *RateLimit // X-RateLimit-* headers, nil if not present
+	NextURL    string     // the rel="next" URL of the Link header
+}
+
+func NewResponseInfo(res *http.Response) *ResponseInfo {
+	ri := &ResponseInfo{
+		StatusCode: res.StatusCode,
+		Status:     res.Status,
+		RequestID:  res.Header.Get(HeaderRequestID),
+		RateLimit:  ParseRateLimit(res.Header),
+		NextURL:    ParseLinkNext(res.Header.Get(HeaderLink)),
+	}
+	if req := res.Request; req != nil {
+		ri.Method = req.Method
+		ri.URL = req.URL.String()
+	}
+	return ri
+}
+
+// CreditsUsed returns the api credits consumed by the request.
+func (ri *ResponseInfo) CreditsUsed() int {
+	if ri.RateLimit != nil {
+		return ri.RateLimit.Used
+	}
+	return 0
+}
+
+func (ri *ResponseInfo) String() string {
+	return toString(ri)
+}
+
+// ResponseCollector collects the ResponseInfo of all api calls (including retries) made with the context.
+// A ResponseCollector is safe for concurrent use.
+type ResponseCollector struct {
+	mu    sync.Mutex
+	infos []*ResponseInfo
+}
+
+// Add adds the ResponseInfo to the collector.
+func (rc *ResponseCollector) Add(ri *ResponseInfo) {
+	rc.mu.Lock()
+	rc.infos = append(rc.infos, ri)
+	rc.mu.Unlock()
+}
+
+// Responses returns all the collected ResponseInfo.
+func (rc *ResponseCollector) Responses() []*ResponseInfo {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	ris := make([]*ResponseInfo, len(rc.infos))
+	copy(ris, rc.infos)
+	return ris
+}
+
+// Last returns the last collected ResponseInfo, returns nil if nothing collected.
+func (rc *ResponseCollector) Last() *ResponseInfo {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	if n := len(rc.infos); n > 0 {
+		return rc.infos[n-1]
+	}
+	return nil
+}
+
+// CreditsUsed returns the total api credits consumed by the collected responses.
+func (rc *ResponseCollector) CreditsUsed() int {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	n := 0
+	for _, ri := range rc.infos {
+		n += ri.CreditsUsed()
+	}
+	return n
+}
+
+// Reset clears the collected ResponseInfo.
+func (rc *ResponseCollector) Reset() {
+	rc.mu.Lock()
+	rc.infos = nil
+	rc.mu.Unlock()
+}
+
+type responseCollectorKey struct{}
+
+// WithResponseCollector returns a copy of ctx which carries the ResponseCollector rc.
+// The api calls made with the returned context add their ResponseInfo to rc.
+func WithResponseCollector(ctx context.Context, rc *ResponseCollector) context.Context {
+	return context.WithValue(ctx, responseCollectorKey{}, rc)
+}
+
+// GetResponseCollector returns the ResponseCollector carried by ctx, returns nil if not found.
+func GetResponseCollector(ctx context.Context) *ResponseCollector {
+	rc, _ := ctx.Value(responseCollectorKey{}).(*ResponseCollector)
+	return rc
+}
+
+func collectResponse(res *http.Response) {
+	if req := res.Request; req != nil {
+		if rc := GetResponseCollector(req.Context()); rc != nil {
+			rc.Add(NewResponseInfo(res))
+		}
+	}
+}
+
+// ParseLinkNext returns the rel="next" URL of the Link header value.
+// Example: `<https://domain.freshdesk.com/api/v2/tickets?page=2>; rel="next"`
+func ParseLinkNext(link string) string {
+	for _, s := range strings.Split(link, ",") {
+		ss := strings.Split(s, ";")
+
+		u := strings.TrimSpace(ss[0])
+		if len(u) < 2 || u[0] != '<' || u[len(u)-1] != '>' {
+			continue
+		}
+
+		for _, p := range ss[1:] {
+			k, v, ok := strings.Cut(strings.TrimSpace(p), "=")
+			if ok && strings.EqualFold(strings.TrimSpace(k), "rel") {
+				for _, r := range strings.Fields(strings.Trim(strings.TrimSpace(v), `"`)) {
+					if strings.EqualFold(r, "next") {
+						return u[1 : len(u)-1]
+					}
+				}
+			}
+		}
+	}
+	return ""
+}
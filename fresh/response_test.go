@@ -0,0 +1,65 @@
+package fresh
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestParseLinkNext(t *testing.T) {
+	cs := []struct {
+		s string
+		w string
+	}{
+		{"", ""},
+		{`<https://example.freshdesk.com/api/v2/tickets?page=2>; rel="next"`, "https://example.freshdesk.com/api/v2/tickets?page=2"},
+		{`<https://a/prev>; rel="prev", <https://a/next?page=3&per_page=100>; rel="next"`, "https://a/next?page=3&per_page=100"},
+		{`<https://a/prev>; rel="prev"`, ""},
+		{`https://a/next; rel="next"`, ""},
+	}
+
+	for i, c := range cs {
+		a := ParseLinkNext(c.s)
+		if a != c.w {
+			t.Errorf("[%d] ParseLinkNext(%q) = %q, want %q", i, c.s, a, c.w)
+		}
+	}
+}
+
+func TestResponseCollector(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(HeaderRequestID, "req-1")
+		w.Header().Set(HeaderRateLimitTotal, "100")
+		w.Header().Set(HeaderRateLimitRemaining, "97")
+		w.Header().Set(HeaderRateLimitUsed, "3")
+		w.Header().Set(HeaderLink, `<http://`+r.Host+`/items?page=2>; rel="next"`)
+		w.Write([]byte(`[]`))
+	}))
+	defer ts.Close()
+
+	c := &Client{APIKey: "x"}
+
+	rc := &ResponseCollector{}
+	ctx := WithResponseCollector(context.Background(), rc)
+
+	var result []any
+	next, err := c.DoList(ctx, ts.URL+"/items", nil, &result)
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	if !next {
+		t.Errorf("next = %v, want true", next)
+	}
+
+	ri := rc.Last()
+	if ri == nil {
+		t.Fatal("ResponseCollector.Last() = nil")
+	}
+	if ri.StatusCode != http.StatusOK || ri.RequestID != "req-1" || ri.NextURL != ts.URL+"/items?page=2" || ri.CreditsUsed() != 3 {
+		t.Errorf("ResponseInfo = %v", ri)
+	}
+	if n := rc.CreditsUsed(); n != 3 {
+		t.Errorf("CreditsUsed() = %d, want %d", n, 3)
+	}
+}
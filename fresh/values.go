@@ -178,6 +178,13 @@ func (vs Values) SetInt(name string, value int) {
 	}
 }
 
+// SetIntPtr sets the value if it is not nil, the zero value is set too.
+func (vs Values) SetIntPtr(name string, value *int) {
+	if value != nil {
+		vs.Set(name, num.Itoa(*value))
+	}
+}
+
 func (vs Values) SetInt64(name string, value int64) {
 	if value != 0 {
 		vs.Set(name, num.Ltoa(value))
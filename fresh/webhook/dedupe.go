@@ -0,0 +1,89 @@
+package webhook
+
+import (
+	"sync"
+	"time"
+)
+
+// DefaultDedupeTTL the default TTL of the claimed keys of the MemoryDeduper.
+const DefaultDedupeTTL = 24 * time.Hour
+
+// Deduper dedupes the webhooks which are delivered more than once.
+type Deduper interface {
+	// Claim claims the key of a webhook, returns false if the key is already claimed.
+	Claim(key string) bool
+
+	// Release releases the claimed key, so that the retried webhook of the key can be processed again.
+	Release(key string)
+}
+
+// MemoryDeduper an in-memory Deduper, the claimed keys expire after the TTL.
+// The expired keys are swept at most once per TTL/2, so a Claim does not scan all the keys.
+type MemoryDeduper struct {
+	// TTL the ttl of the claimed keys, 0 means DefaultDedupeTTL.
+	TTL time.Duration
+
+	mu    sync.Mutex
+	keys  map[string]time.Time
+	swept time.Time
+	now   func() time.Time
+}
+
+// NewMemoryDeduper returns a MemoryDeduper with the ttl of the claimed keys, ttl <= 0 means DefaultDedupeTTL.
+func NewMemoryDeduper(ttl time.Duration) *MemoryDeduper {
+	if ttl <= 0 {
+		ttl = DefaultDedupeTTL
+	}
+	return &MemoryDeduper{TTL: ttl}
+}
+
+func (md *MemoryDeduper) ttl() time.Duration {
+	if md.TTL > 0 {
+		return md.TTL
+	}
+	return DefaultDedupeTTL
+}
+
+// Claim implements the Deduper interface.
+func (md *MemoryDeduper) Claim(key string) bool {
+	md.mu.Lock()
+	defer md.mu.Unlock()
+
+	now := time.Now()
+	if md.now != nil {
+		now = md.now()
+	}
+	if md.keys == nil {
+		md.keys = make(map[string]time.Time)
+		md.swept = now
+	}
+
+	ttl := md.ttl()
+	if now.Sub(md.swept) >= ttl/2 {
+		md.sweep(now, ttl)
+	}
+
+	if t, ok := md.keys[key]; ok && now.Sub(t) < ttl {
+		return false
+	}
+	md.keys[key] = now
+	return true
+}
+
+// sweep deletes the expired keys.
+func (md *MemoryDeduper) sweep(now time.Time, ttl time.Duration) {
+	for k, t := range md.keys {
+		if now.Sub(t) >= ttl {
+			delete(md.keys, k)
+		}
+	}
+	md.swept = now
+}
+
+// Release implements the Deduper interface.
+func (md *MemoryDeduper) Release(key string) {
+	md.mu.Lock()
+	defer md.mu.Unlock()
+
+	delete(md.keys, key)
+}
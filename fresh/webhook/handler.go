@@ -0,0 +1,154 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+
+	"github.com/askasoft/pango/log"
+)
+
+// EventAny registers a callback for all the events
+const EventAny = "*"
+
+// Events the product specific part of a Handler: decodes the payloads to the events of type E and enriches them.
+type Events[E any] interface {
+	// Decode decodes the request body to an event, the error is responded as 400 Bad Request.
+	Decode(data []byte) (E, error)
+
+	// Type returns the event type of the event, which the callbacks are registered by.
+	Type(e E) string
+
+	// Key returns the dedupe key of the event, "" means no dedupe.
+	Key(e E) string
+
+	// Enrich prepares the event before the callbacks are called (e.g. gets the ticket of the event).
+	// It is called only if there are callbacks of the event.
+	Enrich(ctx context.Context, e E) error
+}
+
+// Callback the callback of the webhook events
+type Callback[E any] func(ctx context.Context, e E) error
+
+// Handler a http.Handler which receives the webhooks, verifies, decodes and dedupes them,
+// and dispatches the events to the callbacks registered by the event type.
+// The callbacks are called synchronously in the order of registration, and a callback error is responded
+// as 500 Internal Server Error, so that the webhook can be retried.
+type Handler[E any] struct {
+	// Verifier verifies the requests, nil means no verification.
+	Verifier Verifier
+
+	// Deduper dedupes the events by Events.Key(), nil means no dedupe.
+	Deduper Deduper
+
+	// Events decodes and enriches the events, it must be set.
+	Events Events[E]
+
+	// MaxBodySize the maximum size of the request body, default is DefaultMaxBodySize.
+	MaxBodySize int64
+
+	// Logger the logger of the errors
+	Logger log.Logger
+
+	mu        sync.RWMutex
+	callbacks map[string][]Callback[E]
+}
+
+// On registers the callback of the event type, use EventAny to receive all the events.
+func (h *Handler[E]) On(et string, cb Callback[E]) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.callbacks == nil {
+		h.callbacks = make(map[string][]Callback[E])
+	}
+	h.callbacks[et] = append(h.callbacks[et], cb)
+}
+
+func (h *Handler[E]) handlers(et string) []Callback[E] {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	cbs := make([]Callback[E], 0, len(h.callbacks[et])+len(h.callbacks[EventAny]))
+	cbs = append(cbs, h.callbacks[et]...)
+	return append(cbs, h.callbacks[EventAny]...)
+}
+
+// ServeHTTP implements the http.Handler interface.
+func (h *Handler[E]) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	if h.Verifier != nil {
+		if err := h.Verifier.Verify(r); err != nil {
+			h.error(w, err, http.StatusUnauthorized)
+			return
+		}
+	}
+
+	data, err := ReadBody(r, h.MaxBodySize)
+	if err != nil {
+		h.error(w, err, http.StatusBadRequest)
+		return
+	}
+
+	e, err := h.Events.Decode(data)
+	if err != nil {
+		h.error(w, err, http.StatusBadRequest)
+		return
+	}
+
+	if err := h.Handle(r.Context(), e); err != nil {
+		h.error(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Handle dedupes the event, enriches it and calls the callbacks of the event type.
+// A duplicated event is ignored, and the dedupe key is released if the enrichment or the callbacks failed.
+func (h *Handler[E]) Handle(ctx context.Context, e E) (err error) {
+	cbs := h.handlers(h.Events.Type(e))
+	if len(cbs) == 0 {
+		return nil
+	}
+
+	if key := h.Events.Key(e); h.Deduper != nil && key != "" {
+		if !h.Deduper.Claim(key) {
+			if h.Logger != nil {
+				h.Logger.Debugf("webhook: duplicated %s", key)
+			}
+			return nil
+		}
+
+		defer func() {
+			if err != nil {
+				h.Deduper.Release(key)
+			}
+		}()
+	}
+
+	if err = h.Events.Enrich(ctx, e); err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, cb := range cbs {
+		if err := cb(ctx, e); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (h *Handler[E]) error(w http.ResponseWriter, err error, status int) {
+	if h.Logger != nil {
+		h.Logger.Warnf("webhook: %d %v", status, err)
+	}
+	http.Error(w, http.StatusText(status), status)
+}
@@ -0,0 +1,107 @@
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+)
+
+// ErrInvalidTemplate the error returned by Template if the value is not a struct
+var ErrInvalidTemplate = errors.New("webhook: template value must be a struct")
+
+var marshalerType = reflect.TypeFor[json.Marshaler]()
+
+// Template returns the json payload template of the struct v, to configure the webhook action
+// with the same payload shape as the struct which decodes it.
+//
+// The field name is the name of the json tag, and the field value is the "placeholder" tag (e.g. "{{ticket.subject}}"),
+// which is always quoted as a json string. The fields of the nested structs without the placeholder tag are rendered
+// as the nested objects, and the other fields without the placeholder tag are rendered with their values of v.
+//
+// Example:
+//
+//	type Payload struct {
+//		Event   string `json:"event"`
+//		ID      string `json:"id" placeholder:"{{ticket.id}}"`
+//		Subject string `json:"subject" placeholder:"{{ticket.subject}}"`
+//	}
+//
+//	s, err := webhook.Template(&Payload{Event: "ticket_created"})
+//	// {"event": "ticket_created", "id": "{{ticket.id}}", "subject": "{{ticket.subject}}"}
+func Template(v any) (string, error) {
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	if rv.Kind() != reflect.Struct {
+		return "", ErrInvalidTemplate
+	}
+
+	buf := &bytes.Buffer{}
+	if err := writeTemplate(buf, rv); err != nil {
+		return "", err
+	}
+
+	out := &bytes.Buffer{}
+	if err := json.Indent(out, buf.Bytes(), "", "  "); err != nil {
+		return "", err
+	}
+	return out.String(), nil
+}
+
+func writeTemplate(buf *bytes.Buffer, rv reflect.Value) error {
+	rt := rv.Type()
+
+	buf.WriteByte('{')
+	n := 0
+	for i := 0; i < rt.NumField(); i++ {
+		sf := rt.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+
+		name, _, _ := strings.Cut(sf.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+
+		if n > 0 {
+			buf.WriteByte(',')
+		}
+		n++
+
+		bs, _ := json.Marshal(name)
+		buf.Write(bs)
+		buf.WriteByte(':')
+
+		fv := rv.Field(i)
+		if ph, ok := sf.Tag.Lookup("placeholder"); ok {
+			bs, _ = json.Marshal(ph)
+			buf.Write(bs)
+			continue
+		}
+
+		if fv.Kind() == reflect.Pointer && fv.Type().Elem().Kind() == reflect.Struct {
+			if fv.IsNil() {
+				fv = reflect.New(fv.Type().Elem())
+			}
+			fv = fv.Elem()
+		}
+		if fv.Kind() == reflect.Struct && !reflect.PointerTo(fv.Type()).Implements(marshalerType) {
+			if err := writeTemplate(buf, fv); err != nil {
+				return err
+			}
+			continue
+		}
+
+		bs, err := json.Marshal(fv.Interface())
+		if err != nil {
+			return err
+		}
+		buf.Write(bs)
+	}
+	buf.WriteByte('}')
+	return nil
+}
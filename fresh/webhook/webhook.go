@@ -0,0 +1,261 @@
+// Package webhook provides the common parts of the Freshdesk/Freshservice webhook receivers:
+// the request verification, the decoding of the placeholder payloads, the dedupe and the generic Handler.
+package webhook
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	// DefaultSecretHeader the default header of the shared secret, configure it in the custom headers of the webhook action.
+	DefaultSecretHeader = "X-Webhook-Secret"
+
+	// DefaultMaxBodySize the default maximum size of the webhook request body
+	DefaultMaxBodySize = 1 << 20
+)
+
+var (
+	// ErrUnauthorized the error returned by a Verifier if the request is not authorized
+	ErrUnauthorized = errors.New("webhook: unauthorized")
+
+	// ErrInvalidPayload the error returned by DecodeFields if the payload is not a json object
+	ErrInvalidPayload = errors.New("webhook: invalid payload")
+)
+
+// Verifier verifies the webhook request.
+type Verifier interface {
+	Verify(r *http.Request) error
+}
+
+// VerifierFunc an adapter to allow the use of ordinary functions as Verifier.
+type VerifierFunc func(r *http.Request) error
+
+// Verify calls f(r).
+func (f VerifierFunc) Verify(r *http.Request) error {
+	return f(r)
+}
+
+// Secret verifies the shared secret header of the request.
+type Secret struct {
+	Header string // the header name, default is DefaultSecretHeader
+	Value  string // the shared secret
+}
+
+// Verify implements the Verifier interface.
+func (s *Secret) Verify(r *http.Request) error {
+	h := s.Header
+	if h == "" {
+		h = DefaultSecretHeader
+	}
+
+	if s.Value == "" || !equal(r.Header.Get(h), s.Value) {
+		return fmt.Errorf("%w: invalid secret header %q", ErrUnauthorized, h)
+	}
+	return nil
+}
+
+// BasicAuth verifies the basic auth credentials of the request.
+type BasicAuth struct {
+	Username string
+	Password string
+}
+
+// APIKey returns a BasicAuth of the api key credentials ("apikey:X"),
+// which are sent by the webhook action configured with the api key authentication.
+func APIKey(apikey string) *BasicAuth {
+	return &BasicAuth{Username: apikey, Password: "X"}
+}
+
+// Verify implements the Verifier interface.
+func (ba *BasicAuth) Verify(r *http.Request) error {
+	u, p, ok := r.BasicAuth()
+	if !ok || ba.Username == "" || !equal(u, ba.Username) || !equal(p, ba.Password) {
+		return fmt.Errorf("%w: invalid basic auth", ErrUnauthorized)
+	}
+	return nil
+}
+
+// AnyOf returns a Verifier which passes if any of the verifiers passes.
+func AnyOf(vs ...Verifier) Verifier {
+	return VerifierFunc(func(r *http.Request) error {
+		err := error(ErrUnauthorized)
+		for _, v := range vs {
+			if err = v.Verify(r); err == nil {
+				return nil
+			}
+		}
+		return err
+	})
+}
+
+func equal(a, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
+
+// ReadBody reads the request body up to max bytes (DefaultMaxBodySize if max <= 0).
+func ReadBody(r *http.Request, max int64) ([]byte, error) {
+	if max <= 0 {
+		max = DefaultMaxBodySize
+	}
+
+	data, err := io.ReadAll(io.LimitReader(r.Body, max+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > max {
+		return nil, fmt.Errorf("%w: body exceeds %d bytes", ErrInvalidPayload, max)
+	}
+	return data, nil
+}
+
+// Fields the flattened values of a webhook payload.
+// The keys are normalized by NormalizeKey, e.g. {"ticket": {"id": 1}} and {"ticket.id": 1} are both "ticket_id".
+type Fields map[string]string
+
+// NormalizeKey returns the lower-cased key with the "." replaced by "_".
+func NormalizeKey(key string) string {
+	return strings.ToLower(strings.ReplaceAll(key, ".", "_"))
+}
+
+// DecodeFields decodes the json object payload to Fields.
+// If the payload is wrapped in an object of one of the unwraps keys (e.g. "freshdesk_webhook"), the wrapped object is decoded.
+func DecodeFields(data []byte, unwraps ...string) (Fields, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var m map[string]any
+	if err := dec.Decode(&m); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPayload, err)
+	}
+	if m == nil {
+		return nil, fmt.Errorf("%w: not a json object", ErrInvalidPayload)
+	}
+
+	for _, u := range unwraps {
+		if w, ok := m[u].(map[string]any); ok {
+			m = w
+			break
+		}
+	}
+
+	fs := Fields{}
+	fs.flatten("", m)
+	return fs, nil
+}
+
+func (fs Fields) flatten(prefix string, v any) {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, e := range v {
+			if prefix != "" {
+				k = prefix + "_" + k
+			}
+			fs.flatten(NormalizeKey(k), e)
+		}
+	case []any:
+		ss := make([]string, 0, len(v))
+		for _, e := range v {
+			ss = append(ss, toString(e))
+		}
+		fs[prefix] = strings.Join(ss, ",")
+	default:
+		fs[prefix] = toString(v)
+	}
+}
+
+func toString(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		bs, _ := json.Marshal(v)
+		return string(bs)
+	}
+}
+
+// Get returns the value of the key, the key is normalized by NormalizeKey.
+func (fs Fields) Get(key string) string {
+	return fs[NormalizeKey(key)]
+}
+
+// reIDPrefix matches the leading "#" and the prefix of the letters and "-" (e.g. "INC-") of an id.
+var reIDPrefix = regexp.MustCompile(`^#?([A-Za-z]+-)?`)
+
+// Int64 returns the int64 value of the key, the leading "#" and prefix like "INC-" are ignored (e.g. "#123", "INC-123", "#SR-123").
+// It returns 0 if the value is not a number (e.g. "2024-01-02").
+func (fs Fields) Int64(key string) int64 {
+	s := reIDPrefix.ReplaceAllString(strings.TrimSpace(fs.Get(key)), "")
+
+	n, _ := strconv.ParseInt(s, 10, 64)
+	return n
+}
+
+// Strings returns the comma separated values of the key, the empty values are removed.
+func (fs Fields) Strings(key string) []string {
+	var ss []string
+	for _, s := range strings.Split(fs.Get(key), ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			ss = append(ss, s)
+		}
+	}
+	return ss
+}
+
+// Decode sets the fields of the struct pointer v by the json tag names (see Template).
+// The string, bool and integer fields are supported, the nested struct fields are decoded with the prefix "name_".
+func (fs Fields) Decode(v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.Elem().Kind() != reflect.Struct {
+		return ErrInvalidTemplate
+	}
+	fs.decode("", rv.Elem())
+	return nil
+}
+
+func (fs Fields) decode(prefix string, rv reflect.Value) {
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		sf := rt.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+
+		name, _, _ := strings.Cut(sf.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		key := NormalizeKey(prefix + name)
+
+		fv := rv.Field(i)
+		switch fv.Kind() {
+		case reflect.String:
+			fv.SetString(fs[key])
+		case reflect.Bool:
+			b, _ := strconv.ParseBool(fs[key])
+			fv.SetBool(b)
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			fv.SetInt(fs.Int64(key))
+		case reflect.Struct:
+			fs.decode(key+"_", fv)
+		}
+	}
+}
@@ -0,0 +1,261 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestVerifiers(t *testing.T) {
+	v := AnyOf(&Secret{Value: "secret"}, APIKey("apikey"))
+
+	req := httptest.NewRequest(http.MethodPost, "/webhook", nil)
+	if err := v.Verify(req); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("Verify() = %v", err)
+	}
+
+	req.Header.Set(DefaultSecretHeader, "secret")
+	if err := v.Verify(req); err != nil {
+		t.Errorf("Verify(secret) = %v", err)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/webhook", nil)
+	req.SetBasicAuth("apikey", "X")
+	if err := v.Verify(req); err != nil {
+		t.Errorf("Verify(apikey) = %v", err)
+	}
+
+	if err := (&Secret{}).Verify(req); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("Verify(empty secret) = %v", err)
+	}
+}
+
+func TestDecodeFields(t *testing.T) {
+	fs, err := DecodeFields([]byte(`{"wrap":{"Ticket":{"ID":"#12","tags":["a","b"]},"ticket.subject":"s","n":null,"b":true,"f":1.5}}`), "none", "wrap")
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+
+	cs := []struct {
+		k, w string
+	}{
+		{"ticket_id", "#12"},
+		{"ticket.id", "#12"},
+		{"ticket_tags", "a,b"},
+		{"TICKET_SUBJECT", "s"},
+		{"n", ""},
+		{"b", "true"},
+		{"f", "1.5"},
+	}
+	for i, c := range cs {
+		if a := fs.Get(c.k); a != c.w {
+			t.Errorf("[%d] Get(%q) = %q, want %q", i, c.k, a, c.w)
+		}
+	}
+
+	if a := fs.Int64("ticket.id"); a != 12 {
+		t.Errorf("Int64() = %d", a)
+	}
+	for v, w := range map[string]int64{"12": 12, " #12 ": 12, "INC-12": 12, "#SR-7": 7, "-5": -5, "2024-01-02": 0, "#-5": -5, "INC-": 0, "x": 0} {
+		if a := (Fields{"id": v}).Int64("id"); a != w {
+			t.Errorf("Int64(%q) = %d, want %d", v, a, w)
+		}
+	}
+	if a := fs.Strings("ticket.tags"); len(a) != 2 {
+		t.Errorf("Strings() = %q", a)
+	}
+
+	if _, err := DecodeFields([]byte(`null`)); !errors.Is(err, ErrInvalidPayload) {
+		t.Errorf("DecodeFields(null) = %v", err)
+	}
+}
+
+func TestReadBody(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader("12345"))
+	if _, err := ReadBody(req, 4); !errors.Is(err, ErrInvalidPayload) {
+		t.Errorf("ReadBody() = %v", err)
+	}
+}
+
+type testPayload struct {
+	Event  string `json:"event"`
+	ID     int64  `json:"id" placeholder:"{{ticket.id}}"`
+	Closed bool   `json:"closed" placeholder:"{{ticket.closed}}"`
+	Agent  struct {
+		Email string `json:"email" placeholder:"{{ticket.agent.email}}"`
+	} `json:"agent"`
+	Skip string `json:"-"`
+}
+
+func TestTemplateDecode(t *testing.T) {
+	s, err := Template(testPayload{Event: "created"})
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+
+	w := `{
+  "event": "created",
+  "id": "{{ticket.id}}",
+  "closed": "{{ticket.closed}}",
+  "agent": {
+    "email": "{{ticket.agent.email}}"
+  }
+}`
+	if s != w {
+		t.Fatalf("Template() = %s, want %s", s, w)
+	}
+
+	s = strings.NewReplacer("{{ticket.id}}", "#3", "{{ticket.closed}}", "true", "{{ticket.agent.email}}", "a@b.c").Replace(s)
+	fs, err := DecodeFields([]byte(s))
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+
+	p := &testPayload{}
+	if err = fs.Decode(p); err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	if p.Event != "created" || p.ID != 3 || !p.Closed || p.Agent.Email != "a@b.c" {
+		t.Errorf("Decode() = %+v", p)
+	}
+
+	if _, err = Template("s"); !errors.Is(err, ErrInvalidTemplate) {
+		t.Errorf("Template(string) = %v", err)
+	}
+}
+
+// testEvents decodes the payload to the testPayload, the dedupe key is the id.
+type testEvents struct {
+	enriched int
+}
+
+func (te *testEvents) Decode(data []byte) (*testPayload, error) {
+	fs, err := DecodeFields(data)
+	if err != nil {
+		return nil, err
+	}
+	p := &testPayload{}
+	return p, fs.Decode(p)
+}
+
+func (te *testEvents) Type(p *testPayload) string {
+	return p.Event
+}
+
+func (te *testEvents) Key(p *testPayload) string {
+	return strconv.FormatInt(p.ID, 10)
+}
+
+func (te *testEvents) Enrich(ctx context.Context, p *testPayload) error {
+	te.enriched++
+	return nil
+}
+
+func TestHandler(t *testing.T) {
+	te := &testEvents{}
+	h := &Handler[*testPayload]{Verifier: &Secret{Value: "secret"}, Deduper: NewMemoryDeduper(time.Minute), Events: te}
+
+	var ids []int64
+	fail := true
+	h.On("created", func(ctx context.Context, p *testPayload) error {
+		if fail {
+			fail = false
+			return errors.New("failed")
+		}
+		ids = append(ids, p.ID)
+		return nil
+	})
+
+	serve := func(method, body string) int {
+		req := httptest.NewRequest(method, "/webhook", strings.NewReader(body))
+		req.Header.Set(DefaultSecretHeader, "secret")
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	cs := []struct {
+		m, b string
+		w    int
+	}{
+		{http.MethodGet, `{}`, http.StatusMethodNotAllowed},
+		{http.MethodPost, `[]`, http.StatusBadRequest},
+		{http.MethodPost, `{"event":"created","id":"1"}`, http.StatusInternalServerError},
+		{http.MethodPost, `{"event":"created","id":"1"}`, http.StatusNoContent}, // released after the failure
+		{http.MethodPost, `{"event":"created","id":"1"}`, http.StatusNoContent}, // duplicated
+		{http.MethodPost, `{"event":"updated","id":"2"}`, http.StatusNoContent}, // no callbacks
+	}
+	for i, c := range cs {
+		if a := serve(c.m, c.b); a != c.w {
+			t.Errorf("[%d] %s %s = %d, want %d", i, c.m, c.b, a, c.w)
+		}
+	}
+
+	if len(ids) != 1 || ids[0] != 1 {
+		t.Errorf("ids = %v, want [1]", ids)
+	}
+	if te.enriched != 2 {
+		t.Errorf("enriched = %d, want %d", te.enriched, 2)
+	}
+}
+
+func TestMemoryDeduper(t *testing.T) {
+	now := time.Now()
+
+	md := NewMemoryDeduper(time.Minute)
+	md.now = func() time.Time { return now }
+
+	if !md.Claim("a") || md.Claim("a") {
+		t.Fatal("Claim(a) should succeed only once")
+	}
+
+	md.Release("a")
+	if !md.Claim("a") {
+		t.Fatal("Claim(a) should succeed after Release")
+	}
+
+	now = now.Add(time.Minute)
+	if !md.Claim("a") {
+		t.Fatal("Claim(a) should succeed after TTL")
+	}
+}
+
+func TestMemoryDeduperSweep(t *testing.T) {
+	now := time.Now()
+
+	md := NewMemoryDeduper(time.Minute)
+	md.now = func() time.Time { return now }
+
+	md.Claim("a")
+	now = now.Add(time.Second * 20)
+	md.Claim("b")
+	if len(md.keys) != 2 {
+		t.Fatalf("keys = %d, want %d", len(md.keys), 2)
+	}
+
+	// a is expired, b is not, the sweep runs once per TTL/2
+	now = now.Add(time.Second * 45)
+	md.Claim("c")
+	if _, ok := md.keys["a"]; ok || len(md.keys) != 2 {
+		t.Errorf("keys = %v, want [b c]", md.keys)
+	}
+	if md.Claim("b") {
+		t.Error("Claim(b) should fail before TTL")
+	}
+}
+
+func TestMemoryDeduperZeroTTL(t *testing.T) {
+	if md := NewMemoryDeduper(0); md.TTL != DefaultDedupeTTL {
+		t.Errorf("TTL = %v, want %v", md.TTL, DefaultDedupeTTL)
+	}
+
+	md := &MemoryDeduper{}
+	if !md.Claim("a") || md.Claim("a") {
+		t.Error("Claim(a) should succeed only once with the zero TTL")
+	}
+}
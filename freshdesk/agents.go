@@ -2,7 +2,10 @@ package freshdesk
 
 import (
 	"context"
+	"iter"
 	"net/url"
+
+	"github.com/askasoft/gofresh/fresh"
 )
 
 // ---------------------------------------------------
@@ -47,39 +50,27 @@ func (c *Client) GetAgent(ctx context.Context, aid int64) (*Agent, error) {
 }
 
 func (c *Client) ListAgents(ctx context.Context, lao *ListAgentsOption) ([]*Agent, bool, error) {
+	return hasNext(c.listAgents(ctx, lao))
+}
+
+func (c *Client) listAgents(ctx context.Context, lo ListOption) ([]*Agent, string, error) {
 	url := c.Endpoint("/agents")
 	agents := []*Agent{}
-	next, err := c.DoList(ctx, url, lao, &agents)
+	next, err := c.DoListLink(ctx, url, lo, &agents)
 	return agents, next, err
 }
 
 func (c *Client) IterAgents(ctx context.Context, lao *ListAgentsOption, iaf func(*Agent) error) error {
-	if lao == nil {
-		lao = &ListAgentsOption{}
-	}
-	if lao.Page < 1 {
-		lao.Page = 1
-	}
-	if lao.PerPage < 1 {
-		lao.PerPage = 100
-	}
+	return c.agentsPaginator().Iter(ctx, lao, iaf)
+}
 
-	for {
-		agents, next, err := c.ListAgents(ctx, lao)
-		if err != nil {
-			return err
-		}
-		for _, c := range agents {
-			if err = iaf(c); err != nil {
-				return err
-			}
-		}
-		if !next {
-			break
-		}
-		lao.Page++
-	}
-	return nil
+// AllAgents is like IterAgents but returns an iterator, the lao will not be modified.
+func (c *Client) AllAgents(ctx context.Context, lao *ListAgentsOption) iter.Seq2[*Agent, error] {
+	return c.agentsPaginator().All(ctx, lao)
+}
+
+func (c *Client) agentsPaginator() *fresh.Paginator[*Agent] {
+	return newPaginator(c.listAgents)
 }
 
 func (c *Client) CreateAgent(ctx context.Context, agent *AgentCreate) (*Agent, error) {
@@ -58,7 +58,7 @@ func TestAgentAPIs(t *testing.T) {
 }
 
 func TestListAgents(t *testing.T) {
-	fd := testNewFreshdesk(t)
+	fd := testNewLiveFreshdesk(t)
 	if fd == nil {
 		return
 	}
@@ -0,0 +1,98 @@
+package freshdesk
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"slices"
+	"strings"
+
+	"github.com/askasoft/gofresh/fresh"
+)
+
+// ---------------------------------------------------
+// Archived Ticket
+
+// GetTicketOrArchived gets the ticket by GetTicket, and falls back to GetArchivedTicket if the ticket is not found (404),
+// since the old closed tickets are moved to the archive.
+// include: conversations, requester, company, stats
+// The archived endpoint does not support the "conversations" include,
+// the conversations of the archived ticket are got by AllArchivedTicketConversations instead.
+// The original error is returned if the archived ticket is not found too.
+func (c *Client) GetTicketOrArchived(ctx context.Context, tid int64, include ...string) (*Ticket, error) {
+	ticket, err := c.GetTicket(ctx, tid, include...)
+	if !errors.Is(err, ErrNotFound) {
+		return ticket, err
+	}
+
+	ainclude := slices.DeleteFunc(slices.Clone(include), func(s string) bool {
+		return s == "conversations"
+	})
+
+	at, aerr := c.GetArchivedTicket(ctx, tid, ainclude...)
+	if aerr != nil {
+		if errors.Is(aerr, ErrNotFound) {
+			return ticket, err
+		}
+		return at, aerr
+	}
+
+	if len(ainclude) < len(include) {
+		for cv, err := range c.AllArchivedTicketConversations(ctx, tid, nil) {
+			if err != nil {
+				return at, err
+			}
+			at.Conversations = append(at.Conversations, cv)
+		}
+	}
+	return at, nil
+}
+
+// GetArchivedTicket gets the archived ticket, the archived ticket is not found by GetTicket.
+// include: stats, requester, company
+func (c *Client) GetArchivedTicket(ctx context.Context, tid int64, include ...string) (*Ticket, error) {
+	url := c.Endpoint("/tickets/archived/%d", tid)
+	if len(include) > 0 {
+		s := strings.Join(include, ",")
+		url += "?include=" + s
+	}
+
+	ticket := &Ticket{}
+	if err := c.DoGet(ctx, url, ticket); err != nil {
+		return ticket, err
+	}
+
+	ticket.Archived = true
+	return ticket, nil
+}
+
+func (c *Client) DeleteArchivedTicket(ctx context.Context, tid int64) error {
+	url := c.Endpoint("/tickets/archived/%d", tid)
+	return c.DoDelete(ctx, url)
+}
+
+func (c *Client) ListArchivedTicketConversations(ctx context.Context, tid int64, lco *ListConversationsOption) ([]*Conversation, bool, error) {
+	return hasNext(c.listArchivedTicketConversations(ctx, tid, lco))
+}
+
+func (c *Client) listArchivedTicketConversations(ctx context.Context, tid int64, lo ListOption) ([]*Conversation, string, error) {
+	url := c.Endpoint("/tickets/archived/%d/conversations", tid)
+	conversations := []*Conversation{}
+	next, err := c.DoListLink(ctx, url, lo, &conversations)
+	return conversations, next, err
+}
+
+func (c *Client) IterArchivedTicketConversations(ctx context.Context, tid int64, lco *ListConversationsOption, icf func(*Conversation) error) error {
+	return c.archivedTicketConversationsPaginator(tid).Iter(ctx, lco, icf)
+}
+
+// AllArchivedTicketConversations is like IterArchivedTicketConversations but returns an iterator, the lco will not be modified.
+func (c *Client) AllArchivedTicketConversations(ctx context.Context, tid int64, lco *ListConversationsOption) iter.Seq2[*Conversation, error] {
+	return c.archivedTicketConversationsPaginator(tid).All(ctx, lco)
+}
+
+func (c *Client) archivedTicketConversationsPaginator(tid int64) *fresh.Paginator[*Conversation] {
+	return newPaginator(func(ctx context.Context, lo ListOption) ([]*Conversation, string, error) {
+		return c.listArchivedTicketConversations(ctx, tid, lo)
+	})
+}
@@ -0,0 +1,85 @@
+package freshdesk_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/askasoft/gofresh/freshdesk"
+	"github.com/askasoft/gofresh/freshdesk/fdtest"
+)
+
+func TestArchivedTickets(t *testing.T) {
+	fs := fdtest.NewServer()
+	defer fs.Close()
+
+	fd := testNewClient(fs)
+
+	ticket, err := fd.CreateTicket(ctxbg, &freshdesk.TicketCreate{Email: "a@example.com", Subject: "old", Status: freshdesk.TicketStatusClosed})
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	for i := range 3 {
+		if _, err := fd.CreateNote(ctxbg, ticket.ID, &freshdesk.NoteCreate{Body: fmt.Sprintf("note %d", i)}); err != nil {
+			t.Fatalf("ERROR: %v", err)
+		}
+	}
+
+	if _, err = fd.GetArchivedTicket(ctxbg, ticket.ID); !errors.Is(err, freshdesk.ErrNotFound) {
+		t.Errorf("GetArchivedTicket() = %v, want %v", err, freshdesk.ErrNotFound)
+	}
+
+	if !fs.ArchiveTicket(ticket.ID) {
+		t.Fatalf("ArchiveTicket(%d) = false", ticket.ID)
+	}
+
+	if _, err = fd.GetTicket(ctxbg, ticket.ID); !errors.Is(err, freshdesk.ErrNotFound) {
+		t.Errorf("GetTicket() = %v, want %v", err, freshdesk.ErrNotFound)
+	}
+	if tickets, _, err := fd.ListTickets(ctxbg, nil); err != nil || len(tickets) != 0 {
+		t.Errorf("ListTickets() = %v, %v", tickets, err)
+	}
+
+	at, err := fd.GetArchivedTicket(ctxbg, ticket.ID)
+	if err != nil || !at.Archived || at.Subject != "old" {
+		t.Fatalf("GetArchivedTicket() = %v, %v", at, err)
+	}
+	if bs, _ := json.Marshal(at); strings.Contains(string(bs), "archived") {
+		t.Errorf("json.Marshal(archived ticket) = %s", bs)
+	}
+
+	if at, err = fd.GetTicketOrArchived(ctxbg, ticket.ID); err != nil || at.ID != ticket.ID || !at.Archived || len(at.Conversations) != 0 {
+		t.Fatalf("GetTicketOrArchived() = %v, %v", at, err)
+	}
+	if at, err = fd.GetTicketOrArchived(ctxbg, ticket.ID, "requester", "conversations"); err != nil || !at.Archived || len(at.Conversations) != 3 || at.Conversations[2].Body != "note 2" {
+		t.Fatalf("GetTicketOrArchived(conversations) = %v, %v", at, err)
+	}
+	if _, err = fd.GetTicketOrArchived(ctxbg, 999); !errors.Is(err, freshdesk.ErrNotFound) {
+		t.Errorf("GetTicketOrArchived() = %v, want %v", err, freshdesk.ErrNotFound)
+	}
+
+	if _, _, err = fd.ListTicketConversations(ctxbg, ticket.ID, nil); !errors.Is(err, freshdesk.ErrNotFound) {
+		t.Errorf("ListTicketConversations() = %v, want %v", err, freshdesk.ErrNotFound)
+	}
+
+	n := 0
+	err = fd.IterArchivedTicketConversations(ctxbg, ticket.ID, &freshdesk.ListConversationsOption{PerPage: 2}, func(c *freshdesk.Conversation) error {
+		if c.Body != fmt.Sprintf("note %d", n) {
+			return fmt.Errorf("#%d Conversation = %v", n, c)
+		}
+		n++
+		return nil
+	})
+	if err != nil || n != 3 {
+		t.Fatalf("IterArchivedTicketConversations() = %d, %v", n, err)
+	}
+
+	if err = fd.DeleteArchivedTicket(ctxbg, ticket.ID); err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	if _, err = fd.GetArchivedTicket(ctxbg, ticket.ID); !errors.Is(err, freshdesk.ErrNotFound) {
+		t.Errorf("GetArchivedTicket() = %v, want %v", err, freshdesk.ErrNotFound)
+	}
+}
@@ -0,0 +1,62 @@
+package freshdesk_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/askasoft/gofresh/fresh"
+	"github.com/askasoft/gofresh/freshdesk"
+	"github.com/askasoft/gofresh/freshdesk/fdtest"
+)
+
+func TestArticleTranslations(t *testing.T) {
+	fs := fdtest.NewServer()
+	defer fs.Close()
+
+	fd := testNewClient(fs)
+
+	category, err := fd.CreateCategory(ctxbg, &freshdesk.CategoryCreate{Name: "category"})
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	folder, err := fd.CreateFolder(ctxbg, category.ID, &freshdesk.FolderCreate{Name: "folder"})
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	article, err := fd.CreateArticle(ctxbg, folder.ID, &freshdesk.ArticleCreate{Title: "title", Description: "desc"})
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+
+	_, err = fd.GetArticleTranslated(ctxbg, article.ID, "ja")
+	if re, ok := fresh.AsResultError(err); !ok || re.StatusCode != http.StatusNotFound {
+		t.Fatalf("GetArticleTranslated() = %v, want 404", err)
+	}
+
+	ja, err := fd.CreateArticleTranslated(ctxbg, article.ID, "ja", &freshdesk.ArticleCreate{Title: "タイトル", Description: "説明"})
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	if ja.ID != article.ID || ja.FolderID != folder.ID || ja.Status != freshdesk.ArticleStatusDraft {
+		t.Fatalf("CreateArticleTranslated() = %v", ja)
+	}
+
+	if _, err = fd.CreateArticleTranslated(ctxbg, article.ID, "ja", &freshdesk.ArticleCreate{Title: "x", Description: "x"}); err == nil {
+		t.Fatal("CreateArticleTranslated() should fail for the existing translation")
+	}
+
+	if _, err = fd.UpdateArticleTranslated(ctxbg, article.ID, "ja", &freshdesk.ArticleUpdate{Title: "新タイトル"}); err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+
+	ja, err = fd.GetArticleTranslated(ctxbg, article.ID, "ja")
+	if err != nil || ja.Title != "新タイトル" || ja.Description != "説明" {
+		t.Fatalf("GetArticleTranslated() = %v, %v", ja, err)
+	}
+
+	// the primary article is not changed
+	article, err = fd.GetArticle(ctxbg, article.ID)
+	if err != nil || article.Title != "title" {
+		t.Fatalf("GetArticle() = %v, %v", article, err)
+	}
+}
@@ -0,0 +1,51 @@
+package freshdesk_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/askasoft/gofresh/freshdesk"
+	"github.com/askasoft/gofresh/freshdesk/fdtest"
+)
+
+func TestTicketAttachmentStreams(t *testing.T) {
+	fs := fdtest.NewServer()
+	defer fs.Close()
+
+	fd := testNewClient(fs)
+
+	fsys := fstest.MapFS{"docs/manual.txt": &fstest.MapFile{Data: []byte("manual")}}
+	tc := &freshdesk.TicketCreate{
+		Email:       "requester@example.com",
+		Subject:     "test",
+		Description: "description",
+		Attachments: []*freshdesk.Attachment{
+			freshdesk.NewAttachmentFS(fsys, "docs/manual.txt"),
+			freshdesk.NewAttachmentReader("log.txt", strings.NewReader("log")),
+		},
+	}
+	ticket, err := fd.CreateTicket(ctxbg, tc)
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	if len(ticket.Attachments) != 2 || ticket.Attachments[0].Name != "manual.txt" || ticket.Attachments[0].Size != 6 || ticket.Attachments[1].Size != 3 {
+		t.Fatalf("CreateTicket().Attachments = %v", ticket.Attachments)
+	}
+
+	nc := &freshdesk.NoteCreate{
+		Body:        "note",
+		Attachments: []*freshdesk.Attachment{freshdesk.NewAttachmentReader("big.bin", bytes.NewReader(make([]byte, freshdesk.ConversationAttachmentsMaxSize+1)))},
+	}
+	_, err = fd.CreateNote(ctxbg, ticket.ID, nc)
+
+	var ase *freshdesk.AttachmentsSizeError
+	if !errors.As(err, &ase) || ase.Limit != freshdesk.ConversationAttachmentsMaxSize {
+		t.Fatalf("CreateNote() = %v, want AttachmentsSizeError", err)
+	}
+	if convs, _, _ := fd.ListTicketConversations(ctxbg, ticket.ID, nil); len(convs) != 0 {
+		t.Fatalf("ListTicketConversations() = %v", convs)
+	}
+}
@@ -1,6 +1,11 @@
 package freshdesk
 
-import "context"
+import (
+	"context"
+	"iter"
+
+	"github.com/askasoft/gofresh/fresh"
+)
 
 // ---------------------------------------------------
 // Automation
@@ -8,39 +13,29 @@ import "context"
 type ListAutomationRulesOption = PageOption
 
 func (c *Client) ListAutomationRules(ctx context.Context, aType AutomationType, laro *ListAutomationRulesOption) ([]*AutomationRule, bool, error) {
+	return hasNext(c.listAutomationRules(ctx, aType, laro))
+}
+
+func (c *Client) listAutomationRules(ctx context.Context, aType AutomationType, lo ListOption) ([]*AutomationRule, string, error) {
 	url := c.Endpoint("/automations/%d/rules", aType)
 	rules := []*AutomationRule{}
-	next, err := c.DoList(ctx, url, laro, &rules)
+	next, err := c.DoListLink(ctx, url, lo, &rules)
 	return rules, next, err
 }
 
 func (c *Client) IterAutomationRules(ctx context.Context, aType AutomationType, laro *ListAutomationRulesOption, iarf func(*AutomationRule) error) error {
-	if laro == nil {
-		laro = &ListAutomationRulesOption{}
-	}
-	if laro.Page < 1 {
-		laro.Page = 1
-	}
-	if laro.PerPage < 1 {
-		laro.PerPage = 100
-	}
+	return c.automationRulesPaginator(aType).Iter(ctx, laro, iarf)
+}
 
-	for {
-		ars, next, err := c.ListAutomationRules(ctx, aType, laro)
-		if err != nil {
-			return err
-		}
-		for _, ar := range ars {
-			if err = iarf(ar); err != nil {
-				return err
-			}
-		}
-		if !next {
-			break
-		}
-		laro.Page++
-	}
-	return nil
+// AllAutomationRules is like IterAutomationRules but returns an iterator, the laro will not be modified.
+func (c *Client) AllAutomationRules(ctx context.Context, aType AutomationType, laro *ListAutomationRulesOption) iter.Seq2[*AutomationRule, error] {
+	return c.automationRulesPaginator(aType).All(ctx, laro)
+}
+
+func (c *Client) automationRulesPaginator(aType AutomationType) *fresh.Paginator[*AutomationRule] {
+	return newPaginator(func(ctx context.Context, lo ListOption) ([]*AutomationRule, string, error) {
+		return c.listAutomationRules(ctx, aType, lo)
+	})
 }
 
 func (c *Client) GetAutomationRule(ctx context.Context, aType AutomationType, rid int64) (*AutomationRule, error) {
@@ -5,7 +5,7 @@ import (
 )
 
 func TestAutomationAPIs(t *testing.T) {
-	fd := testNewFreshdesk(t)
+	fd := testNewLiveFreshdesk(t)
 	if fd == nil {
 		return
 	}
@@ -0,0 +1,173 @@
+package freshdesk
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type BusinessHour struct {
+	ID int64 `json:"id,omitempty"`
+
+	// Name of the business hour
+	Name string `json:"name,omitempty"`
+
+	// Description of the business hour
+	Description string `json:"description,omitempty"`
+
+	// Set as true if it is the default business hour
+	IsDefault bool `json:"is_default,omitempty"`
+
+	// Time zone of the business hour (e.g. "Eastern Time (US & Canada)"), see LoadLocation
+	TimeZone string `json:"time_zone,omitempty"`
+
+	// The working hours of the week days, the key is the lower case week day (e.g. "monday"),
+	// a day without the key is not a working day
+	BusinessHours map[string]*BusinessHourTime `json:"business_hours,omitempty"`
+
+	// The holidays of the business hour
+	Holidays []*Holiday `json:"holiday_list,omitempty"`
+
+	CreatedAt Time `json:"created_at,omitzero"`
+
+	UpdatedAt Time `json:"updated_at,omitzero"`
+}
+
+func (bh *BusinessHour) String() string {
+	return toString(bh)
+}
+
+type BusinessHourTime struct {
+	// Start time of the working hours (e.g. "8:00 am")
+	StartTime string `json:"start_time,omitempty"`
+
+	// End time of the working hours (e.g. "5:00 pm")
+	EndTime string `json:"end_time,omitempty"`
+}
+
+func (bht *BusinessHourTime) String() string {
+	return toString(bht)
+}
+
+type Holiday struct {
+	// Name of the holiday
+	Name string `json:"name,omitempty"`
+
+	// Date of the holiday, "Jan 01" for every year, or "2006-01-02" for the specified year
+	Date string `json:"date,omitempty"`
+}
+
+func (h *Holiday) String() string {
+	return toString(h)
+}
+
+// IsHoliday reports whether the date of t is the holiday
+func (h *Holiday) IsHoliday(t time.Time) bool {
+	if d, err := time.Parse(time.DateOnly, h.Date); err == nil {
+		return d.Year() == t.Year() && d.Month() == t.Month() && d.Day() == t.Day()
+	}
+
+	for _, f := range []string{"Jan 02", "Jan 2", "January 2", "01-02"} {
+		if d, err := time.Parse(f, h.Date); err == nil {
+			return d.Month() == t.Month() && d.Day() == t.Day()
+		}
+	}
+	return false
+}
+
+// ErrNoBusinessHours the business hour has no working hours
+var ErrNoBusinessHours = errors.New("freshdesk: no working hours in the business hour")
+
+// Location returns the time.Location of the TimeZone, see LoadLocation.
+func (bh *BusinessHour) Location() (*time.Location, error) {
+	return LoadLocation(bh.TimeZone)
+}
+
+// IsHoliday reports whether the date of t is a holiday, t should be in the Location of the business hour.
+func (bh *BusinessHour) IsHoliday(t time.Time) bool {
+	for _, h := range bh.Holidays {
+		if h.IsHoliday(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// WorkingHours returns the working hours of the date of t, t should be in the Location of the business hour.
+// Returns zero times if the date is not a working day or is a holiday.
+func (bh *BusinessHour) WorkingHours(t time.Time) (start, end time.Time, err error) {
+	bht, ok := bh.BusinessHours[strings.ToLower(t.Weekday().String())]
+	if !ok || bht == nil || bh.IsHoliday(t) {
+		return
+	}
+
+	var so, eo int
+	if so, err = parseClock(bht.StartTime); err != nil {
+		return
+	}
+	if eo, err = parseClock(bht.EndTime); err != nil {
+		return
+	}
+	if eo == 23*60+59 {
+		// "11:59 pm" means the end of the day
+		eo = 24 * 60
+	}
+
+	if so >= eo {
+		return
+	}
+
+	y, m, d := t.Date()
+	start = time.Date(y, m, d, 0, so, 0, 0, t.Location())
+	end = time.Date(y, m, d, 0, eo, 0, 0, t.Location())
+	return
+}
+
+// Add returns the time after the working duration d from t, the non-working hours and the holidays are skipped.
+// The result is in the time zone of the business hour.
+func (bh *BusinessHour) Add(t time.Time, d time.Duration) (time.Time, error) {
+	loc, err := bh.Location()
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	t = t.In(loc)
+
+	// give up after 1 year and 1 week without working hours
+	for idle := 0; idle <= 366+7; {
+		start, end, err := bh.WorkingHours(t)
+		if err != nil {
+			return time.Time{}, err
+		}
+
+		if !end.IsZero() && t.Before(end) {
+			idle = 0
+			if t.Before(start) {
+				t = start
+			}
+			r := end.Sub(t)
+			if d <= r {
+				return t.Add(d), nil
+			}
+			d -= r
+		} else {
+			idle++
+		}
+
+		t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+	}
+
+	return time.Time{}, fmt.Errorf("%w: %s", ErrNoBusinessHours, bh.Name)
+}
+
+// parseClock parses the clock time (e.g. "8:00 am", "17:00") to the minutes from the midnight.
+func parseClock(s string) (int, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	for _, f := range []string{"3:04 pm", "3:04pm", "15:04"} {
+		if t, err := time.Parse(f, s); err == nil {
+			return t.Hour()*60 + t.Minute(), nil
+		}
+	}
+	return 0, fmt.Errorf("freshdesk: invalid business hour time %q", s)
+}
@@ -0,0 +1,22 @@
+package freshdesk
+
+import (
+	"context"
+)
+
+// ---------------------------------------------------
+// Business Hour
+
+func (c *Client) GetBusinessHour(ctx context.Context, bhid int64) (*BusinessHour, error) {
+	url := c.Endpoint("/business_hours/%d", bhid)
+	bh := &BusinessHour{}
+	err := c.DoGet(ctx, url, bh)
+	return bh, err
+}
+
+func (c *Client) ListBusinessHours(ctx context.Context) ([]*BusinessHour, error) {
+	url := c.Endpoint("/business_hours")
+	bhs := []*BusinessHour{}
+	err := c.DoGet(ctx, url, &bhs)
+	return bhs, err
+}
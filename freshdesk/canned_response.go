@@ -0,0 +1,143 @@
+package freshdesk
+
+import "github.com/askasoft/pango/num"
+
+type CannedResponseVisibility int
+
+const (
+	CannedResponseVisibilityAllAgents    CannedResponseVisibility = 0
+	CannedResponseVisibilityPersonal     CannedResponseVisibility = 1
+	CannedResponseVisibilitySelectGroups CannedResponseVisibility = 2
+)
+
+func (crv CannedResponseVisibility) String() string {
+	switch crv {
+	case CannedResponseVisibilityAllAgents:
+		return "AllAgents"
+	case CannedResponseVisibilityPersonal:
+		return "Personal"
+	case CannedResponseVisibilitySelectGroups:
+		return "SelectGroups"
+	default:
+		return num.Itoa(int(crv))
+	}
+}
+
+// Ptr returns the pointer of a copy of the crv, for the CannedResponseCreate.Visibility.
+func (crv CannedResponseVisibility) Ptr() *CannedResponseVisibility {
+	return &crv
+}
+
+type CannedResponseFolder struct {
+	ID int64 `json:"id,omitempty"`
+
+	Name string `json:"name,omitempty"`
+
+	// Set to true if the folder is the personal folder of the agent
+	Personal bool `json:"personal,omitempty"`
+
+	// Number of canned responses in the folder
+	ResponsesCount int `json:"responses_count,omitempty"`
+
+	// The canned responses of the folder (GetCannedResponseFolder only)
+	CannedResponses []*CannedResponse `json:"canned_responses,omitempty"`
+
+	CreatedAt Time `json:"created_at,omitzero"`
+
+	UpdatedAt Time `json:"updated_at,omitzero"`
+}
+
+func (crf *CannedResponseFolder) String() string {
+	return toString(crf)
+}
+
+type CannedResponseFolderCreate struct {
+	Name string `json:"name,omitempty"`
+}
+
+func (crf *CannedResponseFolderCreate) String() string {
+	return toString(crf)
+}
+
+type CannedResponseFolderUpdate = CannedResponseFolderCreate
+
+type CannedResponse struct {
+	ID int64 `json:"id,omitempty"`
+
+	// Title of the canned response
+	Title string `json:"title,omitempty"`
+
+	// ID of the folder to which the canned response belongs
+	FolderID int64 `json:"folder_id,omitempty"`
+
+	// Content of the canned response in plain text
+	Content string `json:"content,omitempty"`
+
+	// Content of the canned response in HTML, which may contain the placeholders (e.g. {{ticket.requester.name}})
+	ContentHTML string `json:"content_html,omitempty"`
+
+	// Visibility of the canned response
+	Visibility CannedResponseVisibility `json:"visibility,omitempty"`
+
+	// IDs of the groups which can use the canned response (Visibility = CannedResponseVisibilitySelectGroups)
+	GroupIDs []int64 `json:"group_ids,omitempty"`
+
+	// Attachments of the canned response
+	Attachments []*Attachment `json:"attachments,omitempty"`
+
+	CreatedAt Time `json:"created_at,omitzero"`
+
+	UpdatedAt Time `json:"updated_at,omitzero"`
+}
+
+func (cr *CannedResponse) String() string {
+	return toString(cr)
+}
+
+type CannedResponseCreate struct {
+	// Title of the canned response
+	Title string `json:"title,omitempty"`
+
+	// Content of the canned response in HTML
+	ContentHTML string `json:"content_html,omitempty"`
+
+	// ID of the folder to which the canned response belongs
+	FolderID int64 `json:"folder_id,omitempty"`
+
+	// Visibility of the canned response, nil means the default (all agents) for create and unchanged for update.
+	// Use CannedResponseVisibility.Ptr() to set the CannedResponseVisibilityAllAgents (0) explicitly.
+	Visibility *CannedResponseVisibility `json:"visibility,omitempty"`
+
+	// IDs of the groups which can use the canned response (Visibility = CannedResponseVisibilitySelectGroups)
+	GroupIDs []int64 `json:"group_ids,omitempty"`
+
+	// Attachments of the canned response
+	Attachments []*Attachment `json:"attachments,omitempty"`
+}
+
+func (cr *CannedResponseCreate) AddAttachment(path string, data ...[]byte) {
+	a := NewAttachment(path, data...)
+	cr.Attachments = append(cr.Attachments, a)
+}
+
+func (cr *CannedResponseCreate) Files() Files {
+	return ((Attachments)(cr.Attachments)).Files()
+}
+
+func (cr *CannedResponseCreate) Values() Values {
+	vs := Values{}
+
+	vs.SetString("title", cr.Title)
+	vs.SetString("content_html", cr.ContentHTML)
+	vs.SetInt64("folder_id", cr.FolderID)
+	vs.SetIntPtr("visibility", (*int)(cr.Visibility))
+	vs.SetInt64s("group_ids", cr.GroupIDs)
+
+	return vs
+}
+
+func (cr *CannedResponseCreate) String() string {
+	return toString(cr)
+}
+
+type CannedResponseUpdate = CannedResponseCreate
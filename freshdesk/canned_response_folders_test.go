@@ -0,0 +1,115 @@
+package freshdesk_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/askasoft/gofresh/freshdesk"
+	"github.com/askasoft/gofresh/freshdesk/fdtest"
+)
+
+func TestCannedResponses(t *testing.T) {
+	fs := fdtest.NewServer()
+	defer fs.Close()
+
+	fd := testNewClient(fs)
+
+	folder, err := fd.CreateCannedResponseFolder(ctxbg, &freshdesk.CannedResponseFolderCreate{Name: "Greetings"})
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+
+	crc := &freshdesk.CannedResponseCreate{
+		Title:       "Hello",
+		ContentHTML: "<p>Hi {{ticket.requester.firstname}}, about #{{ ticket.id }} {{ticket.subject}}: {{ticket.unknown}}</p>",
+		FolderID:    folder.ID,
+	}
+	crc.AddAttachment("guide.txt", []byte("guide"))
+	cr, err := fd.CreateCannedResponse(ctxbg, crc)
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	if len(cr.Attachments) != 1 {
+		t.Fatalf("CreateCannedResponse() = %v", cr)
+	}
+
+	if _, err = fd.CreateCannedResponse(ctxbg, &freshdesk.CannedResponseCreate{Title: "x", ContentHTML: "x", FolderID: 999}); !errors.Is(err, freshdesk.ErrValidation) {
+		t.Errorf("CreateCannedResponse() = %v, want %v", err, freshdesk.ErrValidation)
+	}
+
+	folders, err := fd.ListCannedResponseFolders(ctxbg)
+	if err != nil || len(folders) != 1 || folders[0].Name != "Greetings" {
+		t.Fatalf("ListCannedResponseFolders() = %v, %v", folders, err)
+	}
+
+	if folder, err = fd.GetCannedResponseFolder(ctxbg, folder.ID); err != nil || folder.ResponsesCount != 1 || folder.CannedResponses[0].ID != cr.ID {
+		t.Fatalf("GetCannedResponseFolder() = %v, %v", folder, err)
+	}
+
+	if cr, err = fd.UpdateCannedResponse(ctxbg, cr.ID, &freshdesk.CannedResponseUpdate{Title: "Hello!"}); err != nil || cr.Title != "Hello!" {
+		t.Fatalf("UpdateCannedResponse() = %v, %v", cr, err)
+	}
+
+	var titles []string
+	for cr, err := range fd.AllCannedResponses(ctxbg, folder.ID, nil) {
+		if err != nil {
+			t.Fatalf("ERROR: %v", err)
+		}
+		titles = append(titles, cr.Title)
+	}
+	if len(titles) != 1 || titles[0] != "Hello!" {
+		t.Fatalf("AllCannedResponses() = %v", titles)
+	}
+
+	contact, err := fd.CreateContact(ctxbg, &freshdesk.ContactCreate{Name: "John Smith", Email: "john@example.com"})
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	ticket, err := fd.CreateTicket(ctxbg, &freshdesk.TicketCreate{RequesterID: contact.ID, Subject: "<Printer>", Description: "broken"})
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+
+	if _, err = fd.ReplyWithCannedResponse(ctxbg, nil, nil, cr.ID, nil); !errors.Is(err, freshdesk.ErrTicketRequired) {
+		t.Fatalf("ReplyWithCannedResponse(nil) = %v, want %v", err, freshdesk.ErrTicketRequired)
+	}
+
+	// the unknown placeholder is not sent to the requester
+	if _, err = fd.ReplyWithCannedResponse(ctxbg, ticket, nil, cr.ID, nil); !errors.Is(err, freshdesk.ErrUnresolvedPlaceholders) {
+		t.Fatalf("ReplyWithCannedResponse() = %v, want %v", err, freshdesk.ErrUnresolvedPlaceholders)
+	}
+	for c, err := range fd.AllTicketConversations(ctxbg, ticket.ID, nil) {
+		if err != nil {
+			t.Fatalf("ERROR: %v", err)
+		}
+		t.Fatalf("unexpected conversation %v", c)
+	}
+
+	if cr, err = fd.UpdateCannedResponse(ctxbg, cr.ID, &freshdesk.CannedResponseUpdate{
+		ContentHTML: "<p>Hi {{ticket.requester.firstname}}, about #{{ ticket.id }} {{ticket.subject}}</p>",
+		Visibility:  freshdesk.CannedResponseVisibilityPersonal.Ptr(),
+	}); err != nil || cr.Visibility != freshdesk.CannedResponseVisibilityPersonal {
+		t.Fatalf("UpdateCannedResponse() = %v, %v", cr, err)
+	}
+
+	// the all agents visibility (0) is sent
+	if cr, err = fd.UpdateCannedResponse(ctxbg, cr.ID, &freshdesk.CannedResponseUpdate{
+		Visibility: freshdesk.CannedResponseVisibilityAllAgents.Ptr(),
+	}); err != nil || cr.Visibility != freshdesk.CannedResponseVisibilityAllAgents {
+		t.Fatalf("UpdateCannedResponse() = %v, %v", cr, err)
+	}
+
+	reply, err := fd.ReplyWithCannedResponse(ctxbg, ticket, nil, cr.ID, &freshdesk.ReplyCreate{CcEmails: []string{"cc@example.com"}})
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+
+	want := fmt.Sprintf("<p>Hi John, about #%d &lt;Printer&gt;</p>", ticket.ID)
+	if reply.Body != want || len(reply.CcEmails) != 1 || len(reply.Attachments) != 1 || reply.Attachments[0].Name != "guide.txt" {
+		t.Fatalf("ReplyWithCannedResponse() = %v, want body %q", reply, want)
+	}
+	if data, err := fd.DoReadFileNoAuth(ctxbg, reply.Attachments[0].AttachmentURL); err != nil || string(data) != "guide" {
+		t.Fatalf("reply attachment = %q, %v", data, err)
+	}
+}
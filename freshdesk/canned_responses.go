@@ -0,0 +1,275 @@
+package freshdesk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"iter"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/askasoft/gofresh/fresh"
+)
+
+// ---------------------------------------------------
+// Canned Response
+
+// PerPage: 1 ~ 100, default: 30
+type ListCannedResponsesOption = PageOption
+
+func (c *Client) CreateCannedResponseFolder(ctx context.Context, folder *CannedResponseFolderCreate) (*CannedResponseFolder, error) {
+	url := c.Endpoint("/canned_response_folders")
+	result := &CannedResponseFolder{}
+	if err := c.DoPost(ctx, url, folder, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c *Client) UpdateCannedResponseFolder(ctx context.Context, fid int64, folder *CannedResponseFolderUpdate) (*CannedResponseFolder, error) {
+	url := c.Endpoint("/canned_response_folders/%d", fid)
+	result := &CannedResponseFolder{}
+	if err := c.DoPut(ctx, url, folder, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GetCannedResponseFolder gets the folder with its canned responses (without the content).
+func (c *Client) GetCannedResponseFolder(ctx context.Context, fid int64) (*CannedResponseFolder, error) {
+	url := c.Endpoint("/canned_response_folders/%d", fid)
+	folder := &CannedResponseFolder{}
+	err := c.DoGet(ctx, url, folder)
+	return folder, err
+}
+
+func (c *Client) ListCannedResponseFolders(ctx context.Context) ([]*CannedResponseFolder, error) {
+	url := c.Endpoint("/canned_response_folders")
+	folders := []*CannedResponseFolder{}
+	err := c.DoGet(ctx, url, &folders)
+	return folders, err
+}
+
+func (c *Client) CreateCannedResponse(ctx context.Context, cr *CannedResponseCreate) (*CannedResponse, error) {
+	url := c.Endpoint("/canned_responses")
+	result := &CannedResponse{}
+	if err := c.DoPost(ctx, url, cr, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c *Client) UpdateCannedResponse(ctx context.Context, crid int64, cr *CannedResponseUpdate) (*CannedResponse, error) {
+	url := c.Endpoint("/canned_responses/%d", crid)
+	result := &CannedResponse{}
+	if err := c.DoPut(ctx, url, cr, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c *Client) GetCannedResponse(ctx context.Context, crid int64) (*CannedResponse, error) {
+	url := c.Endpoint("/canned_responses/%d", crid)
+	cr := &CannedResponse{}
+	err := c.DoGet(ctx, url, cr)
+	return cr, err
+}
+
+// ListCannedResponses lists the canned responses (with the content) of the folder.
+func (c *Client) ListCannedResponses(ctx context.Context, fid int64, lco *ListCannedResponsesOption) ([]*CannedResponse, bool, error) {
+	return hasNext(c.listCannedResponses(ctx, fid, lco))
+}
+
+func (c *Client) listCannedResponses(ctx context.Context, fid int64, lo ListOption) ([]*CannedResponse, string, error) {
+	url := c.Endpoint("/canned_response_folders/%d/responses", fid)
+	crs := []*CannedResponse{}
+	next, err := c.DoListLink(ctx, url, lo, &crs)
+	return crs, next, err
+}
+
+func (c *Client) IterCannedResponses(ctx context.Context, fid int64, lco *ListCannedResponsesOption, icf func(*CannedResponse) error) error {
+	return c.cannedResponsesPaginator(fid).Iter(ctx, lco, icf)
+}
+
+// AllCannedResponses is like IterCannedResponses but returns an iterator, the lco will not be modified.
+func (c *Client) AllCannedResponses(ctx context.Context, fid int64, lco *ListCannedResponsesOption) iter.Seq2[*CannedResponse, error] {
+	return c.cannedResponsesPaginator(fid).All(ctx, lco)
+}
+
+func (c *Client) cannedResponsesPaginator(fid int64) *fresh.Paginator[*CannedResponse] {
+	return newPaginator(func(ctx context.Context, lo ListOption) ([]*CannedResponse, string, error) {
+		return c.listCannedResponses(ctx, fid, lo)
+	})
+}
+
+// CannedResponsePlaceholders returns the html values of the placeholders of the ticket and the contact (the requester),
+// e.g. "ticket.id", "ticket.subject", "ticket.url", "ticket.requester.name", "ticket.requester.firstname".
+// The custom fields are returned as "ticket.<name>" and "ticket.requester.<name>".
+// The ticket.Requester is used if the contact is nil.
+func (c *Client) CannedResponsePlaceholders(ticket *Ticket, contact *Contact) map[string]string {
+	phs := map[string]string{}
+
+	set := func(key string, value any) {
+		var s string
+		switch v := value.(type) {
+		case nil:
+		case string:
+			s = v
+		case fmt.Stringer:
+			s = v.String()
+		default:
+			s = fmt.Sprint(v)
+		}
+		phs[key] = html.EscapeString(s)
+	}
+
+	if ticket != nil {
+		set("ticket.id", ticket.ID)
+		set("ticket.subject", ticket.Subject)
+		set("ticket.description_text", ticket.DescriptionText)
+		set("ticket.status", ticket.Status)
+		set("ticket.priority", ticket.Priority)
+		set("ticket.source", ticket.Source)
+		set("ticket.ticket_type", ticket.Type)
+		set("ticket.tags", strings.Join(ticket.Tags, ", "))
+		if ticket.DueBy != nil {
+			set("ticket.due_by_time", ticket.DueBy)
+		}
+		set("ticket.url", c.GetAgentTicketURL(ticket.ID))
+		set("ticket.portal_url", (*fresh.Client)(c).Permalink("/support/tickets/%d", ticket.ID))
+		for k, v := range ticket.CustomFields {
+			set("ticket."+k, v)
+		}
+
+		// the description is html
+		phs["ticket.description"] = ticket.Description
+
+		if contact == nil {
+			contact = ticket.Requester
+		}
+	}
+
+	if contact != nil {
+		first, last, _ := strings.Cut(contact.Name, " ")
+
+		set("ticket.requester.id", contact.ID)
+		set("ticket.requester.name", contact.Name)
+		set("ticket.requester.firstname", first)
+		set("ticket.requester.lastname", last)
+		set("ticket.requester.email", contact.Email)
+		set("ticket.requester.phone", contact.Phone)
+		set("ticket.requester.mobile", contact.Mobile)
+		set("ticket.requester.address", contact.Address)
+		set("ticket.requester.job_title", contact.JobTitle)
+		set("ticket.requester.language", contact.Language)
+		for k, v := range contact.CustomFields {
+			set("ticket.requester."+k, v)
+		}
+	}
+
+	return phs
+}
+
+var rePlaceholder = regexp.MustCompile(`{{\s*([\w.]+)\s*}}`)
+
+// ErrTicketRequired the ticket is nil
+var ErrTicketRequired = errors.New("freshdesk: the ticket is required")
+
+// ErrUnresolvedPlaceholders the content contains the placeholders which have no value
+var ErrUnresolvedPlaceholders = errors.New("freshdesk: unresolved placeholders")
+
+// RenderPlaceholders replaces the placeholders "{{name}}" of the html content by the values,
+// the unknown placeholders are kept as is.
+func RenderPlaceholders(content string, values map[string]string) string {
+	return rePlaceholder.ReplaceAllStringFunc(content, func(s string) string {
+		name := rePlaceholder.FindStringSubmatch(s)[1]
+		if v, ok := values[name]; ok {
+			return v
+		}
+		return s
+	})
+}
+
+// RenderPlaceholdersStrict is like RenderPlaceholders but returns an error wrapping ErrUnresolvedPlaceholders
+// with the names of the unknown placeholders.
+func RenderPlaceholdersStrict(content string, values map[string]string) (string, error) {
+	var unknowns []string
+	for _, m := range rePlaceholder.FindAllStringSubmatch(content, -1) {
+		if _, ok := values[m[1]]; !ok && !slices.Contains(unknowns, m[1]) {
+			unknowns = append(unknowns, m[1])
+		}
+	}
+	if len(unknowns) > 0 {
+		return "", fmt.Errorf("%w: %s", ErrUnresolvedPlaceholders, strings.Join(unknowns, ", "))
+	}
+	return RenderPlaceholders(content, values), nil
+}
+
+// RenderCannedResponse renders the placeholders of the canned response content with the ticket and the contact (the requester),
+// see CannedResponsePlaceholders. The unknown placeholders are kept as is.
+func (c *Client) RenderCannedResponse(cr *CannedResponse, ticket *Ticket, contact *Contact) string {
+	return RenderPlaceholders(cr.ContentHTML, c.CannedResponsePlaceholders(ticket, contact))
+}
+
+// ReplyWithCannedResponse renders the canned response with the ticket and the contact (the requester),
+// and posts it to the ticket by CreateReply.
+// The requester is got by GetContact if both the contact and the ticket.Requester are nil.
+// The attachments of the canned response are downloaded to a temporary directory and streamed to the reply.
+// The reply (can be nil) provides the other fields of the reply (e.g. FromEmail, CcEmails), it will not be modified.
+// An error wrapping ErrUnresolvedPlaceholders is returned without posting if the canned response contains unknown placeholders.
+func (c *Client) ReplyWithCannedResponse(ctx context.Context, ticket *Ticket, contact *Contact, crid int64, reply *ReplyCreate) (*Reply, error) {
+	if ticket == nil {
+		return nil, ErrTicketRequired
+	}
+
+	cr, err := c.GetCannedResponse(ctx, crid)
+	if err != nil {
+		return nil, err
+	}
+
+	if contact == nil && ticket.Requester == nil && ticket.RequesterID != 0 {
+		if contact, err = c.GetContact(ctx, ticket.RequesterID); err != nil {
+			return nil, err
+		}
+	}
+
+	body, err := RenderPlaceholdersStrict(cr.ContentHTML, c.CannedResponsePlaceholders(ticket, contact))
+	if err != nil {
+		return nil, fmt.Errorf("canned response #%d: %w", cr.ID, err)
+	}
+
+	rc := &ReplyCreate{}
+	if reply != nil {
+		*rc = *reply
+	}
+	rc.Body = body
+	rc.Attachments = append([]*Attachment{}, rc.Attachments...)
+
+	if len(cr.Attachments) > 0 {
+		dir, err := os.MkdirTemp("", "freshdesk-")
+		if err != nil {
+			return nil, err
+		}
+		defer os.RemoveAll(dir)
+
+		drs, err := c.DownloadAttachments(ctx, dir, cr.Attachments, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		for i, dr := range drs {
+			f, err := os.Open(dr.Path)
+			if err != nil {
+				return nil, err
+			}
+			defer f.Close()
+
+			rc.Attachments = append(rc.Attachments, NewAttachmentReader(cr.Attachments[i].Name, f))
+		}
+	}
+
+	return c.CreateReply(ctx, ticket.ID, rc)
+}
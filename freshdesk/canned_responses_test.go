@@ -0,0 +1,50 @@
+package freshdesk
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRenderCannedResponse(t *testing.T) {
+	fd := &Client{Domain: "example.freshdesk.com"}
+
+	ticket := &Ticket{
+		ID:           12,
+		Subject:      "Tom & Jerry",
+		Description:  "<p>help</p>",
+		Status:       TicketStatusPending,
+		Tags:         []string{"a", "b"},
+		CustomFields: map[string]any{"cf_order": 1001},
+		Requester:    &Contact{Name: "Mary Ann Lee", Email: "mary@example.com"},
+	}
+	cr := &CannedResponse{
+		ContentHTML: "{{ticket.requester.firstname}}/{{ticket.requester.lastname}} {{ticket.subject}} {{ticket.status}} [{{ticket.tags}}] " +
+			"{{ticket.cf_order}} {{ticket.description}} {{ticket.url}} {{ ticket.portal_url }} {{ticket.agent.name}}",
+	}
+
+	want := "Mary/Ann Lee Tom &amp; Jerry Pending [a, b] 1001 <p>help</p> " +
+		"https://example.freshdesk.com/a/tickets/12 https://example.freshdesk.com/support/tickets/12 {{ticket.agent.name}}"
+	if a := fd.RenderCannedResponse(cr, ticket, nil); a != want {
+		t.Errorf("RenderCannedResponse() = %q, want %q", a, want)
+	}
+
+	// the contact overrides the ticket.Requester
+	cr.ContentHTML = "Dear {{ticket.requester.name}}"
+	if a := fd.RenderCannedResponse(cr, ticket, &Contact{Name: "Bob"}); a != "Dear Bob" {
+		t.Errorf("RenderCannedResponse() = %q", a)
+	}
+}
+
+func TestRenderPlaceholdersStrict(t *testing.T) {
+	values := map[string]string{"ticket.id": "12"}
+
+	a, err := RenderPlaceholdersStrict("#{{ ticket.id }}", values)
+	if err != nil || a != "#12" {
+		t.Errorf("RenderPlaceholdersStrict() = %q, %v", a, err)
+	}
+
+	_, err = RenderPlaceholdersStrict("{{ticket.agent.name}} #{{ticket.id}} {{ticket.x}} {{ticket.agent.name}}", values)
+	if !errors.Is(err, ErrUnresolvedPlaceholders) || err.Error() != "freshdesk: unresolved placeholders: ticket.agent.name, ticket.x" {
+		t.Errorf("RenderPlaceholdersStrict() = %v, want %v", err, ErrUnresolvedPlaceholders)
+	}
+}
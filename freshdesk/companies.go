@@ -2,7 +2,10 @@ package freshdesk
 
 import (
 	"context"
+	"iter"
 	"net/url"
+
+	"github.com/askasoft/gofresh/fresh"
 )
 
 // ---------------------------------------------------
@@ -27,39 +30,27 @@ func (c *Client) GetCompany(ctx context.Context, cid int64) (*Company, error) {
 }
 
 func (c *Client) ListCompanies(ctx context.Context, lco *ListCompaniesOption) ([]*Company, bool, error) {
+	return hasNext(c.listCompanies(ctx, lco))
+}
+
+func (c *Client) listCompanies(ctx context.Context, lo ListOption) ([]*Company, string, error) {
 	url := c.Endpoint("/companies")
 	result := []*Company{}
-	next, err := c.DoList(ctx, url, lco, &result)
+	next, err := c.DoListLink(ctx, url, lo, &result)
 	return result, next, err
 }
 
 func (c *Client) IterCompanies(ctx context.Context, lco *ListCompaniesOption, icf func(*Company) error) error {
-	if lco == nil {
-		lco = &ListCompaniesOption{}
-	}
-	if lco.Page < 1 {
-		lco.Page = 1
-	}
-	if lco.PerPage < 1 {
-		lco.PerPage = 100
-	}
+	return c.companiesPaginator().Iter(ctx, lco, icf)
+}
 
-	for {
-		companies, next, err := c.ListCompanies(ctx, lco)
-		if err != nil {
-			return err
-		}
-		for _, c := range companies {
-			if err = icf(c); err != nil {
-				return err
-			}
-		}
-		if !next {
-			break
-		}
-		lco.Page++
-	}
-	return nil
+// AllCompanies is like IterCompanies but returns an iterator, the lco will not be modified.
+func (c *Client) AllCompanies(ctx context.Context, lco *ListCompaniesOption) iter.Seq2[*Company, error] {
+	return c.companiesPaginator().All(ctx, lco)
+}
+
+func (c *Client) companiesPaginator() *fresh.Paginator[*Company] {
+	return newPaginator(c.listCompanies)
 }
 
 // Search Companies
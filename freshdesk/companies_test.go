@@ -5,7 +5,7 @@ import (
 )
 
 func TestListCompanies(t *testing.T) {
-	fd := testNewFreshdesk(t)
+	fd := testNewLiveFreshdesk(t)
 	if fd == nil {
 		return
 	}
@@ -22,7 +22,7 @@ func TestListCompanies(t *testing.T) {
 }
 
 func TestExportCompany(t *testing.T) {
-	fd := testNewFreshdesk(t)
+	fd := testNewLiveFreshdesk(t)
 	if fd == nil {
 		return
 	}
@@ -5,7 +5,7 @@ import (
 )
 
 func TestCompanyFieldsAPIs(t *testing.T) {
-	fd := testNewFreshdesk(t)
+	fd := testNewLiveFreshdesk(t)
 	if fd == nil {
 		return
 	}
@@ -46,7 +46,7 @@ func TestCompanyFieldsAPIs(t *testing.T) {
 }
 
 func TestListCompanyFieldsAPIs(t *testing.T) {
-	fd := testNewFreshdesk(t)
+	fd := testNewLiveFreshdesk(t)
 	if fd == nil {
 		return
 	}
@@ -5,7 +5,7 @@ import (
 )
 
 func TestContactFieldsAPIs(t *testing.T) {
-	fd := testNewFreshdesk(t)
+	fd := testNewLiveFreshdesk(t)
 	if fd == nil {
 		return
 	}
@@ -49,7 +49,7 @@ func TestContactFieldsAPIs(t *testing.T) {
 }
 
 func TestListContactFieldsAPIs(t *testing.T) {
-	fd := testNewFreshdesk(t)
+	fd := testNewLiveFreshdesk(t)
 	if fd == nil {
 		return
 	}
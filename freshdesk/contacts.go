@@ -2,7 +2,10 @@ package freshdesk
 
 import (
 	"context"
+	"iter"
 	"net/url"
+
+	"github.com/askasoft/gofresh/fresh"
 )
 
 // ---------------------------------------------------
@@ -102,39 +105,67 @@ func (c *Client) HardDeleteContact(ctx context.Context, cid int64, force ...bool
 }
 
 func (c *Client) ListContacts(ctx context.Context, lco *ListContactsOption) ([]*Contact, bool, error) {
+	return hasNext(c.listContacts(ctx, lco))
+}
+
+func (c *Client) listContacts(ctx context.Context, lo ListOption) ([]*Contact, string, error) {
 	url := c.Endpoint("/contacts")
 	contacts := []*Contact{}
-	next, err := c.DoList(ctx, url, lco, &contacts)
+	next, err := c.DoListLink(ctx, url, lo, &contacts)
 	return contacts, next, err
 }
 
 func (c *Client) IterContacts(ctx context.Context, lco *ListContactsOption, icf func(*Contact) error) error {
-	if lco == nil {
-		lco = &ListContactsOption{}
-	}
-	if lco.Page < 1 {
-		lco.Page = 1
+	return c.contactsPaginator().Iter(ctx, lco, icf)
+}
+
+// AllContacts is like IterContacts but returns an iterator, the lco will not be modified.
+func (c *Client) AllContacts(ctx context.Context, lco *ListContactsOption) iter.Seq2[*Contact, error] {
+	return c.contactsPaginator().All(ctx, lco)
+}
+
+func (c *Client) contactsPaginator() *fresh.Paginator[*Contact] {
+	return newPaginator(c.listContacts)
+}
+
+// AllContactPages returns an iterator over all the pages of contacts, the lco will not be modified.
+// The Page.Next can be saved to resume the iteration by AllContactPagesFrom.
+func (c *Client) AllContactPages(ctx context.Context, lco *ListContactsOption) iter.Seq2[*Page[*Contact], error] {
+	return c.contactsPaginator().Pages(ctx, lco)
+}
+
+// AllContactPagesFrom returns an iterator over the pages of contacts starting from the next URL (Page.Next).
+func (c *Client) AllContactPagesFrom(ctx context.Context, next string) iter.Seq2[*Page[*Contact], error] {
+	return c.contactsPaginator().PagesFrom(ctx, next)
+}
+
+// IterContactsConcurrently iterates the contacts by concurrent page requests, the contacts are delivered in order.
+// The Freshdesk contacts api does not support ordering by updated_at, so the cco.Window is not used.
+// The lco.Page is ignored, and the lco will not be modified.
+func (c *Client) IterContactsConcurrently(ctx context.Context, lco *ListContactsOption, cco *ConcurrentOption, icf func(*Contact) error) error {
+	return newConcurrent[*Contact](cco, 0).Iter(ctx, c.contactsQueries(lco), icf)
+}
+
+// AllContactsConcurrently is like IterContactsConcurrently but returns an iterator.
+func (c *Client) AllContactsConcurrently(ctx context.Context, lco *ListContactsOption, cco *ConcurrentOption) iter.Seq2[*Contact, error] {
+	return newConcurrent[*Contact](cco, 0).All(ctx, c.contactsQueries(lco))
+}
+
+func (c *Client) contactsQueries(lco *ListContactsOption) []fresh.QueryPageFunc[*Contact] {
+	base := ListContactsOption{PerPage: 100}
+	if lco != nil {
+		base = *lco
 	}
-	if lco.PerPage < 1 {
-		lco.PerPage = 100
+	if base.PerPage < 1 {
+		base.PerPage = 100
 	}
 
-	for {
-		contacts, next, err := c.ListContacts(ctx, lco)
-		if err != nil {
-			return err
-		}
-		for _, c := range contacts {
-			if err = icf(c); err != nil {
-				return err
-			}
-		}
-		if !next {
-			break
-		}
-		lco.Page++
+	qp := func(ctx context.Context, page int) ([]*Contact, bool, error) {
+		o := base
+		o.Page = page
+		return c.ListContacts(ctx, &o)
 	}
-	return nil
+	return []fresh.QueryPageFunc[*Contact]{qp}
 }
 
 func (c *Client) SearchContacts(ctx context.Context, keyword string) ([]*User, error) {
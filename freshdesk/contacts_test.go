@@ -14,7 +14,7 @@ var (
 )
 
 func TestContactAPIs(t *testing.T) {
-	fd := testNewFreshdesk(t)
+	fd := testNewLiveFreshdesk(t)
 	if fd == nil {
 		return
 	}
@@ -63,7 +63,7 @@ func TestContactAPIs(t *testing.T) {
 	}
 }
 func TestExportContacts(t *testing.T) {
-	fd := testNewFreshdesk(t)
+	fd := testNewLiveFreshdesk(t)
 	if fd == nil {
 		return
 	}
@@ -81,7 +81,7 @@ func TestExportContacts(t *testing.T) {
 }
 
 func TestGetContacts(t *testing.T) {
-	fd := testNewFreshdesk(t)
+	fd := testNewLiveFreshdesk(t)
 	if fd == nil {
 		return
 	}
@@ -0,0 +1,41 @@
+package freshdesk
+
+import (
+	"context"
+	"path/filepath"
+	"strconv"
+
+	"github.com/askasoft/gofresh/fresh"
+)
+
+// DownloadAttachment downloads the attachment from its AttachmentURL to the path atomically,
+// the size of the downloaded file is checked by the a.Size.
+func (c *Client) DownloadAttachment(ctx context.Context, a *Attachment, path string) (*DownloadResult, error) {
+	return c.DoDownloadFileNoAuth(ctx, a.AttachmentURL, path, &DownloadOption{Size: int64(a.Size)})
+}
+
+// DownloadAttachments downloads the attachments to the directory dir concurrently,
+// the files are named "<attachment id>_<name>", and workers is the maximum number of concurrent downloads (default 4).
+func (c *Client) DownloadAttachments(ctx context.Context, dir string, as []*Attachment, workers int) ([]*DownloadResult, error) {
+	return fresh.DownloadAttachments(ctx, fresh.AttachmentFiles(dir, as), workers, c.DownloadAttachment)
+}
+
+// DownloadTicketAttachments downloads the attachments of the ticket and its conversations to the directory dir concurrently,
+// the ticket attachments are saved as "<attachment id>_<name>",
+// and the conversation attachments are saved as "<conversation id>/<attachment id>_<name>".
+func (c *Client) DownloadTicketAttachments(ctx context.Context, tid int64, dir string, workers int) ([]*DownloadResult, error) {
+	ticket, err := c.GetTicket(ctx, tid)
+	if err != nil {
+		return nil, err
+	}
+
+	afs := fresh.AttachmentFiles(dir, ticket.Attachments)
+	for conv, err := range c.AllTicketConversations(ctx, tid, nil) {
+		if err != nil {
+			return nil, err
+		}
+		afs = append(afs, fresh.AttachmentFiles(filepath.Join(dir, strconv.FormatInt(conv.ID, 10)), conv.Attachments)...)
+	}
+
+	return fresh.DownloadAttachments(ctx, afs, workers, c.DownloadAttachment)
+}
@@ -0,0 +1,60 @@
+package freshdesk_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/askasoft/gofresh/freshdesk"
+	"github.com/askasoft/gofresh/freshdesk/fdtest"
+)
+
+func TestDownloadTicketAttachments(t *testing.T) {
+	fs := fdtest.NewServer()
+	defer fs.Close()
+
+	fd := testNewClient(fs)
+
+	tc := &freshdesk.TicketCreate{
+		Email:       "requester@example.com",
+		Subject:     "test",
+		Description: "description",
+		Attachments: []*freshdesk.Attachment{freshdesk.NewAttachment("a/b.txt", []byte("ticket"))},
+	}
+	ticket, err := fd.CreateTicket(ctxbg, tc)
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+
+	nc := &freshdesk.NoteCreate{
+		Body:        "note",
+		Attachments: []*freshdesk.Attachment{freshdesk.NewAttachment("note.txt", []byte("note"))},
+	}
+	note, err := fd.CreateNote(ctxbg, ticket.ID, nc)
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+
+	dir := t.TempDir()
+	drs, err := fd.DownloadTicketAttachments(ctxbg, ticket.ID, dir, 2)
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+
+	want := map[string]string{
+		fmt.Sprintf("%d_b.txt", ticket.Attachments[0].ID):              "ticket",
+		fmt.Sprintf("%d/%d_note.txt", note.ID, note.Attachments[0].ID): "note",
+	}
+	if len(drs) != len(want) {
+		t.Fatalf("DownloadTicketAttachments() = %v", drs)
+	}
+	for _, dr := range drs {
+		rel, _ := filepath.Rel(dir, dr.Path)
+		bs, err := os.ReadFile(dr.Path)
+		if err != nil || string(bs) != want[filepath.ToSlash(rel)] || !strings.HasPrefix(dr.ContentType, "text/plain") {
+			t.Errorf("%v = %q, %v", dr, bs, err)
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package freshdesk
+
+type EmailConfig struct {
+	ID int64 `json:"id,omitempty"`
+
+	// Name of the email config
+	Name string `json:"name,omitempty"`
+
+	// ID of the product associated with the email config
+	ProductID int64 `json:"product_id,omitempty"`
+
+	// The email address to which the emails of the customers are sent (the support email)
+	ToEmail string `json:"to_email,omitempty"`
+
+	// The email address from which the replies are sent
+	ReplyEmail string `json:"reply_email,omitempty"`
+
+	// ID of the group to which the tickets of the email config are assigned
+	GroupID int64 `json:"group_id,omitempty"`
+
+	// Set to true if it is the primary email config
+	PrimaryRole bool `json:"primary_role,omitempty"`
+
+	// Set to true if the email config is active
+	Active bool `json:"active,omitempty"`
+
+	CreatedAt Time `json:"created_at,omitzero"`
+
+	UpdatedAt Time `json:"updated_at,omitzero"`
+}
+
+func (ec *EmailConfig) String() string {
+	return toString(ec)
+}
@@ -0,0 +1,146 @@
+package freshdesk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"net/mail"
+	"strings"
+
+	"github.com/askasoft/gofresh/fresh"
+)
+
+// ---------------------------------------------------
+// Email Config
+
+type ListEmailConfigsOption = PageOption
+
+func (c *Client) GetEmailConfig(ctx context.Context, ecid int64) (*EmailConfig, error) {
+	url := c.Endpoint("/email_configs/%d", ecid)
+	ec := &EmailConfig{}
+	err := c.DoGet(ctx, url, ec)
+	return ec, err
+}
+
+func (c *Client) ListEmailConfigs(ctx context.Context, leco *ListEmailConfigsOption) ([]*EmailConfig, bool, error) {
+	return hasNext(c.listEmailConfigs(ctx, leco))
+}
+
+func (c *Client) listEmailConfigs(ctx context.Context, lo ListOption) ([]*EmailConfig, string, error) {
+	url := c.Endpoint("/email_configs")
+	ecs := []*EmailConfig{}
+	next, err := c.DoListLink(ctx, url, lo, &ecs)
+	return ecs, next, err
+}
+
+func (c *Client) IterEmailConfigs(ctx context.Context, leco *ListEmailConfigsOption, iecf func(*EmailConfig) error) error {
+	return c.emailConfigsPaginator().Iter(ctx, leco, iecf)
+}
+
+// AllEmailConfigs is like IterEmailConfigs but returns an iterator, the leco will not be modified.
+func (c *Client) AllEmailConfigs(ctx context.Context, leco *ListEmailConfigsOption) iter.Seq2[*EmailConfig, error] {
+	return c.emailConfigsPaginator().All(ctx, leco)
+}
+
+func (c *Client) emailConfigsPaginator() *fresh.Paginator[*EmailConfig] {
+	return newPaginator(c.listEmailConfigs)
+}
+
+// ErrEmailConfigNotFound no active email config matches the from address
+var ErrEmailConfigNotFound = errors.New("freshdesk: email config not found")
+
+// EmailConfigResolver resolves the from addresses to the active email configs,
+// a from address matches the ReplyEmail (preferred) or the ToEmail of an email config case-insensitively.
+type EmailConfigResolver struct {
+	replies map[string]*EmailConfig
+	tos     map[string]*EmailConfig
+}
+
+// NewEmailConfigResolver returns a EmailConfigResolver of the email configs, the inactive email configs are ignored.
+func NewEmailConfigResolver(ecs []*EmailConfig) *EmailConfigResolver {
+	ecr := &EmailConfigResolver{
+		replies: make(map[string]*EmailConfig, len(ecs)),
+		tos:     make(map[string]*EmailConfig, len(ecs)),
+	}
+
+	for _, ec := range ecs {
+		if !ec.Active {
+			continue
+		}
+		if k := emailAddress(ec.ReplyEmail); k != "" {
+			if _, ok := ecr.replies[k]; !ok {
+				ecr.replies[k] = ec
+			}
+		}
+		if k := emailAddress(ec.ToEmail); k != "" {
+			if _, ok := ecr.tos[k]; !ok {
+				ecr.tos[k] = ec
+			}
+		}
+	}
+	return ecr
+}
+
+// LoadEmailConfigResolver returns a EmailConfigResolver of all the email configs.
+func (c *Client) LoadEmailConfigResolver(ctx context.Context) (*EmailConfigResolver, error) {
+	ecs := []*EmailConfig{}
+	for ec, err := range c.AllEmailConfigs(ctx, nil) {
+		if err != nil {
+			return nil, err
+		}
+		ecs = append(ecs, ec)
+	}
+	return NewEmailConfigResolver(ecs), nil
+}
+
+// Resolve returns the email config of the from address (e.g. "sales@example.com", "Sales <sales@example.com>"),
+// returns an error which wraps ErrEmailConfigNotFound if no active email config matches.
+func (ecr *EmailConfigResolver) Resolve(from string) (*EmailConfig, error) {
+	k := emailAddress(from)
+	if ec, ok := ecr.replies[k]; ok {
+		return ec, nil
+	}
+	if ec, ok := ecr.tos[k]; ok {
+		return ec, nil
+	}
+	return nil, fmt.Errorf("%w: %q", ErrEmailConfigNotFound, from)
+}
+
+// EmailConfigID returns the ID of the email config of the from address, see Resolve.
+func (ecr *EmailConfigResolver) EmailConfigID(from string) (int64, error) {
+	ec, err := ecr.Resolve(from)
+	if err != nil {
+		return 0, err
+	}
+	return ec.ID, nil
+}
+
+// emailAddress returns the lower case address of the email s (e.g. "Name <addr>"), returns the trimmed s if it can not be parsed.
+func emailAddress(s string) string {
+	if a, err := mail.ParseAddress(s); err == nil {
+		s = a.Address
+	}
+	return strings.ToLower(strings.TrimSpace(s))
+}
+
+// CreateOutboundEmailFrom creates the outbound email from the address (e.g. "sales@example.com"),
+// the EmailConfigID of the email is resolved by the ecr (LoadEmailConfigResolver is called if ecr is nil).
+// The email will not be modified.
+func (c *Client) CreateOutboundEmailFrom(ctx context.Context, ecr *EmailConfigResolver, from string, email *OutboundEmail) (*Ticket, error) {
+	if ecr == nil {
+		var err error
+		if ecr, err = c.LoadEmailConfigResolver(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	ecid, err := ecr.EmailConfigID(from)
+	if err != nil {
+		return nil, err
+	}
+
+	oe := *email
+	oe.EmailConfigID = ecid
+	return c.CreateOutboundEmail(ctx, &oe)
+}
@@ -0,0 +1,44 @@
+package freshdesk
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestEmailConfigResolver(t *testing.T) {
+	ecr := NewEmailConfigResolver([]*EmailConfig{
+		{ID: 1, ToEmail: "support@example.com", ReplyEmail: "support@example.com", Active: true, PrimaryRole: true},
+		{ID: 2, ToEmail: "sales@example.com", ReplyEmail: "Sales@Example.com", Active: true},
+		{ID: 3, ToEmail: "brand@example.freshdesk.com", ReplyEmail: "hello@brand.com", Active: true},
+		{ID: 4, ToEmail: "old@example.com", ReplyEmail: "old@example.com", Active: false},
+		{ID: 5, ToEmail: "hello@brand.com", ReplyEmail: "noreply@brand.com", Active: true},
+	})
+
+	cs := []struct {
+		from string
+		want int64
+	}{
+		{"support@example.com", 1},
+		{"sales@example.com", 2},
+		{"Sales Team <SALES@example.com>", 2},
+		{"  hello@brand.com ", 3},
+		{"brand@example.freshdesk.com", 3},
+		{"noreply@brand.com", 5},
+	}
+
+	for i, c := range cs {
+		a, err := ecr.EmailConfigID(c.from)
+		if err != nil {
+			t.Fatalf("#%d EmailConfigID(%q): %v", i, c.from, err)
+		}
+		if a != c.want {
+			t.Errorf("#%d EmailConfigID(%q) = %d, want %d", i, c.from, a, c.want)
+		}
+	}
+
+	for _, from := range []string{"old@example.com", "unknown@example.com", ""} {
+		if _, err := ecr.EmailConfigID(from); !errors.Is(err, ErrEmailConfigNotFound) || errors.Is(err, ErrNotFound) {
+			t.Errorf("EmailConfigID(%q) = %v, want %v", from, err, ErrEmailConfigNotFound)
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package freshdesk_test
+
+import (
+	"context"
+	"time"
+
+	"github.com/askasoft/gofresh/freshdesk"
+	"github.com/askasoft/gofresh/freshdesk/fdtest"
+)
+
+var ctxbg = context.Background()
+
+// testNewClient returns a freshdesk.Client connected to the emulator.
+func testNewClient(s *fdtest.Server) *freshdesk.Client {
+	return &freshdesk.Client{
+		Domain:  s.Domain,
+		APIKey:  s.APIKey,
+		BaseURL: s.URL,
+	}
+}
+
+// testClock returns a clock which advances a second on every call.
+func testClock(start time.Time) func() time.Time {
+	n := 0
+	return func() time.Time {
+		n++
+		return start.Add(time.Duration(n) * time.Second)
+	}
+}
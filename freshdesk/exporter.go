@@ -0,0 +1,103 @@
+package freshdesk
+
+import (
+	"context"
+	"iter"
+
+	"github.com/askasoft/gofresh/fresh/export"
+)
+
+// TicketExportColumns the default CSV columns of the exported tickets
+var TicketExportColumns = []*export.Column[*Ticket]{
+	{Name: "id", Value: func(t *Ticket) any { return t.ID }},
+	{Name: "parent_id", Value: func(t *Ticket) any { return t.ParentID }},
+	{Name: "subject", Value: func(t *Ticket) any { return t.Subject }},
+	{Name: "description_text", Value: func(t *Ticket) any { return t.DescriptionText }},
+	{Name: "type", Value: func(t *Ticket) any { return t.Type }},
+	{Name: "status", Value: func(t *Ticket) any { return t.Status }},
+	{Name: "priority", Value: func(t *Ticket) any { return t.Priority }},
+	{Name: "source", Value: func(t *Ticket) any { return t.Source }},
+	{Name: "requester_id", Value: func(t *Ticket) any { return t.RequesterID }},
+	{Name: "responder_id", Value: func(t *Ticket) any { return t.ResponderID }},
+	{Name: "group_id", Value: func(t *Ticket) any { return t.GroupID }},
+	{Name: "company_id", Value: func(t *Ticket) any { return t.CompanyID }},
+	{Name: "product_id", Value: func(t *Ticket) any { return t.ProductID }},
+	{Name: "email_config_id", Value: func(t *Ticket) any { return t.EmailConfigID }},
+	{Name: "to_emails", Value: func(t *Ticket) any { return t.ToEmails }},
+	{Name: "cc_emails", Value: func(t *Ticket) any { return t.CcEmails }},
+	{Name: "tags", Value: func(t *Ticket) any { return t.Tags }},
+	{Name: "spam", Value: func(t *Ticket) any { return t.Spam }},
+	{Name: "deleted", Value: func(t *Ticket) any { return t.Deleted }},
+	{Name: "due_by", Value: func(t *Ticket) any { return t.DueBy }},
+	{Name: "fr_due_by", Value: func(t *Ticket) any { return t.FrDueBy }},
+	{Name: "created_at", Value: func(t *Ticket) any { return &t.CreatedAt }},
+	{Name: "updated_at", Value: func(t *Ticket) any { return &t.UpdatedAt }},
+}
+
+// ConversationExportColumns the default CSV columns of the exported conversations
+var ConversationExportColumns = []*export.Column[*Conversation]{
+	{Name: "id", Value: func(c *Conversation) any { return c.ID }},
+	{Name: "ticket_id", Value: func(c *Conversation) any { return c.TicketID }},
+	{Name: "user_id", Value: func(c *Conversation) any { return c.UserID }},
+	{Name: "source", Value: func(c *Conversation) any { return c.Source }},
+	{Name: "incoming", Value: func(c *Conversation) any { return c.Incoming }},
+	{Name: "private", Value: func(c *Conversation) any { return c.Private }},
+	{Name: "support_email", Value: func(c *Conversation) any { return c.SupportEmail }},
+	{Name: "from_email", Value: func(c *Conversation) any { return c.FromEmail }},
+	{Name: "to_emails", Value: func(c *Conversation) any { return c.ToEmails }},
+	{Name: "cc_emails", Value: func(c *Conversation) any { return c.CcEmails }},
+	{Name: "bcc_emails", Value: func(c *Conversation) any { return c.BccEmails }},
+	{Name: "body_text", Value: func(c *Conversation) any { return c.BodyText }},
+	{Name: "created_at", Value: func(c *Conversation) any { return &c.CreatedAt }},
+	{Name: "updated_at", Value: func(c *Conversation) any { return &c.UpdatedAt }},
+}
+
+// NewTicketExporter returns an Exporter which exports the tickets of lto with their conversations and attachments to the dir.
+// The attachments are downloaded from the AttachmentURL without authentication (the url is pre-signed).
+// The tickets are listed by AllTicketsSliced to get around the 300 pages limit of ListTickets,
+// and each ticket is got by GetTicket for the description and the attachments which are not listed.
+// Note that only the tickets created in the past 30 days are listed if lto.UpdatedSince is not set.
+// The lto.Page/OrderBy/OrderType are ignored, and the lto will not be modified.
+func (c *Client) NewTicketExporter(dir string, format export.Format, lto *ListTicketsOption) *export.Exporter[*Ticket, *Conversation] {
+	base := ListTicketsOption{}
+	if lto != nil {
+		base = *lto
+	}
+
+	src := &export.Source[*Ticket, *Conversation]{
+		Tickets: func(ctx context.Context) iter.Seq2[*Ticket, error] {
+			o := base
+			return c.getTickets(ctx, c.AllTicketsSliced(ctx, &o))
+		},
+		Conversations: func(ctx context.Context, t *Ticket) iter.Seq2[*Conversation, error] {
+			return c.AllTicketConversations(ctx, t.ID, nil)
+		},
+		TicketID:                func(t *Ticket) int64 { return t.ID },
+		ConversationID:          func(c *Conversation) int64 { return c.ID },
+		TicketAttachments:       func(t *Ticket) []*Attachment { return t.Attachments },
+		ConversationAttachments: func(c *Conversation) []*Attachment { return c.Attachments },
+		TicketCustomFields:      func(t *Ticket) map[string]any { return t.CustomFields },
+		TicketColumns:           TicketExportColumns,
+		ConversationColumns:     ConversationExportColumns,
+		Download: func(ctx context.Context, a *Attachment, path string) error {
+			_, err := c.DownloadAttachment(ctx, a, path)
+			return err
+		},
+	}
+	return export.NewExporter(src, dir, format)
+}
+
+// getTickets gets each ticket of the tickets by GetTicket,
+// since the listed tickets have no description and attachments.
+func (c *Client) getTickets(ctx context.Context, tickets iter.Seq2[*Ticket, error]) iter.Seq2[*Ticket, error] {
+	return func(yield func(*Ticket, error) bool) {
+		for t, err := range tickets {
+			if err == nil {
+				t, err = c.GetTicket(ctx, t.ID)
+			}
+			if !yield(t, err) || err != nil {
+				return
+			}
+		}
+	}
+}
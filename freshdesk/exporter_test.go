@@ -0,0 +1,95 @@
+package freshdesk
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/askasoft/gofresh/fresh/export"
+	"github.com/askasoft/gofresh/freshdesk/fdtest"
+	"github.com/askasoft/pango/num"
+)
+
+func TestTicketExporter(t *testing.T) {
+	fd := testNewFreshdesk(t)
+	if fd == nil {
+		return
+	}
+
+	tc := &TicketCreate{
+		Name:        "Export Test",
+		Email:       "export@example.com",
+		Subject:     "export test",
+		Description: "export test",
+		Status:      TicketStatusOpen,
+		Priority:    TicketPriorityLow,
+	}
+	ticket, err := fd.CreateTicket(ctxbg, tc)
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	defer fd.DeleteTicket(ctxbg, ticket.ID)
+
+	dir := t.TempDir()
+	ex := fd.NewTicketExporter(dir, export.FormatJSONL, &ListTicketsOption{UpdatedSince: ticket.CreatedAt})
+	if err := ex.Export(ctxbg); err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+
+	f, err := os.Open(filepath.Join(dir, "tickets.jsonl"))
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	defer f.Close()
+
+	found := false
+	for s := bufio.NewScanner(f); s.Scan(); {
+		et := &Ticket{}
+		if err := json.Unmarshal(s.Bytes(), et); err != nil {
+			t.Fatalf("ERROR: %v", err)
+		}
+		found = found || et.ID == ticket.ID
+	}
+	if !found {
+		t.Errorf("ticket #%d is not exported", ticket.ID)
+	}
+}
+
+func TestTicketExporterAttachments(t *testing.T) {
+	fs := fdtest.NewServer()
+	defer fs.Close()
+
+	fd := &Client{Domain: fs.Domain, APIKey: fs.APIKey, BaseURL: fs.URL}
+
+	tc := &TicketCreate{Email: "export@example.com", Subject: "export", Description: "<p>export description</p>"}
+	tc.AddAttachment("ticket.txt", []byte("ticket"))
+	ticket, err := fd.CreateTicket(ctxbg, tc)
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+
+	dir := t.TempDir()
+	if err := fd.NewTicketExporter(dir, export.FormatJSONL, nil).Export(ctxbg); err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+
+	// the description and the attachments are not listed by the list api
+	bs, err := os.ReadFile(filepath.Join(dir, "tickets.jsonl"))
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	et := &Ticket{}
+	if err := json.Unmarshal(bs, et); err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	if et.ID != ticket.ID || et.Description != ticket.Description || len(et.Attachments) != 1 {
+		t.Errorf("tickets.jsonl = %s", bs)
+	}
+
+	path := filepath.Join(dir, "attachments", num.Ltoa(ticket.ID), num.Ltoa(ticket.Attachments[0].ID)+"_ticket.txt")
+	if bs, err := os.ReadFile(path); err != nil || string(bs) != "ticket" {
+		t.Errorf("%s = %q, %v", path, bs, err)
+	}
+}
@@ -0,0 +1,208 @@
+// Package fdhook provides a http.Handler which receives the webhooks of the Freshdesk automation rules
+// ("Trigger webhook" action), decodes them to the typed events and dispatches them to the registered callbacks.
+//
+// The payload of the webhook is the template configured in the automation action, see PayloadTemplate.
+// The keys of the payload may be nested or dotted ({"ticket": {"id": ...}} or {"ticket.id": ...}),
+// and the payload may be wrapped in a "freshdesk_webhook" object.
+//
+// Example:
+//
+//	h := fdhook.NewHandler(&webhook.Secret{Value: secret})
+//	h.Client = fd // optional, to get the ticket of the event
+//	h.Include = []string{freshdesk.TicketIncludeConversations}
+//	h.OnTicketCreated(func(ctx context.Context, e *fdhook.Event) error {
+//		...
+//	})
+//	http.Handle("/webhooks/freshdesk", h)
+package fdhook
+
+import (
+	"context"
+	"strings"
+
+	"github.com/askasoft/gofresh/fresh/webhook"
+	"github.com/askasoft/gofresh/freshdesk"
+)
+
+// EventType the type of the webhook event
+type EventType string
+
+const (
+	EventTicketCreated EventType = "ticket_created"
+	EventTicketUpdated EventType = "ticket_updated"
+	EventNoteAdded     EventType = "note_added"
+
+	// EventAny registers a callback for all the events
+	EventAny EventType = webhook.EventAny
+)
+
+// PayloadTemplate a json payload template for the "Trigger webhook" action of the automation rules.
+// Set "event" to the EventType of the rule, or remove it to detect the type from the {{triggered_event}}.
+const PayloadTemplate = `{
+  "freshdesk_webhook": {
+    "event": "ticket_updated",
+    "triggered_event": "{{triggered_event}}",
+    "ticket_id": "{{ticket.id}}",
+    "ticket_subject": "{{ticket.subject}}",
+    "ticket_url": "{{ticket.url}}",
+    "ticket_status": "{{ticket.status}}",
+    "ticket_priority": "{{ticket.priority}}",
+    "ticket_source": "{{ticket.source}}",
+    "ticket_type": "{{ticket.ticket_type}}",
+    "ticket_tags": "{{ticket.tags}}",
+    "ticket_group_name": "{{ticket.group.name}}",
+    "ticket_agent_name": "{{ticket.agent.name}}",
+    "ticket_agent_email": "{{ticket.agent.email}}",
+    "ticket_requester_name": "{{ticket.requester.name}}",
+    "ticket_requester_email": "{{ticket.requester.email}}",
+    "ticket_latest_public_comment": "{{ticket.latest_public_comment}}",
+    "ticket_latest_private_comment": "{{ticket.latest_private_comment}}"
+  }
+}`
+
+// Event the decoded webhook event
+type Event struct {
+	Type           EventType
+	TriggeredEvent string // the rendered {{triggered_event}}, e.g. "{ticket_action:created}"
+
+	TicketID       int64
+	Subject        string
+	URL            string
+	Status         freshdesk.TicketStatus
+	Priority       freshdesk.TicketPriority
+	Source         freshdesk.TicketSource
+	TicketType     string
+	Tags           []string
+	GroupName      string
+	AgentName      string
+	AgentEmail     string
+	RequesterName  string
+	RequesterEmail string
+
+	LatestPublicComment  string
+	LatestPrivateComment string
+
+	// Fields all the flattened values of the payload
+	Fields webhook.Fields
+
+	// Ticket the ticket got by Handler.Client, nil if Handler.Client is not set
+	Ticket *freshdesk.Ticket
+}
+
+// ParseEvent decodes the webhook payload to an Event.
+func ParseEvent(data []byte) (*Event, error) {
+	fs, err := webhook.DecodeFields(data, "freshdesk_webhook")
+	if err != nil {
+		return nil, err
+	}
+
+	e := &Event{
+		Type:                 EventType(fs.Get("event")),
+		TriggeredEvent:       fs.Get("triggered_event"),
+		TicketID:             fs.Int64("ticket_id"),
+		Subject:              fs.Get("ticket_subject"),
+		URL:                  fs.Get("ticket_url"),
+		Status:               freshdesk.ParseTicketStatus(fs.Get("ticket_status")),
+		Priority:             freshdesk.ParseTicketPriority(fs.Get("ticket_priority")),
+		Source:               freshdesk.ParseTicketSource(fs.Get("ticket_source")),
+		TicketType:           fs.Get("ticket_type"),
+		Tags:                 fs.Strings("ticket_tags"),
+		GroupName:            fs.Get("ticket_group_name"),
+		AgentName:            fs.Get("ticket_agent_name"),
+		AgentEmail:           fs.Get("ticket_agent_email"),
+		RequesterName:        fs.Get("ticket_requester_name"),
+		RequesterEmail:       fs.Get("ticket_requester_email"),
+		LatestPublicComment:  fs.Get("ticket_latest_public_comment"),
+		LatestPrivateComment: fs.Get("ticket_latest_private_comment"),
+		Fields:               fs,
+	}
+
+	if e.Type == "" {
+		e.Type = detectEventType(e.TriggeredEvent)
+	}
+	return e, nil
+}
+
+func detectEventType(te string) EventType {
+	te = strings.ToLower(te)
+	switch {
+	case strings.Contains(te, "ticket_action:created"):
+		return EventTicketCreated
+	case strings.Contains(te, "note_type") || strings.Contains(te, "reply_sent") || strings.Contains(te, "note_added"):
+		return EventNoteAdded
+	default:
+		return EventTicketUpdated
+	}
+}
+
+// Callback the callback of the webhook events
+type Callback func(ctx context.Context, e *Event) error
+
+// Handler a http.Handler which receives the Freshdesk webhooks.
+// The callbacks are called synchronously in the order of registration, and a callback error is responded
+// as 500 Internal Server Error.
+// The Freshdesk webhooks have no dedupe key, so the Deduper is not used.
+type Handler struct {
+	webhook.Handler[*Event]
+
+	// Client the Freshdesk client to get the ticket of the event (Event.Ticket), nil means no enrichment.
+	Client *freshdesk.Client
+
+	// Include the include options of the GetTicket, e.g. freshdesk.TicketIncludeConversations
+	Include []string
+}
+
+// NewHandler returns a Handler with the Verifier v.
+func NewHandler(v webhook.Verifier) *Handler {
+	h := &Handler{}
+	h.Verifier = v
+	h.Events = (*events)(h)
+	return h
+}
+
+// On registers the callback of the event type, use EventAny to receive all the events.
+func (h *Handler) On(et EventType, cb Callback) {
+	h.Handler.On(string(et), webhook.Callback[*Event](cb))
+}
+
+// OnTicketCreated registers the callback of the EventTicketCreated.
+func (h *Handler) OnTicketCreated(cb Callback) {
+	h.On(EventTicketCreated, cb)
+}
+
+// OnTicketUpdated registers the callback of the EventTicketUpdated.
+func (h *Handler) OnTicketUpdated(cb Callback) {
+	h.On(EventTicketUpdated, cb)
+}
+
+// OnNoteAdded registers the callback of the EventNoteAdded.
+func (h *Handler) OnNoteAdded(cb Callback) {
+	h.On(EventNoteAdded, cb)
+}
+
+// events implements the webhook.Events of the Handler.
+type events Handler
+
+func (es *events) Decode(data []byte) (*Event, error) {
+	return ParseEvent(data)
+}
+
+func (es *events) Type(e *Event) string {
+	return string(e.Type)
+}
+
+func (es *events) Key(e *Event) string {
+	return ""
+}
+
+// Enrich gets the ticket of the event if the Client is set.
+func (es *events) Enrich(ctx context.Context, e *Event) error {
+	if es.Client != nil && e.TicketID != 0 && e.Ticket == nil {
+		t, err := es.Client.GetTicket(ctx, e.TicketID, es.Include...)
+		if err != nil {
+			return err
+		}
+		e.Ticket = t
+	}
+	return nil
+}
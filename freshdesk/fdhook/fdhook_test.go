@@ -0,0 +1,127 @@
+package fdhook
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/askasoft/gofresh/fresh/webhook"
+	"github.com/askasoft/gofresh/freshdesk"
+	"github.com/askasoft/gofresh/freshdesk/fdtest"
+)
+
+func TestParseEvent(t *testing.T) {
+	cs := []struct {
+		p  string
+		et EventType
+		id int64
+	}{
+		{`{"freshdesk_webhook":{"triggered_event":"{ticket_action:created}","ticket_id":123}}`, EventTicketCreated, 123},
+		{`{"triggered_event":"{note_type:private}","ticket.id":"#45"}`, EventNoteAdded, 45},
+		{`{"ticket":{"id":"6"},"triggered_event":"{status:{from:Open,to:Pending}}"}`, EventTicketUpdated, 6},
+		{`{"event":"note_added","ticket_id":"7"}`, EventNoteAdded, 7},
+	}
+
+	for i, c := range cs {
+		e, err := ParseEvent([]byte(c.p))
+		if err != nil {
+			t.Errorf("[%d] ParseEvent() = %v", i, err)
+			continue
+		}
+		if e.Type != c.et || e.TicketID != c.id {
+			t.Errorf("[%d] ParseEvent() = (%v, %d), want (%v, %d)", i, e.Type, e.TicketID, c.et, c.id)
+		}
+	}
+
+	e, err := ParseEvent([]byte(`{"ticket_status":"Pending","ticket_priority":"Urgent","ticket_source":"Portal","ticket_tags":"a, b,"}`))
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	if e.Status != freshdesk.TicketStatusPending || e.Priority != freshdesk.TicketPriorityUrgent || e.Source != freshdesk.TicketSourcePortal {
+		t.Errorf("ParseEvent() = %v, %v, %v", e.Status, e.Priority, e.Source)
+	}
+	if len(e.Tags) != 2 || e.Tags[0] != "a" || e.Tags[1] != "b" {
+		t.Errorf("Tags = %q", e.Tags)
+	}
+
+	if _, err := ParseEvent([]byte(`[]`)); !errors.Is(err, webhook.ErrInvalidPayload) {
+		t.Errorf("ParseEvent([]) = %v", err)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	fs := fdtest.NewServer()
+	defer fs.Close()
+
+	fd := &freshdesk.Client{Domain: fs.Domain, APIKey: fs.APIKey, BaseURL: fs.URL}
+	ticket, err := fd.CreateTicket(context.Background(), &freshdesk.TicketCreate{
+		Email:       "requester@example.com",
+		Subject:     "webhook",
+		Description: "webhook",
+		Status:      freshdesk.TicketStatusOpen,
+		Priority:    freshdesk.TicketPriorityLow,
+	})
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+
+	h := NewHandler(&webhook.Secret{Value: "secret"})
+	h.Client = fd
+	h.Include = []string{freshdesk.TicketIncludeRequester}
+
+	var events []*Event
+	h.OnTicketCreated(func(ctx context.Context, e *Event) error {
+		events = append(events, e)
+		return nil
+	})
+	h.On(EventAny, func(ctx context.Context, e *Event) error {
+		if e.Type == EventNoteAdded {
+			return errors.New("failed")
+		}
+		return nil
+	})
+
+	payload := strings.ReplaceAll(PayloadTemplate, "{{ticket.id}}", "1")
+	payload = strings.ReplaceAll(payload, `"event": "ticket_updated",`, "")
+	payload = strings.ReplaceAll(payload, "{{triggered_event}}", "{ticket_action:created}")
+
+	cs := []struct {
+		m string
+		s string
+		p string
+		w int
+	}{
+		{http.MethodGet, "secret", payload, http.StatusMethodNotAllowed},
+		{http.MethodPost, "invalid", payload, http.StatusUnauthorized},
+		{http.MethodPost, "secret", "{", http.StatusBadRequest},
+		{http.MethodPost, "secret", payload, http.StatusNoContent},
+		{http.MethodPost, "secret", `{"event":"note_added","ticket_id":1}`, http.StatusInternalServerError},
+		{http.MethodPost, "secret", `{"event":"ticket_created","ticket_id":99999}`, http.StatusInternalServerError},
+	}
+
+	for i, c := range cs {
+		req := httptest.NewRequest(c.m, "/webhook", strings.NewReader(c.p))
+		req.Header.Set(webhook.DefaultSecretHeader, c.s)
+
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != c.w {
+			t.Errorf("[%d] ServeHTTP() = %d, want %d", i, rec.Code, c.w)
+		}
+	}
+
+	if len(events) != 1 {
+		t.Fatalf("events = %d, want 1", len(events))
+	}
+
+	e := events[0]
+	if e.Type != EventTicketCreated || e.TicketID != ticket.ID || e.Ticket == nil || e.Ticket.Subject != "webhook" {
+		t.Errorf("event = %+v", e)
+	}
+	if e.Ticket.RequesterID != ticket.RequesterID {
+		t.Errorf("event.Ticket.RequesterID = %d, want %d", e.Ticket.RequesterID, ticket.RequesterID)
+	}
+}
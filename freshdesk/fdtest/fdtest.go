@@ -0,0 +1,842 @@
+// Package fdtest provides an in-memory Freshdesk emulator for the tests.
+//
+// The emulator covers tickets (with the archived tickets and the ticket search), conversations, contacts, companies, groups, agents,
+// solutions (with the article translations), canned responses, time entries, SLA policies, business hours,
+// surveys and satisfaction ratings, email configs and mailboxes.
+// It enforces the basic auth, paginates the lists with the Link headers, returns the ResultError shaped error bodies,
+// and can simulate the 429 Too Many Requests responses with the Retry-After header.
+//
+// The package does not import the freshdesk package, so that it can be used by the tests of the freshdesk package.
+//
+// Example:
+//
+//	fs := fdtest.NewServer()
+//	defer fs.Close()
+//
+//	fd := &freshdesk.Client{Domain: fs.Domain, APIKey: fs.APIKey, BaseURL: fs.URL}
+//	ticket, err := fd.CreateTicket(ctx, &freshdesk.TicketCreate{...})
+package fdtest
+
+import (
+	"net/http"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/askasoft/gofresh/fresh"
+	"github.com/askasoft/gofresh/fresh/freshtest"
+)
+
+const (
+	// Domain the domain of the emulated Freshdesk
+	Domain = "fdtest.freshdesk.com"
+
+	// APIKey the api key of the emulated Freshdesk
+	APIKey = "fdtest-apikey"
+)
+
+// Record a json object stored in the Store
+type Record = freshtest.Record
+
+// Server an in-memory Freshdesk emulator.
+type Server struct {
+	*freshtest.Server
+
+	// TicketsMaxPage the maximum page number of ListTickets, default is 300.
+	TicketsMaxPage int
+}
+
+// NewServer starts and returns a new Freshdesk emulator, the caller should call Close when finished.
+func NewServer() *Server {
+	s := &Server{
+		Server:         freshtest.NewServer(Domain, APIKey),
+		TicketsMaxPage: 300,
+	}
+	s.register()
+	return s
+}
+
+func (s *Server) register() {
+	tickets := &freshtest.Resource{
+		Collection: "tickets",
+		Required:   [][]string{{"requester_id", "phone", "email", "twitter_id", "facebook_id", "unique_external_id"}},
+		Defaults: Record{
+			"status":        int64(2), // open
+			"priority":      int64(1), // low
+			"source":        int64(2), // portal
+			"tags":          []any{},
+			"cc_emails":     []any{},
+			"custom_fields": map[string]any{},
+			"spam":          false,
+			"deleted":       false,
+			"is_escalated":  false,
+		},
+		Match:    matchTicket,
+		Sort:     sortTickets,
+		Validate: s.validateTicket,
+		Removed:  isArchived,
+		Brief:    briefTicket(false),
+	}
+
+	s.Handle(http.MethodGet, "/tickets", func(c *freshtest.Context) {
+		if c.PageNumber() > s.TicketsMaxPage {
+			c.Invalid(fresh.FieldError{Field: "page", Message: "You cannot access a page beyond the 300th page", Code: "invalid_value"})
+			return
+		}
+		if slices.Contains(strings.Split(c.Query.Get("include"), ","), "description") {
+			dts := *tickets
+			dts.Brief = briefTicket(true)
+			dts.List(c)
+			return
+		}
+		tickets.List(c)
+	})
+	s.Handle(http.MethodPost, "/tickets", tickets.Create)
+	s.Handle(http.MethodGet, "/search/tickets", searchTickets)
+	s.Handle(http.MethodPost, "/tickets/outbound_email", (&freshtest.Resource{
+		Collection: "tickets",
+		Required:   [][]string{{"email"}, {"subject"}, {"email_config_id"}},
+		Defaults:   outboundDefaults(tickets.Defaults),
+		Validate:   freshtest.Validators(validateEmailConfig, s.validateTicket),
+	}).Create)
+	s.Handle(http.MethodGet, "/tickets/:id", func(c *freshtest.Context) {
+		if t := tickets.Find(c); t != nil {
+			c.JSON(http.StatusOK, includeTicket(c, t))
+		}
+	})
+	s.Handle(http.MethodPut, "/tickets/:id", tickets.Update)
+	s.Handle(http.MethodDelete, "/tickets/:id", tickets.SoftDelete)
+	s.Handle(http.MethodPut, "/tickets/:id/restore", tickets.Restore)
+
+	conversations := &freshtest.Resource{
+		Collection: "conversations",
+		Parent:     &freshtest.Parent{Collection: "tickets", Key: "ticket_id"},
+	}
+	replies := &freshtest.Resource{
+		Collection: "conversations",
+		Parent:     conversations.Parent,
+		Required:   [][]string{{"body"}},
+		Defaults:   Record{"incoming": false, "private": false, "source": int64(0)},
+		Validate:   s.touchTicket,
+	}
+	notes := &freshtest.Resource{
+		Collection: "conversations",
+		Parent:     conversations.Parent,
+		Required:   [][]string{{"body"}},
+		Defaults:   Record{"incoming": false, "private": true, "source": int64(2)},
+		Validate:   s.touchTicket,
+	}
+
+	s.Handle(http.MethodGet, "/tickets/:id/conversations", func(c *freshtest.Context) {
+		if tickets.Find(c) != nil {
+			conversations.List(c)
+		}
+	})
+	s.Handle(http.MethodPost, "/tickets/:id/reply", replies.Create)
+	s.Handle(http.MethodPost, "/tickets/:id/notes", notes.Create)
+	s.Handle(http.MethodPut, "/conversations/:id", conversations.Update)
+	s.Handle(http.MethodDelete, "/conversations/:id", conversations.Delete)
+	s.Handle(http.MethodDelete, "/attachments/:id", deleteAttachment)
+
+	archivedTickets := &freshtest.Resource{
+		Collection: "tickets",
+		Removed:    func(r Record) bool { return !isArchived(r) },
+	}
+
+	s.Handle(http.MethodGet, "/tickets/archived/:id", archivedTickets.Get)
+	s.Handle(http.MethodDelete, "/tickets/archived/:id", archivedTickets.Delete)
+	s.Handle(http.MethodGet, "/tickets/archived/:id/conversations", func(c *freshtest.Context) {
+		if archivedTickets.Find(c) != nil {
+			conversations.List(c)
+		}
+	})
+
+	timeEntries := &freshtest.Resource{
+		Collection: "time_entries",
+		Parent:     &freshtest.Parent{Collection: "tickets", Key: "ticket_id"},
+		Defaults:   Record{"billable": true, "timer_running": false, "time_spent": "00:00"},
+		Match:      matchTimeEntry,
+	}
+
+	s.Handle(http.MethodGet, "/tickets/:id/time_entries", timeEntries.List)
+	s.Handle(http.MethodPost, "/tickets/:id/time_entries", timeEntries.Create)
+	s.Handle(http.MethodGet, "/time_entries", timeEntries.List)
+	s.Handle(http.MethodGet, "/time_entries/:id", timeEntries.Get)
+	s.Handle(http.MethodPut, "/time_entries/:id", timeEntries.Update)
+	s.Handle(http.MethodDelete, "/time_entries/:id", timeEntries.Delete)
+	s.Handle(http.MethodPut, "/time_entries/:id/toggle_timer", func(c *freshtest.Context) {
+		if te := timeEntries.Find(c); te != nil {
+			te = c.Store().Update("time_entries", te.ID(), Record{"timer_running": !te.Bool("timer_running")})
+			c.JSON(http.StatusOK, te)
+		}
+	})
+
+	contacts := &freshtest.Resource{
+		Collection: "contacts",
+		Required:   [][]string{{"name"}, {"email", "phone", "mobile", "twitter_id", "unique_external_id"}},
+		Defaults:   Record{"active": false, "deleted": false, "tags": []any{}, "custom_fields": map[string]any{}},
+		Match:      matchContact,
+		Validate:   freshtest.Unique("contacts", "email"),
+	}
+
+	s.Handle(http.MethodGet, "/contacts", contacts.List)
+	s.Handle(http.MethodPost, "/contacts", contacts.Create)
+	s.Handle(http.MethodGet, "/contacts/:id", contacts.Get)
+	s.Handle(http.MethodPut, "/contacts/:id", contacts.Update)
+	s.Handle(http.MethodDelete, "/contacts/:id", contacts.SoftDelete)
+	s.Handle(http.MethodDelete, "/contacts/:id/hard_delete", contacts.Delete)
+	s.Handle(http.MethodPut, "/contacts/:id/restore", contacts.Restore)
+
+	s.HandleResource("/companies", &freshtest.Resource{
+		Collection: "companies",
+		Required:   [][]string{{"name"}},
+		Defaults:   Record{"domains": []any{}, "custom_fields": map[string]any{}},
+		Validate:   freshtest.Unique("companies", "name"),
+	})
+
+	s.HandleResource("/groups", &freshtest.Resource{
+		Collection: "groups",
+		Required:   [][]string{{"name"}},
+		Defaults:   Record{"agent_ids": []any{}},
+		Validate:   freshtest.Unique("groups", "name"),
+	})
+
+	agents := &freshtest.Resource{
+		Collection: "agents",
+		Required:   [][]string{{"email"}},
+		Defaults:   Record{"ticket_scope": int64(1), "occasional": false}, // global ticket scope
+		Validate:   validateAgent,
+	}
+
+	s.Handle(http.MethodGet, "/agents/me", func(c *freshtest.Context) {
+		if as := c.Store().Find("agents", nil); len(as) > 0 {
+			c.JSON(http.StatusOK, as[0])
+			return
+		}
+		c.NotFound()
+	})
+	s.HandleResource("/agents", agents)
+
+	s.registerSolutions()
+	s.registerCannedResponses()
+
+	slaPolicies := &freshtest.Resource{
+		Collection: "sla_policies",
+		Required:   [][]string{{"name"}, {"sla_target"}},
+		Defaults:   Record{"active": true, "is_default": false},
+	}
+
+	s.Handle(http.MethodGet, "/sla_policies", slaPolicies.List)
+	s.Handle(http.MethodPost, "/sla_policies", slaPolicies.Create)
+	s.Handle(http.MethodPut, "/sla_policies/:id", slaPolicies.Update)
+
+	// the business hours are read only, insert them to the Store directly
+	businessHours := &freshtest.Resource{Collection: "business_hours"}
+
+	s.Handle(http.MethodGet, "/business_hours", businessHours.List)
+	s.Handle(http.MethodGet, "/business_hours/:id", businessHours.Get)
+
+	// the surveys are read only, insert them to the Store directly
+	surveys := &freshtest.Resource{
+		Collection: "surveys",
+		Match: func(c *freshtest.Context, r Record) bool {
+			return c.Query.Get("state") != "active" || r.Bool("active")
+		},
+	}
+	ratings := &freshtest.Resource{
+		Collection: "satisfaction_ratings",
+		Match: func(c *freshtest.Context, r Record) bool {
+			t, err := fresh.ParseTime(c.Query.Get("created_since"))
+			return err != nil || !r.Time("created_at").Before(t.Time)
+		},
+	}
+	ticketRatings := &freshtest.Resource{
+		Collection: "satisfaction_ratings",
+		Parent:     &freshtest.Parent{Collection: "tickets", Key: "ticket_id"},
+		Required:   [][]string{{"ratings"}},
+		Validate:   validateSatisfactionRating,
+	}
+
+	s.Handle(http.MethodGet, "/surveys", surveys.List)
+	s.Handle(http.MethodGet, "/surveys/satisfaction_ratings", ratings.List)
+	s.Handle(http.MethodGet, "/tickets/:id/satisfaction_ratings", ticketRatings.List)
+	s.Handle(http.MethodPost, "/tickets/:id/satisfaction_ratings", ticketRatings.Create)
+
+	// the email configs are created with the mailboxes, or inserted to the Store directly
+	emailConfigs := &freshtest.Resource{Collection: "email_configs"}
+
+	s.Handle(http.MethodGet, "/email_configs", emailConfigs.List)
+	s.Handle(http.MethodGet, "/email_configs/:id", emailConfigs.Get)
+
+	s.HandleResource("/email/mailboxes", &freshtest.Resource{
+		Collection: "mailboxes",
+		Required:   [][]string{{"name"}, {"support_email"}, {"mailbox_type"}},
+		Defaults:   Record{"active": true, "default_reply_email": false},
+		Match:      matchMailbox,
+		Validate:   freshtest.Validators(freshtest.Unique("mailboxes", "support_email"), s.validateMailbox),
+	})
+}
+
+// outboundDefaults returns the defaults of the outbound email ticket, which is closed by default.
+func outboundDefaults(defaults Record) Record {
+	r := defaults.Clone()
+	r["status"] = int64(5)  // closed
+	r["source"] = int64(10) // outbound email
+	return r
+}
+
+// validateEmailConfig checks the email_config_id of the ticket is an active email config.
+func validateEmailConfig(c *freshtest.Context, r Record, create bool) bool {
+	if ec := c.Store().Get("email_configs", r.Int64("email_config_id")); ec == nil || !ec.Bool("active") {
+		c.Invalid(fresh.FieldError{Field: "email_config_id", Message: "There is no active email config matching the given email_config_id", Code: "invalid_value"})
+		return false
+	}
+	return true
+}
+
+// validateMailbox sets the forward email of the Freshdesk mailbox, and creates the email config of the new mailbox.
+func (s *Server) validateMailbox(c *freshtest.Context, r Record, create bool) bool {
+	if !create {
+		return true
+	}
+
+	if r.String("mailbox_type") == "freshdesk_mailbox" {
+		local, _, _ := strings.Cut(r.String("support_email"), "@")
+		r["freshdesk_mailbox"] = map[string]any{"forward_email": local + "@" + s.Domain}
+	}
+
+	c.Store().Insert("email_configs", Record{
+		"name":        r["name"],
+		"to_email":    r["support_email"],
+		"reply_email": r["support_email"],
+		"group_id":    r["group_id"],
+		"product_id":  r["product_id"],
+		"active":      true,
+	})
+	return true
+}
+
+func matchMailbox(c *freshtest.Context, r Record) bool {
+	if !matchInt64(c, r, "product_id", "group_id") {
+		return false
+	}
+	if v := c.Query.Get("support_email"); v != "" && !strings.EqualFold(v, r.String("support_email")) {
+		return false
+	}
+	if v := c.Query.Get("forward_email"); v != "" {
+		fm, _ := freshtest.AsRecord(r["freshdesk_mailbox"])
+		if !strings.EqualFold(v, fm.String("forward_email")) {
+			return false
+		}
+	}
+	return true
+}
+
+// validateSatisfactionRating requires the rating of the default question,
+// and sets the survey (the first active survey), the user, agent and group of the ticket.
+func validateSatisfactionRating(c *freshtest.Context, r Record, create bool) bool {
+	if rs, _ := freshtest.AsRecord(r["ratings"]); rs.IsEmpty("default_question") {
+		c.Invalid(fresh.FieldError{Field: "ratings", Message: "The default_question rating is mandatory", Code: "missing_field"})
+		return false
+	}
+
+	if ss := c.Store().Find("surveys", func(s Record) bool { return s.Bool("active") }); len(ss) > 0 {
+		r["survey_id"] = ss[0].ID()
+	}
+
+	t := c.Store().Get("tickets", c.ID(0))
+	r["user_id"] = t["requester_id"]
+	r["agent_id"] = t["responder_id"]
+	r["group_id"] = t["group_id"]
+	return true
+}
+
+func (s *Server) registerSolutions() {
+	categories := &freshtest.Resource{
+		Collection: "categories",
+		Required:   [][]string{{"name"}},
+	}
+	s.HandleResource("/solutions/categories", categories)
+
+	folders := &freshtest.Resource{
+		Collection: "folders",
+		Parent:     &freshtest.Parent{Collection: "categories", Key: "category_id"},
+		Required:   [][]string{{"name"}},
+		Defaults:   Record{"visibility": int64(1)}, // all users
+		Match: func(c *freshtest.Context, r Record) bool {
+			return r.IsEmpty("parent_folder_id") // only the top level folders
+		},
+	}
+	subfolders := &freshtest.Resource{
+		Collection: "folders",
+		Parent:     &freshtest.Parent{Collection: "folders", Key: "parent_folder_id"},
+		Required:   folders.Required,
+		Defaults:   folders.Defaults,
+		Validate: func(c *freshtest.Context, r Record, create bool) bool {
+			if create {
+				r["category_id"] = c.Store().Get("folders", c.ID(0))["category_id"]
+			}
+			return true
+		},
+	}
+
+	s.Handle(http.MethodGet, "/solutions/categories/:id/folders", folders.List)
+	s.Handle(http.MethodPost, "/solutions/categories/:id/folders", folders.Create)
+	s.Handle(http.MethodGet, "/solutions/folders/:id/subfolders", subfolders.List)
+	s.Handle(http.MethodPost, "/solutions/folders/:id/subfolders", subfolders.Create)
+	s.Handle(http.MethodGet, "/solutions/folders/:id", folders.Get)
+	s.Handle(http.MethodPut, "/solutions/folders/:id", folders.Update)
+	s.Handle(http.MethodDelete, "/solutions/folders/:id", folders.Delete)
+
+	articles := &freshtest.Resource{
+		Collection: "articles",
+		Parent:     &freshtest.Parent{Collection: "folders", Key: "folder_id"},
+		Required:   [][]string{{"title"}, {"description"}},
+		Defaults:   Record{"status": int64(1), "tags": []any{}, "hits": int64(0)}, // draft
+		Validate: func(c *freshtest.Context, r Record, create bool) bool {
+			if create {
+				r["category_id"] = c.Store().Get("folders", c.ID(0))["category_id"]
+			}
+			return true
+		},
+	}
+
+	s.Handle(http.MethodGet, "/solutions/folders/:id/articles", articles.List)
+	s.Handle(http.MethodPost, "/solutions/folders/:id/articles", articles.Create)
+	s.Handle(http.MethodGet, "/solutions/articles/:id", articles.Get)
+	s.Handle(http.MethodPut, "/solutions/articles/:id", articles.Update)
+	s.Handle(http.MethodDelete, "/solutions/articles/:id", articles.Delete)
+
+	s.Handle(http.MethodGet, "/solutions/articles/:id/*", s.getArticleTranslated)
+	s.Handle(http.MethodPost, "/solutions/articles/:id/*", s.createArticleTranslated)
+	s.Handle(http.MethodPut, "/solutions/articles/:id/*", s.updateArticleTranslated)
+}
+
+func (s *Server) registerCannedResponses() {
+	folders := &freshtest.Resource{
+		Collection: "canned_response_folders",
+		Required:   [][]string{{"name"}},
+		Defaults:   Record{"personal": false},
+	}
+
+	s.Handle(http.MethodGet, "/canned_response_folders", folders.List)
+	s.Handle(http.MethodPost, "/canned_response_folders", folders.Create)
+	s.Handle(http.MethodGet, "/canned_response_folders/:id", s.getCannedResponseFolder)
+	s.Handle(http.MethodPut, "/canned_response_folders/:id", folders.Update)
+
+	responses := &freshtest.Resource{
+		Collection: "canned_responses",
+		Required:   [][]string{{"title"}, {"content_html"}, {"folder_id"}},
+		Defaults:   Record{"visibility": int64(0)}, // all agents
+		Validate: func(c *freshtest.Context, r Record, create bool) bool {
+			if _, ok := r["folder_id"]; ok && c.Store().Get("canned_response_folders", r.Int64("folder_id")) == nil {
+				c.Invalid(fresh.FieldError{Field: "folder_id", Message: "There is no folder matching the given folder_id", Code: "invalid_value"})
+				return false
+			}
+			return true
+		},
+	}
+	folderResponses := &freshtest.Resource{
+		Collection: "canned_responses",
+		Parent:     &freshtest.Parent{Collection: "canned_response_folders", Key: "folder_id"},
+	}
+
+	s.Handle(http.MethodGet, "/canned_response_folders/:id/responses", folderResponses.List)
+	s.Handle(http.MethodPost, "/canned_responses", responses.Create)
+	s.Handle(http.MethodGet, "/canned_responses/:id", responses.Get)
+	s.Handle(http.MethodPut, "/canned_responses/:id", responses.Update)
+}
+
+// getCannedResponseFolder writes the folder with the brief canned responses (without the content) of the folder.
+func (s *Server) getCannedResponseFolder(c *freshtest.Context) {
+	folder := c.Store().Get("canned_response_folders", c.ID(0))
+	if folder == nil {
+		c.NotFound()
+		return
+	}
+
+	crs := []Record{}
+	for _, r := range c.Store().Find("canned_responses", func(r Record) bool { return r.Int64("folder_id") == folder.ID() }) {
+		crs = append(crs, Record{"id": r["id"], "title": r["title"], "folder_id": r["folder_id"], "created_at": r["created_at"], "updated_at": r["updated_at"]})
+	}
+
+	folder = folder.Clone()
+	folder["responses_count"] = len(crs)
+	folder["canned_responses"] = crs
+	c.JSON(http.StatusOK, folder)
+}
+
+// articleTranslation returns the translation record of the article ":id" in the language of the last path segment.
+func articleTranslation(c *freshtest.Context) Record {
+	aid, lang := c.ID(0), path.Base(c.Request.URL.Path)
+	ts := c.Store().Find("article_translations", func(r Record) bool {
+		return r.Int64("article_id") == aid && r.String("language") == lang
+	})
+	if len(ts) > 0 {
+		return ts[0]
+	}
+	return nil
+}
+
+// translatedArticle returns the translated article of the translation record t, the id is the id of the article.
+func translatedArticle(t Record) Record {
+	r := t.Clone()
+	r["id"] = t.Int64("article_id")
+	delete(r, "article_id")
+	return r
+}
+
+func (s *Server) getArticleTranslated(c *freshtest.Context) {
+	if t := articleTranslation(c); t != nil {
+		c.JSON(http.StatusOK, translatedArticle(t))
+		return
+	}
+	c.NotFound()
+}
+
+func (s *Server) createArticleTranslated(c *freshtest.Context) {
+	a := c.Store().Get("articles", c.ID(0))
+	if a == nil {
+		c.NotFound()
+		return
+	}
+
+	if articleTranslation(c) != nil {
+		c.Error(http.StatusConflict, &fresh.ResultError{
+			Description: "Validation failed",
+			Errors:      []fresh.FieldError{{Field: "language", Message: "The translation already exists", Code: "duplicate_value"}},
+		})
+		return
+	}
+
+	r, ok := c.Bind()
+	if !ok {
+		return
+	}
+	if !c.Require(r, "title") || !c.Require(r, "description") {
+		return
+	}
+
+	delete(r, "id")
+	r["article_id"] = a.ID()
+	r["language"] = path.Base(c.Request.URL.Path)
+	r["folder_id"] = a["folder_id"]
+	r["category_id"] = a["category_id"]
+	if r.IsEmpty("status") {
+		r["status"] = int64(1) // draft
+	}
+
+	r = c.Store().Insert("article_translations", r)
+	c.JSON(http.StatusCreated, translatedArticle(r))
+}
+
+func (s *Server) updateArticleTranslated(c *freshtest.Context) {
+	t := articleTranslation(c)
+	if t == nil {
+		c.NotFound()
+		return
+	}
+
+	patch, ok := c.Bind()
+	if !ok {
+		return
+	}
+	delete(patch, "article_id")
+	delete(patch, "language")
+
+	t = c.Store().Update("article_translations", t.ID(), patch)
+	c.JSON(http.StatusOK, translatedArticle(t))
+}
+
+// validateTicket sets the requester_id of the ticket by the email, a contact is created if not found.
+func (s *Server) validateTicket(c *freshtest.Context, r Record, create bool) bool {
+	if !create || !r.IsEmpty("requester_id") || (r.IsEmpty("email") && r.IsEmpty("phone")) {
+		return true
+	}
+
+	email, phone := r.String("email"), r.String("phone")
+	cs := c.Store().Find("contacts", func(ct Record) bool {
+		if email != "" {
+			return strings.EqualFold(ct.String("email"), email)
+		}
+		return ct.String("phone") == phone || ct.String("mobile") == phone
+	})
+	if len(cs) > 0 {
+		r["requester_id"] = cs[0].ID()
+		return true
+	}
+
+	name := r.String("name")
+	if name == "" {
+		if name = phone; email != "" {
+			name, _, _ = strings.Cut(email, "@")
+		}
+	}
+
+	ct := Record{"name": name, "active": false, "deleted": false}
+	if email != "" {
+		ct["email"] = email
+	} else {
+		ct["phone"] = phone
+	}
+	ct = c.Store().Insert("contacts", ct)
+	r["requester_id"] = ct.ID()
+	return true
+}
+
+// touchTicket updates the updated_at of the parent ticket of a new conversation.
+func (s *Server) touchTicket(c *freshtest.Context, r Record, create bool) bool {
+	if create {
+		c.Store().Update("tickets", c.ID(0), nil)
+	}
+	return true
+}
+
+// ArchiveTicket moves the ticket to the archive, the archived ticket is not found by the ticket apis except the archived ticket apis.
+// Returns false if the ticket is not found.
+func (s *Server) ArchiveTicket(tid int64) bool {
+	return s.Store.Update("tickets", tid, Record{"archived": true}) != nil
+}
+
+func isArchived(r Record) bool {
+	return r.Bool("archived")
+}
+
+// includeTicket returns a copy of the ticket with the embedded resources of the "include" parameter.
+func includeTicket(c *freshtest.Context, t Record) Record {
+	t = t.Clone()
+	for _, inc := range strings.Split(c.Query.Get("include"), ",") {
+		switch inc {
+		case "conversations":
+			t["conversations"] = c.Store().Find("conversations", func(r Record) bool {
+				return r.Int64("ticket_id") == t.ID()
+			})
+		case "requester":
+			if ct := c.Store().Get("contacts", t.Int64("requester_id")); ct != nil {
+				t["requester"] = ct
+			}
+		case "company":
+			if cp := c.Store().Get("companies", t.Int64("company_id")); cp != nil {
+				t["company"] = cp
+			}
+		case "stats":
+			t["stats"] = Record{"created_at": t["created_at"], "updated_at": t["updated_at"]}
+		}
+	}
+	return t
+}
+
+// briefTicket returns a function which converts the ticket to the form of the list,
+// which has no attachments, and no description unless it is included by "include=description".
+func briefTicket(description bool) func(Record) Record {
+	return func(r Record) Record {
+		b := r.Clone()
+		delete(b, "attachments")
+		if !description {
+			delete(b, "description")
+			delete(b, "description_text")
+		}
+		return b
+	}
+}
+
+// attachmentCollections the collections of the records which have the "attachments".
+var attachmentCollections = []string{"tickets", "conversations", "articles", "canned_responses"}
+
+// deleteAttachment handles the "/attachments/:id" api, removes the attachment from the record which has it.
+func deleteAttachment(c *freshtest.Context) {
+	aid := c.ID(0)
+	for _, coll := range attachmentCollections {
+		for _, r := range c.Store().Find(coll, nil) {
+			as, _ := r["attachments"].([]any)
+			i := slices.IndexFunc(as, func(a any) bool {
+				ar, ok := freshtest.AsRecord(a)
+				return ok && ar.ID() == aid
+			})
+			if i >= 0 {
+				c.Store().Update(coll, r.ID(), Record{"attachments": slices.Delete(slices.Clone(as), i, i+1)})
+				c.NoContent()
+				return
+			}
+		}
+	}
+	c.NotFound()
+}
+
+// searchTickets handles the "/search/tickets" api.
+// The results are paginated by 30 without the Link header, the page number should not exceed 10,
+// and the archived, deleted and spam tickets are not included.
+func searchTickets(c *freshtest.Context) {
+	qm, ok := c.FilterQuery()
+	if !ok {
+		return
+	}
+	if qm == nil {
+		c.Invalid(fresh.FieldError{Field: "query", Message: "It should be a/an String", Code: "missing_field"})
+		return
+	}
+
+	page := c.PageNumber()
+	if page > 10 {
+		c.Invalid(fresh.FieldError{Field: "page", Message: "It should be a Positive Integer less than or equal to 10", Code: "invalid_value"})
+		return
+	}
+
+	rs := c.Store().Find("tickets", func(r Record) bool {
+		return !isArchived(r) && !r.Bool("deleted") && !r.Bool("spam") && qm(r)
+	})
+
+	total := len(rs)
+	start := min((page-1)*30, total)
+	end := min(start+30, total)
+	c.JSON(http.StatusOK, Record{"results": rs[start:end], "total": total})
+}
+
+func matchTicket(c *freshtest.Context, r Record) bool {
+	if isArchived(r) {
+		return false
+	}
+
+	switch c.Query.Get("filter") {
+	case "deleted":
+		if !r.Bool("deleted") {
+			return false
+		}
+	case "spam":
+		if !r.Bool("spam") {
+			return false
+		}
+	default:
+		if r.Bool("deleted") || r.Bool("spam") {
+			return false
+		}
+	}
+
+	if !matchInt64(c, r, "requester_id", "company_id") {
+		return false
+	}
+
+	if email := c.Query.Get("email"); email != "" {
+		if ct := c.Store().Get("contacts", r.Int64("requester_id")); ct == nil || !strings.EqualFold(ct.String("email"), email) {
+			return false
+		}
+	}
+
+	return matchUpdatedSince(c, r)
+}
+
+func sortTickets(c *freshtest.Context, rs []Record) {
+	key := c.Query.Get("order_by")
+	if key == "" {
+		key = "created_at"
+	}
+	desc := c.Query.Get("order_type") != "asc"
+
+	slices.SortStableFunc(rs, func(a, b Record) int {
+		var n int
+		switch key {
+		case "status":
+			n = int(a.Int64(key) - b.Int64(key))
+		default:
+			n = a.Time(key).Compare(b.Time(key))
+		}
+		if n == 0 {
+			n = int(a.ID() - b.ID())
+		}
+		if desc {
+			n = -n
+		}
+		return n
+	})
+}
+
+func matchTimeEntry(c *freshtest.Context, r Record) bool {
+	if !matchInt64(c, r, "agent_id", "company_id") {
+		return false
+	}
+
+	if b := c.Query.Get("billable"); b != "" && r.Bool("billable") != (b == "true") {
+		return false
+	}
+
+	if t, err := fresh.ParseTime(c.Query.Get("executed_after")); err == nil && r.Time("executed_at").Before(t.Time) {
+		return false
+	}
+	if t, err := fresh.ParseTime(c.Query.Get("executed_before")); err == nil && r.Time("executed_at").After(t.Time) {
+		return false
+	}
+	return true
+}
+
+func matchContact(c *freshtest.Context, r Record) bool {
+	if (c.Query.Get("state") == "deleted") != r.Bool("deleted") {
+		return false
+	}
+
+	if !matchInt64(c, r, "company_id") {
+		return false
+	}
+
+	for _, k := range []string{"email", "mobile", "phone"} {
+		if v := c.Query.Get(k); v != "" && !strings.EqualFold(r.String(k), v) {
+			return false
+		}
+	}
+
+	return matchUpdatedSince(c, r)
+}
+
+func matchInt64(c *freshtest.Context, r Record, keys ...string) bool {
+	for _, k := range keys {
+		if v := c.Query.Get(k); v != "" && v != strconv.FormatInt(r.Int64(k), 10) {
+			return false
+		}
+	}
+	return true
+}
+
+func matchUpdatedSince(c *freshtest.Context, r Record) bool {
+	if us := c.Query.Get("updated_since"); us != "" {
+		t, err := fresh.ParseTime(us)
+		if err == nil && r.Time("updated_at").Before(t.Time) {
+			return false
+		}
+	}
+	return true
+}
+
+// validateAgent moves the contact fields of the agent to the "contact" object.
+func validateAgent(c *freshtest.Context, r Record, create bool) bool {
+	ct := map[string]any{}
+	if !create {
+		if a := c.Store().Get("agents", c.ID(0)); a != nil {
+			if m, ok := a["contact"].(map[string]any); ok {
+				ct = m
+			}
+		}
+	}
+
+	for _, k := range []string{"name", "email", "phone", "mobile", "job_title", "language", "time_zone"} {
+		if v, ok := r[k]; ok {
+			ct[k] = v
+			delete(r, k)
+		}
+	}
+	if create && ct["name"] == nil {
+		ct["name"], _, _ = strings.Cut(Record(ct).String("email"), "@")
+	}
+	r["contact"] = ct
+
+	if v, ok := r["agent_type"]; ok {
+		r["type"] = v
+		delete(r, "agent_type")
+	}
+	return true
+}
+
+// SetNow replaces the clock of the emulator, which is used to set the created_at/updated_at of the records.
+func (s *Server) SetNow(now func() time.Time) {
+	s.Store.Now = now
+}
@@ -0,0 +1,330 @@
+package fdtest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/askasoft/gofresh/fresh"
+	"github.com/askasoft/gofresh/freshdesk"
+)
+
+var ctxbg = context.Background()
+
+func testClock(start time.Time) func() time.Time {
+	n := 0
+	return func() time.Time {
+		n++
+		return start.Add(time.Duration(n) * time.Second)
+	}
+}
+
+func testNewClient(s *Server) *freshdesk.Client {
+	return &freshdesk.Client{
+		Domain:  s.Domain,
+		APIKey:  s.APIKey,
+		BaseURL: s.URL,
+	}
+}
+
+func TestTickets(t *testing.T) {
+	fs := NewServer()
+	defer fs.Close()
+
+	fd := testNewClient(fs)
+
+	tc := &freshdesk.TicketCreate{
+		Email:       "requester@example.com",
+		Subject:     "test",
+		Description: "description",
+		Priority:    freshdesk.TicketPriorityHigh,
+		Attachments: []*freshdesk.Attachment{freshdesk.NewAttachment("hello.txt", []byte("hello"))},
+	}
+	ticket, err := fd.CreateTicket(ctxbg, tc)
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	if ticket.ID != 1 || ticket.Status != freshdesk.TicketStatusOpen || ticket.Priority != freshdesk.TicketPriorityHigh || ticket.RequesterID == 0 {
+		t.Fatalf("CreateTicket() = %v", ticket)
+	}
+	if len(ticket.Attachments) != 1 || ticket.Attachments[0].Size != 5 {
+		t.Fatalf("CreateTicket().Attachments = %v", ticket.Attachments)
+	}
+
+	data, err := fd.DoReadFileNoAuth(ctxbg, ticket.Attachments[0].AttachmentURL)
+	if err != nil || string(data) != "hello" {
+		t.Fatalf("DoReadFileNoAuth() = %q, %v", data, err)
+	}
+
+	if _, err = fd.CreateReply(ctxbg, ticket.ID, &freshdesk.ReplyCreate{Body: "reply"}); err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	if _, err = fd.CreateNote(ctxbg, ticket.ID, &freshdesk.NoteCreate{Body: "note", Private: true}); err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+
+	convs, _, err := fd.ListTicketConversations(ctxbg, ticket.ID, nil)
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	if len(convs) != 2 || convs[0].Body != "reply" || convs[0].Private || !convs[1].Private {
+		t.Fatalf("ListTicketConversations() = %v", convs)
+	}
+
+	// the list has no description and attachments unless include=description
+	tickets, _, err := fd.ListTickets(ctxbg, nil)
+	if err != nil || len(tickets) != 1 || tickets[0].Description != "" || len(tickets[0].Attachments) != 0 {
+		t.Fatalf("ListTickets() = %v, %v", tickets, err)
+	}
+	tickets, _, err = fd.ListTickets(ctxbg, &freshdesk.ListTicketsOption{Include: freshdesk.TicketIncludeDescription})
+	if err != nil || len(tickets) != 1 || tickets[0].Description != "description" || len(tickets[0].Attachments) != 0 {
+		t.Fatalf("ListTickets(description) = %v, %v", tickets, err)
+	}
+
+	tu := &freshdesk.TicketUpdate{Status: freshdesk.TicketStatusResolved}
+	if ticket, err = fd.UpdateTicket(ctxbg, ticket.ID, tu); err != nil || ticket.Status != freshdesk.TicketStatusResolved {
+		t.Fatalf("UpdateTicket() = %v, %v", ticket, err)
+	}
+
+	if err = fd.DeleteTicket(ctxbg, ticket.ID); err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	if tickets, _, _ := fd.ListTickets(ctxbg, nil); len(tickets) != 0 {
+		t.Fatalf("ListTickets() = %v", tickets)
+	}
+	if err = fd.RestoreTicket(ctxbg, ticket.ID); err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	if tickets, _, _ := fd.ListTickets(ctxbg, nil); len(tickets) != 1 {
+		t.Fatalf("ListTickets() = %v", tickets)
+	}
+}
+
+func TestTicketsPagination(t *testing.T) {
+	fs := NewServer()
+	defer fs.Close()
+
+	fs.SetNow(testClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)))
+
+	fd := testNewClient(fs)
+	for i := 0; i < 25; i++ {
+		if _, err := fd.CreateTicket(ctxbg, &freshdesk.TicketCreate{Email: "a@example.com", Subject: "test"}); err != nil {
+			t.Fatalf("ERROR: %v", err)
+		}
+	}
+
+	lto := &freshdesk.ListTicketsOption{PerPage: 10, OrderBy: freshdesk.TicketOrderByCreatedAt, OrderType: freshdesk.OrderAsc}
+
+	pages := 0
+	n := int64(0)
+	for pg, err := range fd.AllTicketPages(ctxbg, lto) {
+		if err != nil {
+			t.Fatalf("ERROR: %v", err)
+		}
+		pages++
+		for _, tk := range pg.Items {
+			if n++; tk.ID != n {
+				t.Fatalf("ticket = #%d, want #%d", tk.ID, n)
+			}
+		}
+		if (pg.Next == "") != (pages == 3) {
+			t.Errorf("page %d next = %q", pages, pg.Next)
+		}
+	}
+	if pages != 3 || n != 25 {
+		t.Errorf("pages = %d, tickets = %d", pages, n)
+	}
+
+	_, _, err := fd.ListTickets(ctxbg, &freshdesk.ListTicketsOption{PerPage: 101})
+	if re, ok := fresh.AsResultError(err); !ok || re.StatusCode != http.StatusBadRequest || len(re.Errors) != 1 || re.Errors[0].Field != "per_page" {
+		t.Errorf("ListTickets() = %v", err)
+	}
+}
+
+func TestContacts(t *testing.T) {
+	fs := NewServer()
+	defer fs.Close()
+
+	fd := testNewClient(fs)
+
+	cc := &freshdesk.ContactCreate{Name: "test", Email: "test@example.com"}
+	contact, err := fd.CreateContact(ctxbg, cc)
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+
+	_, err = fd.CreateContact(ctxbg, cc)
+	if re, ok := fresh.AsResultError(err); !ok || re.StatusCode != http.StatusConflict || re.Errors[0].Code != "duplicate_value" {
+		t.Errorf("CreateContact() = %v", err)
+	}
+	if !errors.Is(err, freshdesk.ErrDuplicate) || !errors.Is(err, freshdesk.ErrValidation) {
+		t.Errorf("CreateContact() = %v, want %v", err, freshdesk.ErrDuplicate)
+	}
+	if re, _ := freshdesk.AsResultError(err); re == nil || len(re.FieldErrors(cc)["Email"]) != 1 {
+		t.Errorf("CreateContact() = %v, want the error of the Email", err)
+	}
+
+	_, err = fd.CreateContact(ctxbg, &freshdesk.ContactCreate{Email: "noname@example.com"})
+	if re, ok := fresh.AsResultError(err); !ok || re.StatusCode != http.StatusBadRequest || re.Errors[0].Field != "name" {
+		t.Errorf("CreateContact() = %v", err)
+	}
+
+	if err = fd.DeleteContact(ctxbg, contact.ID); err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	if err = fd.HardDeleteContact(ctxbg, contact.ID); err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+
+	_, err = fd.GetContact(ctxbg, contact.ID)
+	if re, ok := fresh.AsResultError(err); !ok || re.StatusCode != http.StatusNotFound {
+		t.Errorf("GetContact() = %v", err)
+	}
+	if !errors.Is(err, freshdesk.ErrNotFound) {
+		t.Errorf("GetContact() = %v, want %v", err, freshdesk.ErrNotFound)
+	}
+}
+
+func TestCompaniesGroupsAgents(t *testing.T) {
+	fs := NewServer()
+	defer fs.Close()
+
+	fd := testNewClient(fs)
+
+	company, err := fd.CreateCompany(ctxbg, &freshdesk.CompanyCreate{Name: "company"})
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	if company, err = fd.UpdateCompany(ctxbg, company.ID, &freshdesk.CompanyUpdate{Description: "desc"}); err != nil || company.Description != "desc" {
+		t.Fatalf("UpdateCompany() = %v, %v", company, err)
+	}
+
+	group, err := fd.CreateGroup(ctxbg, &freshdesk.GroupCreate{Name: "group"})
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	if err = fd.DeleteGroup(ctxbg, group.ID); err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+
+	agent, err := fd.CreateAgent(ctxbg, &freshdesk.AgentCreate{Name: "agent", Email: "agent@example.com", TicketScope: freshdesk.AgentTicketScopeGroup})
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	if agent.Contact == nil || agent.Contact.Email != "agent@example.com" || agent.TicketScope != freshdesk.AgentTicketScopeGroup {
+		t.Fatalf("CreateAgent() = %v", agent)
+	}
+
+	me, err := fd.GetCurrentAgent(ctxbg)
+	if err != nil || me.ID != agent.ID {
+		t.Fatalf("GetCurrentAgent() = %v, %v", me, err)
+	}
+}
+
+func TestSolutions(t *testing.T) {
+	fs := NewServer()
+	defer fs.Close()
+
+	fd := testNewClient(fs)
+
+	category, err := fd.CreateCategory(ctxbg, &freshdesk.CategoryCreate{Name: "category"})
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+
+	folder, err := fd.CreateFolder(ctxbg, category.ID, &freshdesk.FolderCreate{Name: "folder"})
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+
+	if _, err = fd.CreateFolder(ctxbg, category.ID, &freshdesk.FolderCreate{Name: "subfolder", ParentFolderID: folder.ID}); err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+
+	folders, _, err := fd.ListCategoryFolders(ctxbg, category.ID, nil)
+	if err != nil || len(folders) != 1 {
+		t.Fatalf("ListCategoryFolders() = %v, %v", folders, err)
+	}
+
+	subfolders, _, err := fd.ListSubFolders(ctxbg, folder.ID, nil)
+	if err != nil || len(subfolders) != 1 || subfolders[0].Name != "subfolder" {
+		t.Fatalf("ListSubFolders() = %v, %v", subfolders, err)
+	}
+
+	article, err := fd.CreateArticle(ctxbg, folder.ID, &freshdesk.ArticleCreate{Title: "title", Description: "desc"})
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	if article.FolderID != folder.ID || article.Status != freshdesk.ArticleStatusDraft {
+		t.Fatalf("CreateArticle() = %v", article)
+	}
+
+	articles, _, err := fd.ListFolderArticles(ctxbg, folder.ID, nil)
+	if err != nil || len(articles) != 1 {
+		t.Fatalf("ListFolderArticles() = %v, %v", articles, err)
+	}
+}
+
+func TestTimeEntries(t *testing.T) {
+	fs := NewServer()
+	defer fs.Close()
+
+	fd := testNewClient(fs)
+
+	ticket, err := fd.CreateTicket(ctxbg, &freshdesk.TicketCreate{Email: "a@example.com", Subject: "test"})
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+
+	te, err := fd.CreateTimeEntry(ctxbg, ticket.ID, &freshdesk.TimeEntryCreate{AgentID: 1, Note: "note"})
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+
+	if te, err = fd.ToggleTimer(ctxbg, te.ID); err != nil || !te.TimerRunning {
+		t.Fatalf("ToggleTimer() = %v, %v", te, err)
+	}
+
+	tes, _, err := fd.ListTimeEntries(ctxbg, nil)
+	if err != nil || len(tes) != 1 || tes[0].TicketID != ticket.ID {
+		t.Fatalf("ListTimeEntries() = %v, %v", tes, err)
+	}
+
+	if _, err = fd.CreateTimeEntry(ctxbg, 99, &freshdesk.TimeEntryCreate{}); !isStatus(err, http.StatusNotFound) {
+		t.Errorf("CreateTimeEntry() = %v", err)
+	}
+}
+
+func TestAuthAndThrottle(t *testing.T) {
+	fs := NewServer()
+	defer fs.Close()
+
+	fd := testNewClient(fs)
+	fd.APIKey = "invalid"
+
+	_, _, err := fd.ListTickets(ctxbg, nil)
+	if re, ok := fresh.AsResultError(err); !ok || re.StatusCode != http.StatusUnauthorized || re.Code != "invalid_credentials" {
+		t.Errorf("ListTickets() = %v", err)
+	}
+
+	fd = testNewClient(fs)
+	fs.Throttle(1, time.Millisecond)
+
+	_, _, err = fd.ListTickets(ctxbg, nil)
+	if re, ok := fresh.AsResultError(err); !ok || re.StatusCode != http.StatusTooManyRequests || re.RetryAfter != time.Second {
+		t.Errorf("ListTickets() = %v", err)
+	}
+
+	fs.Throttle(1, time.Millisecond)
+	fd.Retryer = freshdesk.NewRetryer(time.Millisecond, 1, nil)
+	if _, _, err = fd.ListTickets(ctxbg, nil); err != nil {
+		t.Errorf("ListTickets() = %v", err)
+	}
+}
+
+func isStatus(err error, status int) bool {
+	re, ok := fresh.AsResultError(err)
+	return ok && re.StatusCode == status
+}
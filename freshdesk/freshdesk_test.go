@@ -2,9 +2,13 @@ package freshdesk
 
 import (
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/askasoft/gofresh/fresh/cassette"
+	"github.com/askasoft/gofresh/freshdesk/fdtest"
 	"github.com/askasoft/pango/log"
 	"github.com/askasoft/pango/log/httplog"
 )
@@ -16,21 +20,82 @@ func init() {
 	tlog.SetLevel(log.LevelInfo)
 }
 
+// testNewFreshdesk returns a Client of the Freshdesk specified by FDK_APIKEY/FDK_DOMAIN (see testNewLiveFreshdesk),
+// or a Client of the in-memory emulator (fdtest) if they and FDK_CASSETTE are not set.
 func testNewFreshdesk(t *testing.T) *Client {
+	if os.Getenv("FDK_CASSETTE") == "" && (os.Getenv("FDK_APIKEY") == "" || os.Getenv("FDK_DOMAIN") == "") {
+		srv := fdtest.NewServer()
+		t.Cleanup(srv.Close)
+
+		return &Client{
+			Domain:  srv.Domain,
+			APIKey:  srv.APIKey,
+			BaseURL: srv.URL,
+			Retryer: NewRetryer(time.Second*3, 1, tlog.GetLogger("FDK")),
+		}
+	}
+
+	return testNewLiveFreshdesk(t)
+}
+
+// testNewLiveFreshdesk returns a Client of the Freshdesk specified by FDK_APIKEY/FDK_DOMAIN,
+// it is used by the tests which depend on the existing data or the apis not covered by the emulator.
+// If FDK_CASSETTE (a directory) is set, the exchanges are recorded to the cassette file of the test,
+// and the cassette file is replayed if FDK_APIKEY/FDK_DOMAIN are not set.
+// No cassette files are committed, they are only used to reproduce a run recorded against a live account.
+// The emails of FDK_CASSETTE_EMAILS (comma separated) are redacted in the cassette file.
+// The test is skipped if neither the Freshdesk nor the cassette file is available.
+func testNewLiveFreshdesk(t *testing.T) *Client {
+	logger := tlog.GetLogger("FDK")
+
 	apikey := os.Getenv("FDK_APIKEY")
+	domain := os.Getenv("FDK_DOMAIN")
+
+	if dir := os.Getenv("FDK_CASSETTE"); dir != "" {
+		path := filepath.Join(dir, strings.ReplaceAll(t.Name(), "/", "_")+".json")
+
+		mode := cassette.ModeRecord
+		if apikey == "" || domain == "" {
+			if _, err := os.Stat(path); err != nil {
+				t.Skip("FDK_APIKEY/FDK_DOMAIN not set and no cassette " + path)
+				return nil
+			}
+			mode, apikey, domain = cassette.ModeReplay, "cassette", "cassette.freshdesk.com"
+		}
+
+		rec, err := cassette.New(path, mode)
+		if err != nil {
+			t.Fatalf("ERROR: %v", err)
+		}
+		if es := os.Getenv("FDK_CASSETTE_EMAILS"); es != "" {
+			rec.Emails = strings.Split(es, ",")
+		}
+		rec.Transport = httplog.LoggingRoundTripper(logger)
+
+		t.Cleanup(func() {
+			if err := rec.Save(); err != nil {
+				t.Errorf("ERROR: %v", err)
+			}
+		})
+
+		return &Client{
+			Domain:    domain,
+			APIKey:    apikey,
+			Transport: rec,
+			Retryer:   NewRetryer(time.Second*3, 1, logger),
+		}
+	}
+
 	if apikey == "" {
 		t.Skip("FDK_APIKEY not set")
 		return nil
 	}
 
-	domain := os.Getenv("FDK_DOMAIN")
 	if domain == "" {
 		t.Skip("FDK_DOMAIN not set")
 		return nil
 	}
 
-	logger := tlog.GetLogger("FDK")
-
 	fdk := &Client{
 		Domain:    domain,
 		APIKey:    apikey,
@@ -1,6 +1,11 @@
 package freshdesk
 
-import "context"
+import (
+	"context"
+	"iter"
+
+	"github.com/askasoft/gofresh/fresh"
+)
 
 // ---------------------------------------------------
 // Group
@@ -24,39 +29,27 @@ func (c *Client) CreateGroup(ctx context.Context, group *GroupCreate) (*Group, e
 }
 
 func (c *Client) ListGroups(ctx context.Context, lgo *ListGroupsOption) ([]*Group, bool, error) {
+	return hasNext(c.listGroups(ctx, lgo))
+}
+
+func (c *Client) listGroups(ctx context.Context, lo ListOption) ([]*Group, string, error) {
 	url := c.Endpoint("/groups")
 	groups := []*Group{}
-	next, err := c.DoList(ctx, url, lgo, &groups)
+	next, err := c.DoListLink(ctx, url, lo, &groups)
 	return groups, next, err
 }
 
 func (c *Client) IterGroups(ctx context.Context, lgo *ListGroupsOption, igf func(*Group) error) error {
-	if lgo == nil {
-		lgo = &ListGroupsOption{}
-	}
-	if lgo.Page < 1 {
-		lgo.Page = 1
-	}
-	if lgo.PerPage < 1 {
-		lgo.PerPage = 100
-	}
+	return c.groupsPaginator().Iter(ctx, lgo, igf)
+}
 
-	for {
-		groups, next, err := c.ListGroups(ctx, lgo)
-		if err != nil {
-			return err
-		}
-		for _, g := range groups {
-			if err = igf(g); err != nil {
-				return err
-			}
-		}
-		if !next {
-			break
-		}
-		lgo.Page++
-	}
-	return nil
+// AllGroups is like IterGroups but returns an iterator, the lgo will not be modified.
+func (c *Client) AllGroups(ctx context.Context, lgo *ListGroupsOption) iter.Seq2[*Group, error] {
+	return c.groupsPaginator().All(ctx, lgo)
+}
+
+func (c *Client) groupsPaginator() *fresh.Paginator[*Group] {
+	return newPaginator(c.listGroups)
 }
 
 func (c *Client) UpdateGroup(ctx context.Context, gid int64, group *GroupUpdate) (*Group, error) {
@@ -0,0 +1,196 @@
+package freshdesk
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"strings"
+
+	"github.com/askasoft/gofresh/fresh/kbsync"
+)
+
+// NewKBMirror returns a kbsync.Mirror of the solutions in the directory dir,
+// the articles are mirrored with their translations of the languages (e.g. "ja", "fr").
+// The visibility of a folder is pushed only if it needs no company/segment ids,
+// and a new folder without visibility is visible to the agents only.
+func (c *Client) NewKBMirror(dir string, languages ...string) *kbsync.Mirror {
+	return kbsync.NewMirror(&kbRemote{c}, dir, languages...)
+}
+
+// kbRemote implements the kbsync.Remote
+type kbRemote struct {
+	c *Client
+}
+
+func (kr *kbRemote) Categories(ctx context.Context) iter.Seq2[*kbsync.Document, error] {
+	return kbsync.Convert(kr.c.AllCategories(ctx, nil), categoryDocument)
+}
+
+func (kr *kbRemote) Folders(ctx context.Context, cid int64) iter.Seq2[*kbsync.Document, error] {
+	return kbsync.Convert(kr.c.AllCategoryFolders(ctx, cid, nil), folderDocument)
+}
+
+func (kr *kbRemote) SubFolders(ctx context.Context, fid int64) iter.Seq2[*kbsync.Document, error] {
+	return kbsync.Convert(kr.c.AllSubFolders(ctx, fid, nil), folderDocument)
+}
+
+func (kr *kbRemote) Articles(ctx context.Context, fid int64) iter.Seq2[*kbsync.Document, error] {
+	return kbsync.Convert(kr.c.AllFolderArticles(ctx, fid, nil), articleDocument)
+}
+
+func (kr *kbRemote) Translation(ctx context.Context, aid int64, lang string) (*kbsync.Document, error) {
+	a, err := kr.c.GetArticleTranslated(ctx, aid, lang)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	d := articleDocument(a)
+	d.Language = lang
+	return d, nil
+}
+
+func (kr *kbRemote) Save(ctx context.Context, d, parent *kbsync.Document) (*kbsync.Document, error) {
+	switch d.Kind {
+	case kbsync.KindCategory:
+		return kr.saveCategory(ctx, d)
+	case kbsync.KindFolder:
+		return kr.saveFolder(ctx, d, parent)
+	default:
+		return kr.saveArticle(ctx, d, parent)
+	}
+}
+
+func (kr *kbRemote) saveCategory(ctx context.Context, d *kbsync.Document) (*kbsync.Document, error) {
+	cc := &CategoryCreate{Name: d.Title, Description: d.Body}
+
+	var c *Category
+	var err error
+	if d.ID == 0 {
+		c, err = kr.c.CreateCategory(ctx, cc)
+	} else {
+		c, err = kr.c.UpdateCategory(ctx, d.ID, cc)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return categoryDocument(c), nil
+}
+
+func (kr *kbRemote) saveFolder(ctx context.Context, d, parent *kbsync.Document) (*kbsync.Document, error) {
+	fc := &FolderCreate{Name: d.Title, Description: d.Body}
+	if v := FolderVisibility(d.Visibility); v >= FolderVisibilityAllUsers && v <= FolderVisibilityAgents {
+		fc.Visibility = v
+	}
+	if d.ID == 0 && fc.Visibility == 0 {
+		fc.Visibility = FolderVisibilityAgents
+	}
+
+	var f *Folder
+	var err error
+	switch {
+	case d.ID != 0:
+		f, err = kr.c.UpdateFolder(ctx, d.ID, fc)
+	case parent.Kind == kbsync.KindFolder:
+		f, err = kr.c.CreateSubFolder(ctx, parent.ID, fc)
+	default:
+		f, err = kr.c.CreateFolder(ctx, parent.ID, fc)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return folderDocument(f), nil
+}
+
+func (kr *kbRemote) saveArticle(ctx context.Context, d, parent *kbsync.Document) (*kbsync.Document, error) {
+	ac := articleCreate(d)
+
+	var a *Article
+	var err error
+	switch {
+	case d.Language != "" && d.Hash == "":
+		a, err = kr.c.CreateArticleTranslated(ctx, d.ID, d.Language, ac)
+	case d.Language != "":
+		a, err = kr.c.UpdateArticleTranslated(ctx, d.ID, d.Language, ac)
+	case d.ID == 0:
+		a, err = kr.c.CreateArticle(ctx, parent.ID, ac)
+	default:
+		a, err = kr.c.UpdateArticle(ctx, d.ID, ac)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	sd := articleDocument(a)
+	if d.Language != "" {
+		sd.ID, sd.Language = d.ID, d.Language
+	}
+	return sd, nil
+}
+
+func categoryDocument(c *Category) *kbsync.Document {
+	return &kbsync.Document{
+		Kind:  kbsync.KindCategory,
+		ID:    c.ID,
+		Title: c.Name,
+		Body:  c.Description,
+	}
+}
+
+func folderDocument(f *Folder) *kbsync.Document {
+	return &kbsync.Document{
+		Kind:       kbsync.KindFolder,
+		ID:         f.ID,
+		Title:      f.Name,
+		Visibility: int(f.Visibility),
+		Body:       f.Description,
+	}
+}
+
+func articleDocument(a *Article) *kbsync.Document {
+	d := &kbsync.Document{
+		Kind:   kbsync.KindArticle,
+		ID:     a.ID,
+		Title:  a.Title,
+		Status: int(a.Status),
+		Tags:   a.Tags,
+		Body:   a.Description,
+	}
+	if sd := a.SeoData; sd != nil {
+		d.SeoTitle = sd.MetaTitle
+		d.SeoDescription = sd.MetaDescription
+		for s := range strings.SplitSeq(sd.MetaKeywords, ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				d.Keywords = append(d.Keywords, s)
+			}
+		}
+	}
+	return d
+}
+
+func articleCreate(d *kbsync.Document) *ArticleCreate {
+	tags := d.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+
+	ac := &ArticleCreate{
+		Title:       d.Title,
+		Description: d.Body,
+		Status:      ArticleStatus(d.Status),
+		Tags:        &tags,
+	}
+	if ac.Status == 0 {
+		ac.Status = ArticleStatusDraft
+	}
+	if d.SeoTitle != "" || d.SeoDescription != "" || len(d.Keywords) > 0 {
+		ac.SeoData = &ArticleSeoData{
+			MetaTitle:       d.SeoTitle,
+			MetaDescription: d.SeoDescription,
+			MetaKeywords:    strings.Join(d.Keywords, ", "),
+		}
+	}
+	return ac
+}
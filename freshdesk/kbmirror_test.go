@@ -0,0 +1,88 @@
+package freshdesk_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/askasoft/gofresh/freshdesk"
+	"github.com/askasoft/gofresh/freshdesk/fdtest"
+)
+
+func TestKBMirror(t *testing.T) {
+	fs := fdtest.NewServer()
+	defer fs.Close()
+
+	fd := testNewClient(fs)
+
+	category, err := fd.CreateCategory(ctxbg, &freshdesk.CategoryCreate{Name: "FAQ"})
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	folder, err := fd.CreateFolder(ctxbg, category.ID, &freshdesk.FolderCreate{Name: "General", Visibility: freshdesk.FolderVisibilityAllUsers})
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	ac := &freshdesk.ArticleCreate{
+		Title:       "How to",
+		Description: "<p>how to</p>",
+		Status:      freshdesk.ArticleStatusPublished,
+		SeoData:     &freshdesk.ArticleSeoData{MetaTitle: "seo", MetaKeywords: "a, b"},
+	}
+	article, err := fd.CreateArticle(ctxbg, folder.ID, ac)
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	if _, err := fd.CreateArticleTranslated(ctxbg, article.ID, "ja", &freshdesk.ArticleCreate{Title: "使い方", Description: "<p>使い方</p>"}); err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+
+	dir := t.TempDir()
+	m := fd.NewKBMirror(dir, "ja", "fr")
+
+	res, err := m.Pull(ctxbg)
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	if len(res.Created) != 4 {
+		t.Fatalf("Pull() = %+v", res)
+	}
+
+	apath := filepath.Join(dir, "1-faq", "1-general", "1-how-to.html")
+	bs, err := os.ReadFile(apath)
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	for _, s := range []string{"status: 2\n", "seo_title: \"seo\"\n", "keywords: [\"a\",\"b\"]\n", "<p>how to</p>"} {
+		if !strings.Contains(string(bs), s) {
+			t.Errorf("%s does not contain %q:\n%s", apath, s, bs)
+		}
+	}
+
+	// edit the article, add a french translation
+	if err := os.WriteFile(apath, []byte(strings.Replace(string(bs), "<p>how to</p>", "<p>how to (edited)</p>", 1)), 0o660); err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	fpath := filepath.Join(dir, "1-faq", "1-general", "1-how-to.fr.html")
+	if err := os.WriteFile(fpath, []byte("---\ntitle: Mode d'emploi\nlanguage: fr\n---\n<p>mode d'emploi</p>\n"), 0o660); err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+
+	if res, err = m.Push(ctxbg); err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	if len(res.Created) != 1 || len(res.Updated) != 1 {
+		t.Fatalf("Push() = %+v", res)
+	}
+
+	article, err = fd.GetArticle(ctxbg, article.ID)
+	if err != nil || article.Description != "<p>how to (edited)</p>" || article.SeoData.MetaKeywords != "a, b" {
+		t.Fatalf("GetArticle() = %v, %v", article, err)
+	}
+
+	fr, err := fd.GetArticleTranslated(ctxbg, article.ID, "fr")
+	if err != nil || fr.Title != "Mode d'emploi" {
+		t.Fatalf("GetArticleTranslated() = %v, %v", fr, err)
+	}
+}
@@ -0,0 +1,139 @@
+package freshdesk
+
+type MailboxType string
+
+type MailboxAccessType string
+
+type MailboxAuthentication string
+
+const (
+	MailboxTypeFreshdesk MailboxType = "freshdesk_mailbox"
+	MailboxTypeCustom    MailboxType = "custom_mailbox"
+
+	MailboxAccessTypeIncoming MailboxAccessType = "incoming"
+	MailboxAccessTypeOutgoing MailboxAccessType = "outgoing"
+	MailboxAccessTypeBoth     MailboxAccessType = "both"
+
+	MailboxAuthenticationPlain MailboxAuthentication = "plain"
+	MailboxAuthenticationLogin MailboxAuthentication = "login"
+	MailboxAuthenticationOAuth MailboxAuthentication = "oauth"
+)
+
+type Mailbox struct {
+	ID int64 `json:"id,omitempty"`
+
+	// Name of the mailbox
+	Name string `json:"name,omitempty"`
+
+	// The support email address of the mailbox
+	SupportEmail string `json:"support_email,omitempty"`
+
+	// ID of the group to which the tickets of the mailbox are assigned
+	GroupID int64 `json:"group_id,omitempty"`
+
+	// Set to true if the support email is the default reply email
+	DefaultReplyEmail bool `json:"default_reply_email,omitempty"`
+
+	// Set to true if the mailbox is active
+	Active bool `json:"active,omitempty"`
+
+	// Type of the mailbox
+	MailboxType MailboxType `json:"mailbox_type,omitempty"`
+
+	// ID of the product associated with the mailbox
+	ProductID int64 `json:"product_id,omitempty"`
+
+	// The settings of the Freshdesk mailbox (MailboxType = MailboxTypeFreshdesk)
+	FreshdeskMailbox *FreshdeskMailbox `json:"freshdesk_mailbox,omitempty"`
+
+	// The settings of the custom mailbox (MailboxType = MailboxTypeCustom)
+	CustomMailbox *CustomMailbox `json:"custom_mailbox,omitempty"`
+
+	CreatedAt Time `json:"created_at,omitzero"`
+
+	UpdatedAt Time `json:"updated_at,omitzero"`
+}
+
+func (mb *Mailbox) String() string {
+	return toString(mb)
+}
+
+type FreshdeskMailbox struct {
+	// The Freshdesk email address to which the emails of the support email are forwarded
+	ForwardEmail string `json:"forward_email,omitempty"`
+}
+
+func (fm *FreshdeskMailbox) String() string {
+	return toString(fm)
+}
+
+type CustomMailbox struct {
+	// Access type of the custom mailbox servers
+	AccessType MailboxAccessType `json:"access_type,omitempty"`
+
+	// The incoming (IMAP) mail server (AccessType = incoming/both)
+	Incoming *MailServer `json:"incoming,omitempty"`
+
+	// The outgoing (SMTP) mail server (AccessType = outgoing/both)
+	Outgoing *MailServer `json:"outgoing,omitempty"`
+}
+
+func (cm *CustomMailbox) String() string {
+	return toString(cm)
+}
+
+type MailServer struct {
+	// Host name of the mail server
+	MailServer string `json:"mail_server,omitempty"`
+
+	// Port of the mail server
+	Port int `json:"port,omitempty"`
+
+	// Set to true to connect with SSL
+	UseSSL bool `json:"use_ssl"`
+
+	// Set to true to delete the emails from the server after fetched (incoming only)
+	DeleteFromServer bool `json:"delete_from_server,omitempty"`
+
+	// Authentication type of the mail server
+	Authentication MailboxAuthentication `json:"authentication,omitempty"`
+
+	// User name of the mail server
+	UserName string `json:"user_name,omitempty"`
+
+	// Password of the mail server, it is never returned by the api
+	Password string `json:"password,omitempty"`
+}
+
+func (ms *MailServer) String() string {
+	return toString(ms)
+}
+
+type MailboxCreate struct {
+	// Name of the mailbox
+	Name string `json:"name,omitempty"`
+
+	// The support email address of the mailbox
+	SupportEmail string `json:"support_email,omitempty"`
+
+	// ID of the group to which the tickets of the mailbox are assigned
+	GroupID int64 `json:"group_id,omitempty"`
+
+	// Set to true if the support email is the default reply email
+	DefaultReplyEmail bool `json:"default_reply_email,omitempty"`
+
+	// Type of the mailbox
+	MailboxType MailboxType `json:"mailbox_type,omitempty"`
+
+	// ID of the product associated with the mailbox
+	ProductID int64 `json:"product_id,omitempty"`
+
+	// The settings of the custom mailbox (MailboxType = MailboxTypeCustom)
+	CustomMailbox *CustomMailbox `json:"custom_mailbox,omitempty"`
+}
+
+func (mb *MailboxCreate) String() string {
+	return toString(mb)
+}
+
+type MailboxUpdate = MailboxCreate
@@ -0,0 +1,93 @@
+package freshdesk
+
+import (
+	"context"
+	"iter"
+
+	"github.com/askasoft/gofresh/fresh"
+)
+
+// ---------------------------------------------------
+// Mailbox
+
+type ListMailboxesOption struct {
+	SupportEmail string
+	ForwardEmail string
+	ProductID    int64
+	GroupID      int64
+	OrderBy      string    // name, support_email, created_at, updated_at
+	OrderType    OrderType // asc, desc (default)
+	Page         int
+	PerPage      int
+}
+
+func (lmo *ListMailboxesOption) IsNil() bool {
+	return lmo == nil
+}
+
+func (lmo *ListMailboxesOption) Values() Values {
+	q := Values{}
+	q.SetString("support_email", lmo.SupportEmail)
+	q.SetString("forward_email", lmo.ForwardEmail)
+	q.SetInt64("product_id", lmo.ProductID)
+	q.SetInt64("group_id", lmo.GroupID)
+	q.SetString("order_by", lmo.OrderBy)
+	q.SetString("order_type", (string)(lmo.OrderType))
+	q.SetInt("page", lmo.Page)
+	q.SetInt("per_page", lmo.PerPage)
+	return q
+}
+
+func (c *Client) CreateMailbox(ctx context.Context, mailbox *MailboxCreate) (*Mailbox, error) {
+	url := c.Endpoint("/email/mailboxes")
+	result := &Mailbox{}
+	if err := c.DoPost(ctx, url, mailbox, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c *Client) GetMailbox(ctx context.Context, mbid int64) (*Mailbox, error) {
+	url := c.Endpoint("/email/mailboxes/%d", mbid)
+	mailbox := &Mailbox{}
+	err := c.DoGet(ctx, url, mailbox)
+	return mailbox, err
+}
+
+func (c *Client) ListMailboxes(ctx context.Context, lmo *ListMailboxesOption) ([]*Mailbox, bool, error) {
+	return hasNext(c.listMailboxes(ctx, lmo))
+}
+
+func (c *Client) listMailboxes(ctx context.Context, lo ListOption) ([]*Mailbox, string, error) {
+	url := c.Endpoint("/email/mailboxes")
+	mailboxes := []*Mailbox{}
+	next, err := c.DoListLink(ctx, url, lo, &mailboxes)
+	return mailboxes, next, err
+}
+
+func (c *Client) IterMailboxes(ctx context.Context, lmo *ListMailboxesOption, imf func(*Mailbox) error) error {
+	return c.mailboxesPaginator().Iter(ctx, lmo, imf)
+}
+
+// AllMailboxes is like IterMailboxes but returns an iterator, the lmo will not be modified.
+func (c *Client) AllMailboxes(ctx context.Context, lmo *ListMailboxesOption) iter.Seq2[*Mailbox, error] {
+	return c.mailboxesPaginator().All(ctx, lmo)
+}
+
+func (c *Client) mailboxesPaginator() *fresh.Paginator[*Mailbox] {
+	return newPaginator(c.listMailboxes)
+}
+
+func (c *Client) UpdateMailbox(ctx context.Context, mbid int64, mailbox *MailboxUpdate) (*Mailbox, error) {
+	url := c.Endpoint("/email/mailboxes/%d", mbid)
+	result := &Mailbox{}
+	if err := c.DoPut(ctx, url, mailbox, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c *Client) DeleteMailbox(ctx context.Context, mbid int64) error {
+	url := c.Endpoint("/email/mailboxes/%d", mbid)
+	return c.DoDelete(ctx, url)
+}
@@ -0,0 +1,92 @@
+package freshdesk_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/askasoft/gofresh/freshdesk"
+	"github.com/askasoft/gofresh/freshdesk/fdtest"
+)
+
+func TestMailboxesAndOutboundEmail(t *testing.T) {
+	fs := fdtest.NewServer()
+	defer fs.Close()
+
+	fd := testNewClient(fs)
+
+	support, err := fd.CreateMailbox(ctxbg, &freshdesk.MailboxCreate{Name: "Support", SupportEmail: "support@example.com", MailboxType: freshdesk.MailboxTypeFreshdesk})
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	if !support.Active || support.FreshdeskMailbox == nil || support.FreshdeskMailbox.ForwardEmail != "support@"+fdtest.Domain {
+		t.Fatalf("CreateMailbox() = %v", support)
+	}
+
+	mbc := &freshdesk.MailboxCreate{
+		Name:         "Brand",
+		SupportEmail: "hello@brand.com",
+		MailboxType:  freshdesk.MailboxTypeCustom,
+		CustomMailbox: &freshdesk.CustomMailbox{
+			AccessType: freshdesk.MailboxAccessTypeOutgoing,
+			Outgoing:   &freshdesk.MailServer{MailServer: "smtp.brand.com", Port: 587, Authentication: freshdesk.MailboxAuthenticationLogin, UserName: "hello"},
+		},
+	}
+	brand, err := fd.CreateMailbox(ctxbg, mbc)
+	if err != nil || brand.CustomMailbox.Outgoing.Port != 587 {
+		t.Fatalf("CreateMailbox() = %v, %v", brand, err)
+	}
+
+	if _, err = fd.CreateMailbox(ctxbg, mbc); !errors.Is(err, freshdesk.ErrDuplicate) {
+		t.Errorf("CreateMailbox() = %v, want %v", err, freshdesk.ErrDuplicate)
+	}
+
+	mbs, _, err := fd.ListMailboxes(ctxbg, &freshdesk.ListMailboxesOption{ForwardEmail: "support@" + fdtest.Domain})
+	if err != nil || len(mbs) != 1 || mbs[0].ID != support.ID {
+		t.Fatalf("ListMailboxes() = %v, %v", mbs, err)
+	}
+
+	mbc.Name = "Brand Inc."
+	if brand, err = fd.UpdateMailbox(ctxbg, brand.ID, mbc); err != nil || brand.Name != "Brand Inc." {
+		t.Fatalf("UpdateMailbox() = %v, %v", brand, err)
+	}
+	if mb, err := fd.GetMailbox(ctxbg, brand.ID); err != nil || mb.SupportEmail != "hello@brand.com" {
+		t.Fatalf("GetMailbox() = %v, %v", mb, err)
+	}
+
+	ecs, _, err := fd.ListEmailConfigs(ctxbg, nil)
+	if err != nil || len(ecs) != 2 {
+		t.Fatalf("ListEmailConfigs() = %v, %v", ecs, err)
+	}
+	if ec, err := fd.GetEmailConfig(ctxbg, ecs[1].ID); err != nil || ec.ReplyEmail != "hello@brand.com" {
+		t.Fatalf("GetEmailConfig() = %v, %v", ec, err)
+	}
+
+	oe := &freshdesk.OutboundEmail{Email: "customer@example.com", Subject: "news", Description: "hi", Status: freshdesk.TicketStatusClosed}
+	ticket, err := fd.CreateOutboundEmailFrom(ctxbg, nil, "Brand <Hello@Brand.com>", oe)
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	if ticket.EmailConfigID != ecs[1].ID || ticket.Source != freshdesk.TicketSourceOutboundEmail || oe.EmailConfigID != 0 {
+		t.Fatalf("CreateOutboundEmailFrom() = %v", ticket)
+	}
+
+	ecr, err := fd.LoadEmailConfigResolver(ctxbg)
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	if _, err = fd.CreateOutboundEmailFrom(ctxbg, ecr, "unknown@example.com", oe); !errors.Is(err, freshdesk.ErrEmailConfigNotFound) {
+		t.Errorf("CreateOutboundEmailFrom() = %v, want %v", err, freshdesk.ErrEmailConfigNotFound)
+	}
+
+	oe.EmailConfigID = 999
+	if _, err = fd.CreateOutboundEmail(ctxbg, oe); !errors.Is(err, freshdesk.ErrValidation) {
+		t.Errorf("CreateOutboundEmail() = %v, want %v", err, freshdesk.ErrValidation)
+	}
+
+	if err = fd.DeleteMailbox(ctxbg, brand.ID); err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	if _, err = fd.GetMailbox(ctxbg, brand.ID); !errors.Is(err, freshdesk.ErrNotFound) {
+		t.Errorf("GetMailbox() = %v, want %v", err, freshdesk.ErrNotFound)
+	}
+}
@@ -1,6 +1,11 @@
 package freshdesk
 
-import "context"
+import (
+	"context"
+	"iter"
+
+	"github.com/askasoft/gofresh/fresh"
+)
 
 // ---------------------------------------------------
 // Product
@@ -15,37 +20,25 @@ func (c *Client) GetProduct(ctx context.Context, id int64) (*Product, error) {
 }
 
 func (c *Client) ListProducts(ctx context.Context, lpo *ListProductsOption) ([]*Product, bool, error) {
+	return hasNext(c.listProducts(ctx, lpo))
+}
+
+func (c *Client) listProducts(ctx context.Context, lo ListOption) ([]*Product, string, error) {
 	url := c.Endpoint("/products")
 	products := []*Product{}
-	next, err := c.DoList(ctx, url, lpo, &products)
+	next, err := c.DoListLink(ctx, url, lo, &products)
 	return products, next, err
 }
 
 func (c *Client) IterProducts(ctx context.Context, lpo *ListProductsOption, ipf func(*Product) error) error {
-	if lpo == nil {
-		lpo = &ListProductsOption{}
-	}
-	if lpo.Page < 1 {
-		lpo.Page = 1
-	}
-	if lpo.PerPage < 1 {
-		lpo.PerPage = 100
-	}
-
-	for {
-		ps, next, err := c.ListProducts(ctx, lpo)
-		if err != nil {
-			return err
-		}
-		for _, ag := range ps {
-			if err = ipf(ag); err != nil {
-				return err
-			}
-		}
-		if !next {
-			break
-		}
-		lpo.Page++
-	}
-	return nil
+	return c.productsPaginator().Iter(ctx, lpo, ipf)
+}
+
+// AllProducts is like IterProducts but returns an iterator, the lpo will not be modified.
+func (c *Client) AllProducts(ctx context.Context, lpo *ListProductsOption) iter.Seq2[*Product, error] {
+	return c.productsPaginator().All(ctx, lpo)
+}
+
+func (c *Client) productsPaginator() *fresh.Paginator[*Product] {
+	return newPaginator(c.listProducts)
 }
@@ -1,6 +1,11 @@
 package freshdesk
 
-import "context"
+import (
+	"context"
+	"iter"
+
+	"github.com/askasoft/gofresh/fresh"
+)
 
 // ---------------------------------------------------
 // Role
@@ -15,37 +20,25 @@ func (c *Client) GetRole(ctx context.Context, rid int64) (*Role, error) {
 }
 
 func (c *Client) ListRoles(ctx context.Context, lro *ListRolesOption) ([]*Role, bool, error) {
+	return hasNext(c.listRoles(ctx, lro))
+}
+
+func (c *Client) listRoles(ctx context.Context, lo ListOption) ([]*Role, string, error) {
 	url := c.Endpoint("/roles")
 	roles := []*Role{}
-	next, err := c.DoList(ctx, url, lro, &roles)
+	next, err := c.DoListLink(ctx, url, lo, &roles)
 	return roles, next, err
 }
 
 func (c *Client) IterRoles(ctx context.Context, lro *ListRolesOption, irf func(*Role) error) error {
-	if lro == nil {
-		lro = &ListRolesOption{}
-	}
-	if lro.Page < 1 {
-		lro.Page = 1
-	}
-	if lro.PerPage < 1 {
-		lro.PerPage = 100
-	}
-
-	for {
-		roles, next, err := c.ListRoles(ctx, lro)
-		if err != nil {
-			return err
-		}
-		for _, g := range roles {
-			if err = irf(g); err != nil {
-				return err
-			}
-		}
-		if !next {
-			break
-		}
-		lro.Page++
-	}
-	return nil
+	return c.rolesPaginator().Iter(ctx, lro, irf)
+}
+
+// AllRoles is like IterRoles but returns an iterator, the lro will not be modified.
+func (c *Client) AllRoles(ctx context.Context, lro *ListRolesOption) iter.Seq2[*Role, error] {
+	return c.rolesPaginator().All(ctx, lro)
+}
+
+func (c *Client) rolesPaginator() *fresh.Paginator[*Role] {
+	return newPaginator(c.listRoles)
 }
@@ -5,7 +5,7 @@ import (
 )
 
 func TestGetHelpdeskSettings(t *testing.T) {
-	fd := testNewFreshdesk(t)
+	fd := testNewLiveFreshdesk(t)
 	if fd == nil {
 		return
 	}
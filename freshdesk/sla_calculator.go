@@ -0,0 +1,149 @@
+package freshdesk
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// SLACalculator computes the expected due times (FrDueBy/DueBy) of the tickets locally by the SLA policies and the business hours,
+// so that the breaches can be predicted before the IsEscalated/FrEscalated of the tickets are set by Freshdesk.
+type SLACalculator struct {
+	// Policies the SLA policies
+	Policies []*SLAPolicy
+
+	// BusinessHours the business hours
+	BusinessHours []*BusinessHour
+
+	// GroupBusinessHours the business hour id of the group id
+	GroupBusinessHours map[int64]int64
+}
+
+// NewSLACalculator returns a SLACalculator of the SLA policies, the business hours and the groups (for the business hour of the ticket group).
+func NewSLACalculator(policies []*SLAPolicy, bhs []*BusinessHour, groups []*Group) *SLACalculator {
+	sc := &SLACalculator{
+		Policies:           policies,
+		BusinessHours:      bhs,
+		GroupBusinessHours: make(map[int64]int64, len(groups)),
+	}
+
+	for _, g := range groups {
+		if g.BusinessHourID != 0 {
+			sc.GroupBusinessHours[g.ID] = g.BusinessHourID
+		}
+	}
+	return sc
+}
+
+// LoadSLACalculator returns a SLACalculator of all the SLA policies, business hours and groups.
+func (c *Client) LoadSLACalculator(ctx context.Context) (*SLACalculator, error) {
+	policies, err := c.ListSLAPolicies(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	bhs, err := c.ListBusinessHours(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	groups := []*Group{}
+	for g, err := range c.AllGroups(ctx, nil) {
+		if err != nil {
+			return nil, err
+		}
+		groups = append(groups, g)
+	}
+
+	return NewSLACalculator(policies, bhs, groups), nil
+}
+
+// Policy returns the SLA policy of the ticket, the first (by Position) active policy which matches the ticket,
+// or the default policy if no policy matches. Returns nil if no policy is found.
+func (sc *SLACalculator) Policy(t *Ticket) *SLAPolicy {
+	sps := slices.Clone(sc.Policies)
+	slices.SortStableFunc(sps, func(a, b *SLAPolicy) int {
+		return cmp.Compare(a.Position, b.Position)
+	})
+
+	var dsp *SLAPolicy
+	for _, sp := range sps {
+		if sp.IsDefault {
+			if dsp == nil {
+				dsp = sp
+			}
+			continue
+		}
+		if sp.Active && sp.Applicable(t) {
+			return sp
+		}
+	}
+	return dsp
+}
+
+// BusinessHour returns the business hour of the ticket group, or the default business hour if the group has no business hour.
+// Returns nil if no business hour is found.
+func (sc *SLACalculator) BusinessHour(t *Ticket) *BusinessHour {
+	if bhid, ok := sc.GroupBusinessHours[t.GroupID]; ok {
+		for _, bh := range sc.BusinessHours {
+			if bh.ID == bhid {
+				return bh
+			}
+		}
+	}
+
+	for _, bh := range sc.BusinessHours {
+		if bh.IsDefault {
+			return bh
+		}
+	}
+	return nil
+}
+
+// DueBy computes the expected FrDueBy (first response) and DueBy (resolution) of the ticket from its CreatedAt,
+// by the SLA target of the ticket Priority in the SLA policy of the ticket.
+// The target times are added in the business hour of the ticket group if the SLA target is in the business hours,
+// otherwise in the calendar hours.
+// A zero time is returned if the SLA target has no respond/resolve time.
+func (sc *SLACalculator) DueBy(t *Ticket) (frDueBy, dueBy time.Time, err error) {
+	sp := sc.Policy(t)
+	if sp == nil {
+		err = fmt.Errorf("freshdesk: no SLA policy for the ticket #%d", t.ID)
+		return
+	}
+
+	var stt *SLATargetTime
+	if sp.SLATarget != nil {
+		stt = sp.SLATarget.Get(t.Priority)
+	}
+	if stt == nil {
+		err = fmt.Errorf("freshdesk: no SLA target of the priority %v in the SLA policy #%d", t.Priority, sp.ID)
+		return
+	}
+
+	add := func(tm time.Time, d time.Duration) (time.Time, error) {
+		return tm.Add(d), nil
+	}
+	if stt.BusinessHours {
+		bh := sc.BusinessHour(t)
+		if bh == nil {
+			err = fmt.Errorf("freshdesk: no business hour for the ticket #%d", t.ID)
+			return
+		}
+		add = bh.Add
+	}
+
+	if stt.RespondWithin > 0 {
+		if frDueBy, err = add(t.CreatedAt.Time, stt.RespondDuration()); err != nil {
+			return
+		}
+	}
+	if stt.ResolveWithin > 0 {
+		if dueBy, err = add(t.CreatedAt.Time, stt.ResolveDuration()); err != nil {
+			return
+		}
+	}
+	return
+}
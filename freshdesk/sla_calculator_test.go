@@ -0,0 +1,183 @@
+package freshdesk
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestLoadLocation(t *testing.T) {
+	cs := []struct {
+		name string
+		want string
+	}{
+		{"Eastern Time (US & Canada)", "America/New_York"},
+		{"Tokyo", "Asia/Tokyo"},
+		{"Europe/Paris", "Europe/Paris"},
+		{"", "UTC"},
+	}
+
+	for i, c := range cs {
+		loc, err := LoadLocation(c.name)
+		if err != nil {
+			t.Fatalf("#%d LoadLocation(%q): %v", i, c.name, err)
+		}
+		if a := loc.String(); a != c.want {
+			t.Errorf("#%d LoadLocation(%q) = %q, want %q", i, c.name, a, c.want)
+		}
+	}
+
+	if _, err := LoadLocation("Nowhere"); err == nil {
+		t.Error("LoadLocation(Nowhere) should fail")
+	}
+}
+
+func testBusinessHours() []*BusinessHour {
+	weekday := &BusinessHourTime{StartTime: "9:00 am", EndTime: "6:00 pm"}
+	allday := &BusinessHourTime{StartTime: "12:00 am", EndTime: "11:59 pm"}
+
+	return []*BusinessHour{
+		{
+			ID:        1,
+			Name:      "Tokyo",
+			IsDefault: true,
+			TimeZone:  "Osaka",
+			BusinessHours: map[string]*BusinessHourTime{
+				"monday": weekday, "tuesday": weekday, "wednesday": weekday, "thursday": weekday, "friday": weekday,
+			},
+			Holidays: []*Holiday{{Name: "New Year", Date: "Jan 01"}, {Name: "Bridge", Date: "2026-01-02"}},
+		},
+		{
+			ID:       2,
+			Name:     "24x7",
+			TimeZone: "UTC",
+			BusinessHours: map[string]*BusinessHourTime{
+				"monday": allday, "tuesday": allday, "wednesday": allday, "thursday": allday,
+				"friday": allday, "saturday": allday, "sunday": allday,
+			},
+		},
+	}
+}
+
+func TestBusinessHourAdd(t *testing.T) {
+	bh := testBusinessHours()[0]
+	jst, _ := bh.Location()
+
+	cs := []struct {
+		from time.Time
+		d    time.Duration
+		want time.Time
+	}{
+		// in the working hours
+		{time.Date(2025, 12, 30, 10, 0, 0, 0, jst), 2 * time.Hour, time.Date(2025, 12, 30, 12, 0, 0, 0, jst)},
+		// before the working hours
+		{time.Date(2025, 12, 30, 7, 0, 0, 0, jst), time.Hour, time.Date(2025, 12, 30, 10, 0, 0, 0, jst)},
+		// the end of the working hours
+		{time.Date(2025, 12, 30, 17, 0, 0, 0, jst), time.Hour, time.Date(2025, 12, 30, 18, 0, 0, 0, jst)},
+		// skip the holidays and the weekend (Thu 1/1, Fri 1/2, Sat, Sun)
+		{time.Date(2025, 12, 31, 17, 0, 0, 0, jst), 8 * time.Hour, time.Date(2026, 1, 5, 16, 0, 0, 0, jst)},
+		// from UTC
+		{time.Date(2026, 1, 9, 8, 30, 0, 0, time.UTC), time.Hour, time.Date(2026, 1, 12, 9, 30, 0, 0, jst)},
+	}
+
+	for i, c := range cs {
+		a, err := bh.Add(c.from, c.d)
+		if err != nil {
+			t.Fatalf("#%d Add(%v, %v): %v", i, c.from, c.d, err)
+		}
+		if !a.Equal(c.want) {
+			t.Errorf("#%d Add(%v, %v) = %v, want %v", i, c.from, c.d, a, c.want)
+		}
+	}
+
+	bh = &BusinessHour{Name: "None", BusinessHours: map[string]*BusinessHourTime{}}
+	if _, err := bh.Add(time.Now(), time.Hour); !errors.Is(err, ErrNoBusinessHours) {
+		t.Errorf("Add() = %v, want %v", err, ErrNoBusinessHours)
+	}
+}
+
+func TestSLACalculatorDueBy(t *testing.T) {
+	policies := []*SLAPolicy{
+		{
+			ID:        1,
+			Name:      "Default",
+			Active:    true,
+			IsDefault: true,
+			Position:  3,
+			SLATarget: &SLATarget{
+				Priority1: &SLATargetTime{RespondWithin: 2 * 3600, ResolveWithin: 8 * 3600, BusinessHours: true},
+				Priority4: &SLATargetTime{RespondWithin: 3600, ResolveWithin: 4 * 3600},
+			},
+		},
+		{
+			ID:           2,
+			Name:         "VIP",
+			Active:       true,
+			Position:     1,
+			ApplicableTo: &SLAApplicableTo{GroupIDs: []int64{10}},
+			SLATarget: &SLATarget{
+				Priority1: &SLATargetTime{RespondWithin: 3600, ResolveWithin: 2 * 3600, BusinessHours: true},
+			},
+		},
+		{
+			ID:           3,
+			Name:         "Inactive",
+			Position:     2,
+			ApplicableTo: &SLAApplicableTo{GroupIDs: []int64{20}},
+		},
+	}
+	groups := []*Group{{ID: 10, BusinessHourID: 2}, {ID: 20}}
+
+	sc := NewSLACalculator(policies, testBusinessHours(), groups)
+
+	jst, _ := LoadLocation("Tokyo")
+	created := Time{Time: time.Date(2025, 12, 31, 17, 0, 0, 0, jst)}
+
+	cs := []struct {
+		ticket  *Ticket
+		policy  int64
+		frDueBy time.Time
+		dueBy   time.Time
+	}{
+		// the default policy in the default business hour
+		{
+			&Ticket{ID: 1, GroupID: 20, Priority: TicketPriorityLow, CreatedAt: created},
+			1,
+			time.Date(2026, 1, 5, 10, 0, 0, 0, jst),
+			time.Date(2026, 1, 5, 16, 0, 0, 0, jst),
+		},
+		// the default policy in the calendar hours
+		{
+			&Ticket{ID: 2, Priority: TicketPriorityUrgent, CreatedAt: created},
+			1,
+			created.Add(time.Hour),
+			created.Add(4 * time.Hour),
+		},
+		// the matched policy in the business hour of the group
+		{
+			&Ticket{ID: 3, GroupID: 10, Priority: TicketPriorityLow, CreatedAt: created},
+			2,
+			created.Add(time.Hour),
+			created.Add(2 * time.Hour),
+		},
+	}
+
+	for i, c := range cs {
+		if sp := sc.Policy(c.ticket); sp == nil || sp.ID != c.policy {
+			t.Errorf("#%d Policy() = %v, want #%d", i, sp, c.policy)
+		}
+
+		frDueBy, dueBy, err := sc.DueBy(c.ticket)
+		if err != nil {
+			t.Fatalf("#%d DueBy(): %v", i, err)
+		}
+		if !frDueBy.Equal(c.frDueBy) || !dueBy.Equal(c.dueBy) {
+			t.Errorf("#%d DueBy() = (%v, %v), want (%v, %v)", i, frDueBy, dueBy, c.frDueBy, c.dueBy)
+		}
+	}
+
+	// no SLA target of the priority
+	if _, _, err := sc.DueBy(&Ticket{ID: 4, GroupID: 10, Priority: TicketPriorityHigh, CreatedAt: created}); err == nil {
+		t.Error("DueBy() should fail without the SLA target")
+	}
+}
@@ -0,0 +1,35 @@
+package freshdesk
+
+import (
+	"context"
+)
+
+// ---------------------------------------------------
+// SLA Policy
+
+func (c *Client) CreateSLAPolicy(ctx context.Context, sp *SLAPolicyCreate) (*SLAPolicy, error) {
+	url := c.Endpoint("/sla_policies")
+	result := &SLAPolicy{}
+	if err := c.DoPost(ctx, url, sp, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c *Client) UpdateSLAPolicy(ctx context.Context, spid int64, sp *SLAPolicyUpdate) (*SLAPolicy, error) {
+	url := c.Endpoint("/sla_policies/%d", spid)
+	result := &SLAPolicy{}
+	if err := c.DoPut(ctx, url, sp, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ListSLAPolicies lists all the SLA policies.
+// The api has no endpoint to get or delete a SLA policy.
+func (c *Client) ListSLAPolicies(ctx context.Context) ([]*SLAPolicy, error) {
+	url := c.Endpoint("/sla_policies")
+	policies := []*SLAPolicy{}
+	err := c.DoGet(ctx, url, &policies)
+	return policies, err
+}
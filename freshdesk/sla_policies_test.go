@@ -0,0 +1,95 @@
+package freshdesk_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/askasoft/gofresh/freshdesk"
+	"github.com/askasoft/gofresh/freshdesk/fdtest"
+)
+
+func TestSLAPolicies(t *testing.T) {
+	fs := fdtest.NewServer()
+	defer fs.Close()
+
+	fd := testNewClient(fs)
+
+	fs.Store.Insert("business_hours", fdtest.Record{
+		"name":       "Support",
+		"is_default": true,
+		"time_zone":  "Eastern Time (US & Canada)",
+		"business_hours": map[string]any{
+			"monday":    map[string]any{"start_time": "8:00 am", "end_time": "5:00 pm"},
+			"tuesday":   map[string]any{"start_time": "8:00 am", "end_time": "5:00 pm"},
+			"wednesday": map[string]any{"start_time": "8:00 am", "end_time": "5:00 pm"},
+			"thursday":  map[string]any{"start_time": "8:00 am", "end_time": "5:00 pm"},
+			"friday":    map[string]any{"start_time": "8:00 am", "end_time": "5:00 pm"},
+		},
+		"holiday_list": []any{map[string]any{"name": "Christmas", "date": "Dec 25"}},
+	})
+
+	bhs, err := fd.ListBusinessHours(ctxbg)
+	if err != nil || len(bhs) != 1 || len(bhs[0].BusinessHours) != 5 || len(bhs[0].Holidays) != 1 {
+		t.Fatalf("ListBusinessHours() = %v, %v", bhs, err)
+	}
+	if bh, err := fd.GetBusinessHour(ctxbg, bhs[0].ID); err != nil || bh.TimeZone != "Eastern Time (US & Canada)" {
+		t.Fatalf("GetBusinessHour() = %v, %v", bh, err)
+	}
+
+	spc := &freshdesk.SLAPolicyCreate{
+		Name:         "Email",
+		ApplicableTo: &freshdesk.SLAApplicableTo{Sources: []freshdesk.TicketSource{freshdesk.TicketSourceEmail}},
+		SLATarget: &freshdesk.SLATarget{
+			Priority1: &freshdesk.SLATargetTime{RespondWithin: 3600, ResolveWithin: 4 * 3600, BusinessHours: true},
+		},
+		Escalation: &freshdesk.SLAEscalation{
+			Response: &freshdesk.SLAEscalationLevel{EscalationTime: 0, AgentIDs: []int64{-1}},
+		},
+	}
+	sp, err := fd.CreateSLAPolicy(ctxbg, spc)
+	if err != nil || !sp.Active || sp.ApplicableTo.Sources[0] != freshdesk.TicketSourceEmail {
+		t.Fatalf("CreateSLAPolicy() = %v, %v", sp, err)
+	}
+
+	if _, err = fd.CreateSLAPolicy(ctxbg, &freshdesk.SLAPolicyCreate{Name: "x"}); !errors.Is(err, freshdesk.ErrValidation) {
+		t.Errorf("CreateSLAPolicy() = %v, want %v", err, freshdesk.ErrValidation)
+	}
+
+	spc.Description = "email tickets"
+	if sp, err = fd.UpdateSLAPolicy(ctxbg, sp.ID, spc); err != nil || sp.Description != "email tickets" {
+		t.Fatalf("UpdateSLAPolicy() = %v, %v", sp, err)
+	}
+
+	sc, err := fd.LoadSLACalculator(ctxbg)
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+
+	// Thursday 16:30 EST
+	est, _ := freshdesk.LoadLocation("America/New_York")
+	ticket := &freshdesk.Ticket{
+		ID:        1,
+		Source:    freshdesk.TicketSourceEmail,
+		Priority:  freshdesk.TicketPriorityLow,
+		CreatedAt: freshdesk.Time{Time: time.Date(2026, 12, 24, 16, 30, 0, 0, est)},
+	}
+
+	// skip Christmas (Friday 12/25) and the weekend
+	frDueBy, dueBy, err := sc.DueBy(ticket)
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	if want := time.Date(2026, 12, 28, 8, 30, 0, 0, est); !frDueBy.Equal(want) {
+		t.Errorf("DueBy() FrDueBy = %v, want %v", frDueBy, want)
+	}
+	if want := time.Date(2026, 12, 28, 11, 30, 0, 0, est); !dueBy.Equal(want) {
+		t.Errorf("DueBy() DueBy = %v, want %v", dueBy, want)
+	}
+
+	// no policy matches the phone ticket
+	ticket.Source = freshdesk.TicketSourcePhone
+	if _, _, err = sc.DueBy(ticket); err == nil {
+		t.Error("DueBy() should fail without the SLA policy")
+	}
+}
@@ -0,0 +1,211 @@
+package freshdesk
+
+import (
+	"slices"
+	"time"
+)
+
+type SLAPolicy struct {
+	ID int64 `json:"id,omitempty"`
+
+	// Name of the SLA policy
+	Name string `json:"name,omitempty"`
+
+	// Description of the SLA policy
+	Description string `json:"description,omitempty"`
+
+	// Set as true if the SLA policy is active
+	Active bool `json:"active,omitempty"`
+
+	// Set as true if it is the default SLA policy, the default policy applies to the tickets which match no other policy
+	IsDefault bool `json:"is_default,omitempty"`
+
+	// Rank of the SLA policy, the first matched policy applies to the ticket
+	Position int `json:"position,omitempty"`
+
+	// The SLA targets of the ticket priorities
+	SLATarget *SLATarget `json:"sla_target,omitempty"`
+
+	// The conditions of the tickets to which the SLA policy applies
+	ApplicableTo *SLAApplicableTo `json:"applicable_to,omitempty"`
+
+	// The escalation rules of the SLA violations
+	Escalation *SLAEscalation `json:"escalation,omitempty"`
+
+	CreatedAt Time `json:"created_at,omitzero"`
+
+	UpdatedAt Time `json:"updated_at,omitzero"`
+}
+
+func (sp *SLAPolicy) String() string {
+	return toString(sp)
+}
+
+// Applicable reports whether the SLA policy applies to the ticket by the ApplicableTo conditions,
+// the default policy applies to all tickets.
+func (sp *SLAPolicy) Applicable(t *Ticket) bool {
+	if sp.IsDefault {
+		return true
+	}
+	return sp.ApplicableTo != nil && sp.ApplicableTo.Match(t)
+}
+
+type SLATarget struct {
+	Priority1 *SLATargetTime `json:"priority_1,omitempty"`
+
+	Priority2 *SLATargetTime `json:"priority_2,omitempty"`
+
+	Priority3 *SLATargetTime `json:"priority_3,omitempty"`
+
+	Priority4 *SLATargetTime `json:"priority_4,omitempty"`
+}
+
+func (st *SLATarget) String() string {
+	return toString(st)
+}
+
+// Get returns the SLA target time of the ticket priority, returns nil if not set.
+func (st *SLATarget) Get(p TicketPriority) *SLATargetTime {
+	switch p {
+	case TicketPriorityLow:
+		return st.Priority1
+	case TicketPriorityMedium:
+		return st.Priority2
+	case TicketPriorityHigh:
+		return st.Priority3
+	case TicketPriorityUrgent:
+		return st.Priority4
+	default:
+		return nil
+	}
+}
+
+type SLATargetTime struct {
+	// Time within which the first response should be sent, in seconds
+	RespondWithin int64 `json:"respond_within,omitempty"`
+
+	// Time within which the next response should be sent, in seconds
+	NextRespondWithin int64 `json:"next_respond_within,omitempty"`
+
+	// Time within which the ticket should be resolved, in seconds
+	ResolveWithin int64 `json:"resolve_within,omitempty"`
+
+	// Set as true if the times are in the business hours, otherwise the times are in the calendar hours
+	BusinessHours bool `json:"business_hours"`
+
+	// Set as true if the escalation is enabled
+	EscalationEnabled bool `json:"escalation_enabled"`
+}
+
+func (stt *SLATargetTime) String() string {
+	return toString(stt)
+}
+
+// RespondDuration returns the RespondWithin as time.Duration
+func (stt *SLATargetTime) RespondDuration() time.Duration {
+	return time.Duration(stt.RespondWithin) * time.Second
+}
+
+// ResolveDuration returns the ResolveWithin as time.Duration
+func (stt *SLATargetTime) ResolveDuration() time.Duration {
+	return time.Duration(stt.ResolveWithin) * time.Second
+}
+
+type SLAApplicableTo struct {
+	// IDs of the companies
+	CompanyIDs []int64 `json:"company_ids,omitempty"`
+
+	// IDs of the groups
+	GroupIDs []int64 `json:"group_ids,omitempty"`
+
+	// IDs of the products
+	ProductIDs []int64 `json:"product_ids,omitempty"`
+
+	// Sources of the tickets
+	Sources []TicketSource `json:"sources,omitempty"`
+
+	// Types of the tickets
+	TicketTypes []string `json:"ticket_types,omitempty"`
+}
+
+func (sat *SLAApplicableTo) String() string {
+	return toString(sat)
+}
+
+// IsEmpty reports whether there is no condition
+func (sat *SLAApplicableTo) IsEmpty() bool {
+	return len(sat.CompanyIDs) == 0 && len(sat.GroupIDs) == 0 && len(sat.ProductIDs) == 0 && len(sat.Sources) == 0 && len(sat.TicketTypes) == 0
+}
+
+// Match reports whether the ticket matches all the (non-empty) conditions, returns false if there is no condition.
+func (sat *SLAApplicableTo) Match(t *Ticket) bool {
+	if sat.IsEmpty() {
+		return false
+	}
+	return (len(sat.CompanyIDs) == 0 || slices.Contains(sat.CompanyIDs, t.CompanyID)) &&
+		(len(sat.GroupIDs) == 0 || slices.Contains(sat.GroupIDs, t.GroupID)) &&
+		(len(sat.ProductIDs) == 0 || slices.Contains(sat.ProductIDs, t.ProductID)) &&
+		(len(sat.Sources) == 0 || slices.Contains(sat.Sources, t.Source)) &&
+		(len(sat.TicketTypes) == 0 || slices.Contains(sat.TicketTypes, t.Type))
+}
+
+type SLAEscalation struct {
+	// The escalation when the first response is not sent in time
+	Response *SLAEscalationLevel `json:"response,omitempty"`
+
+	// The escalation levels when the ticket is not resolved in time
+	Resolution *SLAResolutionEscalation `json:"resolution,omitempty"`
+}
+
+func (se *SLAEscalation) String() string {
+	return toString(se)
+}
+
+type SLAResolutionEscalation struct {
+	Level1 *SLAEscalationLevel `json:"level_1,omitempty"`
+
+	Level2 *SLAEscalationLevel `json:"level_2,omitempty"`
+
+	Level3 *SLAEscalationLevel `json:"level_3,omitempty"`
+
+	Level4 *SLAEscalationLevel `json:"level_4,omitempty"`
+}
+
+func (sre *SLAResolutionEscalation) String() string {
+	return toString(sre)
+}
+
+type SLAEscalationLevel struct {
+	// Time in seconds after the SLA violation when the escalation is sent (0 means immediately)
+	EscalationTime int64 `json:"escalation_time"`
+
+	// IDs of the agents to whom the escalation is sent, -1 means the assigned agent
+	AgentIDs []int64 `json:"agent_ids,omitempty"`
+}
+
+func (sel *SLAEscalationLevel) String() string {
+	return toString(sel)
+}
+
+type SLAPolicyCreate struct {
+	// Name of the SLA policy
+	Name string `json:"name,omitempty"`
+
+	// Description of the SLA policy
+	Description string `json:"description,omitempty"`
+
+	// The conditions of the tickets to which the SLA policy applies
+	ApplicableTo *SLAApplicableTo `json:"applicable_to,omitempty"`
+
+	// The SLA targets of the ticket priorities
+	SLATarget *SLATarget `json:"sla_target,omitempty"`
+
+	// The escalation rules of the SLA violations
+	Escalation *SLAEscalation `json:"escalation,omitempty"`
+}
+
+func (sp *SLAPolicyCreate) String() string {
+	return toString(sp)
+}
+
+type SLAPolicyUpdate = SLAPolicyCreate
@@ -2,7 +2,10 @@ package freshdesk
 
 import (
 	"context"
+	"iter"
 	"net/url"
+
+	"github.com/askasoft/gofresh/fresh"
 )
 
 // ---------------------------------------------------
@@ -68,75 +71,53 @@ func (c *Client) GetCategoryTranslated(ctx context.Context, cid int64, lang stri
 }
 
 func (c *Client) ListCategories(ctx context.Context, lco *ListCategoriesOption) ([]*Category, bool, error) {
+	return hasNext(c.listCategories(ctx, lco))
+}
+
+func (c *Client) listCategories(ctx context.Context, lo ListOption) ([]*Category, string, error) {
 	url := c.Endpoint("/solutions/categories")
 	categories := []*Category{}
-	next, err := c.DoList(ctx, url, lco, &categories)
+	next, err := c.DoListLink(ctx, url, lo, &categories)
 	return categories, next, err
 }
 
 func (c *Client) IterCategories(ctx context.Context, lco *ListCategoriesOption, icf func(*Category) error) error {
-	if lco == nil {
-		lco = &ListCategoriesOption{}
-	}
-	if lco.Page < 1 {
-		lco.Page = 1
-	}
-	if lco.PerPage < 1 {
-		lco.PerPage = 100
-	}
+	return c.categoriesPaginator().Iter(ctx, lco, icf)
+}
 
-	for {
-		categories, next, err := c.ListCategories(ctx, lco)
-		if err != nil {
-			return err
-		}
-		for _, c := range categories {
-			if err = icf(c); err != nil {
-				return err
-			}
-		}
-		if !next {
-			break
-		}
-		lco.Page++
-	}
-	return nil
+// AllCategories is like IterCategories but returns an iterator, the lco will not be modified.
+func (c *Client) AllCategories(ctx context.Context, lco *ListCategoriesOption) iter.Seq2[*Category, error] {
+	return c.categoriesPaginator().All(ctx, lco)
+}
+
+func (c *Client) categoriesPaginator() *fresh.Paginator[*Category] {
+	return newPaginator(c.listCategories)
 }
 
 func (c *Client) ListCategoriesTranslated(ctx context.Context, lang string, lco *ListCategoriesOption) ([]*Category, bool, error) {
-	url := c.Domain + "/api/v2/solutions/categories/" + lang
+	return hasNext(c.listCategoriesTranslated(ctx, lang, lco))
+}
+
+func (c *Client) listCategoriesTranslated(ctx context.Context, lang string, lo ListOption) ([]*Category, string, error) {
+	url := c.Endpoint("/solutions/categories/%s", lang)
 	categories := []*Category{}
-	next, err := c.DoList(ctx, url, lco, &categories)
+	next, err := c.DoListLink(ctx, url, lo, &categories)
 	return categories, next, err
 }
 
 func (c *Client) IterCategoriesTranslated(ctx context.Context, lang string, lco *ListCategoriesOption, icf func(*Category) error) error {
-	if lco == nil {
-		lco = &ListCategoriesOption{}
-	}
-	if lco.Page < 1 {
-		lco.Page = 1
-	}
-	if lco.PerPage < 1 {
-		lco.PerPage = 100
-	}
+	return c.categoriesTranslatedPaginator(lang).Iter(ctx, lco, icf)
+}
 
-	for {
-		categories, next, err := c.ListCategoriesTranslated(ctx, lang, lco)
-		if err != nil {
-			return err
-		}
-		for _, c := range categories {
-			if err = icf(c); err != nil {
-				return err
-			}
-		}
-		if !next {
-			break
-		}
-		lco.Page++
-	}
-	return nil
+// AllCategoriesTranslated is like IterCategoriesTranslated but returns an iterator, the lco will not be modified.
+func (c *Client) AllCategoriesTranslated(ctx context.Context, lang string, lco *ListCategoriesOption) iter.Seq2[*Category, error] {
+	return c.categoriesTranslatedPaginator(lang).All(ctx, lco)
+}
+
+func (c *Client) categoriesTranslatedPaginator(lang string) *fresh.Paginator[*Category] {
+	return newPaginator(func(ctx context.Context, lo ListOption) ([]*Category, string, error) {
+		return c.listCategoriesTranslated(ctx, lang, lo)
+	})
 }
 
 func (c *Client) DeleteCategory(ctx context.Context, cid int64) error {
@@ -153,6 +134,15 @@ func (c *Client) CreateFolder(ctx context.Context, cid int64, folder *FolderCrea
 	return result, nil
 }
 
+func (c *Client) CreateSubFolder(ctx context.Context, fid int64, folder *FolderCreate) (*Folder, error) {
+	url := c.Endpoint("/solutions/folders/%d/subfolders", fid)
+	result := &Folder{}
+	if err := c.DoPost(ctx, url, folder, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
 func (c *Client) CreateFolderTranslated(ctx context.Context, fid int64, lang string, folder *FolderCreate) (*Folder, error) {
 	url := c.Endpoint("/solutions/folders/%d/%s", fid, lang)
 	result := &Folder{}
@@ -195,147 +185,107 @@ func (c *Client) GetFolderTranslated(ctx context.Context, fid int64, lang string
 }
 
 func (c *Client) ListCategoryFolders(ctx context.Context, cid int64, lfo *ListFoldersOption) ([]*Folder, bool, error) {
+	return hasNext(c.listCategoryFolders(ctx, cid, lfo))
+}
+
+func (c *Client) listCategoryFolders(ctx context.Context, cid int64, lo ListOption) ([]*Folder, string, error) {
 	url := c.Endpoint("/solutions/categories/%d/folders", cid)
 	folders := []*Folder{}
-	next, err := c.DoList(ctx, url, lfo, &folders)
+	next, err := c.DoListLink(ctx, url, lo, &folders)
 	return folders, next, err
 }
 
 func (c *Client) IterCategoryFolders(ctx context.Context, cid int64, lfo *ListFoldersOption, iff func(*Folder) error) error {
-	if lfo == nil {
-		lfo = &ListFoldersOption{}
-	}
-	if lfo.Page < 1 {
-		lfo.Page = 1
-	}
-	if lfo.PerPage < 1 {
-		lfo.PerPage = 100
-	}
+	return c.categoryFoldersPaginator(cid).Iter(ctx, lfo, iff)
+}
 
-	for {
-		folders, next, err := c.ListCategoryFolders(ctx, cid, lfo)
-		if err != nil {
-			return err
-		}
-		for _, f := range folders {
-			if err = iff(f); err != nil {
-				return err
-			}
-		}
-		if !next {
-			break
-		}
-		lfo.Page++
-	}
-	return nil
+// AllCategoryFolders is like IterCategoryFolders but returns an iterator, the lfo will not be modified.
+func (c *Client) AllCategoryFolders(ctx context.Context, cid int64, lfo *ListFoldersOption) iter.Seq2[*Folder, error] {
+	return c.categoryFoldersPaginator(cid).All(ctx, lfo)
+}
+
+func (c *Client) categoryFoldersPaginator(cid int64) *fresh.Paginator[*Folder] {
+	return newPaginator(func(ctx context.Context, lo ListOption) ([]*Folder, string, error) {
+		return c.listCategoryFolders(ctx, cid, lo)
+	})
 }
 
 func (c *Client) ListCategoryFoldersTranslated(ctx context.Context, cid int64, lang string, lfo *ListFoldersOption) ([]*Folder, bool, error) {
+	return hasNext(c.listCategoryFoldersTranslated(ctx, cid, lang, lfo))
+}
+
+func (c *Client) listCategoryFoldersTranslated(ctx context.Context, cid int64, lang string, lo ListOption) ([]*Folder, string, error) {
 	url := c.Endpoint("/solutions/categories/%d/folders/%s", cid, lang)
 	folders := []*Folder{}
-	next, err := c.DoList(ctx, url, lfo, &folders)
+	next, err := c.DoListLink(ctx, url, lo, &folders)
 	return folders, next, err
 }
 
 func (c *Client) IterCategoryFoldersTranslated(ctx context.Context, cid int64, lang string, lfo *ListFoldersOption, iff func(*Folder) error) error {
-	if lfo == nil {
-		lfo = &ListFoldersOption{}
-	}
-	if lfo.Page < 1 {
-		lfo.Page = 1
-	}
-	if lfo.PerPage < 1 {
-		lfo.PerPage = 100
-	}
+	return c.categoryFoldersTranslatedPaginator(cid, lang).Iter(ctx, lfo, iff)
+}
 
-	for {
-		folders, next, err := c.ListCategoryFoldersTranslated(ctx, cid, lang, lfo)
-		if err != nil {
-			return err
-		}
-		for _, f := range folders {
-			if err = iff(f); err != nil {
-				return err
-			}
-		}
-		if !next {
-			break
-		}
-		lfo.Page++
-	}
-	return nil
+// AllCategoryFoldersTranslated is like IterCategoryFoldersTranslated but returns an iterator, the lfo will not be modified.
+func (c *Client) AllCategoryFoldersTranslated(ctx context.Context, cid int64, lang string, lfo *ListFoldersOption) iter.Seq2[*Folder, error] {
+	return c.categoryFoldersTranslatedPaginator(cid, lang).All(ctx, lfo)
+}
+
+func (c *Client) categoryFoldersTranslatedPaginator(cid int64, lang string) *fresh.Paginator[*Folder] {
+	return newPaginator(func(ctx context.Context, lo ListOption) ([]*Folder, string, error) {
+		return c.listCategoryFoldersTranslated(ctx, cid, lang, lo)
+	})
 }
 
 func (c *Client) ListSubFolders(ctx context.Context, fid int64, lfo *ListFoldersOption) ([]*Folder, bool, error) {
+	return hasNext(c.listSubFolders(ctx, fid, lfo))
+}
+
+func (c *Client) listSubFolders(ctx context.Context, fid int64, lo ListOption) ([]*Folder, string, error) {
 	url := c.Endpoint("/solutions/folders/%d/subfolders", fid)
 	folders := []*Folder{}
-	next, err := c.DoList(ctx, url, lfo, &folders)
+	next, err := c.DoListLink(ctx, url, lo, &folders)
 	return folders, next, err
 }
 
 func (c *Client) IterSubFolders(ctx context.Context, fid int64, lfo *ListFoldersOption, iff func(*Folder) error) error {
-	if lfo == nil {
-		lfo = &ListFoldersOption{}
-	}
-	if lfo.Page < 1 {
-		lfo.Page = 1
-	}
-	if lfo.PerPage < 1 {
-		lfo.PerPage = 100
-	}
+	return c.subFoldersPaginator(fid).Iter(ctx, lfo, iff)
+}
 
-	for {
-		folders, next, err := c.ListSubFolders(ctx, fid, lfo)
-		if err != nil {
-			return err
-		}
-		for _, f := range folders {
-			if err = iff(f); err != nil {
-				return err
-			}
-		}
-		if !next {
-			break
-		}
-		lfo.Page++
-	}
-	return nil
+// AllSubFolders is like IterSubFolders but returns an iterator, the lfo will not be modified.
+func (c *Client) AllSubFolders(ctx context.Context, fid int64, lfo *ListFoldersOption) iter.Seq2[*Folder, error] {
+	return c.subFoldersPaginator(fid).All(ctx, lfo)
+}
+
+func (c *Client) subFoldersPaginator(fid int64) *fresh.Paginator[*Folder] {
+	return newPaginator(func(ctx context.Context, lo ListOption) ([]*Folder, string, error) {
+		return c.listSubFolders(ctx, fid, lo)
+	})
 }
 
 func (c *Client) ListSubFoldersTranslated(ctx context.Context, fid int64, lang string, lfo *ListFoldersOption) ([]*Folder, bool, error) {
+	return hasNext(c.listSubFoldersTranslated(ctx, fid, lang, lfo))
+}
+
+func (c *Client) listSubFoldersTranslated(ctx context.Context, fid int64, lang string, lo ListOption) ([]*Folder, string, error) {
 	url := c.Endpoint("/solutions/folders/%d/subfolders/%s", fid, lang)
 	folders := []*Folder{}
-	next, err := c.DoList(ctx, url, lfo, &folders)
+	next, err := c.DoListLink(ctx, url, lo, &folders)
 	return folders, next, err
 }
 
 func (c *Client) IterSubFoldersTranslated(ctx context.Context, fid int64, lang string, lfo *ListFoldersOption, iff func(*Folder) error) error {
-	if lfo == nil {
-		lfo = &ListFoldersOption{}
-	}
-	if lfo.Page < 1 {
-		lfo.Page = 1
-	}
-	if lfo.PerPage < 1 {
-		lfo.PerPage = 100
-	}
+	return c.subFoldersTranslatedPaginator(fid, lang).Iter(ctx, lfo, iff)
+}
 
-	for {
-		folders, next, err := c.ListSubFoldersTranslated(ctx, fid, lang, lfo)
-		if err != nil {
-			return err
-		}
-		for _, f := range folders {
-			if err = iff(f); err != nil {
-				return err
-			}
-		}
-		if !next {
-			break
-		}
-		lfo.Page++
-	}
-	return nil
+// AllSubFoldersTranslated is like IterSubFoldersTranslated but returns an iterator, the lfo will not be modified.
+func (c *Client) AllSubFoldersTranslated(ctx context.Context, fid int64, lang string, lfo *ListFoldersOption) iter.Seq2[*Folder, error] {
+	return c.subFoldersTranslatedPaginator(fid, lang).All(ctx, lfo)
+}
+
+func (c *Client) subFoldersTranslatedPaginator(fid int64, lang string) *fresh.Paginator[*Folder] {
+	return newPaginator(func(ctx context.Context, lo ListOption) ([]*Folder, string, error) {
+		return c.listSubFoldersTranslated(ctx, fid, lang, lo)
+	})
 }
 
 func (c *Client) DeleteFolder(ctx context.Context, fid int64) error {
@@ -344,6 +294,10 @@ func (c *Client) DeleteFolder(ctx context.Context, fid int64) error {
 }
 
 func (c *Client) CreateArticle(ctx context.Context, fid int64, article *ArticleCreate) (*Article, error) {
+	if err := fresh.CheckFilesSize(article.Files(), ArticleAttachmentsMaxSize); err != nil {
+		return nil, err
+	}
+
 	url := c.Endpoint("/solutions/folders/%d/articles", fid)
 	result := &Article{}
 	if err := c.DoPost(ctx, url, article, result); err != nil {
@@ -353,6 +307,10 @@ func (c *Client) CreateArticle(ctx context.Context, fid int64, article *ArticleC
 }
 
 func (c *Client) CreateArticleTranslated(ctx context.Context, aid int64, lang string, article *ArticleCreate) (*Article, error) {
+	if err := fresh.CheckFilesSize(article.Files(), ArticleAttachmentsMaxSize); err != nil {
+		return nil, err
+	}
+
 	url := c.Endpoint("/solutions/articles/%d/%s", aid, lang)
 	result := &Article{}
 	if err := c.DoPost(ctx, url, article, result); err != nil {
@@ -362,6 +320,10 @@ func (c *Client) CreateArticleTranslated(ctx context.Context, aid int64, lang st
 }
 
 func (c *Client) UpdateArticle(ctx context.Context, aid int64, article *ArticleUpdate) (*Article, error) {
+	if err := fresh.CheckFilesSize(article.Files(), ArticleAttachmentsMaxSize); err != nil {
+		return nil, err
+	}
+
 	url := c.Endpoint("/solutions/articles/%d", aid)
 	result := &Article{}
 	if err := c.DoPut(ctx, url, article, result); err != nil {
@@ -371,6 +333,10 @@ func (c *Client) UpdateArticle(ctx context.Context, aid int64, article *ArticleU
 }
 
 func (c *Client) UpdateArticleTranslated(ctx context.Context, aid int64, lang string, article *ArticleUpdate) (*Article, error) {
+	if err := fresh.CheckFilesSize(article.Files(), ArticleAttachmentsMaxSize); err != nil {
+		return nil, err
+	}
+
 	url := c.Endpoint("/solutions/articles/%d/%s", aid, lang)
 	result := &Article{}
 	if err := c.DoPut(ctx, url, article, result); err != nil {
@@ -394,75 +360,55 @@ func (c *Client) GetArticleTranslated(ctx context.Context, aid int64, lang strin
 }
 
 func (c *Client) ListFolderArticles(ctx context.Context, fid int64, lao *ListArticlesOption) ([]*Article, bool, error) {
+	return hasNext(c.listFolderArticles(ctx, fid, lao))
+}
+
+func (c *Client) listFolderArticles(ctx context.Context, fid int64, lo ListOption) ([]*Article, string, error) {
 	url := c.Endpoint("/solutions/folders/%d/articles", fid)
 	articles := []*Article{}
-	next, err := c.DoList(ctx, url, lao, &articles)
+	next, err := c.DoListLink(ctx, url, lo, &articles)
 	return articles, next, err
 }
 
 func (c *Client) IterFolderArticles(ctx context.Context, fid int64, lao *ListArticlesOption, iaf func(*Article) error) error {
-	if lao == nil {
-		lao = &ListArticlesOption{}
-	}
-	if lao.Page < 1 {
-		lao.Page = 1
-	}
-	if lao.PerPage < 1 {
-		lao.PerPage = 100
-	}
+	return c.folderArticlesPaginator(fid).Iter(ctx, lao, iaf)
+}
 
-	for {
-		articles, next, err := c.ListFolderArticles(ctx, fid, lao)
-		if err != nil {
-			return err
-		}
-		for _, a := range articles {
-			if err = iaf(a); err != nil {
-				return err
-			}
-		}
-		if !next {
-			break
-		}
-		lao.Page++
-	}
-	return nil
+// AllFolderArticles is like IterFolderArticles but returns an iterator, the lao will not be modified.
+func (c *Client) AllFolderArticles(ctx context.Context, fid int64, lao *ListArticlesOption) iter.Seq2[*Article, error] {
+	return c.folderArticlesPaginator(fid).All(ctx, lao)
+}
+
+func (c *Client) folderArticlesPaginator(fid int64) *fresh.Paginator[*Article] {
+	return newPaginator(func(ctx context.Context, lo ListOption) ([]*Article, string, error) {
+		return c.listFolderArticles(ctx, fid, lo)
+	})
 }
 
 func (c *Client) ListFolderArticlesTranslated(ctx context.Context, fid int64, lang string, lao *ListArticlesOption) ([]*Article, bool, error) {
+	return hasNext(c.listFolderArticlesTranslated(ctx, fid, lang, lao))
+}
+
+func (c *Client) listFolderArticlesTranslated(ctx context.Context, fid int64, lang string, lo ListOption) ([]*Article, string, error) {
 	url := c.Endpoint("/solutions/folders/%d/farticles/%s", fid, lang)
 	articles := []*Article{}
-	next, err := c.DoList(ctx, url, lao, &articles)
+	next, err := c.DoListLink(ctx, url, lo, &articles)
 	return articles, next, err
 }
 
 func (c *Client) IterFolderArticlesTranslated(ctx context.Context, fid int64, lang string, lao *ListArticlesOption, iaf func(*Article) error) error {
-	if lao == nil {
-		lao = &ListArticlesOption{}
-	}
-	if lao.Page < 1 {
-		lao.Page = 1
-	}
-	if lao.PerPage < 1 {
-		lao.PerPage = 100
-	}
+	return c.folderArticlesTranslatedPaginator(fid, lang).Iter(ctx, lao, iaf)
+}
 
-	for {
-		articles, next, err := c.ListFolderArticlesTranslated(ctx, fid, lang, lao)
-		if err != nil {
-			return err
-		}
-		for _, a := range articles {
-			if err = iaf(a); err != nil {
-				return err
-			}
-		}
-		if !next {
-			break
-		}
-		lao.Page++
-	}
-	return nil
+// AllFolderArticlesTranslated is like IterFolderArticlesTranslated but returns an iterator, the lao will not be modified.
+func (c *Client) AllFolderArticlesTranslated(ctx context.Context, fid int64, lang string, lao *ListArticlesOption) iter.Seq2[*Article, error] {
+	return c.folderArticlesTranslatedPaginator(fid, lang).All(ctx, lao)
+}
+
+func (c *Client) folderArticlesTranslatedPaginator(fid int64, lang string) *fresh.Paginator[*Article] {
+	return newPaginator(func(ctx context.Context, lo ListOption) ([]*Article, string, error) {
+		return c.listFolderArticlesTranslated(ctx, fid, lang, lo)
+	})
 }
 
 func (c *Client) DeleteArticle(ctx context.Context, aid int64) error {
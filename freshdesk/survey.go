@@ -0,0 +1,223 @@
+package freshdesk
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+
+	"github.com/askasoft/pango/num"
+)
+
+type SatisfactionRatingValue int
+
+const (
+	SatisfactionRatingExtremelyHappy   SatisfactionRatingValue = 103
+	SatisfactionRatingVeryHappy        SatisfactionRatingValue = 102
+	SatisfactionRatingHappy            SatisfactionRatingValue = 101
+	SatisfactionRatingNeutral          SatisfactionRatingValue = 100
+	SatisfactionRatingUnhappy          SatisfactionRatingValue = -101
+	SatisfactionRatingVeryUnhappy      SatisfactionRatingValue = -102
+	SatisfactionRatingExtremelyUnhappy SatisfactionRatingValue = -103
+)
+
+func (srv SatisfactionRatingValue) String() string {
+	switch srv {
+	case SatisfactionRatingExtremelyHappy:
+		return "ExtremelyHappy"
+	case SatisfactionRatingVeryHappy:
+		return "VeryHappy"
+	case SatisfactionRatingHappy:
+		return "Happy"
+	case SatisfactionRatingNeutral:
+		return "Neutral"
+	case SatisfactionRatingUnhappy:
+		return "Unhappy"
+	case SatisfactionRatingVeryUnhappy:
+		return "VeryUnhappy"
+	case SatisfactionRatingExtremelyUnhappy:
+		return "ExtremelyUnhappy"
+	default:
+		return num.Itoa(int(srv))
+	}
+}
+
+// IsPositive reports whether the rating is happy (greater than neutral)
+func (srv SatisfactionRatingValue) IsPositive() bool {
+	return srv > SatisfactionRatingNeutral
+}
+
+// IsNegative reports whether the rating is unhappy (less than neutral)
+func (srv SatisfactionRatingValue) IsNegative() bool {
+	return srv < 0
+}
+
+// DefaultQuestionKey the rating key of the default question of a survey
+const DefaultQuestionKey = "default_question"
+
+type Survey struct {
+	ID int64 `json:"id,omitempty"`
+
+	// Title of the survey
+	Title string `json:"title,omitempty"`
+
+	// Set as true if the survey is active
+	Active bool `json:"active,omitempty"`
+
+	// The questions of the survey
+	Questions []*SurveyQuestion `json:"questions,omitempty"`
+
+	CreatedAt Time `json:"created_at,omitzero"`
+
+	UpdatedAt Time `json:"updated_at,omitzero"`
+}
+
+func (s *Survey) String() string {
+	return toString(s)
+}
+
+// Question returns the question of the rating key (e.g. "default_question", "question_123"), returns nil if not found.
+func (s *Survey) Question(key string) *SurveyQuestion {
+	for _, q := range s.Questions {
+		if q.Key() == key {
+			return q
+		}
+	}
+	return nil
+}
+
+// QuestionTexts returns the map of the rating key (e.g. "default_question", "question_123") to the question text (label).
+func (s *Survey) QuestionTexts() map[string]string {
+	m := make(map[string]string, len(s.Questions))
+	for _, q := range s.Questions {
+		m[q.Key()] = q.Label
+	}
+	return m
+}
+
+type SurveyQuestion struct {
+	ID int64 `json:"id,omitempty"`
+
+	// Text of the question
+	Label string `json:"label,omitempty"`
+
+	// The rating values accepted by the question
+	AcceptedRatings []SatisfactionRatingValue `json:"accepted_ratings,omitempty"`
+
+	// Set as true if it is the default question of the survey
+	Default bool `json:"default,omitempty"`
+}
+
+func (sq *SurveyQuestion) String() string {
+	return toString(sq)
+}
+
+// Key returns the key of the question in the SatisfactionRating.Ratings,
+// "default_question" for the default question, otherwise "question_<id>".
+func (sq *SurveyQuestion) Key() string {
+	if sq.Default {
+		return DefaultQuestionKey
+	}
+	return "question_" + num.Ltoa(sq.ID)
+}
+
+type SatisfactionRating struct {
+	ID int64 `json:"id,omitempty"`
+
+	// ID of the survey
+	SurveyID int64 `json:"survey_id,omitempty"`
+
+	// ID of the user (the requester) who rated
+	UserID int64 `json:"user_id,omitempty"`
+
+	// ID of the agent to whom the ticket is assigned
+	AgentID int64 `json:"agent_id,omitempty"`
+
+	// ID of the group to which the ticket is assigned
+	GroupID int64 `json:"group_id,omitempty"`
+
+	// ID of the rated ticket
+	TicketID int64 `json:"ticket_id,omitempty"`
+
+	// Feedback of the user
+	Feedback string `json:"feedback,omitempty"`
+
+	// The ratings of the questions, the key is "default_question" or "question_<id>"
+	Ratings map[string]SatisfactionRatingValue `json:"ratings,omitempty"`
+
+	CreatedAt Time `json:"created_at,omitzero"`
+
+	UpdatedAt Time `json:"updated_at,omitzero"`
+}
+
+func (sr *SatisfactionRating) String() string {
+	return toString(sr)
+}
+
+// Rating returns the rating of the default question
+func (sr *SatisfactionRating) Rating() SatisfactionRatingValue {
+	return sr.Ratings[DefaultQuestionKey]
+}
+
+// QuestionRating a rating of a survey question
+type QuestionRating struct {
+	// Key the key of the question, "default_question" or "question_<id>"
+	Key string
+
+	// Question the question of the survey, nil if the question is not found in the survey
+	Question *SurveyQuestion
+
+	// Rating the rating value
+	Rating SatisfactionRatingValue
+}
+
+// Text returns the text (label) of the question, or the Key if the question is not found in the survey.
+func (qr *QuestionRating) Text() string {
+	if qr.Question != nil {
+		return qr.Question.Label
+	}
+	return qr.Key
+}
+
+func (qr *QuestionRating) String() string {
+	return qr.Text() + ": " + qr.Rating.String()
+}
+
+// QuestionRatings returns the ratings with the questions of the survey (can be nil),
+// the rating of the default question comes first, the others are in the order of the survey questions (or the keys).
+func (sr *SatisfactionRating) QuestionRatings(s *Survey) []*QuestionRating {
+	qrs := make([]*QuestionRating, 0, len(sr.Ratings))
+	for k, v := range sr.Ratings {
+		qr := &QuestionRating{Key: k, Rating: v}
+		if s != nil {
+			qr.Question = s.Question(k)
+		}
+		qrs = append(qrs, qr)
+	}
+
+	index := func(qr *QuestionRating) int {
+		if qr.Key == DefaultQuestionKey {
+			return -1
+		}
+		if qr.Question != nil {
+			return slices.Index(s.Questions, qr.Question)
+		}
+		return len(sr.Ratings)
+	}
+
+	slices.SortFunc(qrs, func(a, b *QuestionRating) int {
+		return cmp.Or(cmp.Compare(index(a), index(b)), strings.Compare(a.Key, b.Key))
+	})
+	return qrs
+}
+
+type SatisfactionRatingCreate struct {
+	// The ratings of the questions, the key is "default_question" or "question_<id>", the default question is mandatory
+	Ratings map[string]SatisfactionRatingValue `json:"ratings,omitempty"`
+
+	// Feedback of the user
+	Feedback string `json:"feedback,omitempty"`
+}
+
+func (src *SatisfactionRatingCreate) String() string {
+	return toString(src)
+}
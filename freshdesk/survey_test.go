@@ -0,0 +1,63 @@
+package freshdesk
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestSatisfactionRatingQuestions(t *testing.T) {
+	survey := &Survey{}
+	err := json.Unmarshal([]byte(`{
+		"id": 1, "title": "Default", "active": true,
+		"questions": [
+			{"id": 11, "label": "How would you rate your overall satisfaction?", "accepted_ratings": [103, 100, -103], "default": true},
+			{"id": 12, "label": "How would you rate the agent?", "accepted_ratings": [103, 100, -103], "default": false},
+			{"id": 13, "label": "How would you rate the response time?", "accepted_ratings": [103, 100, -103], "default": false}
+		]
+	}`), survey)
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+
+	texts := survey.QuestionTexts()
+	if len(texts) != 3 || texts["default_question"] != "How would you rate your overall satisfaction?" || texts["question_13"] != "How would you rate the response time?" {
+		t.Errorf("QuestionTexts() = %v", texts)
+	}
+
+	sr := &SatisfactionRating{
+		SurveyID: 1,
+		Ratings: map[string]SatisfactionRatingValue{
+			"question_13":      SatisfactionRatingNeutral,
+			"question_99":      SatisfactionRatingHappy,
+			"question_12":      SatisfactionRatingExtremelyUnhappy,
+			"default_question": SatisfactionRatingExtremelyHappy,
+		},
+	}
+
+	if r := sr.Rating(); r != SatisfactionRatingExtremelyHappy || !r.IsPositive() || r.IsNegative() {
+		t.Errorf("Rating() = %v", r)
+	}
+
+	want := []string{
+		"How would you rate your overall satisfaction?: ExtremelyHappy",
+		"How would you rate the agent?: ExtremelyUnhappy",
+		"How would you rate the response time?: Neutral",
+		"question_99: Happy",
+	}
+
+	qrs := sr.QuestionRatings(survey)
+	if len(qrs) != len(want) {
+		t.Fatalf("QuestionRatings() = %v", qrs)
+	}
+	for i, qr := range qrs {
+		if a := qr.String(); a != want[i] {
+			t.Errorf("#%d QuestionRating = %q, want %q", i, a, want[i])
+		}
+	}
+
+	// without the survey
+	qrs = sr.QuestionRatings(nil)
+	if qrs[0].Key != "default_question" || qrs[1].Key != "question_12" || qrs[3].Key != "question_99" {
+		t.Errorf("QuestionRatings(nil) = %v", qrs)
+	}
+}
@@ -0,0 +1,122 @@
+package freshdesk
+
+import (
+	"context"
+	"iter"
+
+	"github.com/askasoft/gofresh/fresh"
+)
+
+// ---------------------------------------------------
+// Survey
+
+type SurveyState string
+
+const (
+	SurveyStateActive SurveyState = "active"
+	SurveyStateAll    SurveyState = ""
+)
+
+type ListSurveysOption struct {
+	State   SurveyState // active
+	Page    int
+	PerPage int
+}
+
+func (lso *ListSurveysOption) IsNil() bool {
+	return lso == nil
+}
+
+func (lso *ListSurveysOption) Values() Values {
+	q := Values{}
+	q.SetString("state", string(lso.State))
+	q.SetInt("page", lso.Page)
+	q.SetInt("per_page", lso.PerPage)
+	return q
+}
+
+type ListSatisfactionRatingsOption struct {
+	CreatedSince Time
+	Page         int
+	PerPage      int
+}
+
+func (lsro *ListSatisfactionRatingsOption) IsNil() bool {
+	return lsro == nil
+}
+
+func (lsro *ListSatisfactionRatingsOption) Values() Values {
+	q := Values{}
+	q.SetTime("created_since", lsro.CreatedSince)
+	q.SetInt("page", lsro.Page)
+	q.SetInt("per_page", lsro.PerPage)
+	return q
+}
+
+func (c *Client) ListSurveys(ctx context.Context, lso *ListSurveysOption) ([]*Survey, bool, error) {
+	return hasNext(c.listSurveys(ctx, lso))
+}
+
+func (c *Client) listSurveys(ctx context.Context, lo ListOption) ([]*Survey, string, error) {
+	url := c.Endpoint("/surveys")
+	surveys := []*Survey{}
+	next, err := c.DoListLink(ctx, url, lo, &surveys)
+	return surveys, next, err
+}
+
+func (c *Client) IterSurveys(ctx context.Context, lso *ListSurveysOption, isf func(*Survey) error) error {
+	return c.surveysPaginator().Iter(ctx, lso, isf)
+}
+
+// AllSurveys is like IterSurveys but returns an iterator, the lso will not be modified.
+func (c *Client) AllSurveys(ctx context.Context, lso *ListSurveysOption) iter.Seq2[*Survey, error] {
+	return c.surveysPaginator().All(ctx, lso)
+}
+
+func (c *Client) surveysPaginator() *fresh.Paginator[*Survey] {
+	return newPaginator(c.listSurveys)
+}
+
+// ListSatisfactionRatings lists the satisfaction ratings of all the tickets,
+// the ratings created in the last 30 days are returned if the lsro.CreatedSince is not set.
+func (c *Client) ListSatisfactionRatings(ctx context.Context, lsro *ListSatisfactionRatingsOption) ([]*SatisfactionRating, bool, error) {
+	return hasNext(c.listSatisfactionRatings(ctx, lsro))
+}
+
+func (c *Client) listSatisfactionRatings(ctx context.Context, lo ListOption) ([]*SatisfactionRating, string, error) {
+	url := c.Endpoint("/surveys/satisfaction_ratings")
+	srs := []*SatisfactionRating{}
+	next, err := c.DoListLink(ctx, url, lo, &srs)
+	return srs, next, err
+}
+
+func (c *Client) IterSatisfactionRatings(ctx context.Context, lsro *ListSatisfactionRatingsOption, isrf func(*SatisfactionRating) error) error {
+	return c.satisfactionRatingsPaginator().Iter(ctx, lsro, isrf)
+}
+
+// AllSatisfactionRatings is like IterSatisfactionRatings but returns an iterator, the lsro will not be modified.
+func (c *Client) AllSatisfactionRatings(ctx context.Context, lsro *ListSatisfactionRatingsOption) iter.Seq2[*SatisfactionRating, error] {
+	return c.satisfactionRatingsPaginator().All(ctx, lsro)
+}
+
+func (c *Client) satisfactionRatingsPaginator() *fresh.Paginator[*SatisfactionRating] {
+	return newPaginator(c.listSatisfactionRatings)
+}
+
+func (c *Client) ListTicketSatisfactionRatings(ctx context.Context, tid int64) ([]*SatisfactionRating, error) {
+	url := c.Endpoint("/tickets/%d/satisfaction_ratings", tid)
+	srs := []*SatisfactionRating{}
+	err := c.DoGet(ctx, url, &srs)
+	return srs, err
+}
+
+// CreateSatisfactionRating creates a satisfaction rating of the ticket on behalf of the requester,
+// the rating of the default question is mandatory.
+func (c *Client) CreateSatisfactionRating(ctx context.Context, tid int64, sr *SatisfactionRatingCreate) (*SatisfactionRating, error) {
+	url := c.Endpoint("/tickets/%d/satisfaction_ratings", tid)
+	result := &SatisfactionRating{}
+	if err := c.DoPost(ctx, url, sr, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,84 @@
+package freshdesk_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/askasoft/gofresh/freshdesk"
+	"github.com/askasoft/gofresh/freshdesk/fdtest"
+)
+
+func TestSatisfactionRatings(t *testing.T) {
+	fs := fdtest.NewServer()
+	defer fs.Close()
+
+	fd := testNewClient(fs)
+
+	fs.Store.Insert("surveys", fdtest.Record{"title": "Old", "active": false})
+	fs.Store.Insert("surveys", fdtest.Record{
+		"title":  "Default",
+		"active": true,
+		"questions": []any{
+			map[string]any{"id": 11, "label": "Overall", "accepted_ratings": []any{103, -103}, "default": true},
+			map[string]any{"id": 12, "label": "Agent", "accepted_ratings": []any{103, -103}, "default": false},
+		},
+	})
+
+	surveys, _, err := fd.ListSurveys(ctxbg, &freshdesk.ListSurveysOption{State: freshdesk.SurveyStateActive})
+	if err != nil || len(surveys) != 1 || surveys[0].Title != "Default" {
+		t.Fatalf("ListSurveys() = %v, %v", surveys, err)
+	}
+
+	ticket, err := fd.CreateTicket(ctxbg, &freshdesk.TicketCreate{Email: "a@example.com", Subject: "test"})
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+
+	if _, err = fd.CreateSatisfactionRating(ctxbg, ticket.ID, &freshdesk.SatisfactionRatingCreate{
+		Ratings: map[string]freshdesk.SatisfactionRatingValue{"question_12": freshdesk.SatisfactionRatingHappy},
+	}); !errors.Is(err, freshdesk.ErrValidation) {
+		t.Errorf("CreateSatisfactionRating() = %v, want %v", err, freshdesk.ErrValidation)
+	}
+
+	since := freshdesk.Time{Time: time.Now().Add(-time.Minute)}
+	for i := range 3 {
+		sr, err := fd.CreateSatisfactionRating(ctxbg, ticket.ID, &freshdesk.SatisfactionRatingCreate{
+			Ratings: map[string]freshdesk.SatisfactionRatingValue{
+				freshdesk.DefaultQuestionKey: freshdesk.SatisfactionRatingExtremelyHappy,
+				"question_12":                freshdesk.SatisfactionRatingExtremelyUnhappy,
+			},
+			Feedback: fmt.Sprintf("feedback %d", i),
+		})
+		if err != nil {
+			t.Fatalf("ERROR: %v", err)
+		}
+		if sr.TicketID != ticket.ID || sr.UserID != ticket.RequesterID || sr.SurveyID != surveys[0].ID {
+			t.Fatalf("CreateSatisfactionRating() = %v", sr)
+		}
+	}
+
+	srs, err := fd.ListTicketSatisfactionRatings(ctxbg, ticket.ID)
+	if err != nil || len(srs) != 3 {
+		t.Fatalf("ListTicketSatisfactionRatings() = %v, %v", srs, err)
+	}
+
+	n := 0
+	err = fd.IterSatisfactionRatings(ctxbg, &freshdesk.ListSatisfactionRatingsOption{CreatedSince: since, PerPage: 2}, func(sr *freshdesk.SatisfactionRating) error {
+		qrs := sr.QuestionRatings(surveys[0])
+		if len(qrs) != 2 || qrs[0].Text() != "Overall" || qrs[1].Text() != "Agent" || qrs[1].Rating != freshdesk.SatisfactionRatingExtremelyUnhappy {
+			return fmt.Errorf("QuestionRatings() = %v", qrs)
+		}
+		n++
+		return nil
+	})
+	if err != nil || n != 3 {
+		t.Fatalf("IterSatisfactionRatings() = %d, %v", n, err)
+	}
+
+	future := freshdesk.Time{Time: time.Now().Add(time.Hour)}
+	if srs, _, err = fd.ListSatisfactionRatings(ctxbg, &freshdesk.ListSatisfactionRatingsOption{CreatedSince: future}); err != nil || len(srs) != 0 {
+		t.Fatalf("ListSatisfactionRatings() = %v, %v", srs, err)
+	}
+}
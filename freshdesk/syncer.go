@@ -0,0 +1,85 @@
+package freshdesk
+
+import (
+	"context"
+	"iter"
+	"time"
+
+	"github.com/askasoft/gofresh/fresh/freshsync"
+)
+
+// NewTicketSyncer returns a Syncer of the tickets, the checkpoints are saved to the store (a MemoryStore if nil).
+// It has two sources: "tickets" lists the tickets by IterTicketsSliced in ascending order of updated_at,
+// and "deleted" lists the deleted tickets, which are emitted as freshsync.ChangeDeleted.
+// The lto can specify the other list options (e.g. Include, CompanyID),
+// the lto.Filter/UpdatedSince/Page/OrderBy/OrderType are ignored, and the lto will not be modified.
+func (c *Client) NewTicketSyncer(store freshsync.Store, lto *ListTicketsOption) *freshsync.Syncer[*Ticket] {
+	return freshsync.NewSyncer("freshdesk.tickets", store,
+		c.ticketSyncSource("tickets", "", lto),
+		c.ticketSyncSource("deleted", TicketFilterDeleted, lto),
+	)
+}
+
+func (c *Client) ticketSyncSource(name, filter string, lto *ListTicketsOption) *freshsync.Source[*Ticket] {
+	base := ListTicketsOption{}
+	if lto != nil {
+		base = *lto
+	}
+	base.Filter = filter
+
+	return &freshsync.Source[*Ticket]{
+		Name:    name,
+		Ordered: true,
+		List: func(ctx context.Context, since time.Time) iter.Seq2[*Ticket, error] {
+			o := base
+			o.UpdatedSince = syncSince(since)
+			return c.AllTicketsSliced(ctx, &o)
+		},
+		ID:        func(t *Ticket) int64 { return t.ID },
+		UpdatedAt: ticketUpdatedAt,
+		CreatedAt: func(t *Ticket) time.Time { return t.CreatedAt.Time },
+		Deleted:   func(t *Ticket) bool { return t.Deleted },
+	}
+}
+
+// NewContactSyncer returns a Syncer of the contacts, the checkpoints are saved to the store (a MemoryStore if nil).
+// It has two sources: "contacts" lists the contacts of lco, and "deleted" lists the deleted contacts
+// (only if lco.State is not specified), which are emitted as freshsync.ChangeDeleted.
+// The lco.UpdatedSince/Page are ignored, and the lco will not be modified.
+func (c *Client) NewContactSyncer(store freshsync.Store, lco *ListContactsOption) *freshsync.Syncer[*Contact] {
+	base := ListContactsOption{}
+	if lco != nil {
+		base = *lco
+	}
+
+	s := freshsync.NewSyncer("freshdesk.contacts", store, c.contactSyncSource("contacts", base))
+	if base.State == "" {
+		base.State = ContactStateDeleted
+		s.Sources = append(s.Sources, c.contactSyncSource("deleted", base))
+	}
+	return s
+}
+
+func (c *Client) contactSyncSource(name string, base ListContactsOption) *freshsync.Source[*Contact] {
+	return &freshsync.Source[*Contact]{
+		Name: name,
+		List: func(ctx context.Context, since time.Time) iter.Seq2[*Contact, error] {
+			o := base
+			o.UpdatedSince = syncSince(since)
+			return c.AllContacts(ctx, &o)
+		},
+		ID:        func(ct *Contact) int64 { return ct.ID },
+		UpdatedAt: func(ct *Contact) time.Time { return ct.UpdatedAt.Time },
+		CreatedAt: func(ct *Contact) time.Time { return ct.CreatedAt.Time },
+		Deleted:   func(ct *Contact) bool { return ct.Deleted },
+	}
+}
+
+// syncSince returns the updated_since of the sync time,
+// the zero time is converted to the unix epoch to list all the records (not only the records of the past 30 days).
+func syncSince(t time.Time) Time {
+	if t.IsZero() {
+		t = time.Unix(0, 0)
+	}
+	return Time{Time: t}
+}
@@ -0,0 +1,53 @@
+package freshdesk
+
+import (
+	"context"
+	"testing"
+
+	"github.com/askasoft/gofresh/fresh/freshsync"
+)
+
+func TestTicketSyncer(t *testing.T) {
+	fd := testNewFreshdesk(t)
+	if fd == nil {
+		return
+	}
+
+	tc := &TicketCreate{
+		Name:        "Sync Test",
+		Email:       "sync@example.com",
+		Subject:     "sync test",
+		Description: "sync test",
+		Status:      TicketStatusOpen,
+		Priority:    TicketPriorityLow,
+	}
+	ticket, err := fd.CreateTicket(ctxbg, tc)
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	defer fd.DeleteTicket(ctxbg, ticket.ID)
+
+	s := fd.NewTicketSyncer(nil, nil)
+	s.Start = ticket.CreatedAt.Time
+
+	changes := map[int64]freshsync.ChangeType{}
+	handler := func(ctx context.Context, c *freshsync.Change[*Ticket]) error {
+		changes[c.ID] = c.Type
+		return nil
+	}
+
+	if err = s.Sync(ctxbg, handler); err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	if ct := changes[ticket.ID]; ct != freshsync.ChangeCreated {
+		t.Fatalf("Sync() = %v, want %v", ct, freshsync.ChangeCreated)
+	}
+
+	clear(changes)
+	if err = s.Sync(ctxbg, handler); err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	if ct, ok := changes[ticket.ID]; ok {
+		t.Fatalf("Sync() = %v, want no change", ct)
+	}
+}
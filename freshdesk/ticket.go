@@ -180,6 +180,10 @@ type Ticket struct {
 	// Set to true if the ticket has been marked as spam
 	Spam bool `json:"spam,omitempty"`
 
+	// Set to true by GetArchivedTicket if the ticket has been moved to the archive.
+	// It is a client-side field, it is never sent or written to the json.
+	Archived bool `json:"-"`
+
 	// Timestamp that denotes when the ticket is due to be resolved
 	DueBy *Time `json:"due_by,omitempty"`
 
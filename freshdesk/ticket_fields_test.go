@@ -5,7 +5,7 @@ import (
 )
 
 func TestTicketFieldsAPIs(t *testing.T) {
-	fd := testNewFreshdesk(t)
+	fd := testNewLiveFreshdesk(t)
 	if fd == nil {
 		return
 	}
@@ -49,7 +49,7 @@ func TestTicketFieldsAPIs(t *testing.T) {
 }
 
 func TestListTicketFieldsAPIs(t *testing.T) {
-	fd := testNewFreshdesk(t)
+	fd := testNewLiveFreshdesk(t)
 	if fd == nil {
 		return
 	}
@@ -2,7 +2,14 @@ package freshdesk
 
 import (
 	"context"
+	"errors"
+	"iter"
 	"strings"
+	"time"
+
+	"github.com/askasoft/gofresh/fresh"
+	"github.com/askasoft/gofresh/fresh/query"
+	"github.com/askasoft/pango/num"
 )
 
 // ---------------------------------------------------
@@ -59,6 +66,26 @@ func (lto *ListTicketsOption) Values() Values {
 
 type FilterTicketsOption = FilterOption
 
+// QueryTicketStatus returns a filter query condition that matches any of the ticket statuses.
+func QueryTicketStatus(tss ...TicketStatus) query.Cond {
+	return query.In(query.Number("status"), tss...)
+}
+
+// QueryTicketPriority returns a filter query condition that matches any of the ticket priorities.
+func QueryTicketPriority(tps ...TicketPriority) query.Cond {
+	return query.In(query.Number("priority"), tps...)
+}
+
+// QueryTicketCreated returns a filter query condition that matches the tickets created in the date range [since, until].
+func QueryTicketCreated(since, until Date) query.Cond {
+	return query.Between(query.Date("created_at"), since, until)
+}
+
+// QueryTicketUpdated returns a filter query condition that matches the tickets updated in the date range [since, until].
+func QueryTicketUpdated(since, until Date) query.Cond {
+	return query.Between(query.Date("updated_at"), since, until)
+}
+
 type FilterTicketsResult struct {
 	Total   int       `json:"total"`
 	Results []*Ticket `json:"results"`
@@ -68,6 +95,10 @@ type FilterTicketsResult struct {
 type ListConversationsOption = PageOption
 
 func (c *Client) CreateTicket(ctx context.Context, ticket *TicketCreate) (*Ticket, error) {
+	if err := fresh.CheckFilesSize(ticket.Files(), TicketAttachmentsMaxSize); err != nil {
+		return nil, err
+	}
+
 	url := c.Endpoint("/tickets")
 	result := &Ticket{}
 	if err := c.DoPost(ctx, url, ticket, result); err != nil {
@@ -77,6 +108,10 @@ func (c *Client) CreateTicket(ctx context.Context, ticket *TicketCreate) (*Ticke
 }
 
 func (c *Client) CreateOutboundEmail(ctx context.Context, ticket *OutboundEmail) (*Ticket, error) {
+	if err := fresh.CheckFilesSize(ticket.Files(), TicketAttachmentsMaxSize); err != nil {
+		return nil, err
+	}
+
 	url := c.Endpoint("/tickets/outbound_email")
 	result := &Ticket{}
 	if err := c.DoPost(ctx, url, ticket, result); err != nil {
@@ -87,6 +122,7 @@ func (c *Client) CreateOutboundEmail(ctx context.Context, ticket *OutboundEmail)
 
 // GetTicket Get a Ticket
 // include: conversations, requester, company, stats
+// The archived ticket is not found by GetTicket, see GetTicketOrArchived.
 func (c *Client) GetTicket(ctx context.Context, tid int64, include ...string) (*Ticket, error) {
 	url := c.Endpoint("/tickets/%d", tid)
 	if len(include) > 0 {
@@ -109,41 +145,195 @@ func (c *Client) GetTicket(ctx context.Context, tid int64, include ...string) (*
 // 4. Use 'include' to embed additional details in the response. Each include will consume an additional 2 credits. For example if you embed the stats information you will be charged a total of 3 API credits for the call.
 // 5. For accounts created after 2018-11-30, you will have to use include to get description.
 func (c *Client) ListTickets(ctx context.Context, lto *ListTicketsOption) ([]*Ticket, bool, error) {
+	return hasNext(c.listTickets(ctx, lto))
+}
+
+func (c *Client) listTickets(ctx context.Context, lo ListOption) ([]*Ticket, string, error) {
 	url := c.Endpoint("/tickets")
 	tickets := []*Ticket{}
-	next, err := c.DoList(ctx, url, lto, &tickets)
+	next, err := c.DoListLink(ctx, url, lo, &tickets)
 	return tickets, next, err
 }
 
 func (c *Client) IterTickets(ctx context.Context, lto *ListTicketsOption, itf func(*Ticket) error) error {
-	if lto == nil {
-		lto = &ListTicketsOption{}
+	return c.ticketsPaginator().Iter(ctx, lto, itf)
+}
+
+// AllTickets is like IterTickets but returns an iterator, the lto will not be modified.
+func (c *Client) AllTickets(ctx context.Context, lto *ListTicketsOption) iter.Seq2[*Ticket, error] {
+	return c.ticketsPaginator().All(ctx, lto)
+}
+
+// ticketsPaginator returns a paginator for ListTickets, a *PageLimitError is returned if the tickets exceed 300 pages.
+func (c *Client) ticketsPaginator() *fresh.Paginator[*Ticket] {
+	p := newPaginator(c.listTickets)
+	p.MaxPage = 300
+	return p
+}
+
+// AllTicketPages returns an iterator over all the pages of tickets, the lto will not be modified.
+// The Page.Next can be saved to resume the iteration by AllTicketPagesFrom.
+func (c *Client) AllTicketPages(ctx context.Context, lto *ListTicketsOption) iter.Seq2[*Page[*Ticket], error] {
+	return c.ticketsPaginator().Pages(ctx, lto)
+}
+
+// AllTicketPagesFrom returns an iterator over the pages of tickets starting from the next URL (Page.Next).
+func (c *Client) AllTicketPagesFrom(ctx context.Context, next string) iter.Seq2[*Page[*Ticket], error] {
+	return c.ticketsPaginator().PagesFrom(ctx, next)
+}
+
+// IterTicketsConcurrently iterates the tickets by concurrent page requests, the tickets are delivered in order.
+// If cco.Window is set, the time range [lto.UpdatedSince, cco.Until) is split into time windows,
+// and the tickets of each window are listed in ascending order of updated_at, to get around the 300 pages limit.
+// The lto.Page/OrderBy/OrderType are ignored if the time windows are used, and the lto will not be modified.
+// The lto.UpdatedSince is required if the cco.Window is set, otherwise fresh.ErrWindowStart is returned.
+// If a query (window) has more than 300 pages, a *PageLimitError is returned, use a smaller window or IterTicketsSliced.
+func (c *Client) IterTicketsConcurrently(ctx context.Context, lto *ListTicketsOption, cco *ConcurrentOption, itf func(*Ticket) error) error {
+	qps, err := c.ticketsQueries(lto, cco)
+	if err != nil {
+		return err
+	}
+	return newConcurrent[*Ticket](cco, 300).Iter(ctx, qps, itf)
+}
+
+// AllTicketsConcurrently is like IterTicketsConcurrently but returns an iterator.
+func (c *Client) AllTicketsConcurrently(ctx context.Context, lto *ListTicketsOption, cco *ConcurrentOption) iter.Seq2[*Ticket, error] {
+	qps, err := c.ticketsQueries(lto, cco)
+	if err != nil {
+		return func(yield func(*Ticket, error) bool) {
+			yield(nil, err)
+		}
+	}
+	return newConcurrent[*Ticket](cco, 300).All(ctx, qps)
+}
+
+func (c *Client) ticketsQueries(lto *ListTicketsOption, cco *ConcurrentOption) ([]fresh.QueryPageFunc[*Ticket], error) {
+	base := ListTicketsOption{PerPage: 100}
+	if lto != nil {
+		base = *lto
+	}
+	if base.PerPage < 1 {
+		base.PerPage = 100
+	}
+
+	if cco == nil || cco.Window <= 0 {
+		qp := func(ctx context.Context, page int) ([]*Ticket, bool, error) {
+			o := base
+			o.Page = page
+			return c.ListTickets(ctx, &o)
+		}
+		return []fresh.QueryPageFunc[*Ticket]{qp}, nil
 	}
-	if lto.Page < 1 {
-		lto.Page = 1
+
+	ws, err := cco.Windows(base.UpdatedSince.Time)
+	if err != nil {
+		return nil, err
 	}
-	if lto.PerPage < 1 {
-		lto.PerPage = 100
+
+	base.OrderBy, base.OrderType = TicketOrderByUpdatedAt, OrderAsc
+
+	qps := []fresh.QueryPageFunc[*Ticket]{}
+	for _, w := range ws {
+		qp := func(ctx context.Context, page int) ([]*Ticket, bool, error) {
+			o := base
+			o.UpdatedSince = Time{Time: w.Start}
+			o.Page = page
+
+			tickets, next, err := c.ListTickets(ctx, &o)
+			if err != nil {
+				return nil, false, err
+			}
+
+			tickets, next = fresh.ClipWindow(w, tickets, next, ticketUpdatedAt)
+			return tickets, next, nil
+		}
+		qps = append(qps, qp)
 	}
+	return qps, nil
+}
+
+func ticketUpdatedAt(t *Ticket) time.Time {
+	return t.UpdatedAt.Time
+}
 
-	for {
-		tickets, next, err := c.ListTickets(ctx, lto)
+// IterTicketsSliced iterates all the tickets of lto, gets around the 300 pages limit of ListTickets.
+// The tickets are listed in ascending order of updated_at. When the 300th page is reached,
+// the listing is restarted with the UpdatedSince narrowed to the updated_at of the last ticket,
+// and the tickets which have been delivered with the same updated_at are skipped.
+// If more than 300 pages of tickets have the same updated_at, a *PageLimitError is returned.
+// The lto.Page/OrderBy/OrderType are ignored, and the lto will not be modified.
+func (c *Client) IterTicketsSliced(ctx context.Context, lto *ListTicketsOption, itf func(*Ticket) error) error {
+	for t, err := range c.AllTicketsSliced(ctx, lto) {
 		if err != nil {
 			return err
 		}
-		for _, t := range tickets {
-			if err = itf(t); err != nil {
-				return err
-			}
-		}
-		if !next || lto.Page >= 300 {
-			break
+		if err = itf(t); err != nil {
+			return err
 		}
-		lto.Page++
 	}
 	return nil
 }
 
+// AllTicketsSliced is like IterTicketsSliced but returns an iterator.
+func (c *Client) AllTicketsSliced(ctx context.Context, lto *ListTicketsOption) iter.Seq2[*Ticket, error] {
+	return func(yield func(*Ticket, error) bool) {
+		o := ListTicketsOption{}
+		if lto != nil {
+			o = *lto
+		}
+		o.Page = 0
+		o.OrderBy, o.OrderType = TicketOrderByUpdatedAt, OrderAsc
+		if o.PerPage < 1 {
+			o.PerPage = 100
+		}
+
+		p := c.ticketsPaginator()
+		seen := map[int64]bool{} // the delivered tickets which updated at UpdatedSince
+		for {
+			last, lasts, capped := o.UpdatedSince.Time, seen, false
+			for pg, err := range p.Pages(ctx, &o) {
+				if err != nil {
+					// the paginator yields a PageLimitError only if the MaxPage is reached and there is still a next page
+					var ple *fresh.PageLimitError
+					if errors.As(err, &ple) {
+						capped = true
+						break
+					}
+					yield(nil, err)
+					return
+				}
+
+				for _, t := range pg.Items {
+					if seen[t.ID] {
+						continue
+					}
+
+					if ut := ticketUpdatedAt(t); !ut.Equal(last) {
+						last, lasts = ut, map[int64]bool{}
+					}
+					lasts[t.ID] = true
+
+					if !yield(t, nil) {
+						return
+					}
+				}
+			}
+
+			if !capped {
+				return
+			}
+
+			if last.Equal(o.UpdatedSince.Time) {
+				yield(nil, &fresh.PageLimitError{MaxPage: p.MaxPage, Query: "tickets updated at " + last.UTC().Format(fresh.TimeFormat)})
+				return
+			}
+
+			o.UpdatedSince = Time{Time: last}
+			seen = lasts
+		}
+	}
+}
+
 // FilterTickets
 // Use custom ticket fields that you have created in your account to filter through the tickets and get a list of tickets matching the specified ticket fields.
 // Query Format: "(ticket_field:integer OR ticket_field:'string') AND ticket_field:boolean"
@@ -180,32 +370,128 @@ func (c *Client) FilterTickets(ctx context.Context, fto *FilterTicketsOption) ([
 }
 
 func (c *Client) IterFilterTickets(ctx context.Context, fto *FilterTicketsOption, itf func(*Ticket) error) error {
-	if fto == nil {
-		fto = &FilterTicketsOption{}
-	}
-	if fto.Page < 1 {
-		fto.Page = 1
+	return c.filterTicketsPaginator().Iter(ctx, fto, itf)
+}
+
+// AllFilterTickets is like IterFilterTickets but returns an iterator, the fto will not be modified.
+func (c *Client) AllFilterTickets(ctx context.Context, fto *FilterTicketsOption) iter.Seq2[*Ticket, error] {
+	return c.filterTicketsPaginator().All(ctx, fto)
+}
+
+// filterTicketsPaginator returns a paginator for FilterTickets.
+// The number of objects returned per page is 30, and the page number should not exceed 10.
+func (c *Client) filterTicketsPaginator() *fresh.Paginator[*Ticket] {
+	lp := func(ctx context.Context, lo ListOption) ([]*Ticket, string, error) {
+		url := c.Endpoint("/search/tickets")
+		ftr := &FilterTicketsResult{}
+		if _, err := c.DoListLink(ctx, url, lo, ftr); err != nil {
+			return nil, "", err
+		}
+
+		// the search api has no Link header, build the next page URL by the total
+		q := lo.Values()
+		page := num.Atoi(q.Get("page"))
+		if len(ftr.Results) < 30 || (page-1)*30+len(ftr.Results) >= ftr.Total {
+			return ftr.Results, "", nil
+		}
+
+		q.SetInt("page", page+1)
+		return ftr.Results, url + "?" + q.Encode(), nil
 	}
 
-	for {
-		tickets, total, err := c.FilterTickets(ctx, fto)
+	return &fresh.Paginator[*Ticket]{ListPage: lp, MaxPage: 10}
+}
+
+// IterFilterTicketsSliced iterates all the tickets of fto which created in the date range [since, until],
+// gets around the 10 pages (300 tickets) limit of FilterTickets.
+// The created_at ranges are added to the fto.Query, and the range is split in half until the results of each range fit in 10 pages.
+// If the results of a single day exceed 10 pages, a *PageLimitError is returned.
+// The zero until means today, the fto.Page is ignored, and the fto will not be modified.
+func (c *Client) IterFilterTicketsSliced(ctx context.Context, fto *FilterTicketsOption, since, until Date, itf func(*Ticket) error) error {
+	for t, err := range c.AllFilterTicketsSliced(ctx, fto, since, until) {
 		if err != nil {
 			return err
 		}
-		for _, t := range tickets {
-			if err = itf(t); err != nil {
-				return err
-			}
-		}
-		if len(tickets) < 30 || fto.Page >= 10 || (fto.Page-1)*30+len(tickets) >= total {
-			break
+		if err = itf(t); err != nil {
+			return err
 		}
-		fto.Page++
 	}
 	return nil
 }
 
+// AllFilterTicketsSliced is like IterFilterTicketsSliced but returns an iterator.
+func (c *Client) AllFilterTicketsSliced(ctx context.Context, fto *FilterTicketsOption, since, until Date) iter.Seq2[*Ticket, error] {
+	return func(yield func(*Ticket, error) bool) {
+		query := ""
+		if fto != nil {
+			query = fto.Query
+		}
+		if until.IsZero() {
+			until = Date{Time: time.Now()}
+		}
+
+		p := c.filterTicketsPaginator()
+		ranges := [][2]time.Time{{truncateDay(since.Time), truncateDay(until.Time)}}
+		for len(ranges) > 0 {
+			r := ranges[0]
+			ranges = ranges[1:]
+
+			fo := &FilterTicketsOption{Query: filterCreatedRange(query, r[0], r[1]), Page: 1}
+			tickets, total, err := c.FilterTickets(ctx, fo)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+
+			if total > p.MaxPage*30 {
+				days := int(r[1].Sub(r[0]) / (time.Hour * 24))
+				if days < 1 {
+					yield(nil, &fresh.PageLimitError{MaxPage: p.MaxPage, Query: fo.Query})
+					return
+				}
+
+				m := r[0].AddDate(0, 0, days/2)
+				ranges = append([][2]time.Time{{r[0], m}, {m.AddDate(0, 0, 1), r[1]}}, ranges...)
+				continue
+			}
+
+			for _, t := range tickets {
+				if !yield(t, nil) {
+					return
+				}
+			}
+
+			if len(tickets) < total {
+				fo.Page = 2
+				for t, err := range p.All(ctx, fo) {
+					if !yield(t, err) || err != nil {
+						return
+					}
+				}
+			}
+		}
+	}
+}
+
+// filterCreatedRange adds the created_at range [since, until] to the filter query.
+func filterCreatedRange(query string, since, until time.Time) string {
+	cr := "created_at:>'" + since.Format(fresh.DateFormat) + "' AND created_at:<'" + until.Format(fresh.DateFormat) + "'"
+	if query == "" {
+		return cr
+	}
+	return "(" + query + ") AND " + cr
+}
+
+func truncateDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
 func (c *Client) UpdateTicket(ctx context.Context, tid int64, ticket *TicketUpdate) (*Ticket, error) {
+	if err := fresh.CheckFilesSize(ticket.Files(), TicketAttachmentsMaxSize); err != nil {
+		return nil, err
+	}
+
 	url := c.Endpoint("/tickets/%d", tid)
 	result := &Ticket{}
 	if err := c.DoPut(ctx, url, ticket, result); err != nil {
@@ -311,42 +597,36 @@ func (c *Client) BulkDeleteTickets(ctx context.Context, tids []int64) (string, e
 // Conversation
 
 func (c *Client) ListTicketConversations(ctx context.Context, tid int64, lco *ListConversationsOption) ([]*Conversation, bool, error) {
+	return hasNext(c.listTicketConversations(ctx, tid, lco))
+}
+
+func (c *Client) listTicketConversations(ctx context.Context, tid int64, lo ListOption) ([]*Conversation, string, error) {
 	url := c.Endpoint("/tickets/%d/conversations", tid)
 	conversations := []*Conversation{}
-	next, err := c.DoList(ctx, url, lco, &conversations)
+	next, err := c.DoListLink(ctx, url, lo, &conversations)
 	return conversations, next, err
 }
 
 func (c *Client) IterTicketConversations(ctx context.Context, tid int64, lco *ListConversationsOption, icf func(*Conversation) error) error {
-	if lco == nil {
-		lco = &ListConversationsOption{}
-	}
-	if lco.Page < 1 {
-		lco.Page = 1
-	}
-	if lco.PerPage < 1 {
-		lco.PerPage = 100
-	}
+	return c.ticketConversationsPaginator(tid).Iter(ctx, lco, icf)
+}
 
-	for {
-		conversations, next, err := c.ListTicketConversations(ctx, tid, lco)
-		if err != nil {
-			return err
-		}
-		for _, c := range conversations {
-			if err = icf(c); err != nil {
-				return err
-			}
-		}
-		if !next {
-			break
-		}
-		lco.Page++
-	}
-	return nil
+// AllTicketConversations is like IterTicketConversations but returns an iterator, the lco will not be modified.
+func (c *Client) AllTicketConversations(ctx context.Context, tid int64, lco *ListConversationsOption) iter.Seq2[*Conversation, error] {
+	return c.ticketConversationsPaginator(tid).All(ctx, lco)
+}
+
+func (c *Client) ticketConversationsPaginator(tid int64) *fresh.Paginator[*Conversation] {
+	return newPaginator(func(ctx context.Context, lo ListOption) ([]*Conversation, string, error) {
+		return c.listTicketConversations(ctx, tid, lo)
+	})
 }
 
 func (c *Client) CreateReply(ctx context.Context, tid int64, reply *ReplyCreate) (*Reply, error) {
+	if err := fresh.CheckFilesSize(reply.Files(), ConversationAttachmentsMaxSize); err != nil {
+		return nil, err
+	}
+
 	url := c.Endpoint("/tickets/%d/reply", tid)
 	result := &Reply{}
 	if err := c.DoPost(ctx, url, reply, result); err != nil {
@@ -356,6 +636,10 @@ func (c *Client) CreateReply(ctx context.Context, tid int64, reply *ReplyCreate)
 }
 
 func (c *Client) CreateNote(ctx context.Context, tid int64, note *NoteCreate) (*Note, error) {
+	if err := fresh.CheckFilesSize(note.Files(), ConversationAttachmentsMaxSize); err != nil {
+		return nil, err
+	}
+
 	url := c.Endpoint("/tickets/%d/notes", tid)
 	result := &Note{}
 	if err := c.DoPost(ctx, url, note, result); err != nil {
@@ -366,6 +650,10 @@ func (c *Client) CreateNote(ctx context.Context, tid int64, note *NoteCreate) (*
 
 // UpdateConversation only public & private notes can be edited.
 func (c *Client) UpdateConversation(ctx context.Context, cid int64, note *NoteUpdate) (*Conversation, error) {
+	if err := fresh.CheckFilesSize(note.Files(), ConversationAttachmentsMaxSize); err != nil {
+		return nil, err
+	}
+
 	url := c.Endpoint("/conversations/%d", cid)
 	result := &Conversation{}
 	if err := c.DoPut(ctx, url, note, result); err != nil {
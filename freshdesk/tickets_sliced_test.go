@@ -0,0 +1,97 @@
+package freshdesk_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/askasoft/gofresh/fresh"
+	"github.com/askasoft/gofresh/freshdesk"
+	"github.com/askasoft/gofresh/freshdesk/fdtest"
+)
+
+func TestTicketsSliced(t *testing.T) {
+	fs := fdtest.NewServer()
+	defer fs.Close()
+
+	fs.SetNow(testClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)))
+
+	fd := testNewClient(fs)
+	for i := 0; i < 350; i++ {
+		if _, err := fd.CreateTicket(ctxbg, &freshdesk.TicketCreate{Email: "a@example.com", Subject: "test"}); err != nil {
+			t.Fatalf("ERROR: %v", err)
+		}
+	}
+
+	lto := &freshdesk.ListTicketsOption{PerPage: 1}
+
+	n := 0
+	if err := fd.IterTickets(ctxbg, lto, func(*freshdesk.Ticket) error { n++; return nil }); !errors.Is(err, fresh.ErrMaxPageExceeded) {
+		t.Fatalf("IterTickets() = %v, want %v", err, fresh.ErrMaxPageExceeded)
+	}
+	if n != 300 {
+		t.Errorf("IterTickets() = %d, want %d", n, 300)
+	}
+
+	ids := map[int64]bool{}
+	err := fd.IterTicketsSliced(ctxbg, lto, func(tk *freshdesk.Ticket) error {
+		if ids[tk.ID] {
+			t.Fatalf("duplicated ticket #%d", tk.ID)
+		}
+		ids[tk.ID] = true
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	if len(ids) != 350 {
+		t.Errorf("IterTicketsSliced() = %d, want %d", len(ids), 350)
+	}
+}
+
+func TestFilterTicketsSliced(t *testing.T) {
+	fs := fdtest.NewServer()
+	defer fs.Close()
+
+	day := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for d := 0; d < 4; d++ {
+		for i := 0; i < 110; i++ {
+			ts := day.AddDate(0, 0, d).Add(time.Duration(i) * time.Minute).Format(time.RFC3339)
+			fs.Store.Insert("tickets", fdtest.Record{"subject": "test", "priority": int64(d + 1), "created_at": ts, "updated_at": ts})
+		}
+	}
+
+	fd := testNewClient(fs)
+
+	fto := &freshdesk.FilterTicketsOption{Query: "priority:>1"}
+	if err := fd.IterFilterTickets(ctxbg, fto, func(*freshdesk.Ticket) error { return nil }); !errors.Is(err, fresh.ErrMaxPageExceeded) {
+		t.Fatalf("IterFilterTickets() = %v, want %v", err, fresh.ErrMaxPageExceeded)
+	}
+
+	// 330 tickets in [01-02, 01-04] exceed 10 pages, the range is split into [01-02, 01-03] and [01-04, 01-04]
+	ids := map[int64]bool{}
+	since, until := freshdesk.Date{Time: day.AddDate(0, 0, 1)}, freshdesk.Date{Time: day.AddDate(0, 0, 3)}
+	err := fd.IterFilterTicketsSliced(ctxbg, fto, since, until, func(tk *freshdesk.Ticket) error {
+		if ids[tk.ID] {
+			t.Fatalf("duplicated ticket #%d", tk.ID)
+		}
+		ids[tk.ID] = true
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	if len(ids) != 330 {
+		t.Errorf("IterFilterTicketsSliced() = %d, want %d", len(ids), 330)
+	}
+
+	// a single day can not be split any further
+	for i := 0; i < 300; i++ {
+		ts := day.Add(time.Duration(i) * time.Second).Format(time.RFC3339)
+		fs.Store.Insert("tickets", fdtest.Record{"subject": "test", "priority": int64(2), "created_at": ts, "updated_at": ts})
+	}
+	err = fd.IterFilterTicketsSliced(ctxbg, fto, freshdesk.Date{Time: day}, freshdesk.Date{Time: day}, func(*freshdesk.Ticket) error { return nil })
+	if !errors.Is(err, fresh.ErrMaxPageExceeded) {
+		t.Errorf("IterFilterTicketsSliced() = %v, want %v", err, fresh.ErrMaxPageExceeded)
+	}
+}
@@ -214,7 +214,7 @@ func TestFilterTickets(t *testing.T) {
 	}
 
 	ftp := &FilterTicketsOption{
-		Query: `"created_at:>'2023-10-01'"`,
+		Query: "created_at:>'2023-10-01'",
 	}
 
 	ts, total, err := fd.FilterTickets(ctxbg, ftp)
@@ -235,7 +235,7 @@ func TestIterFilterTickets(t *testing.T) {
 	}
 
 	ftp := &FilterTicketsOption{
-		Query: `"created_at:>'2023-10-01'"`,
+		Query: "created_at:>'2023-10-01'",
 	}
 
 	i := 0
@@ -248,3 +248,54 @@ func TestIterFilterTickets(t *testing.T) {
 		t.Fatalf("ERROR: %v", err)
 	}
 }
+
+func TestIterTicketsSliced(t *testing.T) {
+	fd := testNewFreshdesk(t)
+	if fd == nil {
+		return
+	}
+
+	ltp := &ListTicketsOption{UpdatedSince: Time{Time: time.Now().AddDate(0, -1, 0)}}
+
+	i := 0
+	err := fd.IterTicketsSliced(ctxbg, ltp, func(t *Ticket) error {
+		i++
+		tlog.Infof("%d: #%d [%s] %s", i, t.ID, t.UpdatedAt.String(), t.Subject)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+}
+
+func TestIterFilterTicketsSliced(t *testing.T) {
+	fd := testNewFreshdesk(t)
+	if fd == nil {
+		return
+	}
+
+	ftp := &FilterTicketsOption{
+		Query: "priority:1 OR priority:2",
+	}
+	since, _ := ParseDate("2023-10-01")
+
+	i := 0
+	err := fd.IterFilterTicketsSliced(ctxbg, ftp, since, Date{}, func(t *Ticket) error {
+		i++
+		tlog.Infof("%d: #%d [%s] %s", i, t.ID, t.CreatedAt.String(), t.Subject)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+}
+
+func TestFilterCreatedRange(t *testing.T) {
+	since := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	until := time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)
+
+	w := "(status:2) AND created_at:>'2024-01-01' AND created_at:<'2024-01-31'"
+	if a := filterCreatedRange("status:2", since, until); a != w {
+		t.Errorf("filterCreatedRange() = %q, want %q", a, w)
+	}
+}
@@ -1,6 +1,11 @@
 package freshdesk
 
-import "context"
+import (
+	"context"
+	"iter"
+
+	"github.com/askasoft/gofresh/fresh"
+)
 
 // ---------------------------------------------------
 // Time Entries
@@ -50,39 +55,27 @@ func (c *Client) CreateTimeEntry(ctx context.Context, tid int64, te *TimeEntryCr
 
 // List All Time Entries
 func (c *Client) ListTimeEntries(ctx context.Context, lteo *ListTimeEntriesOption) ([]*TimeEntry, bool, error) {
+	return hasNext(c.listTimeEntries(ctx, lteo))
+}
+
+func (c *Client) listTimeEntries(ctx context.Context, lo ListOption) ([]*TimeEntry, string, error) {
 	url := c.Endpoint("/time_entries")
 	tes := []*TimeEntry{}
-	next, err := c.DoList(ctx, url, lteo, &tes)
+	next, err := c.DoListLink(ctx, url, lo, &tes)
 	return tes, next, err
 }
 
 func (c *Client) IterTimeEntries(ctx context.Context, lteo *ListTimeEntriesOption, itef func(*TimeEntry) error) error {
-	if lteo == nil {
-		lteo = &ListTimeEntriesOption{}
-	}
-	if lteo.Page < 1 {
-		lteo.Page = 1
-	}
-	if lteo.PerPage < 1 {
-		lteo.PerPage = 100
-	}
+	return c.timeEntriesPaginator().Iter(ctx, lteo, itef)
+}
 
-	for {
-		tes, next, err := c.ListTimeEntries(ctx, lteo)
-		if err != nil {
-			return err
-		}
-		for _, te := range tes {
-			if err = itef(te); err != nil {
-				return err
-			}
-		}
-		if !next {
-			break
-		}
-		lteo.Page++
-	}
-	return nil
+// AllTimeEntries is like IterTimeEntries but returns an iterator, the lteo will not be modified.
+func (c *Client) AllTimeEntries(ctx context.Context, lteo *ListTimeEntriesOption) iter.Seq2[*TimeEntry, error] {
+	return c.timeEntriesPaginator().All(ctx, lteo)
+}
+
+func (c *Client) timeEntriesPaginator() *fresh.Paginator[*TimeEntry] {
+	return newPaginator(c.listTimeEntries)
 }
 
 // Update a Time Entry
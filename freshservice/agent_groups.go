@@ -1,6 +1,11 @@
 package freshservice
 
-import "context"
+import (
+	"context"
+	"iter"
+
+	"github.com/askasoft/gofresh/fresh"
+)
 
 // ---------------------------------------------------
 // Agent Group
@@ -24,39 +29,27 @@ func (c *Client) GetAgentGroup(ctx context.Context, id int64) (*AgentGroup, erro
 }
 
 func (c *Client) ListAgentGroups(ctx context.Context, lago *ListAgentGroupsOption) ([]*AgentGroup, bool, error) {
+	return hasNext(c.listAgentGroups(ctx, lago))
+}
+
+func (c *Client) listAgentGroups(ctx context.Context, lo ListOption) ([]*AgentGroup, string, error) {
 	url := c.Endpoint("/groups")
 	result := &agentGroupsResult{}
-	next, err := c.DoList(ctx, url, lago, result)
+	next, err := c.DoListLink(ctx, url, lo, result)
 	return result.Groups, next, err
 }
 
 func (c *Client) IterAgentGroups(ctx context.Context, lago *ListAgentGroupsOption, iagf func(*AgentGroup) error) error {
-	if lago == nil {
-		lago = &ListAgentRolesOption{}
-	}
-	if lago.Page < 1 {
-		lago.Page = 1
-	}
-	if lago.PerPage < 1 {
-		lago.PerPage = 100
-	}
+	return c.agentGroupsPaginator().Iter(ctx, lago, iagf)
+}
 
-	for {
-		ags, next, err := c.ListAgentGroups(ctx, lago)
-		if err != nil {
-			return err
-		}
-		for _, ag := range ags {
-			if err = iagf(ag); err != nil {
-				return err
-			}
-		}
-		if !next {
-			break
-		}
-		lago.Page++
-	}
-	return nil
+// AllAgentGroups is like IterAgentGroups but returns an iterator, the lago will not be modified.
+func (c *Client) AllAgentGroups(ctx context.Context, lago *ListAgentGroupsOption) iter.Seq2[*AgentGroup, error] {
+	return c.agentGroupsPaginator().All(ctx, lago)
+}
+
+func (c *Client) agentGroupsPaginator() *fresh.Paginator[*AgentGroup] {
+	return newPaginator(c.listAgentGroups)
 }
 
 func (c *Client) UpdateAgentGroup(ctx context.Context, id int64, ag *AgentGroupUpdate) (*AgentGroup, error) {
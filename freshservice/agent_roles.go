@@ -1,6 +1,11 @@
 package freshservice
 
-import "context"
+import (
+	"context"
+	"iter"
+
+	"github.com/askasoft/gofresh/fresh"
+)
 
 // ---------------------------------------------------
 // Agent Role
@@ -15,37 +20,25 @@ func (c *Client) GetAgentRole(ctx context.Context, id int64) (*AgentRole, error)
 }
 
 func (c *Client) ListAgentRoles(ctx context.Context, laro *ListAgentRolesOption) ([]*AgentRole, bool, error) {
+	return hasNext(c.listAgentRoles(ctx, laro))
+}
+
+func (c *Client) listAgentRoles(ctx context.Context, lo ListOption) ([]*AgentRole, string, error) {
 	url := c.Endpoint("/roles")
 	result := &agentRolesResult{}
-	next, err := c.DoList(ctx, url, laro, result)
+	next, err := c.DoListLink(ctx, url, lo, result)
 	return result.Roles, next, err
 }
 
 func (c *Client) IterAgentRoles(ctx context.Context, laro *ListAgentRolesOption, iarf func(*AgentRole) error) error {
-	if laro == nil {
-		laro = &ListAgentRolesOption{}
-	}
-	if laro.Page < 1 {
-		laro.Page = 1
-	}
-	if laro.PerPage < 1 {
-		laro.PerPage = 100
-	}
-
-	for {
-		ars, next, err := c.ListAgentRoles(ctx, laro)
-		if err != nil {
-			return err
-		}
-		for _, ar := range ars {
-			if err = iarf(ar); err != nil {
-				return err
-			}
-		}
-		if !next {
-			break
-		}
-		laro.Page++
-	}
-	return nil
+	return c.agentRolesPaginator().Iter(ctx, laro, iarf)
+}
+
+// AllAgentRoles is like IterAgentRoles but returns an iterator, the laro will not be modified.
+func (c *Client) AllAgentRoles(ctx context.Context, laro *ListAgentRolesOption) iter.Seq2[*AgentRole, error] {
+	return c.agentRolesPaginator().All(ctx, laro)
+}
+
+func (c *Client) agentRolesPaginator() *fresh.Paginator[*AgentRole] {
+	return newPaginator(c.listAgentRoles)
 }
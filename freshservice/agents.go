@@ -1,6 +1,11 @@
 package freshservice
 
-import "context"
+import (
+	"context"
+	"iter"
+
+	"github.com/askasoft/gofresh/fresh"
+)
 
 // ---------------------------------------------------
 // Agent
@@ -62,39 +67,27 @@ func (c *Client) GetAgent(ctx context.Context, id int64) (*Agent, error) {
 }
 
 func (c *Client) ListAgents(ctx context.Context, lao *ListAgentsOption) ([]*Agent, bool, error) {
+	return hasNext(c.listAgents(ctx, lao))
+}
+
+func (c *Client) listAgents(ctx context.Context, lo ListOption) ([]*Agent, string, error) {
 	url := c.Endpoint("/agents")
 	result := &agentsResult{}
-	next, err := c.DoList(ctx, url, lao, result)
+	next, err := c.DoListLink(ctx, url, lo, result)
 	return result.Agents, next, err
 }
 
 func (c *Client) IterAgents(ctx context.Context, lao *ListAgentsOption, iaf func(*Agent) error) error {
-	if lao == nil {
-		lao = &ListAgentsOption{}
-	}
-	if lao.Page < 1 {
-		lao.Page = 1
-	}
-	if lao.PerPage < 1 {
-		lao.PerPage = 100
-	}
+	return c.agentsPaginator().Iter(ctx, lao, iaf)
+}
 
-	for {
-		agents, next, err := c.ListAgents(ctx, lao)
-		if err != nil {
-			return err
-		}
-		for _, c := range agents {
-			if err = iaf(c); err != nil {
-				return err
-			}
-		}
-		if !next {
-			break
-		}
-		lao.Page++
-	}
-	return nil
+// AllAgents is like IterAgents but returns an iterator, the lao will not be modified.
+func (c *Client) AllAgents(ctx context.Context, lao *ListAgentsOption) iter.Seq2[*Agent, error] {
+	return c.agentsPaginator().All(ctx, lao)
+}
+
+func (c *Client) agentsPaginator() *fresh.Paginator[*Agent] {
+	return newPaginator(c.listAgents)
 }
 
 // FilterAgents Use Agent attributes to filter your list.
@@ -129,39 +122,27 @@ func (c *Client) IterAgents(ctx context.Context, lao *ListAgentsOption, iaf func
 // created_at	date	Date (YYYY-MM-DD) when the agent is created.
 // updated_at	date	Date (YYYY-MM-DD) when the agent is updated.
 func (c *Client) FilterAgents(ctx context.Context, fao *FilterAgentsOption) ([]*Agent, bool, error) {
+	return hasNext(c.filterAgents(ctx, fao))
+}
+
+func (c *Client) filterAgents(ctx context.Context, lo ListOption) ([]*Agent, string, error) {
 	url := c.Endpoint("/agents")
 	result := &agentsResult{}
-	next, err := c.DoList(ctx, url, fao, result)
+	next, err := c.DoListLink(ctx, url, lo, result)
 	return result.Agents, next, err
 }
 
 func (c *Client) IterFilterAgents(ctx context.Context, fao *FilterAgentsOption, iaf func(*Agent) error) error {
-	if fao == nil {
-		fao = &FilterAgentsOption{}
-	}
-	if fao.Page < 1 {
-		fao.Page = 1
-	}
-	if fao.PerPage < 1 {
-		fao.PerPage = 100
-	}
+	return c.filterAgentsPaginator().Iter(ctx, fao, iaf)
+}
 
-	for {
-		agents, next, err := c.FilterAgents(ctx, fao)
-		if err != nil {
-			return err
-		}
-		for _, c := range agents {
-			if err = iaf(c); err != nil {
-				return err
-			}
-		}
-		if !next {
-			break
-		}
-		fao.Page++
-	}
-	return nil
+// AllFilterAgents is like IterFilterAgents but returns an iterator, the fao will not be modified.
+func (c *Client) AllFilterAgents(ctx context.Context, fao *FilterAgentsOption) iter.Seq2[*Agent, error] {
+	return c.filterAgentsPaginator().All(ctx, fao)
+}
+
+func (c *Client) filterAgentsPaginator() *fresh.Paginator[*Agent] {
+	return newPaginator(c.filterAgents)
 }
 
 // Update an Agent
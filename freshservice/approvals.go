@@ -1,6 +1,11 @@
 package freshservice
 
-import "context"
+import (
+	"context"
+	"iter"
+
+	"github.com/askasoft/gofresh/fresh"
+)
 
 type ListApprovalsOption struct {
 	Parent      string
@@ -31,37 +36,25 @@ func (lao *ListApprovalsOption) Values() Values {
 }
 
 func (c *Client) ListApprovals(ctx context.Context, lao *ListApprovalsOption) ([]*Approval, bool, error) {
+	return hasNext(c.listApprovals(ctx, lao))
+}
+
+func (c *Client) listApprovals(ctx context.Context, lo ListOption) ([]*Approval, string, error) {
 	url := c.Endpoint("/approvals")
 	result := &approvalsResult{}
-	next, err := c.DoList(ctx, url, lao, result)
+	next, err := c.DoListLink(ctx, url, lo, result)
 	return result.Approvals, next, err
 }
 
 func (c *Client) IterApprovals(ctx context.Context, lao *ListApprovalsOption, iaf func(*Approval) error) error {
-	if lao == nil {
-		lao = &ListApprovalsOption{}
-	}
-	if lao.Page < 1 {
-		lao.Page = 1
-	}
-	if lao.PerPage < 1 {
-		lao.PerPage = 100
-	}
+	return c.approvalsPaginator().Iter(ctx, lao, iaf)
+}
+
+// AllApprovals is like IterApprovals but returns an iterator, the lao will not be modified.
+func (c *Client) AllApprovals(ctx context.Context, lao *ListApprovalsOption) iter.Seq2[*Approval, error] {
+	return c.approvalsPaginator().All(ctx, lao)
+}
 
-	for {
-		agents, next, err := c.ListApprovals(ctx, lao)
-		if err != nil {
-			return err
-		}
-		for _, c := range agents {
-			if err = iaf(c); err != nil {
-				return err
-			}
-		}
-		if !next {
-			break
-		}
-		lao.Page++
-	}
-	return nil
+func (c *Client) approvalsPaginator() *fresh.Paginator[*Approval] {
+	return newPaginator(c.listApprovals)
 }
@@ -0,0 +1,65 @@
+package freshservice
+
+type Department struct {
+	ID int64 `json:"id,omitempty"`
+
+	// Name of the department
+	Name string `json:"name,omitempty"`
+
+	// Description about the department
+	Description string `json:"description,omitempty"`
+
+	// Unique ID of the agent in charge of the department
+	HeadUserID int64 `json:"head_user_id,omitempty"`
+
+	// Unique ID of the prime user of the department
+	PrimeUserID int64 `json:"prime_user_id,omitempty"`
+
+	// Email domains associated with the department
+	Domains []string `json:"domains,omitempty"`
+
+	// Custom fields that are associated with departments
+	CustomFields map[string]any `json:"custom_fields,omitempty"`
+
+	CreatedAt Time `json:"created_at,omitzero"`
+
+	UpdatedAt Time `json:"updated_at,omitzero"`
+}
+
+func (d *Department) String() string {
+	return toString(d)
+}
+
+type departmentResult struct {
+	Department *Department `json:"department,omitempty"`
+}
+
+type departmentsResult struct {
+	Departments []*Department `json:"departments,omitempty"`
+}
+
+type DepartmentCreate struct {
+	// Name of the department
+	Name string `json:"name,omitempty"`
+
+	// Description about the department
+	Description string `json:"description,omitempty"`
+
+	// Unique ID of the agent in charge of the department
+	HeadUserID int64 `json:"head_user_id,omitempty"`
+
+	// Unique ID of the prime user of the department
+	PrimeUserID int64 `json:"prime_user_id,omitempty"`
+
+	// Email domains associated with the department
+	Domains []string `json:"domains,omitempty"`
+
+	// Custom fields that are associated with departments
+	CustomFields map[string]any `json:"custom_fields,omitempty"`
+}
+
+func (d *DepartmentCreate) String() string {
+	return toString(d)
+}
+
+type DepartmentUpdate = DepartmentCreate
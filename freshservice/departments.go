@@ -0,0 +1,67 @@
+package freshservice
+
+import (
+	"context"
+	"iter"
+
+	"github.com/askasoft/gofresh/fresh"
+)
+
+// ---------------------------------------------------
+// Department
+
+type ListDepartmentsOption = PageOption
+
+func (c *Client) CreateDepartment(ctx context.Context, department *DepartmentCreate) (*Department, error) {
+	url := c.Endpoint("/departments")
+	result := &departmentResult{}
+	if err := c.DoPost(ctx, url, department, result); err != nil {
+		return nil, err
+	}
+	return result.Department, nil
+}
+
+func (c *Client) GetDepartment(ctx context.Context, id int64) (*Department, error) {
+	url := c.Endpoint("/departments/%d", id)
+	result := &departmentResult{}
+	err := c.DoGet(ctx, url, result)
+	return result.Department, err
+}
+
+func (c *Client) ListDepartments(ctx context.Context, ldo *ListDepartmentsOption) ([]*Department, bool, error) {
+	return hasNext(c.listDepartments(ctx, ldo))
+}
+
+func (c *Client) listDepartments(ctx context.Context, lo ListOption) ([]*Department, string, error) {
+	url := c.Endpoint("/departments")
+	result := &departmentsResult{}
+	next, err := c.DoListLink(ctx, url, lo, result)
+	return result.Departments, next, err
+}
+
+func (c *Client) IterDepartments(ctx context.Context, ldo *ListDepartmentsOption, idf func(*Department) error) error {
+	return c.departmentsPaginator().Iter(ctx, ldo, idf)
+}
+
+// AllDepartments is like IterDepartments but returns an iterator, the ldo will not be modified.
+func (c *Client) AllDepartments(ctx context.Context, ldo *ListDepartmentsOption) iter.Seq2[*Department, error] {
+	return c.departmentsPaginator().All(ctx, ldo)
+}
+
+func (c *Client) departmentsPaginator() *fresh.Paginator[*Department] {
+	return newPaginator(c.listDepartments)
+}
+
+func (c *Client) UpdateDepartment(ctx context.Context, id int64, department *DepartmentUpdate) (*Department, error) {
+	url := c.Endpoint("/departments/%d", id)
+	result := &departmentResult{}
+	if err := c.DoPut(ctx, url, department, result); err != nil {
+		return nil, err
+	}
+	return result.Department, nil
+}
+
+func (c *Client) DeleteDepartment(ctx context.Context, id int64) error {
+	url := c.Endpoint("/departments/%d", id)
+	return c.DoDelete(ctx, url)
+}
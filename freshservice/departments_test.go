@@ -0,0 +1,48 @@
+package freshservice
+
+import (
+	"testing"
+)
+
+func TestDepartments(t *testing.T) {
+	fs := testNewFreshservice(t)
+	if fs == nil {
+		return
+	}
+
+	dc := &DepartmentCreate{
+		Name:        "ApiTestDepartment",
+		Description: "department for test",
+		Domains:     []string{"example.com"},
+	}
+
+	cd, err := fs.CreateDepartment(ctxbg, dc)
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	defer func() {
+		if err := fs.DeleteDepartment(ctxbg, cd.ID); err != nil {
+			t.Errorf("ERROR: %v", err)
+		}
+	}()
+
+	du := &DepartmentUpdate{Description: "department updated"}
+	ud, err := fs.UpdateDepartment(ctxbg, cd.ID, du)
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	if ud.Description != du.Description {
+		t.Errorf("Description = %q, want %q", ud.Description, du.Description)
+	}
+
+	found := false
+	for d, err := range fs.AllDepartments(ctxbg, nil) {
+		if err != nil {
+			t.Fatalf("ERROR: %v", err)
+		}
+		found = found || d.ID == cd.ID
+	}
+	if !found {
+		t.Errorf("department #%d is not listed", cd.ID)
+	}
+}
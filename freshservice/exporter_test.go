@@ -0,0 +1,85 @@
+package freshservice
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/askasoft/gofresh/fresh/export"
+	"github.com/askasoft/gofresh/freshservice/fstest"
+	"github.com/askasoft/pango/num"
+)
+
+func TestTicketExporter(t *testing.T) {
+	fs := fstest.NewServer()
+	defer fs.Close()
+
+	fsv := &Client{Domain: fs.Domain, APIKey: fs.APIKey, BaseURL: fs.URL}
+
+	tc := &TicketCreate{Email: "export@example.com", Subject: "export", Description: "<p>export description</p>"}
+	tc.AddAttachment("ticket.go", []byte("ticket"))
+	ticket, err := fsv.CreateTicket(ctxbg, tc)
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+
+	note := &Note{Body: "export note"}
+	note.AddAttachment("note.txt", []byte("note"))
+	cn, err := fsv.CreateNote(ctxbg, ticket.ID, note)
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+
+	dir := t.TempDir()
+	ex := fsv.NewTicketExporter(dir, export.FormatCSV, nil)
+	if err := ex.Export(ctxbg); err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+
+	bs, err := os.ReadFile(filepath.Join(dir, "tickets.csv"))
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	if lines := strings.Split(strings.TrimSpace(string(bs)), "\n"); len(lines) != 2 {
+		t.Fatalf("tickets.csv = %s", bs)
+	}
+
+	bs, err = os.ReadFile(filepath.Join(dir, "conversations.csv"))
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	if !strings.Contains(string(bs), "_note.txt") {
+		t.Errorf("conversations.csv = %s", bs)
+	}
+
+	files := map[string]string{
+		filepath.Join("attachments", num.Ltoa(ticket.ID), num.Ltoa(ticket.Attachments[0].ID)+"_ticket.go"):             "ticket",
+		filepath.Join("attachments", num.Ltoa(ticket.ID), num.Ltoa(cn.ID), num.Ltoa(cn.Attachments[0].ID)+"_note.txt"): "note",
+	}
+	for path, want := range files {
+		bs, err := os.ReadFile(filepath.Join(dir, path))
+		if err != nil {
+			t.Errorf("ERROR: %v", err)
+		} else if string(bs) != want {
+			t.Errorf("%s = %q, want %q", path, bs, want)
+		}
+	}
+
+	// the description is not listed by the list api
+	dir = t.TempDir()
+	if err := fsv.NewTicketExporter(dir, export.FormatJSONL, nil).Export(ctxbg); err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	if bs, err = os.ReadFile(filepath.Join(dir, "tickets.jsonl")); err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	et := &Ticket{}
+	if err := json.Unmarshal(bs, et); err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	if et.Description != ticket.Description || len(et.Attachments) != 1 {
+		t.Errorf("tickets.jsonl = %s", bs)
+	}
+}
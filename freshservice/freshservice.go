@@ -4,9 +4,11 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"io/fs"
 	"time"
 
 	"github.com/askasoft/gofresh/fresh"
+	"github.com/askasoft/gofresh/fresh/query"
 	"github.com/askasoft/pango/doc/jsonx"
 	"github.com/askasoft/pango/log"
 	"github.com/askasoft/pango/ret"
@@ -18,17 +20,28 @@ func toString(o any) string {
 
 type FieldError = fresh.FieldError
 type ResultError = fresh.ResultError
+type PageLimitError = fresh.PageLimitError
 type Date = fresh.Date
 type Time = fresh.Time
 type TimeSpent = fresh.TimeSpent
 type Attachment = fresh.Attachment
 type Attachments = fresh.Attachments
+type AttachmentsSizeError = fresh.AttachmentsSizeError
+type AttachmentFile = fresh.AttachmentFile
+type DownloadOption = fresh.DownloadOption
+type DownloadResult = fresh.DownloadResult
 type ListOption = fresh.ListOption
 type PageOption = fresh.PageOption
+type Page[T any] = fresh.Page[T]
+type ConcurrentOption = fresh.ConcurrentOption
 type File = fresh.File
 type Files = fresh.Files
 type WithFiles = fresh.WithFiles
 type Values = fresh.Values
+type RateLimit = fresh.RateLimit
+type RateLimiter = fresh.RateLimiter
+type ResponseInfo = fresh.ResponseInfo
+type ResponseCollector = fresh.ResponseCollector
 
 type OrderType string
 
@@ -37,6 +50,17 @@ const (
 	OrderDesc OrderType = "desc"
 )
 
+// The sentinel errors to classify a *ResultError by errors.Is(err, ErrXXX).
+var (
+	ErrNotFound            = fresh.ErrNotFound
+	ErrRateLimited         = fresh.ErrRateLimited
+	ErrUnauthorized        = fresh.ErrUnauthorized
+	ErrAccessDenied        = fresh.ErrAccessDenied
+	ErrFeatureNotSupported = fresh.ErrFeatureNotSupported
+	ErrDuplicate           = fresh.ErrDuplicate
+	ErrValidation          = fresh.ErrValidation
+)
+
 func AsResultError(err error) (*ResultError, bool) {
 	return fresh.AsResultError(err)
 }
@@ -61,17 +85,56 @@ func NewAttachment(file string, data ...[]byte) *Attachment {
 	return fresh.NewAttachment(file, data...)
 }
 
-// default retry on not canceled error or (status = 429 || (status >= 500 && status <= 599))
+func NewAttachmentFS(fsys fs.FS, path string) *Attachment {
+	return fresh.NewAttachmentFS(fsys, path)
+}
+
+func NewAttachmentReader(name string, r io.Reader) *Attachment {
+	return fresh.NewAttachmentReader(name, r)
+}
+
+// default retry on not canceled error or (status = 429 || (status >= 500 && status <= 599)),
+// the validation errors (ErrValidation) are never retried.
 func NewRetryer(retryAfter time.Duration, maxRetries int, logger log.Logger) *ret.Retryer {
 	return fresh.NewRetryer(retryAfter, maxRetries, logger)
 }
 
+// DomainRateLimiter returns the RateLimiter shared by all clients of the domain
+func DomainRateLimiter(domain string) *RateLimiter {
+	return fresh.DomainRateLimiter(domain)
+}
+
+// WithResponseCollector returns a copy of ctx which carries the ResponseCollector rc.
+// The api calls made with the returned context add their ResponseInfo to rc.
+func WithResponseCollector(ctx context.Context, rc *ResponseCollector) context.Context {
+	return fresh.WithResponseCollector(ctx, rc)
+}
+
 type FilterOption struct {
 	Query   string
 	Page    int
 	PerPage int
 }
 
+// NewFilterOption builds the query of the condition qc, returns an error if the query is invalid.
+func NewFilterOption(qc query.Cond) (*FilterOption, error) {
+	fo := &FilterOption{}
+	if err := fo.SetQuery(qc); err != nil {
+		return nil, err
+	}
+	return fo, nil
+}
+
+// SetQuery builds the query of the condition qc and sets it to fo.Query, returns an error if the query is invalid.
+func (fo *FilterOption) SetQuery(qc query.Cond) error {
+	q, err := query.Build(qc)
+	if err != nil {
+		return err
+	}
+	fo.Query = q
+	return nil
+}
+
 func (fo *FilterOption) IsNil() bool {
 	return fo == nil
 }
@@ -84,8 +147,30 @@ func (fo *FilterOption) Values() Values {
 	return q
 }
 
+func newPaginator[T any](lp fresh.ListPageFunc[T]) *fresh.Paginator[T] {
+	return &fresh.Paginator[T]{ListPage: lp, PerPage: 100}
+}
+
+// hasNext converts the next page URL of a list function to whether there is a next page.
+func hasNext[T any](items []T, next string, err error) ([]T, bool, error) {
+	return items, next != "", err
+}
+
+func newConcurrent[T any](cco *ConcurrentOption, maxPage int) *fresh.Concurrent[T] {
+	cc := &fresh.Concurrent[T]{MaxPage: maxPage}
+	if cco != nil {
+		cc.Workers = cco.Workers
+	}
+	return cc
+}
+
 type Client fresh.Client
 
+// SiteURL returns the base url of the site, which is the BaseURL or "https://" + Domain.
+func (c *Client) SiteURL() string {
+	return (*fresh.Client)(c).SiteURL()
+}
+
 func (c *Client) Endpoint(format string, a ...any) string {
 	return (*fresh.Client)(c).Endpoint(format, a...)
 }
@@ -98,6 +183,10 @@ func (c *Client) DoList(ctx context.Context, url string, lo ListOption, result a
 	return (*fresh.Client)(c).DoList(ctx, url, lo, result)
 }
 
+func (c *Client) DoListLink(ctx context.Context, url string, lo ListOption, result any) (string, error) {
+	return (*fresh.Client)(c).DoListLink(ctx, url, lo, result)
+}
+
 func (c *Client) DoPost(ctx context.Context, url string, source, result any) error {
 	return (*fresh.Client)(c).DoPost(ctx, url, source, result)
 }
@@ -122,6 +211,10 @@ func (c *Client) DoSaveFile(ctx context.Context, url string, path string) error
 	return (*fresh.Client)(c).DoSaveFile(ctx, url, path)
 }
 
+func (c *Client) DoDownloadFile(ctx context.Context, url, path string, do *DownloadOption) (*DownloadResult, error) {
+	return (*fresh.Client)(c).DoDownloadFile(ctx, url, path, do)
+}
+
 func (c *Client) DoCopyFileNoAuth(ctx context.Context, url string, w io.Writer) error {
 	return (*fresh.Client)(c).DoCopyFileNoAuth(ctx, url, w)
 }
@@ -134,6 +227,10 @@ func (c *Client) DoSaveFileNoAuth(ctx context.Context, url string, path string)
 	return (*fresh.Client)(c).DoSaveFileNoAuth(ctx, url, path)
 }
 
+func (c *Client) DoDownloadFileNoAuth(ctx context.Context, url, path string, do *DownloadOption) (*DownloadResult, error) {
+	return (*fresh.Client)(c).DoDownloadFileNoAuth(ctx, url, path, do)
+}
+
 func (c *Client) CopyAttachment(ctx context.Context, aid int64, w io.Writer) error {
 	url := c.Endpoint("/attachments/%d", aid)
 	return c.DoCopyFile(ctx, url, w)
@@ -157,22 +254,22 @@ func (c *Client) SaveAttachment(ctx context.Context, aid int64, path string) err
 
 // GetAgentTicketURL return a permlink for agent ticket URL
 func (c *Client) GetAgentTicketURL(tid int64) string {
-	return GetAgentTicketURL(c.Domain, tid)
+	return (*fresh.Client)(c).Permalink("/a/tickets/%d", tid)
 }
 
 // GetSolutionArticleURL return a permlink for solution article URL
 func (c *Client) GetSolutionArticleURL(aid int64) string {
-	return GetSolutionArticleURL(c.Domain, aid)
+	return (*fresh.Client)(c).Permalink("/support/solutions/articles/%d", aid)
 }
 
 // GetHelpdeskAttachmentURL return a permlink for helpdesk attachment/avator URL
 func (c *Client) GetHelpdeskAttachmentURL(aid int64) string {
-	return GetHelpdeskAttachmentURL(c.Domain, aid)
+	return (*fresh.Client)(c).Permalink("/helpdesk/attachments/%d", aid)
 }
 
 // GetServiceCatalogItemURL return a permlink for service catalog URL
 func (c *Client) GetServiceCatalogItemURL(displayID int64) string {
-	return GetServiceCatalogItemURL(c.Domain, displayID)
+	return (*fresh.Client)(c).Permalink("/support/catalog/items/%d", displayID)
 }
 
 // GetAgentTicketURL return a permlink for agent ticket URL
@@ -0,0 +1,214 @@
+// Package fshook provides a http.Handler which receives the webhooks of the Freshservice workflow automator
+// ("Trigger Webhook" node), dedupes them and routes them by the event type to the callbacks of *freshservice.Ticket.
+//
+// The json payload of the "Trigger Webhook" node is generated from the Payload struct by PayloadTemplate,
+// so the configured json and the decoded struct stay in sync.
+//
+// Example:
+//
+//	fmt.Println(fshook.PayloadTemplate(fshook.EventTicketCreated)) // paste it to the "Trigger Webhook" node
+//
+//	h := fshook.NewHandler(&webhook.Secret{Value: secret})
+//	h.Client = fsv // optional, to get the full ticket
+//	h.OnTicketCreated(func(ctx context.Context, p *fshook.Payload, t *freshservice.Ticket) error {
+//		...
+//	})
+//	http.Handle("/webhooks/freshservice", h)
+package fshook
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/askasoft/gofresh/fresh/webhook"
+	"github.com/askasoft/gofresh/freshservice"
+)
+
+// EventType the type of the webhook event, it is the "event" value of the payload configured in the workflow.
+type EventType string
+
+const (
+	EventTicketCreated EventType = "ticket_created"
+	EventTicketUpdated EventType = "ticket_updated"
+	EventNoteAdded     EventType = "note_added"
+
+	// EventAny registers a callback for all the events
+	EventAny EventType = webhook.EventAny
+)
+
+// Payload the webhook payload, the fields are tagged with the placeholders of the Freshservice workflow.
+type Payload struct {
+	Event          EventType `json:"event"`
+	TicketID       string    `json:"ticket_id" placeholder:"{{ticket.id_numeric}}"`
+	Subject        string    `json:"subject" placeholder:"{{ticket.subject}}"`
+	Status         string    `json:"status" placeholder:"{{ticket.status}}"`
+	Priority       string    `json:"priority" placeholder:"{{ticket.priority}}"`
+	Source         string    `json:"source" placeholder:"{{ticket.source}}"`
+	Type           string    `json:"type" placeholder:"{{ticket.ticket_type}}"`
+	Category       string    `json:"category" placeholder:"{{ticket.category}}"`
+	Tags           string    `json:"tags" placeholder:"{{ticket.tags}}"`
+	GroupName      string    `json:"group_name" placeholder:"{{ticket.group.name}}"`
+	AgentEmail     string    `json:"agent_email" placeholder:"{{ticket.agent.email}}"`
+	RequesterName  string    `json:"requester_name" placeholder:"{{ticket.requester.name}}"`
+	RequesterEmail string    `json:"requester_email" placeholder:"{{ticket.requester.email}}"`
+	Timestamp      string    `json:"timestamp" placeholder:"{{ticket.updated_at}}"`
+
+	// Fields all the flattened values of the payload, include the values not defined in the Payload.
+	Fields webhook.Fields `json:"-"`
+}
+
+// PayloadTemplate returns the json payload template of the event type for the "Trigger Webhook" node.
+func PayloadTemplate(et EventType) string {
+	s, err := webhook.Template(&Payload{Event: et})
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// ParsePayload decodes the webhook payload.
+func ParsePayload(data []byte) (*Payload, error) {
+	fs, err := webhook.DecodeFields(data)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &Payload{Fields: fs}
+	if err := fs.Decode(p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// ID returns the ticket id, the prefix like "#INC-" of the ticket id is ignored.
+func (p *Payload) ID() int64 {
+	return webhook.Fields{"id": p.TicketID}.Int64("id")
+}
+
+// Key returns the dedupe key of the payload (ticket id + timestamp of the event), or "" if the ticket id or the timestamp is empty.
+func (p *Payload) Key() string {
+	id := p.ID()
+	if id == 0 || p.Timestamp == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s:%d@%s", p.Event, id, p.Timestamp)
+}
+
+// Ticket returns a ticket filled with the values of the payload.
+func (p *Payload) Ticket() *freshservice.Ticket {
+	t := &freshservice.Ticket{
+		ID:       p.ID(),
+		Subject:  p.Subject,
+		Status:   freshservice.ParseTicketStatus(p.Status),
+		Priority: freshservice.ParseTicketPriority(p.Priority),
+		Source:   freshservice.ParseTicketSource(p.Source),
+		Type:     p.Type,
+		Category: p.Category,
+		Tags:     p.Fields.Strings("tags"),
+		Email:    p.RequesterEmail,
+	}
+
+	if ut, err := freshservice.ParseTime(p.Timestamp); err == nil {
+		t.UpdatedAt = ut
+	}
+	return t
+}
+
+func (p *Payload) String() string {
+	return fmt.Sprintf("%s #%s", p.Event, p.TicketID)
+}
+
+// Callback the callback of the webhook events
+type Callback func(ctx context.Context, p *Payload, t *freshservice.Ticket) error
+
+// Event the webhook event dispatched by the Handler: the payload and the ticket of the payload.
+type Event struct {
+	Payload *Payload
+	Ticket  *freshservice.Ticket
+}
+
+// Handler a http.Handler which receives the Freshservice workflow webhooks.
+// The callbacks are called synchronously in the order of registration, and a callback error is responded
+// as 500 Internal Server Error, so that the webhook can be retried.
+// The payloads are deduped by Payload.Key().
+type Handler struct {
+	webhook.Handler[*Event]
+
+	// Client the Freshservice client to get the ticket of the event, nil means Payload.Ticket() is used.
+	Client *freshservice.Client
+
+	// Include the include options of the GetTicket, e.g. freshservice.TicketIncludeRequester
+	Include []string
+}
+
+// NewHandler returns a Handler with the Verifier v and an in-memory Deduper of 24 hours.
+func NewHandler(v webhook.Verifier) *Handler {
+	h := &Handler{}
+	h.Verifier = v
+	h.Deduper = webhook.NewMemoryDeduper(webhook.DefaultDedupeTTL)
+	h.Events = (*events)(h)
+	return h
+}
+
+// On registers the callback of the event type, use EventAny to receive all the events.
+func (h *Handler) On(et EventType, cb Callback) {
+	h.Handler.On(string(et), func(ctx context.Context, e *Event) error {
+		return cb(ctx, e.Payload, e.Ticket)
+	})
+}
+
+// OnTicketCreated registers the callback of the EventTicketCreated.
+func (h *Handler) OnTicketCreated(cb Callback) {
+	h.On(EventTicketCreated, cb)
+}
+
+// OnTicketUpdated registers the callback of the EventTicketUpdated.
+func (h *Handler) OnTicketUpdated(cb Callback) {
+	h.On(EventTicketUpdated, cb)
+}
+
+// OnNoteAdded registers the callback of the EventNoteAdded.
+func (h *Handler) OnNoteAdded(cb Callback) {
+	h.On(EventNoteAdded, cb)
+}
+
+// Handle dedupes the payload, gets the ticket of the payload and calls the callbacks of the event.
+// A duplicated payload is ignored, and the dedupe key is released if the callbacks failed.
+func (h *Handler) Handle(ctx context.Context, p *Payload) error {
+	return h.Handler.Handle(ctx, &Event{Payload: p})
+}
+
+// events implements the webhook.Events of the Handler.
+type events Handler
+
+func (es *events) Decode(data []byte) (*Event, error) {
+	p, err := ParsePayload(data)
+	if err != nil {
+		return nil, err
+	}
+	if p.Event == "" {
+		return nil, fmt.Errorf("%w: missing event", webhook.ErrInvalidPayload)
+	}
+	return &Event{Payload: p}, nil
+}
+
+func (es *events) Type(e *Event) string {
+	return string(e.Payload.Event)
+}
+
+func (es *events) Key(e *Event) string {
+	return e.Payload.Key()
+}
+
+// Enrich gets the ticket of the payload if the Client is set, otherwise the Payload.Ticket() is used.
+func (es *events) Enrich(ctx context.Context, e *Event) error {
+	t := e.Payload.Ticket()
+	if es.Client != nil && t.ID != 0 {
+		var err error
+		if t, err = es.Client.GetTicket(ctx, t.ID, es.Include...); err != nil {
+			return err
+		}
+	}
+	e.Ticket = t
+	return nil
+}
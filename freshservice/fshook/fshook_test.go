@@ -0,0 +1,131 @@
+package fshook
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/askasoft/gofresh/fresh/webhook"
+	"github.com/askasoft/gofresh/freshservice"
+	"github.com/askasoft/gofresh/freshservice/fstest"
+)
+
+func renderTemplate(et EventType, values map[string]string) string {
+	s := PayloadTemplate(et)
+	for k, v := range values {
+		s = strings.ReplaceAll(s, "{{"+k+"}}", v)
+	}
+	return s
+}
+
+func TestPayloadTemplate(t *testing.T) {
+	s := PayloadTemplate(EventTicketCreated)
+	for _, w := range []string{`"event": "ticket_created"`, `"ticket_id": "{{ticket.id_numeric}}"`, `"timestamp": "{{ticket.updated_at}}"`} {
+		if !strings.Contains(s, w) {
+			t.Errorf("PayloadTemplate() = %s, want %s", s, w)
+		}
+	}
+	if strings.Contains(s, "Fields") {
+		t.Errorf("PayloadTemplate() = %s", s)
+	}
+
+	p, err := ParsePayload([]byte(renderTemplate(EventTicketCreated, map[string]string{
+		"ticket.id_numeric": "INC-12",
+		"ticket.subject":    "subject",
+		"ticket.status":     "Pending",
+		"ticket.priority":   "High",
+		"ticket.tags":       "a,b",
+		"ticket.updated_at": "2024-05-06T10:20:30Z",
+	})))
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+
+	if p.Event != EventTicketCreated || p.ID() != 12 || p.Key() != "ticket_created:12@2024-05-06T10:20:30Z" {
+		t.Errorf("ParsePayload() = %v, %d, %q", p, p.ID(), p.Key())
+	}
+
+	tk := p.Ticket()
+	if tk.ID != 12 || tk.Subject != "subject" || tk.Status != freshservice.TicketStatusPending || tk.Priority != freshservice.TicketPriorityHigh || len(tk.Tags) != 2 || tk.UpdatedAt.IsZero() {
+		t.Errorf("Ticket() = %v", tk)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	fs := fstest.NewServer()
+	defer fs.Close()
+
+	fsv := &freshservice.Client{Domain: fs.Domain, APIKey: fs.APIKey, BaseURL: fs.URL}
+	ticket, err := fsv.CreateTicket(context.Background(), &freshservice.TicketCreate{
+		Email:       "requester@example.com",
+		Subject:     "webhook",
+		Description: "webhook",
+	})
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+
+	h := NewHandler(webhook.APIKey("apikey"))
+	h.Client = fsv
+
+	var tickets []*freshservice.Ticket
+	h.OnTicketUpdated(func(ctx context.Context, p *Payload, t *freshservice.Ticket) error {
+		tickets = append(tickets, t)
+		return nil
+	})
+
+	fails := 1
+	h.OnNoteAdded(func(ctx context.Context, p *Payload, t *freshservice.Ticket) error {
+		if fails > 0 {
+			fails--
+			return errors.New("failed")
+		}
+		tickets = append(tickets, t)
+		return nil
+	})
+
+	values := map[string]string{"ticket.id_numeric": "#SR-" + strconv.FormatInt(ticket.ID, 10), "ticket.updated_at": "t1"}
+
+	updated := renderTemplate(EventTicketUpdated, values)
+	noted := renderTemplate(EventNoteAdded, values)
+
+	cs := []struct {
+		a bool
+		p string
+		w int
+		n int
+	}{
+		{false, updated, http.StatusUnauthorized, 0},
+		{true, `{"ticket_id":"1"}`, http.StatusBadRequest, 0},
+		{true, updated, http.StatusNoContent, 1},
+		{true, updated, http.StatusNoContent, 1}, // duplicated
+		{true, noted, http.StatusInternalServerError, 1},
+		{true, noted, http.StatusNoContent, 2}, // retried
+		{true, noted, http.StatusNoContent, 2}, // duplicated
+		{true, strings.ReplaceAll(updated, "t1", "t2"), http.StatusNoContent, 3},
+		{true, renderTemplate(EventTicketCreated, values), http.StatusNoContent, 3}, // no callbacks
+	}
+
+	for i, c := range cs {
+		req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(c.p))
+		if c.a {
+			req.SetBasicAuth("apikey", "X")
+		}
+
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != c.w || len(tickets) != c.n {
+			t.Errorf("[%d] ServeHTTP() = (%d, %d), want (%d, %d)", i, rec.Code, len(tickets), c.w, c.n)
+		}
+	}
+
+	for i, tk := range tickets {
+		if tk.ID != ticket.ID || tk.Subject != "webhook" {
+			t.Errorf("[%d] ticket = %v", i, tk)
+		}
+	}
+}
@@ -0,0 +1,193 @@
+package fsmigrate
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Kind the kind of the migrated records
+type Kind string
+
+const (
+	KindCompany      Kind = "companies"     // freshdesk company -> freshservice department
+	KindContact      Kind = "contacts"      // freshdesk contact -> freshservice requester
+	KindAgent        Kind = "agents"        // freshdesk agent -> freshservice agent
+	KindGroup        Kind = "groups"        // freshdesk group -> freshservice agent group
+	KindTicket       Kind = "tickets"       // freshdesk ticket -> freshservice ticket
+	KindConversation Kind = "conversations" // freshdesk conversation -> freshservice note
+	KindCategory     Kind = "categories"    // freshdesk solution category -> freshservice solution category
+	KindFolder       Kind = "folders"       // freshdesk solution folder -> freshservice solution folder
+	KindArticle      Kind = "articles"      // freshdesk solution article -> freshservice solution article
+)
+
+// Crosswalk the id mapping of the migrated records (freshdesk id -> freshservice id).
+// The records in the Crosswalk are not migrated again, so a migration can be resumed with the same Crosswalk file.
+// The file is a JSONL file of the records, Save appends the records set since the last Save to the file,
+// so the cost of a Save does not grow with the size of the Crosswalk.
+// It is safe for concurrent use.
+type Crosswalk struct {
+	// Path the crosswalk file, empty means the crosswalk is not saved.
+	Path string
+
+	mu      sync.Mutex
+	ids     map[Kind]map[int64]int64
+	pending []crosswalkRecord // the records to append by Save
+}
+
+// crosswalkRecord a line of the crosswalk file
+type crosswalkRecord struct {
+	Kind Kind  `json:"kind"`
+	FD   int64 `json:"fd"`
+	FS   int64 `json:"fs"`
+}
+
+// NewCrosswalk returns an empty in-memory Crosswalk.
+func NewCrosswalk() *Crosswalk {
+	return &Crosswalk{ids: map[Kind]map[int64]int64{}}
+}
+
+// LoadCrosswalk loads the Crosswalk from the JSONL file of the path, an empty Crosswalk is returned if the file does not exist.
+// The later records override the earlier ones. An incomplete last line (a Save interrupted by a crash) is truncated,
+// so that the next Save appends to a valid file.
+func LoadCrosswalk(path string) (*Crosswalk, error) {
+	cw := NewCrosswalk()
+	cw.Path = path
+
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return cw, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var size int64 // the size of the complete lines
+
+	br := bufio.NewReader(f)
+	for n := 1; ; n++ {
+		line, err := br.ReadBytes('\n')
+		if err != nil {
+			if !errors.Is(err, io.EOF) {
+				return nil, err
+			}
+			if len(line) > 0 {
+				if err := os.Truncate(path, size); err != nil {
+					return nil, err
+				}
+			}
+			return cw, nil
+		}
+		size += int64(len(line))
+
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+
+		var r crosswalkRecord
+		if err := json.Unmarshal(line, &r); err != nil {
+			return nil, fmt.Errorf("fsmigrate: %s:%d: %w", path, n, err)
+		}
+		cw.set(r)
+	}
+}
+
+// Get returns the freshservice id of the freshdesk record.
+func (cw *Crosswalk) Get(kind Kind, fdid int64) (int64, bool) {
+	cw.mu.Lock()
+	defer cw.mu.Unlock()
+
+	fsid, ok := cw.ids[kind][fdid]
+	return fsid, ok
+}
+
+// Lookup returns the freshservice id of the freshdesk record, 0 if not found.
+func (cw *Crosswalk) Lookup(kind Kind, fdid int64) int64 {
+	fsid, _ := cw.Get(kind, fdid)
+	return fsid
+}
+
+// Set sets the freshservice id of the freshdesk record, the record is appended to the file by the next Save.
+func (cw *Crosswalk) Set(kind Kind, fdid, fsid int64) {
+	cw.mu.Lock()
+	defer cw.mu.Unlock()
+
+	r := crosswalkRecord{Kind: kind, FD: fdid, FS: fsid}
+	cw.set(r)
+	if cw.Path != "" {
+		cw.pending = append(cw.pending, r)
+	}
+}
+
+func (cw *Crosswalk) set(r crosswalkRecord) {
+	if cw.ids == nil {
+		cw.ids = map[Kind]map[int64]int64{}
+	}
+
+	m, ok := cw.ids[r.Kind]
+	if !ok {
+		m = map[int64]int64{}
+		cw.ids[r.Kind] = m
+	}
+	m[r.FD] = r.FS
+}
+
+// Len returns the count of the records of the kind.
+func (cw *Crosswalk) Len(kind Kind) int {
+	cw.mu.Lock()
+	defer cw.mu.Unlock()
+
+	return len(cw.ids[kind])
+}
+
+// Save appends the records set since the last Save to the Path, does nothing if the Path is empty.
+// The records are kept to be appended by the next Save if the write fails.
+func (cw *Crosswalk) Save() error {
+	if cw.Path == "" {
+		return nil
+	}
+
+	cw.mu.Lock()
+	defer cw.mu.Unlock()
+
+	if len(cw.pending) == 0 {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	je := json.NewEncoder(&buf)
+	for _, r := range cw.pending {
+		if err := je.Encode(r); err != nil {
+			return err
+		}
+	}
+
+	if dir := filepath.Dir(cw.Path); dir != "" {
+		if err := os.MkdirAll(dir, 0o770); err != nil {
+			return err
+		}
+	}
+
+	f, err := os.OpenFile(cw.Path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o660)
+	if err != nil {
+		return err
+	}
+
+	if _, err = f.Write(buf.Bytes()); err != nil {
+		f.Close()
+		return err
+	}
+	if err = f.Close(); err != nil {
+		return err
+	}
+
+	cw.pending = cw.pending[:0]
+	return nil
+}
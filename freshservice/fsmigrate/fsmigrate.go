@@ -0,0 +1,432 @@
+// Package fsmigrate migrates the data of a Freshdesk account to a Freshservice account.
+//
+// The records are migrated in the dependency order:
+//
+//	freshdesk company      -> freshservice department
+//	freshdesk group        -> freshservice agent group
+//	freshdesk agent        -> freshservice agent (mapped by email, the agents are not created)
+//	freshdesk contact      -> freshservice requester
+//	freshdesk solutions    -> freshservice solutions (the sub folders are flattened)
+//	freshdesk ticket       -> freshservice ticket
+//	freshdesk conversation -> freshservice note (the replies are not sent again)
+//
+// The existing departments/agent groups (same name) and requesters (same email) are mapped instead of created.
+// The attachments are downloaded from Freshdesk to temporary files and uploaded to Freshservice.
+// The id mapping is recorded to the Crosswalk, which is appended to its JSONL file after each created record,
+// so an interrupted migration can be resumed with the same Crosswalk file.
+//
+// Example:
+//
+//	cw, err := fsmigrate.LoadCrosswalk("crosswalk.jsonl")
+//	m := fsmigrate.NewMigrator(fd, fs, cw)
+//	m.Mapping.TicketFields = map[string]string{"cf_region": "region"}
+//	err = m.Migrate(ctx, &freshdesk.ListTicketsOption{UpdatedSince: since})
+package fsmigrate
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/askasoft/gofresh/freshdesk"
+	"github.com/askasoft/gofresh/freshservice"
+	"github.com/askasoft/pango/log"
+)
+
+// Stat the migration statistics of a Kind
+type Stat struct {
+	Created  int // created (or to be created in dry run mode)
+	Existing int // mapped to the existing freshservice records
+	Skipped  int // already migrated (in the Crosswalk) or not mappable
+}
+
+// Migrator migrates the data from Freshdesk (FD) to Freshservice (FS).
+type Migrator struct {
+	FD *freshdesk.Client
+	FS *freshservice.Client
+
+	// Mapping the mapping tables, default is DefaultMapping().
+	Mapping *Mapping
+
+	// Crosswalk the id mapping, default is an in-memory Crosswalk.
+	Crosswalk *Crosswalk
+
+	// DryRun if true, the records are read from Freshdesk and mapped, but nothing is written to Freshservice,
+	// the Crosswalk is not modified, and the attachments are not downloaded.
+	DryRun bool
+
+	// TempDir the directory of the temporary attachment files, default is os.TempDir().
+	// The attachments are downloaded to the temporary files and streamed to Freshservice, instead of being read into memory.
+	TempDir string
+
+	// WorkspaceID the workspace of the created tickets and solution categories, 0 means the primary workspace.
+	WorkspaceID int64
+
+	// TicketHook is called to customize the ticket before it is created.
+	TicketHook func(ctx context.Context, src *freshdesk.Ticket, dst *freshservice.TicketCreate) error
+
+	// Logger the logger of the progress
+	Logger log.Logger
+
+	// Stats the migration statistics
+	Stats map[Kind]*Stat
+}
+
+// NewMigrator returns a Migrator with the DefaultMapping.
+// If cw is nil, an in-memory Crosswalk is used.
+func NewMigrator(fd *freshdesk.Client, fs *freshservice.Client, cw *Crosswalk) *Migrator {
+	if cw == nil {
+		cw = NewCrosswalk()
+	}
+	return &Migrator{
+		FD:        fd,
+		FS:        fs,
+		Mapping:   DefaultMapping(),
+		Crosswalk: cw,
+	}
+}
+
+// Migrate migrates all the data in the dependency order, the tickets are listed by lto.
+func (m *Migrator) Migrate(ctx context.Context, lto *freshdesk.ListTicketsOption) error {
+	steps := []func(context.Context) error{
+		m.MigrateCompanies,
+		m.MigrateGroups,
+		m.MigrateAgents,
+		m.MigrateContacts,
+		m.MigrateSolutions,
+	}
+	for _, step := range steps {
+		if err := step(ctx); err != nil {
+			return err
+		}
+	}
+	return m.MigrateTickets(ctx, lto)
+}
+
+// Stat returns the Stat of the kind.
+func (m *Migrator) Stat(kind Kind) *Stat {
+	if m.Stats == nil {
+		m.Stats = map[Kind]*Stat{}
+	}
+
+	st, ok := m.Stats[kind]
+	if !ok {
+		st = &Stat{}
+		m.Stats[kind] = st
+	}
+	return st
+}
+
+func (m *Migrator) mapping() *Mapping {
+	if m.Mapping == nil {
+		m.Mapping = DefaultMapping()
+	}
+	return m.Mapping
+}
+
+func (m *Migrator) crosswalk() *Crosswalk {
+	if m.Crosswalk == nil {
+		m.Crosswalk = NewCrosswalk()
+	}
+	return m.Crosswalk
+}
+
+func (m *Migrator) logf(format string, args ...any) {
+	if m.Logger != nil {
+		if m.DryRun {
+			format = "[dry-run] " + format
+		}
+		m.Logger.Infof("fsmigrate: "+format, args...)
+	}
+}
+
+// migrated reports whether the record is already migrated, and counts it as skipped.
+func (m *Migrator) migrated(kind Kind, fdid int64) bool {
+	if _, ok := m.crosswalk().Get(kind, fdid); ok {
+		m.Stat(kind).Skipped++
+		return true
+	}
+	return false
+}
+
+// created records the created record to the Crosswalk and appends it to the Crosswalk file.
+func (m *Migrator) created(kind Kind, fdid, fsid int64) error {
+	m.Stat(kind).Created++
+	m.logf("%s #%d -> #%d", kind, fdid, fsid)
+
+	if m.DryRun {
+		return nil
+	}
+
+	cw := m.crosswalk()
+	cw.Set(kind, fdid, fsid)
+	return cw.Save()
+}
+
+// existing records the existing record to the Crosswalk and appends it to the Crosswalk file.
+func (m *Migrator) existing(kind Kind, fdid, fsid int64) error {
+	m.Stat(kind).Existing++
+	m.logf("%s #%d == #%d", kind, fdid, fsid)
+
+	if m.DryRun {
+		return nil
+	}
+
+	cw := m.crosswalk()
+	cw.Set(kind, fdid, fsid)
+	return cw.Save()
+}
+
+// MigrateCompanies migrates the companies to the departments.
+func (m *Migrator) MigrateCompanies(ctx context.Context) error {
+	names := map[string]int64{}
+	for d, err := range m.FS.AllDepartments(ctx, nil) {
+		if err != nil {
+			return err
+		}
+		names[d.Name] = d.ID
+	}
+
+	for c, err := range m.FD.AllCompanies(ctx, nil) {
+		if err != nil {
+			return err
+		}
+		if m.migrated(KindCompany, c.ID) {
+			continue
+		}
+
+		if id, ok := names[c.Name]; ok {
+			if err := m.existing(KindCompany, c.ID, id); err != nil {
+				return err
+			}
+			continue
+		}
+
+		dc := &freshservice.DepartmentCreate{
+			Name:         c.Name,
+			Description:  c.Description,
+			Domains:      c.Domains,
+			CustomFields: mapFields(m.mapping().CompanyFields, c.CustomFields),
+		}
+
+		var id int64
+		if !m.DryRun {
+			d, err := m.FS.CreateDepartment(ctx, dc)
+			if err != nil {
+				return fmt.Errorf("fsmigrate: company #%d: %w", c.ID, err)
+			}
+			id = d.ID
+		}
+		if err := m.created(KindCompany, c.ID, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MigrateGroups migrates the groups to the agent groups, the group members are mapped by the agents Crosswalk.
+// The members are not migrated if the groups are migrated before the agents.
+func (m *Migrator) MigrateGroups(ctx context.Context) error {
+	names := map[string]int64{}
+	for g, err := range m.FS.AllAgentGroups(ctx, nil) {
+		if err != nil {
+			return err
+		}
+		names[g.Name] = g.ID
+	}
+
+	for g, err := range m.FD.AllGroups(ctx, nil) {
+		if err != nil {
+			return err
+		}
+		if m.migrated(KindGroup, g.ID) {
+			continue
+		}
+
+		if id, ok := names[g.Name]; ok {
+			if err := m.existing(KindGroup, g.ID, id); err != nil {
+				return err
+			}
+			continue
+		}
+
+		agc := &freshservice.AgentGroupCreate{
+			WorkspaceID: m.WorkspaceID,
+			Name:        g.Name,
+			Description: g.Description,
+		}
+		for _, aid := range g.AgentIDs {
+			if id := m.crosswalk().Lookup(KindAgent, aid); id != 0 {
+				agc.Members = append(agc.Members, id)
+			}
+		}
+
+		var id int64
+		if !m.DryRun {
+			ag, err := m.FS.CreateAgentGroup(ctx, agc)
+			if err != nil {
+				return fmt.Errorf("fsmigrate: group #%d: %w", g.ID, err)
+			}
+			id = ag.ID
+		}
+		if err := m.created(KindGroup, g.ID, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MigrateAgents maps the agents to the freshservice agents with the same email.
+// The agents are not created, the unmatched agents are skipped.
+func (m *Migrator) MigrateAgents(ctx context.Context) error {
+	for a, err := range m.FD.AllAgents(ctx, nil) {
+		if err != nil {
+			return err
+		}
+		if m.migrated(KindAgent, a.ID) {
+			continue
+		}
+		if a.Contact == nil || a.Contact.Email == "" {
+			m.Stat(KindAgent).Skipped++
+			continue
+		}
+
+		id, err := m.findAgent(ctx, a.Contact.Email)
+		if err != nil {
+			return err
+		}
+		if id == 0 {
+			m.Stat(KindAgent).Skipped++
+			m.logf("agents #%d <%s> not found", a.ID, a.Contact.Email)
+			continue
+		}
+		if err := m.existing(KindAgent, a.ID, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *Migrator) findAgent(ctx context.Context, email string) (int64, error) {
+	for a, err := range m.FS.AllAgents(ctx, &freshservice.ListAgentsOption{Email: email}) {
+		if err != nil {
+			return 0, err
+		}
+		return a.ID, nil
+	}
+	return 0, nil
+}
+
+func (m *Migrator) findRequester(ctx context.Context, email string) (int64, error) {
+	for r, err := range m.FS.AllRequesters(ctx, &freshservice.ListRequestersOption{Email: email}) {
+		if err != nil {
+			return 0, err
+		}
+		return r.ID, nil
+	}
+	return 0, nil
+}
+
+// MigrateContacts migrates the contacts to the requesters, the contacts without email/phone are skipped.
+func (m *Migrator) MigrateContacts(ctx context.Context) error {
+	for c, err := range m.FD.AllContacts(ctx, nil) {
+		if err != nil {
+			return err
+		}
+		if m.migrated(KindContact, c.ID) {
+			continue
+		}
+		if c.Email == "" && c.Phone == "" && c.Mobile == "" {
+			m.Stat(KindContact).Skipped++
+			continue
+		}
+
+		if c.Email != "" {
+			id, err := m.findRequester(ctx, c.Email)
+			if err != nil {
+				return err
+			}
+			if id != 0 {
+				if err := m.existing(KindContact, c.ID, id); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+
+		rc := &freshservice.RequesterCreate{
+			FirstName:         c.Name,
+			JobTitle:          c.JobTitle,
+			PrimaryEmail:      c.Email,
+			SecondaryEmails:   c.OtherEmails,
+			WorkPhoneNumber:   c.Phone,
+			MobilePhoneNumber: c.Mobile,
+			Address:           c.Address,
+			Language:          c.Language,
+			CustomFields:      mapFields(m.mapping().ContactFields, c.CustomFields),
+		}
+		if did := m.crosswalk().Lookup(KindCompany, c.CompanyID); did != 0 {
+			rc.DepartmentIDs = []int64{did}
+		}
+
+		var id int64
+		if !m.DryRun {
+			r, err := m.FS.CreateRequester(ctx, rc)
+			if err != nil {
+				return fmt.Errorf("fsmigrate: contact #%d: %w", c.ID, err)
+			}
+			id = r.ID
+		}
+		if err := m.created(KindContact, c.ID, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readAttachments downloads the freshdesk attachments to a temporary directory,
+// returns the freshservice attachments to upload (read from the files when the request is sent),
+// and a cleanup function to remove the temporary directory after the upload.
+// It returns nil attachments in dry run mode.
+func (m *Migrator) readAttachments(ctx context.Context, as []*freshdesk.Attachment) ([]*freshservice.Attachment, func(), error) {
+	if m.DryRun || len(as) == 0 {
+		return nil, func() {}, nil
+	}
+
+	dir, err := os.MkdirTemp(m.TempDir, "fsmigrate-")
+	if err != nil {
+		return nil, nil, err
+	}
+	cleanup := func() {
+		os.RemoveAll(dir)
+	}
+
+	fsys := os.DirFS(dir)
+
+	var fas []*freshservice.Attachment
+	for i, a := range as {
+		// the attachments are saved to the sub directories by index, so that the same names do not conflict,
+		// the uploaded file name is the base name of the path.
+		name := path.Join(strconv.Itoa(i), attachmentName(a.Name))
+		if err := m.FD.DoSaveFileNoAuth(ctx, a.AttachmentURL, filepath.Join(dir, filepath.FromSlash(name))); err != nil {
+			cleanup()
+			return nil, nil, fmt.Errorf("attachment #%d: %w", a.ID, err)
+		}
+		fas = append(fas, freshservice.NewAttachmentFS(fsys, name))
+	}
+	return fas, cleanup, nil
+}
+
+// attachmentName returns a file name of the attachment name which is valid in a fs.FS.
+func attachmentName(name string) string {
+	name = path.Base(strings.ReplaceAll(name, "\\", "/"))
+	if !fs.ValidPath(name) || name == "." {
+		return "attachment"
+	}
+	return name
+}
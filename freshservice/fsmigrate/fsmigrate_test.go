@@ -0,0 +1,295 @@
+package fsmigrate
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/askasoft/gofresh/freshdesk"
+	"github.com/askasoft/gofresh/freshdesk/fdtest"
+	"github.com/askasoft/gofresh/freshservice"
+	"github.com/askasoft/gofresh/freshservice/fstest"
+)
+
+var ctxbg = context.Background()
+
+type testEnv struct {
+	fd *freshdesk.Client
+	fs *freshservice.Client
+
+	company *freshdesk.Company
+	contact *freshdesk.Contact
+	agent   *freshdesk.Agent
+	ticket  *freshdesk.Ticket
+	note    *freshdesk.Note
+	article *freshdesk.Article
+}
+
+func testNewEnv(t *testing.T) *testEnv {
+	fds := fdtest.NewServer()
+	t.Cleanup(fds.Close)
+
+	fss := fstest.NewServer()
+	t.Cleanup(fss.Close)
+
+	te := &testEnv{
+		fd: &freshdesk.Client{Domain: fds.Domain, APIKey: fds.APIKey, BaseURL: fds.URL},
+		fs: &freshservice.Client{Domain: fss.Domain, APIKey: fss.APIKey, BaseURL: fss.URL},
+	}
+
+	var err error
+	fatal := func() {
+		if err != nil {
+			t.Helper()
+			t.Fatalf("ERROR: %v", err)
+		}
+	}
+
+	te.company, err = te.fd.CreateCompany(ctxbg, &freshdesk.CompanyCreate{Name: "Acme", Domains: []string{"acme.example.com"}})
+	fatal()
+
+	_, err = te.fd.CreateGroup(ctxbg, &freshdesk.GroupCreate{Name: "Level 1"})
+	fatal()
+
+	te.agent, err = te.fd.CreateAgent(ctxbg, &freshdesk.AgentCreate{Name: "Agent", Email: "agent@example.com"})
+	fatal()
+
+	_, err = te.fs.CreateAgent(ctxbg, &freshservice.AgentCreate{FirstName: "Agent", Email: "agent@example.com"})
+	fatal()
+
+	te.contact, err = te.fd.CreateContact(ctxbg, &freshdesk.ContactCreate{Name: "Alice", Email: "alice@example.com", CompanyID: te.company.ID})
+	fatal()
+
+	tc := &freshdesk.TicketCreate{
+		RequesterID:  te.contact.ID,
+		ResponderID:  te.agent.ID,
+		Subject:      "migrate",
+		Description:  "<p>migrate</p>",
+		Status:       freshdesk.TicketStatusPending,
+		Priority:     freshdesk.TicketPriorityHigh,
+		Source:       freshdesk.TicketSourceOutboundEmail,
+		CompanyID:    te.company.ID,
+		CustomFields: map[string]any{"cf_region": "east", "cf_other": "x"},
+	}
+	tc.AddAttachment("ticket.txt", []byte("ticket"))
+	te.ticket, err = te.fd.CreateTicket(ctxbg, tc)
+	fatal()
+
+	nc := &freshdesk.NoteCreate{Body: "private note", Private: true, UserID: te.agent.ID}
+	nc.AddAttachment("note.txt", []byte("note"))
+	te.note, err = te.fd.CreateNote(ctxbg, te.ticket.ID, nc)
+	fatal()
+
+	cat, err := te.fd.CreateCategory(ctxbg, &freshdesk.CategoryCreate{Name: "FAQ"})
+	fatal()
+
+	folder, err := te.fd.CreateFolder(ctxbg, cat.ID, &freshdesk.FolderCreate{Name: "General", Visibility: freshdesk.FolderVisibilitySelectedCompanies, CompanyIDs: []int64{te.company.ID}})
+	fatal()
+
+	te.article, err = te.fd.CreateArticle(ctxbg, folder.ID, &freshdesk.ArticleCreate{Title: "How to", Description: "<p>how to</p>", Status: freshdesk.ArticleStatusPublished})
+	fatal()
+
+	return te
+}
+
+func TestMigrateDryRun(t *testing.T) {
+	te := testNewEnv(t)
+
+	m := NewMigrator(te.fd, te.fs, nil)
+	m.DryRun = true
+	if err := m.Migrate(ctxbg, nil); err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+
+	for _, k := range []Kind{KindCompany, KindGroup, KindContact, KindTicket, KindConversation, KindCategory, KindFolder, KindArticle} {
+		if st := m.Stat(k); st.Created != 1 {
+			t.Errorf("%s: created = %d, want 1", k, st.Created)
+		}
+		if n := m.Crosswalk.Len(k); n != 0 {
+			t.Errorf("%s: crosswalk = %d, want 0", k, n)
+		}
+	}
+
+	for _, err := range te.fs.AllTickets(ctxbg, nil) {
+		if err != nil {
+			t.Fatalf("ERROR: %v", err)
+		}
+		t.Fatal("dry run should not create tickets")
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	te := testNewEnv(t)
+
+	path := filepath.Join(t.TempDir(), "crosswalk.jsonl")
+	cw, err := LoadCrosswalk(path)
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+
+	m := NewMigrator(te.fd, te.fs, cw)
+	m.Mapping.TicketFields = map[string]string{"cf_region": "region"}
+	m.TempDir = t.TempDir()
+	if err := m.Migrate(ctxbg, nil); err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+
+	// the temporary attachment files are removed
+	if des, err := os.ReadDir(m.TempDir); err != nil || len(des) != 0 {
+		t.Errorf("temp dir = %v, %v", des, err)
+	}
+
+	// reload the crosswalk file
+	if cw, err = LoadCrosswalk(path); err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+
+	tid, ok := cw.Get(KindTicket, te.ticket.ID)
+	if !ok {
+		t.Fatalf("ticket #%d is not in the crosswalk", te.ticket.ID)
+	}
+
+	ft, err := te.fs.GetTicket(ctxbg, tid)
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	if ft.Status != freshservice.TicketStatusPending || ft.Priority != freshservice.TicketPriorityHigh || ft.Source != freshservice.TicketSourceEmail {
+		t.Errorf("status/priority/source = %d/%d/%d", ft.Status, ft.Priority, ft.Source)
+	}
+	if ft.RequesterID != cw.Lookup(KindContact, te.contact.ID) || ft.RequesterID == 0 {
+		t.Errorf("requester_id = %d", ft.RequesterID)
+	}
+	if ft.ResponderID != cw.Lookup(KindAgent, te.agent.ID) || ft.ResponderID == 0 {
+		t.Errorf("responder_id = %d", ft.ResponderID)
+	}
+	if ft.DepartmentID != cw.Lookup(KindCompany, te.company.ID) || ft.DepartmentID == 0 {
+		t.Errorf("department_id = %d", ft.DepartmentID)
+	}
+	if ft.Subject != te.ticket.Subject || ft.Description != te.ticket.Description {
+		t.Errorf("subject/description = %q/%q", ft.Subject, ft.Description)
+	}
+	if len(ft.CustomFields) != 1 || ft.CustomFields["region"] != "east" {
+		t.Errorf("custom_fields = %v", ft.CustomFields)
+	}
+	if len(ft.Attachments) != 1 {
+		t.Fatalf("attachments = %d, want 1", len(ft.Attachments))
+	}
+	if ft.Attachments[0].Name != "ticket.txt" {
+		t.Errorf("attachment name = %q, want %q", ft.Attachments[0].Name, "ticket.txt")
+	}
+	if bs, err := te.fs.ReadAttachment(ctxbg, ft.Attachments[0].ID); err != nil || string(bs) != "ticket" {
+		t.Errorf("attachment = %q, %v", bs, err)
+	}
+
+	var notes []*freshservice.Conversation
+	for c, err := range te.fs.AllTicketConversations(ctxbg, tid, nil) {
+		if err != nil {
+			t.Fatalf("ERROR: %v", err)
+		}
+		notes = append(notes, c)
+	}
+	if len(notes) != 1 || !notes[0].Private || len(notes[0].Attachments) != 1 {
+		t.Fatalf("notes = %v", notes)
+	}
+	if nid := cw.Lookup(KindConversation, te.note.ID); nid != notes[0].ID {
+		t.Errorf("crosswalk note = %d, want %d", nid, notes[0].ID)
+	}
+
+	fa, err := te.fs.GetArticle(ctxbg, cw.Lookup(KindArticle, te.article.ID))
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	if fa.Title != te.article.Title || fa.Status != freshservice.ArticleStatusPublished {
+		t.Errorf("article = %v", fa)
+	}
+
+	ff, err := te.fs.GetFolder(ctxbg, fa.FolderID)
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	if ff.Visibility != freshservice.FolderVisibilityDepartments {
+		t.Errorf("folder visibility = %d", ff.Visibility)
+	}
+
+	// resume: nothing is migrated again
+	m = NewMigrator(te.fd, te.fs, cw)
+	if err := m.Migrate(ctxbg, nil); err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	for k, st := range m.Stats {
+		if st.Created != 0 {
+			t.Errorf("%s: created = %d, want 0", k, st.Created)
+		}
+	}
+}
+
+func TestMigrateUnmapped(t *testing.T) {
+	te := testNewEnv(t)
+
+	m := NewMigrator(te.fd, te.fs, nil)
+	delete(m.Mapping.Statuses, freshdesk.TicketStatusPending)
+
+	if err := m.MigrateTickets(ctxbg, nil); !errors.Is(err, ErrUnmapped) {
+		t.Fatalf("MigrateTickets() = %v, want %v", err, ErrUnmapped)
+	}
+}
+
+func TestCrosswalkSave(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "crosswalk.jsonl")
+
+	cw, err := LoadCrosswalk(path)
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+
+	cw.Set(KindTicket, 1, 11)
+	cw.Set(KindTicket, 2, 12)
+	if err := cw.Save(); err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	cw.Set(KindContact, 1, 21)
+	if err := cw.Save(); err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+
+	// only the new records are appended
+	bs, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	want := `{"kind":"tickets","fd":1,"fs":11}
+{"kind":"tickets","fd":2,"fs":12}
+{"kind":"contacts","fd":1,"fs":21}
+`
+	if string(bs) != want {
+		t.Errorf("file = %q, want %q", bs, want)
+	}
+
+	// an interrupted Save
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0o660)
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	f.WriteString(`{"kind":"tick`)
+	f.Close()
+
+	if cw, err = LoadCrosswalk(path); err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	if cw.Len(KindTicket) != 2 || cw.Lookup(KindContact, 1) != 21 {
+		t.Errorf("tickets = %d, contact = %d", cw.Len(KindTicket), cw.Lookup(KindContact, 1))
+	}
+
+	cw.Set(KindTicket, 3, 13)
+	if err := cw.Save(); err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	if cw, err = LoadCrosswalk(path); err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	if n := cw.Lookup(KindTicket, 3); n != 13 {
+		t.Errorf("ticket #3 = %d, want %d", n, 13)
+	}
+}
@@ -0,0 +1,98 @@
+package fsmigrate
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/askasoft/gofresh/freshdesk"
+	"github.com/askasoft/gofresh/freshservice"
+)
+
+// ErrUnmapped the value has no mapping in the Mapping tables
+var ErrUnmapped = errors.New("fsmigrate: unmapped value")
+
+// Mapping the mapping tables of the values which differ between Freshdesk and Freshservice.
+type Mapping struct {
+	// Statuses the ticket status mapping, the custom statuses of Freshdesk should be added.
+	Statuses map[freshdesk.TicketStatus]freshservice.TicketStatus
+
+	// Priorities the ticket priority mapping
+	Priorities map[freshdesk.TicketPriority]freshservice.TicketPriority
+
+	// Sources the ticket source mapping
+	Sources map[freshdesk.TicketSource]freshservice.TicketSource
+
+	// TicketFields the ticket custom field name mapping (freshdesk name -> freshservice name).
+	// The custom fields which are not in the mapping are not migrated.
+	TicketFields map[string]string
+
+	// ContactFields the contact custom field name mapping (freshdesk contact -> freshservice requester).
+	ContactFields map[string]string
+
+	// CompanyFields the company custom field name mapping (freshdesk company -> freshservice department).
+	CompanyFields map[string]string
+}
+
+// DefaultMapping returns a Mapping of the default ticket statuses, priorities and sources, without custom fields.
+func DefaultMapping() *Mapping {
+	return &Mapping{
+		Statuses: map[freshdesk.TicketStatus]freshservice.TicketStatus{
+			freshdesk.TicketStatusOpen:     freshservice.TicketStatusOpen,
+			freshdesk.TicketStatusPending:  freshservice.TicketStatusPending,
+			freshdesk.TicketStatusResolved: freshservice.TicketStatusResolved,
+			freshdesk.TicketStatusClosed:   freshservice.TicketStatusClosed,
+		},
+		Priorities: map[freshdesk.TicketPriority]freshservice.TicketPriority{
+			freshdesk.TicketPriorityLow:    freshservice.TicketPriorityLow,
+			freshdesk.TicketPriorityMedium: freshservice.TicketPriorityMedium,
+			freshdesk.TicketPriorityHigh:   freshservice.TicketPriorityHigh,
+			freshdesk.TicketPriorityUrgent: freshservice.TicketPriorityUrgent,
+		},
+		Sources: map[freshdesk.TicketSource]freshservice.TicketSource{
+			freshdesk.TicketSourceEmail:          freshservice.TicketSourceEmail,
+			freshdesk.TicketSourcePortal:         freshservice.TicketSourcePortal,
+			freshdesk.TicketSourcePhone:          freshservice.TicketSourcePhone,
+			freshdesk.TicketSourceChat:           freshservice.TicketSourceChat,
+			freshdesk.TicketSourceFeedbackWidget: freshservice.TicketSourceFeedbackWidget,
+			freshdesk.TicketSourceOutboundEmail:  freshservice.TicketSourceEmail,
+		},
+	}
+}
+
+// Status returns the freshservice status of the freshdesk status.
+func (m *Mapping) Status(s freshdesk.TicketStatus) (freshservice.TicketStatus, error) {
+	if v, ok := m.Statuses[s]; ok {
+		return v, nil
+	}
+	return 0, fmt.Errorf("%w: ticket status %d", ErrUnmapped, s)
+}
+
+// Priority returns the freshservice priority of the freshdesk priority.
+func (m *Mapping) Priority(p freshdesk.TicketPriority) (freshservice.TicketPriority, error) {
+	if v, ok := m.Priorities[p]; ok {
+		return v, nil
+	}
+	return 0, fmt.Errorf("%w: ticket priority %d", ErrUnmapped, p)
+}
+
+// Source returns the freshservice source of the freshdesk source.
+func (m *Mapping) Source(s freshdesk.TicketSource) (freshservice.TicketSource, error) {
+	if v, ok := m.Sources[s]; ok {
+		return v, nil
+	}
+	return 0, fmt.Errorf("%w: ticket source %d", ErrUnmapped, s)
+}
+
+// mapFields returns the custom fields renamed by the names mapping, nil if no field is mapped.
+func mapFields(names map[string]string, cfs map[string]any) map[string]any {
+	var m map[string]any
+	for k, v := range cfs {
+		if n, ok := names[k]; ok && v != nil {
+			if m == nil {
+				m = map[string]any{}
+			}
+			m[n] = v
+		}
+	}
+	return m
+}
@@ -0,0 +1,159 @@
+package fsmigrate
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/askasoft/gofresh/freshdesk"
+	"github.com/askasoft/gofresh/freshservice"
+)
+
+// MigrateSolutions migrates the solution categories, folders and articles.
+// Freshservice has no sub folders, so the sub folders are migrated as the folders of the same category.
+// The folders visible to the selected companies are visible to the mapped departments,
+// the folders of the other freshdesk specific visibilities (e.g. segments, bots) are visible to the agents only.
+func (m *Migrator) MigrateSolutions(ctx context.Context) error {
+	for c, err := range m.FD.AllCategories(ctx, nil) {
+		if err != nil {
+			return err
+		}
+
+		cid, ok := m.crosswalk().Get(KindCategory, c.ID)
+		if ok {
+			m.Stat(KindCategory).Skipped++
+		} else {
+			cc := &freshservice.CategoryCreate{
+				WorkspaceID: m.WorkspaceID,
+				Name:        c.Name,
+				Description: c.Description,
+			}
+			if !m.DryRun {
+				fc, err := m.FS.CreateCategory(ctx, cc)
+				if err != nil {
+					return fmt.Errorf("fsmigrate: category #%d: %w", c.ID, err)
+				}
+				cid = fc.ID
+			}
+			if err := m.created(KindCategory, c.ID, cid); err != nil {
+				return err
+			}
+		}
+
+		for f, err := range m.FD.AllCategoryFolders(ctx, c.ID, nil) {
+			if err != nil {
+				return err
+			}
+			if err := m.migrateFolder(ctx, cid, f); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (m *Migrator) migrateFolder(ctx context.Context, cid int64, f *freshdesk.Folder) error {
+	fid, ok := m.crosswalk().Get(KindFolder, f.ID)
+	if ok {
+		m.Stat(KindFolder).Skipped++
+	} else {
+		fc := &freshservice.FolderCreate{
+			WorkspaceID: m.WorkspaceID,
+			CategoryID:  cid,
+			Name:        f.Name,
+			Description: f.Description,
+		}
+		m.folderVisibility(f, fc)
+
+		if !m.DryRun {
+			ff, err := m.FS.CreateFolder(ctx, fc)
+			if err != nil {
+				return fmt.Errorf("fsmigrate: folder #%d: %w", f.ID, err)
+			}
+			fid = ff.ID
+		}
+		if err := m.created(KindFolder, f.ID, fid); err != nil {
+			return err
+		}
+	}
+
+	for a, err := range m.FD.AllFolderArticles(ctx, f.ID, nil) {
+		if err != nil {
+			return err
+		}
+		if err := m.migrateArticle(ctx, fid, a); err != nil {
+			return fmt.Errorf("fsmigrate: article #%d: %w", a.ID, err)
+		}
+	}
+
+	for sf, err := range m.FD.AllSubFolders(ctx, f.ID, nil) {
+		if err != nil {
+			return err
+		}
+		if err := m.migrateFolder(ctx, cid, sf); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *Migrator) folderVisibility(f *freshdesk.Folder, fc *freshservice.FolderCreate) {
+	switch f.Visibility {
+	case freshdesk.FolderVisibilityAllUsers:
+		fc.Visibility = freshservice.FolderVisibilityAllUsers
+	case freshdesk.FolderVisibilityLoggedInUsers:
+		fc.Visibility = freshservice.FolderVisibilityLoggedInUsers
+	case freshdesk.FolderVisibilitySelectedCompanies:
+		for _, id := range f.CompanyIDs {
+			if did := m.crosswalk().Lookup(KindCompany, id); did != 0 {
+				fc.DepartmentIDs = append(fc.DepartmentIDs, did)
+			}
+		}
+		if len(fc.DepartmentIDs) > 0 {
+			fc.Visibility = freshservice.FolderVisibilityDepartments
+			return
+		}
+		fc.Visibility = freshservice.FolderVisibilityAgents
+	default:
+		fc.Visibility = freshservice.FolderVisibilityAgents
+	}
+}
+
+func (m *Migrator) migrateArticle(ctx context.Context, fid int64, a *freshdesk.Article) error {
+	if m.migrated(KindArticle, a.ID) {
+		return nil
+	}
+
+	ac := &freshservice.ArticleCreate{
+		Title:       a.Title,
+		Description: a.Description,
+		ArticleType: freshservice.ArticleTypePermanent,
+		FolderID:    fid,
+		Status:      freshservice.ArticleStatusDraft,
+	}
+	if a.Status == freshdesk.ArticleStatusPublished {
+		ac.Status = freshservice.ArticleStatusPublished
+	}
+	if len(a.Tags) > 0 {
+		tags := a.Tags
+		ac.Tags = &tags
+	}
+
+	var (
+		cleanup func()
+		err     error
+	)
+	if ac.Attachments, cleanup, err = m.readAttachments(ctx, a.Attachments); err != nil {
+		return err
+	}
+	defer cleanup()
+
+	var id int64
+	if !m.DryRun {
+		fa, err := m.FS.CreateArticle(ctx, ac)
+		if err != nil {
+			return err
+		}
+		id = fa.ID
+	}
+	return m.created(KindArticle, a.ID, id)
+}
@@ -0,0 +1,177 @@
+package fsmigrate
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/askasoft/gofresh/freshdesk"
+	"github.com/askasoft/gofresh/freshservice"
+)
+
+// MigrateTickets migrates the tickets of lto with their conversations and attachments.
+// The conversations are migrated as notes (the incoming replies are public incoming notes),
+// and the created/updated time of the tickets and conversations are kept.
+// The requester of a ticket is mapped by the contacts Crosswalk, or by the email of the freshdesk contact.
+// The tickets are listed by AllTicketsSliced to get around the 300 pages limit of ListTickets,
+// the lto.Page/OrderBy/OrderType are ignored.
+// Each ticket to migrate is got by GetTicket, since the listed tickets have no description and attachments.
+func (m *Migrator) MigrateTickets(ctx context.Context, lto *freshdesk.ListTicketsOption) error {
+	for t, err := range m.FD.AllTicketsSliced(ctx, lto) {
+		if err != nil {
+			return err
+		}
+
+		tid, ok := m.crosswalk().Get(KindTicket, t.ID)
+		if ok {
+			m.Stat(KindTicket).Skipped++
+		} else {
+			tid, err = m.migrateTicket(ctx, t)
+			if err != nil {
+				return fmt.Errorf("fsmigrate: ticket #%d: %w", t.ID, err)
+			}
+		}
+
+		if err := m.migrateConversations(ctx, t.ID, tid); err != nil {
+			return fmt.Errorf("fsmigrate: ticket #%d: %w", t.ID, err)
+		}
+	}
+	return nil
+}
+
+// TicketCreate returns the freshservice TicketCreate of the freshdesk ticket (without attachments).
+// The t should be got by GetTicket, a listed ticket has no description.
+func (m *Migrator) TicketCreate(ctx context.Context, t *freshdesk.Ticket) (*freshservice.TicketCreate, error) {
+	mp, cw := m.mapping(), m.crosswalk()
+
+	status, err := mp.Status(t.Status)
+	if err != nil {
+		return nil, err
+	}
+	priority, err := mp.Priority(t.Priority)
+	if err != nil {
+		return nil, err
+	}
+	source, err := mp.Source(t.Source)
+	if err != nil {
+		return nil, err
+	}
+
+	tc := &freshservice.TicketCreate{
+		WorkspaceID:  m.WorkspaceID,
+		DepartmentID: cw.Lookup(KindCompany, t.CompanyID),
+		RequesterID:  cw.Lookup(KindContact, t.RequesterID),
+		ResponderID:  cw.Lookup(KindAgent, t.ResponderID),
+		GroupID:      cw.Lookup(KindGroup, t.GroupID),
+		Status:       status,
+		Priority:     priority,
+		Source:       source,
+		Subject:      t.Subject,
+		Description:  t.Description,
+		CcEmails:     t.CcEmails,
+		CustomFields: mapFields(mp.TicketFields, t.CustomFields),
+		CreatedAt:    &freshservice.Time{Time: t.CreatedAt.Time},
+		UpdatedAt:    &freshservice.Time{Time: t.UpdatedAt.Time},
+	}
+	if len(t.Tags) > 0 {
+		tags := t.Tags
+		tc.Tags = &tags
+	}
+
+	if tc.RequesterID == 0 && t.RequesterID != 0 {
+		// the contact is not migrated (e.g. deleted), create the requester by the email
+		c, err := m.FD.GetContact(ctx, t.RequesterID)
+		if err != nil {
+			return nil, fmt.Errorf("requester #%d: %w", t.RequesterID, err)
+		}
+		tc.Name, tc.Email, tc.Phone = c.Name, c.Email, c.Phone
+	}
+
+	if m.TicketHook != nil {
+		if err := m.TicketHook(ctx, t, tc); err != nil {
+			return nil, err
+		}
+	}
+	return tc, nil
+}
+
+func (m *Migrator) migrateTicket(ctx context.Context, t *freshdesk.Ticket) (int64, error) {
+	t, err := m.FD.GetTicket(ctx, t.ID)
+	if err != nil {
+		return 0, err
+	}
+
+	tc, err := m.TicketCreate(ctx, t)
+	if err != nil {
+		return 0, err
+	}
+
+	var cleanup func()
+	if tc.Attachments, cleanup, err = m.readAttachments(ctx, t.Attachments); err != nil {
+		return 0, err
+	}
+	defer cleanup()
+
+	var id int64
+	if !m.DryRun {
+		ft, err := m.FS.CreateTicket(ctx, tc)
+		if err != nil {
+			return 0, err
+		}
+		id = ft.ID
+	}
+	return id, m.created(KindTicket, t.ID, id)
+}
+
+func (m *Migrator) migrateConversations(ctx context.Context, fdtid, fstid int64) error {
+	cw := m.crosswalk()
+
+	for c, err := range m.FD.AllTicketConversations(ctx, fdtid, nil) {
+		if err != nil {
+			return err
+		}
+		if m.migrated(KindConversation, c.ID) {
+			continue
+		}
+
+		note := &freshservice.Note{
+			Body:      c.Body,
+			Incoming:  c.Incoming,
+			Private:   c.Private,
+			CreatedAt: &freshservice.Time{Time: c.CreatedAt.Time},
+			UpdatedAt: &freshservice.Time{Time: c.UpdatedAt.Time},
+		}
+		if note.UserID = cw.Lookup(KindAgent, c.UserID); note.UserID == 0 {
+			note.UserID = cw.Lookup(KindContact, c.UserID)
+		}
+
+		id, err := m.createNote(ctx, fstid, note, c)
+		if err != nil {
+			return fmt.Errorf("conversation #%d: %w", c.ID, err)
+		}
+		if err := m.created(KindConversation, c.ID, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *Migrator) createNote(ctx context.Context, fstid int64, note *freshservice.Note, c *freshdesk.Conversation) (int64, error) {
+	var (
+		cleanup func()
+		err     error
+	)
+	if note.Attachments, cleanup, err = m.readAttachments(ctx, c.Attachments); err != nil {
+		return 0, err
+	}
+	defer cleanup()
+
+	if m.DryRun {
+		return 0, nil
+	}
+
+	n, err := m.FS.CreateNote(ctx, fstid, note)
+	if err != nil {
+		return 0, err
+	}
+	return n.ID, nil
+}
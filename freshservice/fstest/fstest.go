@@ -0,0 +1,915 @@
+// Package fstest provides an in-memory Freshservice emulator for the tests.
+//
+// The emulator covers tickets, conversations, requesters, agents, agent groups, departments, requester groups, approvals,
+// service catalog, solutions, workspaces and time entries. The results are wrapped in the envelopes
+// (e.g. {"ticket": {...}}, {"tickets": [...]}) like the real Freshservice api.
+// It enforces the basic auth, paginates the lists with the Link headers, returns the ResultError shaped error bodies,
+// and can simulate the 429 Too Many Requests responses with the Retry-After header.
+//
+// The package does not import the freshservice package, so that it can be used by the tests of the freshservice package.
+//
+// Example:
+//
+//	fs := fstest.NewServer()
+//	defer fs.Close()
+//
+//	fsv := &freshservice.Client{Domain: fs.Domain, APIKey: fs.APIKey, BaseURL: fs.URL}
+//	ticket, err := fsv.CreateTicket(ctx, &freshservice.TicketCreate{...})
+package fstest
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/askasoft/gofresh/fresh"
+	"github.com/askasoft/gofresh/fresh/freshtest"
+)
+
+const (
+	// Domain the domain of the emulated Freshservice
+	Domain = "fstest.freshservice.com"
+
+	// APIKey the api key of the emulated Freshservice
+	APIKey = "fstest-apikey"
+
+	// WorkspaceID the id of the seeded primary workspace
+	WorkspaceID = 2
+)
+
+// Record a json object stored in the Store
+type Record = freshtest.Record
+
+// Server an in-memory Freshservice emulator.
+type Server struct {
+	*freshtest.Server
+}
+
+// NewServer starts and returns a new Freshservice emulator with the seed data, the caller should call Close when finished.
+func NewServer() *Server {
+	s := &Server{Server: freshtest.NewServer(Domain, APIKey)}
+	s.register()
+	s.Seed()
+	return s
+}
+
+// SetNow replaces the clock of the emulator, which is used to set the created_at/updated_at of the records.
+func (s *Server) SetNow(now func() time.Time) {
+	s.Store.Now = now
+}
+
+// Seed resets the Store and adds the read-only records: the primary workspace, the agent roles,
+// the ticket/agent/requester fields, and a service category with a service item (display_id 1).
+func (s *Server) Seed() {
+	st := s.Store
+	st.Reset()
+
+	st.Insert("workspaces", Record{"id": int64(WorkspaceID), "name": "IT", "primary": true, "restricted": false, "state": "active"})
+
+	for _, n := range []string{"Account Admin", "Admin", "SD Supervisor", "SD Agent"} {
+		st.Insert("roles", Record{"name": n, "description": n, "default": true})
+	}
+
+	for _, tf := range []Record{
+		{"name": "requester", "label": "Requester", "field_type": "default_requester", "required": true},
+		{"name": "subject", "label": "Subject", "field_type": "default_subject", "required": true},
+		{"name": "ticket_type", "label": "Type", "field_type": "default_ticket_type"},
+		{"name": "source", "label": "Source", "field_type": "default_source"},
+		{"name": "status", "label": "Status", "field_type": "default_status", "required": true},
+		{"name": "urgency", "label": "Urgency", "field_type": "default_urgency"},
+		{"name": "impact", "label": "Impact", "field_type": "default_impact"},
+		{"name": "priority", "label": "Priority", "field_type": "default_priority", "required": true},
+		{"name": "group", "label": "Group", "field_type": "default_group"},
+		{"name": "agent", "label": "Agent", "field_type": "default_agent"},
+		{"name": "description", "label": "Description", "field_type": "default_description", "required": true},
+	} {
+		tf["workspace_id"] = int64(WorkspaceID)
+		tf["default_field"] = true
+		st.Insert("ticket_fields", tf)
+	}
+
+	for _, n := range []string{"first_name", "last_name", "email", "job_title", "work_phone_number", "mobile_phone_number"} {
+		st.Insert("agent_fields", Record{"name": n, "label": n, "default_field": true, "mandatory_for_admin": n == "first_name" || n == "email"})
+	}
+	for _, n := range []string{"first_name", "last_name", "primary_email", "job_title", "work_phone_number", "mobile_phone_number"} {
+		st.Insert("requester_fields", Record{"name": n, "label": n, "default_field": true, "mandatory_for_agents": n == "first_name"})
+	}
+
+	sc := st.Insert("service_categories", Record{"name": "Hardware", "description": "Hardware Requests", "position": int64(1), "workspace_id": int64(WorkspaceID)})
+	st.Insert("service_items", Record{
+		"display_id":        int64(1),
+		"name":              "Laptop",
+		"short_description": "Request a new laptop",
+		"description":       "Request a new laptop",
+		"category_id":       sc.ID(),
+		"workspace_id":      int64(WorkspaceID),
+		"item_type":         int64(1),
+		"visibility":        int64(2),
+		"deleted":           false,
+		"cost":              "1000",
+		"delivery_time":     int64(48),
+		"custom_fields":     []any{},
+	})
+}
+
+func (s *Server) register() {
+	s.registerTickets()
+	s.registerUsers()
+	s.registerServiceCatalog()
+	s.registerSolutions()
+
+	s.Handle(http.MethodGet, "/attachments/:id", func(c *freshtest.Context) {
+		c.ServeFile(c.ID(0))
+	})
+
+	readonly(s, "/workspaces", &freshtest.Resource{Collection: "workspaces", Single: "workspace", Plural: "workspaces"})
+	readonly(s, "/roles", &freshtest.Resource{Collection: "roles", Single: "role", Plural: "roles"})
+
+	s.Handle(http.MethodGet, "/ticket_form_fields", fields("ticket_fields", "ticket_fields"))
+	s.Handle(http.MethodGet, "/agent_fields", fields("agent_fields", "agent_fields"))
+	s.Handle(http.MethodGet, "/requester_fields", fields("requester_fields", "requester_fields"))
+}
+
+func (s *Server) registerTickets() {
+	tickets := &freshtest.Resource{
+		Collection: "tickets",
+		Single:     "ticket",
+		Plural:     "tickets",
+		Required:   [][]string{{"requester_id", "email", "phone"}},
+		Defaults: Record{
+			"status":          int64(2), // open
+			"priority":        int64(1), // low
+			"urgency":         int64(1), // low
+			"impact":          int64(1), // low
+			"source":          int64(2), // portal
+			"type":            "Incident",
+			"workspace_id":    int64(WorkspaceID),
+			"tags":            []any{},
+			"cc_emails":       []any{},
+			"fwd_emails":      []any{},
+			"reply_cc_emails": []any{},
+			"attachments":     []any{},
+			"custom_fields":   map[string]any{},
+			"spam":            false,
+			"deleted":         false,
+			"is_escalated":    false,
+			"fr_escalated":    false,
+		},
+		Match:    matchTicket,
+		Sort:     sortTickets,
+		Validate: freshtest.Validators(validateTicket, freshtest.AppendAttachments("tickets")),
+		Brief:    briefTicket,
+	}
+
+	s.Handle(http.MethodGet, "/tickets", tickets.List)
+	s.Handle(http.MethodPost, "/tickets", tickets.Create)
+	s.Handle(http.MethodGet, "/tickets/filter", filterTickets)
+	s.Handle(http.MethodGet, "/tickets/:id", func(c *freshtest.Context) {
+		if t := tickets.Find(c); t != nil {
+			c.JSON(http.StatusOK, Record{"ticket": includeTicket(c, t)})
+		}
+	})
+	s.Handle(http.MethodPut, "/tickets/:id", tickets.Update)
+	s.Handle(http.MethodDelete, "/tickets/:id", tickets.SoftDelete)
+	s.Handle(http.MethodPut, "/tickets/:id/restore", tickets.Restore)
+	s.Handle(http.MethodDelete, "/tickets/:id/attachments/:id", deleteAttachment(tickets))
+
+	conversations := &freshtest.Resource{
+		Collection: "conversations",
+		Single:     "conversation",
+		Plural:     "conversations",
+		Parent:     &freshtest.Parent{Collection: "tickets", Key: "ticket_id"},
+		Validate:   freshtest.AppendAttachments("conversations"),
+	}
+	replies := &freshtest.Resource{
+		Collection: "conversations",
+		Single:     "conversation",
+		Parent:     conversations.Parent,
+		Required:   [][]string{{"body"}},
+		Defaults:   Record{"incoming": false, "private": false, "source": int64(0), "attachments": []any{}},
+		Validate:   touchTicket,
+	}
+	notes := &freshtest.Resource{
+		Collection: "conversations",
+		Single:     "conversation",
+		Parent:     conversations.Parent,
+		Required:   [][]string{{"body"}},
+		Defaults:   Record{"incoming": false, "private": true, "source": int64(2), "attachments": []any{}},
+		Validate:   touchTicket,
+	}
+
+	s.Handle(http.MethodGet, "/tickets/:id/conversations", conversations.List)
+	s.Handle(http.MethodPost, "/tickets/:id/reply", replies.Create)
+	s.Handle(http.MethodPost, "/tickets/:id/notes", notes.Create)
+	s.Handle(http.MethodPut, "/conversations/:id", conversations.Update)
+	s.Handle(http.MethodDelete, "/conversations/:id", conversations.Delete)
+	s.Handle(http.MethodDelete, "/conversations/:id/attachments/:id", deleteAttachment(conversations))
+
+	timeEntries := &freshtest.Resource{
+		Collection: "time_entries",
+		Single:     "time_entry",
+		Plural:     "time_entries",
+		Parent:     &freshtest.Parent{Collection: "tickets", Key: "ticket_id"},
+		Required:   [][]string{{"agent_id"}},
+		Defaults:   Record{"billable": true, "timer_running": false, "time_spent": "00:00", "workspace_id": int64(WorkspaceID)},
+	}
+	s.HandleResource("/tickets/:id/time_entries", timeEntries)
+
+	requestedItems := &freshtest.Resource{
+		Collection: "requested_items",
+		Single:     "requested_item",
+		Plural:     "requested_items",
+		Parent:     &freshtest.Parent{Collection: "tickets", Key: "ticket_id"},
+		Required:   [][]string{{"item_id"}},
+		Defaults:   Record{"quantity": int64(1), "stage": int64(1), "custom_fields": map[string]any{}},
+		Validate: func(c *freshtest.Context, r Record, create bool) bool {
+			if create {
+				si := c.Store().Get("service_items", r.Int64("item_id"))
+				if si == nil {
+					c.Invalid(fresh.FieldError{Field: "item_id", Message: "Service item does not exist", Code: "invalid_value"})
+					return false
+				}
+				r["service_item_id"] = si.ID()
+				r["service_item_name"] = si["name"]
+			}
+			return true
+		},
+	}
+	s.Handle(http.MethodGet, "/tickets/:id/requested_items", requestedItems.List)
+	s.Handle(http.MethodPost, "/tickets/:id/requested_items", requestedItems.Create)
+	s.Handle(http.MethodGet, "/tickets/:id/requested_items/:id", requestedItems.Get)
+
+	s.registerApprovals()
+}
+
+func (s *Server) registerApprovals() {
+	approvals := &freshtest.Resource{
+		Collection: "approvals",
+		Single:     "approval",
+		Plural:     "approvals",
+		Match:      matchApproval,
+	}
+	ticketApprovals := &freshtest.Resource{
+		Collection: "approvals",
+		Single:     "approval",
+		Plural:     "approvals",
+		Parent:     &freshtest.Parent{Collection: "tickets", Key: "parent_id"},
+		Required:   [][]string{{"approver_id"}},
+		Defaults:   Record{"parent": "ticket", "level": int64(1), "approval_type": int64(1)},
+		Match: func(c *freshtest.Context, r Record) bool {
+			return r.String("parent") == "ticket"
+		},
+		Validate: validateApproval,
+	}
+
+	s.Handle(http.MethodGet, "/approvals", func(c *freshtest.Context) {
+		if c.Query.Get("parent") == "" {
+			c.Invalid(fresh.FieldError{Field: "parent", Message: "It should be one of these values: 'ticket,change,release'", Code: "missing_field"})
+			return
+		}
+		approvals.List(c)
+	})
+	s.Handle(http.MethodGet, "/approvals/:id", approvals.Get)
+	s.Handle(http.MethodGet, "/tickets/:id/approvals", ticketApprovals.List)
+	s.Handle(http.MethodPost, "/tickets/:id/approvals", ticketApprovals.Create)
+	s.Handle(http.MethodGet, "/tickets/:id/approvals/:id", ticketApprovals.Get)
+	s.Handle(http.MethodPut, "/tickets/:id/approvals/:id", ticketApprovals.Update)
+	s.Handle(http.MethodPost, "/tickets/:id/approvals/:id", func(c *freshtest.Context) {
+		if ticketApprovals.Find(c) != nil {
+			c.NoContent() // remind
+		}
+	})
+}
+
+func (s *Server) registerUsers() {
+	agents := &freshtest.Resource{
+		Collection: "users",
+		Single:     "agent",
+		Plural:     "agents",
+		Required:   [][]string{{"first_name"}, {"email"}},
+		Defaults:   Record{"is_agent": true, "active": true, "occasional": false, "roles": []any{}, "member_of": []any{}, "observer_of": []any{}},
+		Query:      true,
+		Match:      matchAgent,
+		Validate:   freshtest.Validators(syncEmail("email", "primary_email"), freshtest.Unique("users", "email")),
+		Removed:    func(r Record) bool { return !r.Bool("is_agent") },
+	}
+
+	s.Handle(http.MethodDelete, "/agents/:id", activate(agents, false))
+	s.HandleResource("/agents", agents)
+	s.Handle(http.MethodDelete, "/agents/:id/forget", agents.Delete)
+	s.Handle(http.MethodPut, "/agents/:id/reactivate", activate(agents, true))
+	s.Handle(http.MethodPut, "/agents/:id/convert_to_requester", func(c *freshtest.Context) {
+		if r := agents.Find(c); r != nil {
+			r = c.Store().Update("users", r.ID(), Record{"is_agent": false})
+			c.JSON(http.StatusOK, Record{"agent": r})
+		}
+	})
+
+	requesters := &freshtest.Resource{
+		Collection: "users",
+		Single:     "requester",
+		Plural:     "requesters",
+		Required:   [][]string{{"first_name"}, {"primary_email", "work_phone_number", "mobile_phone_number"}},
+		Defaults:   Record{"is_agent": false, "active": true, "has_logged_in": false, "department_ids": []any{}, "custom_fields": map[string]any{}},
+		Query:      true,
+		Match:      matchRequester,
+		Validate:   freshtest.Validators(syncEmail("primary_email", "email"), freshtest.Unique("users", "primary_email")),
+		Removed:    func(r Record) bool { return r.Bool("is_agent") },
+	}
+
+	s.Handle(http.MethodDelete, "/requesters/:id", activate(requesters, false))
+	s.HandleResource("/requesters", requesters)
+	s.Handle(http.MethodDelete, "/requesters/:id/forget", func(c *freshtest.Context) {
+		if r := requesters.Find(c); r != nil {
+			for _, t := range c.Store().Find("tickets", func(t Record) bool { return t.Int64("requester_id") == r.ID() }) {
+				c.Store().Delete("tickets", t.ID())
+			}
+			c.Store().Delete("users", r.ID())
+			c.NoContent()
+		}
+	})
+	s.Handle(http.MethodPut, "/requesters/:id/reactivate", activate(requesters, true))
+	s.Handle(http.MethodPut, "/requesters/:id/convert_to_agent", func(c *freshtest.Context) {
+		if r := requesters.Find(c); r != nil {
+			r = c.Store().Update("users", r.ID(), Record{"is_agent": true, "occasional": true, "roles": []any{}, "member_of": []any{}})
+			c.JSON(http.StatusOK, Record{"agent": r})
+		}
+	})
+	s.Handle(http.MethodPut, "/requesters/:id/merge", func(c *freshtest.Context) {
+		r := requesters.Find(c)
+		if r == nil {
+			return
+		}
+		for _, id := range strings.Split(c.Query.Get("secondary_requesters"), ",") {
+			sid, _ := strconv.ParseInt(id, 10, 64)
+			if sr := c.Store().Get("users", sid); sr != nil && !sr.Bool("is_agent") && sid != r.ID() {
+				for _, t := range c.Store().Find("tickets", func(t Record) bool { return t.Int64("requester_id") == sid }) {
+					c.Store().Update("tickets", t.ID(), Record{"requester_id": r.ID()})
+				}
+				c.Store().Delete("users", sid)
+			}
+		}
+		c.JSON(http.StatusOK, Record{"requester": r})
+	})
+
+	s.HandleResource("/groups", &freshtest.Resource{
+		Collection: "groups",
+		Single:     "group",
+		Plural:     "groups",
+		Required:   [][]string{{"name"}},
+		Defaults:   Record{"members": []any{}, "observers": []any{}, "leaders": []any{}, "restricted": false, "workspace_id": int64(WorkspaceID)},
+		Validate:   freshtest.Unique("groups", "name"),
+	})
+
+	s.HandleResource("/departments", &freshtest.Resource{
+		Collection: "departments",
+		Single:     "department",
+		Plural:     "departments",
+		Required:   [][]string{{"name"}},
+		Defaults:   Record{"domains": []any{}, "custom_fields": Record{}},
+		Validate:   freshtest.Unique("departments", "name"),
+	})
+
+	s.HandleResource("/requester_groups", &freshtest.Resource{
+		Collection: "requester_groups",
+		Single:     "requester_group",
+		Plural:     "requester_groups",
+		Required:   [][]string{{"name"}},
+		Defaults:   Record{"type": "manual"},
+		Validate:   freshtest.Unique("requester_groups", "name"),
+	})
+	s.Handle(http.MethodGet, "/requester_groups/:id/members", listMembers)
+	s.Handle(http.MethodPost, "/requester_groups/:id/members/:id", addMember)
+	s.Handle(http.MethodDelete, "/requester_groups/:id/members/:id", removeMember)
+}
+
+func (s *Server) registerServiceCatalog() {
+	readonly(s, "/service_catalog/categories", &freshtest.Resource{
+		Collection: "service_categories",
+		Plural:     "service_categories",
+		Match: func(c *freshtest.Context, r Record) bool {
+			return matchInt64(c, r, "workspace_id")
+		},
+	})
+
+	items := &freshtest.Resource{
+		Collection: "service_items",
+		Single:     "service_item",
+		Plural:     "service_items",
+		Required:   [][]string{{"name"}, {"category_id"}},
+		Defaults:   Record{"item_type": int64(1), "visibility": int64(1), "deleted": false, "workspace_id": int64(WorkspaceID), "custom_fields": []any{}},
+		Match: func(c *freshtest.Context, r Record) bool {
+			if st := c.Query.Get("search_term"); st != "" && !strings.Contains(strings.ToLower(r.String("name")), strings.ToLower(st)) {
+				return false
+			}
+			return matchInt64(c, r, "category_id", "workspace_id")
+		},
+		Validate: validateServiceItem,
+	}
+
+	// the service items are identified by the display_id, which is the same as the id in the emulator
+	s.Handle(http.MethodGet, "/service_catalog/items", items.List)
+	s.Handle(http.MethodGet, "/service_catalog/items/search", items.List)
+	s.Handle(http.MethodGet, "/service_catalog/items/:id", items.Get)
+	s.Handle(http.MethodPost, "/service-catalog/items", items.Create)
+	s.Handle(http.MethodPut, "/service-catalog/items/:id", items.Update)
+	s.Handle(http.MethodDelete, "/service-catalog/items/:id", items.Delete)
+}
+
+func (s *Server) registerSolutions() {
+	categories := &freshtest.Resource{
+		Collection: "categories",
+		Single:     "category",
+		Plural:     "categories",
+		Required:   [][]string{{"name"}},
+		Defaults:   Record{"workspace_id": int64(WorkspaceID), "default_category": false, "deleted": false},
+		Match: func(c *freshtest.Context, r Record) bool {
+			return matchTrash(c, r) && matchInt64(c, r, "workspace_id")
+		},
+	}
+	solutions(s, "/solutions/categories", categories)
+
+	folders := &freshtest.Resource{
+		Collection: "folders",
+		Single:     "folder",
+		Plural:     "folders",
+		Required:   [][]string{{"name"}, {"category_id"}},
+		Defaults:   Record{"visibility": int64(1), "default_folder": false, "deleted": false},
+		Match: func(c *freshtest.Context, r Record) bool {
+			return matchTrash(c, r) && matchInt64(c, r, "category_id")
+		},
+		Validate: exists("category_id", "categories"),
+	}
+	solutions(s, "/solutions/folders", folders)
+
+	articles := &freshtest.Resource{
+		Collection: "articles",
+		Single:     "article",
+		Plural:     "articles",
+		Required:   [][]string{{"title"}, {"description"}, {"folder_id"}},
+		Defaults:   Record{"status": int64(1), "article_type": int64(1), "tags": []any{}, "keywords": []any{}, "attachments": []any{}, "views": int64(0), "deleted": false},
+		Match: func(c *freshtest.Context, r Record) bool {
+			if st := c.Query.Get("search_term"); st != "" {
+				st = strings.ToLower(st)
+				if !strings.Contains(strings.ToLower(r.String("title")), st) && !strings.Contains(strings.ToLower(r.String("description")), st) {
+					return false
+				}
+			}
+			return matchTrash(c, r) && matchInt64(c, r, "folder_id")
+		},
+		Brief:    briefArticle,
+		Validate: freshtest.Validators(exists("folder_id", "folders"), validateArticle, freshtest.AppendAttachments("articles")),
+	}
+	s.Handle(http.MethodGet, "/solutions/articles/search", articles.List)
+	s.Handle(http.MethodPut, "/solutions/articles/:id/send_for_approval", func(c *freshtest.Context) {
+		if r := articles.Find(c); r != nil {
+			r = c.Store().Update("articles", r.ID(), Record{"approval_status": int64(1)})
+			c.JSON(http.StatusOK, Record{"article": r})
+		}
+	})
+	s.Handle(http.MethodGet, "/solutions/articles/bulk_restore", func(c *freshtest.Context) {
+		for _, id := range strings.Split(c.Query.Get("ids"), ",") {
+			aid, _ := strconv.ParseInt(id, 10, 64)
+			if c.Store().Get("articles", aid) != nil {
+				c.Store().Update("articles", aid, Record{"deleted": false})
+			}
+		}
+		c.NoContent()
+	})
+	solutions(s, "/solutions/articles", articles)
+}
+
+// readonly registers the list/get handlers of the resource.
+func readonly(s *Server, path string, res *freshtest.Resource) {
+	s.Handle(http.MethodGet, path, res.List)
+	s.Handle(http.MethodGet, path+"/:id", res.Get)
+}
+
+// solutions registers the handlers of the solution resource, the DELETE moves the record to the trash.
+func solutions(s *Server, path string, res *freshtest.Resource) {
+	s.Handle(http.MethodDelete, path+"/:id", res.SoftDelete)
+	s.HandleResource(path, res)
+	s.Handle(http.MethodPut, path+"/:id/restore", res.Restore)
+	s.Handle(http.MethodDelete, path+"/:id/delete_forever", res.Delete)
+}
+
+// fields returns a handler which writes all the records of the collection in the envelope.
+func fields(coll, envelope string) freshtest.HandlerFunc {
+	return func(c *freshtest.Context) {
+		c.JSON(http.StatusOK, Record{envelope: c.Store().Find(coll, nil)})
+	}
+}
+
+// validateTicket sets the requester_id of the ticket by the email or phone, a requester is created if not found.
+func validateTicket(c *freshtest.Context, r Record, create bool) bool {
+	if !create || !r.IsEmpty("requester_id") {
+		return true
+	}
+
+	email, phone := r.String("email"), r.String("phone")
+
+	us := c.Store().Find("users", func(u Record) bool {
+		if email != "" {
+			return strings.EqualFold(u.String("primary_email"), email)
+		}
+		return u.String("work_phone_number") == phone || u.String("mobile_phone_number") == phone
+	})
+	if len(us) > 0 {
+		r["requester_id"] = us[0].ID()
+		return true
+	}
+
+	name := r.String("name")
+	if name == "" {
+		if email == "" {
+			c.Invalid(fresh.FieldError{Field: "name", Message: "It should be a/an String", Code: "missing_field"})
+			return false
+		}
+		name, _, _ = strings.Cut(email, "@")
+	}
+
+	u := c.Store().Insert("users", Record{
+		"first_name":        name,
+		"primary_email":     email,
+		"email":             email,
+		"work_phone_number": phone,
+		"is_agent":          false,
+		"active":            true,
+	})
+	r["requester_id"] = u.ID()
+	return true
+}
+
+// touchTicket updates the updated_at of the parent ticket of a new conversation.
+func touchTicket(c *freshtest.Context, r Record, create bool) bool {
+	if create {
+		c.Store().Update("tickets", c.ID(0), nil)
+	}
+	return true
+}
+
+// includeTicket returns a copy of the ticket with the embedded resources of the "include" parameter.
+func includeTicket(c *freshtest.Context, t Record) Record {
+	t = t.Clone()
+	for _, inc := range strings.Split(c.Query.Get("include"), ",") {
+		switch inc {
+		case "conversations":
+			t["conversations"] = c.Store().Find("conversations", func(r Record) bool {
+				return r.Int64("ticket_id") == t.ID()
+			})
+		case "requester":
+			if u := c.Store().Get("users", t.Int64("requester_id")); u != nil {
+				t["requester"] = u
+			}
+		case "stats":
+			t["stats"] = Record{"ticket_id": t.ID(), "created_at": t["created_at"], "updated_at": t["updated_at"]}
+		}
+	}
+	return t
+}
+
+// filterTickets handles the "/tickets/filter" api, the result contains the "total" of the matched tickets.
+func filterTickets(c *freshtest.Context) {
+	qm, ok := c.FilterQuery()
+	if !ok {
+		return
+	}
+	if qm == nil {
+		c.Invalid(fresh.FieldError{Field: "query", Message: "It should be a/an String", Code: "missing_field"})
+		return
+	}
+
+	rs := c.Store().Find("tickets", func(r Record) bool {
+		return !r.Bool("deleted") && !r.Bool("spam") && qm(r)
+	})
+
+	total := len(rs)
+	if rs, ok = c.Paginate(rs); ok {
+		c.JSON(http.StatusOK, Record{"tickets": rs, "total": total})
+	}
+}
+
+func matchTicket(c *freshtest.Context, r Record) bool {
+	switch c.Query.Get("filter") {
+	case "deleted":
+		if !r.Bool("deleted") {
+			return false
+		}
+	case "spam":
+		if !r.Bool("spam") {
+			return false
+		}
+	default:
+		if r.Bool("deleted") || r.Bool("spam") {
+			return false
+		}
+	}
+
+	if !matchInt64(c, r, "requester_id", "workspace_id") {
+		return false
+	}
+
+	if tt := c.Query.Get("type"); tt != "" && !strings.EqualFold(r.String("type"), tt) {
+		return false
+	}
+
+	if email := c.Query.Get("email"); email != "" {
+		if u := c.Store().Get("users", r.Int64("requester_id")); u == nil || !strings.EqualFold(u.String("primary_email"), email) {
+			return false
+		}
+	}
+
+	if us := c.Query.Get("updated_since"); us != "" {
+		t, err := fresh.ParseTime(us)
+		if err == nil && r.Time("updated_at").Before(t.Time) {
+			return false
+		}
+	}
+	return true
+}
+
+func sortTickets(c *freshtest.Context, rs []Record) {
+	desc := c.Query.Get("order_type") != "asc"
+
+	slices.SortStableFunc(rs, func(a, b Record) int {
+		n := a.Time("created_at").Compare(b.Time("created_at"))
+		if n == 0 {
+			n = int(a.ID() - b.ID())
+		}
+		if desc {
+			n = -n
+		}
+		return n
+	})
+}
+
+// deleteAttachment returns a handler which removes the attachment of the last ":id" from the record of the first ":id".
+func deleteAttachment(res *freshtest.Resource) freshtest.HandlerFunc {
+	return func(c *freshtest.Context) {
+		r := c.Store().Get(res.Collection, c.ID(0))
+		if r == nil {
+			c.NotFound()
+			return
+		}
+
+		as, _ := r["attachments"].([]any)
+		i := slices.IndexFunc(as, func(a any) bool {
+			ar, ok := freshtest.AsRecord(a)
+			return ok && ar.ID() == c.ID(1)
+		})
+		if i < 0 {
+			c.NotFound()
+			return
+		}
+
+		c.Store().Update(res.Collection, r.ID(), Record{"attachments": slices.Delete(slices.Clone(as), i, i+1)})
+		c.NoContent()
+	}
+}
+
+var approvalStatuses = []string{"requested", "approved", "rejected", "canceled"}
+
+// validateApproval sets the name of the approval_status.
+func validateApproval(c *freshtest.Context, r Record, create bool) bool {
+	id := int64(0)
+	if as, ok := freshtest.AsRecord(r["approval_status"]); ok {
+		id = as.Int64("id")
+	} else if !create {
+		return true
+	}
+
+	if id < 0 || id >= int64(len(approvalStatuses)) {
+		c.Invalid(fresh.FieldError{Field: "approval_status", Message: "It should be one of these values: '0,1,2,3'", Code: "invalid_value"})
+		return false
+	}
+
+	r["approval_status"] = Record{"id": id, "name": approvalStatuses[id]}
+	return true
+}
+
+func matchApproval(c *freshtest.Context, r Record) bool {
+	if p := c.Query.Get("parent"); p != "" && r.String("parent") != p {
+		return false
+	}
+
+	if st := c.Query.Get("status"); st != "" {
+		as, _ := freshtest.AsRecord(r["approval_status"])
+		if as.String("name") != st {
+			return false
+		}
+	}
+
+	return matchInt64(c, r, "parent_id", "approver_id", "level", "delegatee_id")
+}
+
+func matchAgent(c *freshtest.Context, r Record) bool {
+	if !r.Bool("is_agent") || !matchUser(c, r, "email") {
+		return false
+	}
+
+	if a := c.Query.Get("active"); a != "" && strconv.FormatBool(r.Bool("active")) != a {
+		return false
+	}
+
+	switch c.Query.Get("state") {
+	case "fulltime":
+		return !r.Bool("occasional")
+	case "occasional":
+		return r.Bool("occasional")
+	}
+	return true
+}
+
+func matchRequester(c *freshtest.Context, r Record) bool {
+	if r.Bool("is_agent") && c.Query.Get("include_agents") != "true" {
+		return false
+	}
+	return matchUser(c, r, "primary_email") && matchInt64(c, r, "workspace_id")
+}
+
+func matchUser(c *freshtest.Context, r Record, emailKey string) bool {
+	if email := c.Query.Get("email"); email != "" && !strings.EqualFold(r.String(emailKey), email) {
+		return false
+	}
+
+	for _, k := range []string{"mobile_phone_number", "work_phone_number"} {
+		if v := c.Query.Get(k); v != "" && r.String(k) != v {
+			return false
+		}
+	}
+	return true
+}
+
+// syncEmail copies the email of the key to the other key, so that the agents and the requesters share the "users" collection.
+func syncEmail(key, other string) func(c *freshtest.Context, r Record, create bool) bool {
+	return func(c *freshtest.Context, r Record, create bool) bool {
+		if v, ok := r[key]; ok {
+			r[other] = v
+		}
+		if create {
+			name := strings.TrimSpace(r.String("first_name") + " " + r.String("last_name"))
+			r["name"] = name
+		}
+		return true
+	}
+}
+
+// activate returns a handler which sets the "active" of the user, writes the user for reactivation or 204 for deactivation.
+func activate(res *freshtest.Resource, active bool) freshtest.HandlerFunc {
+	return func(c *freshtest.Context) {
+		r := res.Find(c)
+		if r == nil {
+			return
+		}
+
+		r = c.Store().Update(res.Collection, r.ID(), Record{"active": active})
+		if active {
+			c.JSON(http.StatusOK, Record{res.Single: r})
+			return
+		}
+		c.NoContent()
+	}
+}
+
+func listMembers(c *freshtest.Context) {
+	if c.Store().Get("requester_groups", c.ID(0)) == nil {
+		c.NotFound()
+		return
+	}
+
+	rs := []Record{}
+	for _, m := range c.Store().Find("requester_group_members", func(m Record) bool {
+		return m.Int64("requester_group_id") == c.ID(0)
+	}) {
+		if u := c.Store().Get("users", m.Int64("requester_id")); u != nil {
+			rs = append(rs, u)
+		}
+	}
+
+	if rs, ok := c.Paginate(rs); ok {
+		c.JSON(http.StatusOK, Record{"requesters": rs})
+	}
+}
+
+func findMember(c *freshtest.Context) (Record, bool) {
+	g, u := c.Store().Get("requester_groups", c.ID(0)), c.Store().Get("users", c.ID(1))
+	if g == nil || u == nil || u.Bool("is_agent") {
+		c.NotFound()
+		return nil, false
+	}
+
+	if g.String("type") != "manual" {
+		c.Error(http.StatusBadRequest, &fresh.ResultError{Code: "invalid_group_type", Message: "Requesters can be added to or removed from manual requester groups only"})
+		return nil, false
+	}
+
+	ms := c.Store().Find("requester_group_members", func(m Record) bool {
+		return m.Int64("requester_group_id") == g.ID() && m.Int64("requester_id") == u.ID()
+	})
+	if len(ms) > 0 {
+		return ms[0], true
+	}
+	return nil, true
+}
+
+func addMember(c *freshtest.Context) {
+	m, ok := findMember(c)
+	if !ok {
+		return
+	}
+	if m == nil {
+		c.Store().Insert("requester_group_members", Record{"requester_group_id": c.ID(0), "requester_id": c.ID(1)})
+	}
+	c.NoContent()
+}
+
+func removeMember(c *freshtest.Context) {
+	m, ok := findMember(c)
+	if !ok {
+		return
+	}
+	if m == nil {
+		c.NotFound()
+		return
+	}
+	c.Store().Delete("requester_group_members", m.ID())
+	c.NoContent()
+}
+
+// validateServiceItem checks the category, and assigns the display_id of the new item.
+func validateServiceItem(c *freshtest.Context, r Record, create bool) bool {
+	if !exists("category_id", "service_categories")(c, r, create) {
+		return false
+	}
+
+	if create {
+		id := int64(1)
+		if is := c.Store().Find("service_items", nil); len(is) > 0 {
+			id = is[len(is)-1].ID() + 1
+		}
+		r["id"], r["display_id"] = id, id
+	}
+	return true
+}
+
+// validateArticle copies the category_id of the folder to the article.
+func validateArticle(c *freshtest.Context, r Record, create bool) bool {
+	if f := c.Store().Get("folders", r.Int64("folder_id")); f != nil {
+		r["category_id"] = f["category_id"]
+	}
+	return true
+}
+
+// briefTicket returns the ticket of the list, which has no description and no attachments.
+func briefTicket(r Record) Record {
+	b := r.Clone()
+	delete(b, "description")
+	delete(b, "description_text")
+	delete(b, "attachments")
+	return b
+}
+
+// briefArticle returns the article of the list, which has the attachment names but no description.
+func briefArticle(r Record) Record {
+	b := r.Clone()
+	delete(b, "description")
+
+	as, _ := r["attachments"].([]any)
+	ns := make([]any, 0, len(as))
+	for _, a := range as {
+		if ar, ok := freshtest.AsRecord(a); ok {
+			ns = append(ns, ar.String("name"))
+		}
+	}
+	b["attachments"] = ns
+	return b
+}
+
+// exists returns a validate function which checks the record of the key exists in the collection.
+func exists(key, coll string) func(c *freshtest.Context, r Record, create bool) bool {
+	return func(c *freshtest.Context, r Record, create bool) bool {
+		if _, ok := r[key]; !ok {
+			return true
+		}
+		if c.Store().Get(coll, r.Int64(key)) == nil {
+			c.Invalid(fresh.FieldError{Field: key, Message: "There is no record matching the given " + key, Code: "invalid_value"})
+			return false
+		}
+		return true
+	}
+}
+
+func matchTrash(c *freshtest.Context, r Record) bool {
+	return (c.Query.Get("filter") == "trash") == r.Bool("deleted")
+}
+
+func matchInt64(c *freshtest.Context, r Record, keys ...string) bool {
+	for _, k := range keys {
+		if v := c.Query.Get(k); v != "" && v != strconv.FormatInt(r.Int64(k), 10) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,268 @@
+package fstest
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/askasoft/gofresh/fresh"
+	"github.com/askasoft/gofresh/freshservice"
+)
+
+var ctxbg = context.Background()
+
+func testNewClient(s *Server) *freshservice.Client {
+	return &freshservice.Client{
+		Domain:  s.Domain,
+		APIKey:  s.APIKey,
+		BaseURL: s.URL,
+	}
+}
+
+func TestTicketsFilter(t *testing.T) {
+	fs := NewServer()
+	defer fs.Close()
+
+	fsv := testNewClient(fs)
+
+	for i, tp := range []freshservice.TicketPriority{freshservice.TicketPriorityLow, freshservice.TicketPriorityHigh, freshservice.TicketPriorityHigh} {
+		tc := &freshservice.TicketCreate{
+			Email:       "requester@example.com",
+			Subject:     "test",
+			Description: "description",
+			Priority:    tp,
+			CustomFields: map[string]any{
+				"seq": i + 1,
+			},
+		}
+		if _, err := fsv.CreateTicket(ctxbg, tc); err != nil {
+			t.Fatalf("ERROR: %v", err)
+		}
+	}
+
+	rs, _, err := fsv.ListRequesters(ctxbg, &freshservice.ListRequestersOption{Email: "requester@example.com"})
+	if err != nil || len(rs) != 1 {
+		t.Fatalf("ListRequesters() = %v, %v", rs, err)
+	}
+
+	fto, err := freshservice.NewFilterOption(freshservice.QueryTicketPriority(freshservice.TicketPriorityHigh))
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+
+	ts, _, err := fsv.FilterTickets(ctxbg, fto)
+	if err != nil || len(ts) != 2 {
+		t.Fatalf("FilterTickets(%q) = %v, %v", fto.Query, ts, err)
+	}
+
+	ts, _, err = fsv.FilterTickets(ctxbg, &freshservice.FilterTicketsOption{Query: "(seq:>3 AND priority:1) OR seq:<1"})
+	if err != nil || len(ts) != 1 || ts[0].Priority != freshservice.TicketPriorityLow {
+		t.Fatalf("FilterTickets() = %v, %v", ts, err)
+	}
+
+	_, _, err = fsv.FilterTickets(ctxbg, &freshservice.FilterTicketsOption{Query: "priority:"})
+	if !isStatus(err, http.StatusBadRequest) {
+		t.Fatalf("FilterTickets() = %v", err)
+	}
+
+	if err = fsv.DeleteTicket(ctxbg, ts[0].ID); err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	ts, _, err = fsv.ListTickets(ctxbg, nil)
+	if err != nil || len(ts) != 2 {
+		t.Fatalf("ListTickets() = %v, %v", ts, err)
+	}
+	if ts[0].Description != "" || ts[0].Attachments != nil {
+		t.Fatalf("ListTickets() = %v", ts[0])
+	}
+	ts, _, err = fsv.ListTickets(ctxbg, &freshservice.ListTicketsOption{Filter: "deleted"})
+	if err != nil || len(ts) != 1 {
+		t.Fatalf("ListTickets(deleted) = %v, %v", ts, err)
+	}
+}
+
+func TestUsers(t *testing.T) {
+	fs := NewServer()
+	defer fs.Close()
+
+	fsv := testNewClient(fs)
+
+	agent, err := fsv.CreateAgent(ctxbg, &freshservice.AgentCreate{FirstName: "Agent", LastName: "Smith", Email: "agent@example.com"})
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+
+	_, err = fsv.CreateAgent(ctxbg, &freshservice.AgentCreate{FirstName: "Agent", Email: "AGENT@example.com"})
+	if !isStatus(err, http.StatusConflict) {
+		t.Fatalf("CreateAgent(duplicate) = %v", err)
+	}
+
+	req, err := fsv.CreateRequester(ctxbg, &freshservice.RequesterCreate{FirstName: "John", LastName: "Doe", PrimaryEmail: "john@example.com"})
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+
+	as, _, err := fsv.FilterAgents(ctxbg, &freshservice.FilterAgentsOption{Query: "~[first_name|last_name]:'smi'"})
+	if err != nil || len(as) != 1 || as[0].ID != agent.ID {
+		t.Fatalf("FilterAgents() = %v, %v", as, err)
+	}
+
+	rs, _, err := fsv.ListRequesters(ctxbg, &freshservice.ListRequestersOption{Query: `"last_name:'doe'"`})
+	if err != nil || len(rs) != 1 || rs[0].ID != req.ID {
+		t.Fatalf("ListRequesters() = %v, %v", rs, err)
+	}
+
+	if _, err = fsv.GetRequester(ctxbg, agent.ID); !isStatus(err, http.StatusNotFound) {
+		t.Fatalf("GetRequester(agent) = %v", err)
+	}
+
+	if err = fsv.DeactivateRequester(ctxbg, req.ID); err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	if req, err = fsv.GetRequester(ctxbg, req.ID); err != nil || req.Active {
+		t.Fatalf("GetRequester() = %v, %v", req, err)
+	}
+
+	rg, err := fsv.CreateRequesterGroup(ctxbg, &freshservice.RequesterGroup{Name: "Group"})
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	if err = fsv.AddRequesterToRequesterGroup(ctxbg, rg.ID, req.ID); err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	if err = fsv.AddRequesterToRequesterGroup(ctxbg, rg.ID, agent.ID); !isStatus(err, http.StatusNotFound) {
+		t.Fatalf("AddRequesterToRequesterGroup(agent) = %v", err)
+	}
+
+	ms, _, err := fsv.ListRequesterGroupMembers(ctxbg, rg.ID, nil)
+	if err != nil || len(ms) != 1 || ms[0].ID != req.ID {
+		t.Fatalf("ListRequesterGroupMembers() = %v, %v", ms, err)
+	}
+
+	if err = fsv.DeleteRequesterFromRequesterGroup(ctxbg, rg.ID, req.ID); err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+
+	ca, err := fsv.ConvertRequesterToAgent(ctxbg, req.ID)
+	if err != nil || ca.ID != req.ID || !ca.Occasional {
+		t.Fatalf("ConvertRequesterToAgent() = %v, %v", ca, err)
+	}
+
+	as, _, err = fsv.ListAgents(ctxbg, &freshservice.ListAgentsOption{State: freshservice.AgentStateOccasional})
+	if err != nil || len(as) != 1 || as[0].ID != req.ID {
+		t.Fatalf("ListAgents(occasional) = %v, %v", as, err)
+	}
+}
+
+func TestApprovals(t *testing.T) {
+	fs := NewServer()
+	defer fs.Close()
+
+	fsv := testNewClient(fs)
+
+	ticket, err := fsv.CreateTicket(ctxbg, &freshservice.TicketCreate{Email: "requester@example.com", Subject: "test", Description: "test"})
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+
+	ap, err := fsv.RequestTicketApproval(ctxbg, ticket.ID, &freshservice.Approval{ApproverID: 100, ApprovalType: freshservice.ApprovalTypeAnyone})
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	if ap.ParentID != ticket.ID || ap.ApprovalStatus == nil || ap.ApprovalStatus.Name != freshservice.ApprovalStatusRequested.String() {
+		t.Fatalf("RequestTicketApproval() = %v", ap)
+	}
+
+	lao := &freshservice.ListApprovalsOption{Parent: "ticket", Status: freshservice.ApprovalStatusRequested.String()}
+	aps, _, err := fsv.ListApprovals(ctxbg, lao)
+	if err != nil || len(aps) != 1 {
+		t.Fatalf("ListApprovals() = %v, %v", aps, err)
+	}
+
+	if _, _, err = fsv.ListApprovals(ctxbg, nil); !isStatus(err, http.StatusBadRequest) {
+		t.Fatalf("ListApprovals(nil) = %v", err)
+	}
+
+	ap, err = fsv.CancelTicketApproval(ctxbg, ticket.ID, ap.ID)
+	if err != nil || ap.ApprovalStatus.Name != freshservice.ApprovalStatusCanceled.String() {
+		t.Fatalf("CancelTicketApproval() = %v, %v", ap, err)
+	}
+
+	aps, _, err = fsv.ListApprovals(ctxbg, lao)
+	if err != nil || len(aps) != 0 {
+		t.Fatalf("ListApprovals() = %v, %v", aps, err)
+	}
+
+	ri, err := fsv.AddCatelogItemToTicket(ctxbg, ticket.ID, freshservice.CatalogItem{ItemID: 1})
+	if err != nil || ri.ServiceItemID != 1 {
+		t.Fatalf("AddCatelogItemToTicket() = %v, %v", ri, err)
+	}
+	if _, err = fsv.AddCatelogItemToTicket(ctxbg, ticket.ID, freshservice.CatalogItem{ItemID: 9}); !isStatus(err, http.StatusBadRequest) {
+		t.Fatalf("AddCatelogItemToTicket(9) = %v", err)
+	}
+}
+
+func TestSolutionsTrash(t *testing.T) {
+	fs := NewServer()
+	defer fs.Close()
+
+	fsv := testNewClient(fs)
+
+	cat, err := fsv.CreateCategory(ctxbg, &freshservice.CategoryCreate{Name: "Category"})
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+
+	if _, err = fsv.CreateFolder(ctxbg, &freshservice.FolderCreate{CategoryID: cat.ID + 1, Name: "Folder"}); !isStatus(err, http.StatusBadRequest) {
+		t.Fatalf("CreateFolder(invalid category) = %v", err)
+	}
+
+	if err = fsv.DeleteCategory(ctxbg, cat.ID); err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+
+	cs, _, err := fsv.ListCategories(ctxbg, nil)
+	if err != nil || len(cs) != 0 {
+		t.Fatalf("ListCategories() = %v, %v", cs, err)
+	}
+	cs, _, err = fsv.ListCategories(ctxbg, &freshservice.ListCategoriesOption{Trash: true})
+	if err != nil || len(cs) != 1 {
+		t.Fatalf("ListCategories(trash) = %v, %v", cs, err)
+	}
+
+	if err = fsv.RestoreCategory(ctxbg, cat.ID); err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	if err = fsv.PermanentDeleteCategory(ctxbg, cat.ID); err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	if _, err = fsv.GetCategory(ctxbg, cat.ID); !isStatus(err, http.StatusNotFound) {
+		t.Fatalf("GetCategory() = %v", err)
+	}
+}
+
+func TestAuthAndThrottle(t *testing.T) {
+	fs := NewServer()
+	defer fs.Close()
+
+	fsv := testNewClient(fs)
+	fsv.APIKey = "invalid"
+
+	_, _, err := fsv.ListTickets(ctxbg, nil)
+	if re, ok := fresh.AsResultError(err); !ok || re.StatusCode != http.StatusUnauthorized {
+		t.Errorf("ListTickets() = %v", err)
+	}
+
+	fsv = testNewClient(fs)
+	fs.Throttle(1, time.Millisecond)
+	fsv.Retryer = freshservice.NewRetryer(time.Millisecond, 1, nil)
+	if _, _, err = fsv.ListWorkspaces(ctxbg, nil); err != nil {
+		t.Errorf("ListWorkspaces() = %v", err)
+	}
+}
+
+func isStatus(err error, status int) bool {
+	re, ok := fresh.AsResultError(err)
+	return ok && re.StatusCode == status
+}
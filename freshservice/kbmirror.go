@@ -0,0 +1,184 @@
+package freshservice
+
+import (
+	"context"
+	"errors"
+	"iter"
+
+	"github.com/askasoft/gofresh/fresh/kbsync"
+)
+
+// NewKBMirror returns a kbsync.Mirror of the solutions in the directory dir.
+// Freshservice has no sub folders and no translation api, so the folders are mirrored in the categories only,
+// and the articles are mirrored in their own languages.
+// The visibility of a folder is pushed only if it needs no department/group ids,
+// and a new folder without visibility is visible to the agents only.
+func (c *Client) NewKBMirror(dir string) *kbsync.Mirror {
+	return kbsync.NewMirror(&kbRemote{c}, dir)
+}
+
+// kbRemote implements the kbsync.Remote
+type kbRemote struct {
+	c *Client
+}
+
+func (kr *kbRemote) Categories(ctx context.Context) iter.Seq2[*kbsync.Document, error] {
+	return kbsync.Convert(kr.c.AllCategories(ctx, nil), categoryDocument)
+}
+
+func (kr *kbRemote) Folders(ctx context.Context, cid int64) iter.Seq2[*kbsync.Document, error] {
+	return kbsync.Convert(kr.c.AllFolders(ctx, &ListFoldersOption{CategoryID: cid}), folderDocument)
+}
+
+func (kr *kbRemote) SubFolders(ctx context.Context, fid int64) iter.Seq2[*kbsync.Document, error] {
+	return func(yield func(*kbsync.Document, error) bool) {}
+}
+
+// Articles iterates the articles of the folder, the articles are got one by one,
+// since the listed articles have no description.
+func (kr *kbRemote) Articles(ctx context.Context, fid int64) iter.Seq2[*kbsync.Document, error] {
+	return func(yield func(*kbsync.Document, error) bool) {
+		for ai, err := range kr.c.AllArticles(ctx, &ListArticlesOption{FolderID: fid}) {
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+
+			a, err := kr.c.GetArticle(ctx, ai.ID)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if !yield(articleDocument(a), nil) {
+				return
+			}
+		}
+	}
+}
+
+func (kr *kbRemote) Translation(ctx context.Context, aid int64, lang string) (*kbsync.Document, error) {
+	return nil, nil
+}
+
+func (kr *kbRemote) Save(ctx context.Context, d, parent *kbsync.Document) (*kbsync.Document, error) {
+	switch d.Kind {
+	case kbsync.KindCategory:
+		return kr.saveCategory(ctx, d)
+	case kbsync.KindFolder:
+		return kr.saveFolder(ctx, d, parent)
+	default:
+		return kr.saveArticle(ctx, d, parent)
+	}
+}
+
+func (kr *kbRemote) saveCategory(ctx context.Context, d *kbsync.Document) (*kbsync.Document, error) {
+	cc := &CategoryCreate{Name: d.Title, Description: d.Body}
+
+	var c *Category
+	var err error
+	if d.ID == 0 {
+		c, err = kr.c.CreateCategory(ctx, cc)
+	} else {
+		c, err = kr.c.UpdateCategory(ctx, d.ID, cc)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return categoryDocument(c), nil
+}
+
+func (kr *kbRemote) saveFolder(ctx context.Context, d, parent *kbsync.Document) (*kbsync.Document, error) {
+	if parent.Kind != kbsync.KindCategory {
+		return nil, errors.New("freshservice: sub folder is not supported")
+	}
+
+	fc := &FolderCreate{Name: d.Title, Description: d.Body, CategoryID: parent.ID}
+	if v := FolderVisibility(d.Visibility); v >= FolderVisibilityAllUsers && v <= FolderVisibilityAgents {
+		fc.Visibility = v
+	}
+
+	var f *Folder
+	var err error
+	if d.ID == 0 {
+		if fc.Visibility == 0 {
+			fc.Visibility = FolderVisibilityAgents
+		}
+		f, err = kr.c.CreateFolder(ctx, fc)
+	} else {
+		f, err = kr.c.UpdateFolder(ctx, d.ID, fc)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return folderDocument(f), nil
+}
+
+func (kr *kbRemote) saveArticle(ctx context.Context, d, parent *kbsync.Document) (*kbsync.Document, error) {
+	tags, keywords := d.Tags, d.Keywords
+	if tags == nil {
+		tags = []string{}
+	}
+	if keywords == nil {
+		keywords = []string{}
+	}
+
+	ac := &ArticleCreate{
+		Title:       d.Title,
+		Description: d.Body,
+		ArticleType: ArticleType(d.Type),
+		FolderID:    parent.ID,
+		Status:      ArticleStatus(d.Status),
+		Tags:        &tags,
+		Keywords:    &keywords,
+	}
+	if ac.ArticleType == 0 {
+		ac.ArticleType = ArticleTypePermanent
+	}
+	if ac.Status == 0 {
+		ac.Status = ArticleStatusDraft
+	}
+
+	var a *Article
+	var err error
+	if d.ID == 0 {
+		a, err = kr.c.CreateArticle(ctx, ac)
+	} else {
+		a, err = kr.c.UpdateArticle(ctx, d.ID, ac)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return articleDocument(a), nil
+}
+
+func categoryDocument(c *Category) *kbsync.Document {
+	return &kbsync.Document{
+		Kind:  kbsync.KindCategory,
+		ID:    c.ID,
+		Title: c.Name,
+		Body:  c.Description,
+	}
+}
+
+func folderDocument(f *Folder) *kbsync.Document {
+	return &kbsync.Document{
+		Kind:       kbsync.KindFolder,
+		ID:         f.ID,
+		Title:      f.Name,
+		Visibility: int(f.Visibility),
+		Body:       f.Description,
+	}
+}
+
+func articleDocument(a *Article) *kbsync.Document {
+	return &kbsync.Document{
+		Kind:     kbsync.KindArticle,
+		ID:       a.ID,
+		Title:    a.Title,
+		Status:   int(a.Status),
+		Type:     int(a.ArticleType),
+		Tags:     a.Tags,
+		Keywords: a.Keywords,
+		Body:     a.Description,
+	}
+}
@@ -0,0 +1,94 @@
+package freshservice
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/askasoft/gofresh/freshservice/fstest"
+)
+
+func TestKBMirror(t *testing.T) {
+	fs := fstest.NewServer()
+	defer fs.Close()
+
+	fsv := &Client{Domain: fs.Domain, APIKey: fs.APIKey, BaseURL: fs.URL}
+
+	category, err := fsv.CreateCategory(ctxbg, &CategoryCreate{Name: "FAQ"})
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	folder, err := fsv.CreateFolder(ctxbg, &FolderCreate{Name: "General", CategoryID: category.ID, Visibility: FolderVisibilityAllUsers})
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	keywords := []string{"kb"}
+	article, err := fsv.CreateArticle(ctxbg, &ArticleCreate{
+		Title:       "How to",
+		Description: "<p>how to</p>",
+		FolderID:    folder.ID,
+		ArticleType: ArticleTypeWorkaround,
+		Status:      ArticleStatusPublished,
+		Keywords:    &keywords,
+	})
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+
+	dir := t.TempDir()
+	m := fsv.NewKBMirror(dir)
+
+	res, err := m.Pull(ctxbg)
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	if len(res.Created) != 3 {
+		t.Fatalf("Pull() = %+v", res)
+	}
+
+	fdir := filepath.Dir(filepath.Join(dir, res.Created[1]))
+	apath := filepath.Join(dir, res.Created[2])
+	bs, err := os.ReadFile(apath)
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	for _, s := range []string{"type: 2\n", "keywords: [\"kb\"]\n", "<p>how to</p>"} {
+		if !strings.Contains(string(bs), s) {
+			t.Errorf("%s does not contain %q:\n%s", apath, s, bs)
+		}
+	}
+
+	// edit the article, add an article
+	if err := os.WriteFile(apath, []byte(strings.Replace(string(bs), "<p>how to</p>", "<p>how to (edited)</p>", 1)), 0o660); err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(fdir, "new.html"), []byte("---\ntitle: New\n---\n<p>new</p>\n"), 0o660); err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+
+	if res, err = m.Push(ctxbg); err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	if len(res.Created) != 1 || len(res.Updated) != 1 || res.Unchanged != 2 {
+		t.Fatalf("Push() = %+v", res)
+	}
+
+	article, err = fsv.GetArticle(ctxbg, article.ID)
+	if err != nil || article.Description != "<p>how to (edited)</p>" || article.ArticleType != ArticleTypeWorkaround {
+		t.Fatalf("GetArticle() = %v, %v", article, err)
+	}
+
+	articles, _, err := fsv.ListArticles(ctxbg, &ListArticlesOption{FolderID: folder.ID})
+	if err != nil || len(articles) != 2 || articles[1].Title != "New" || articles[1].Status != ArticleStatusDraft {
+		t.Fatalf("ListArticles() = %v, %v", articles, err)
+	}
+
+	// the pushed files are up to date
+	if res, err = m.Pull(ctxbg); err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	if res.Unchanged != 4 || len(res.Created)+len(res.Updated)+len(res.Conflicts) != 0 {
+		t.Fatalf("Pull() = %+v", res)
+	}
+}
@@ -2,8 +2,10 @@ package freshservice
 
 import (
 	"context"
+	"iter"
 	"strings"
 
+	"github.com/askasoft/gofresh/fresh"
 	"github.com/askasoft/pango/asg"
 )
 
@@ -60,39 +62,27 @@ func (c *Client) GetRequesterGroup(ctx context.Context, id int64) (*RequesterGro
 }
 
 func (c *Client) ListRequesterGroups(ctx context.Context, lrgo *ListRequesterGroupsOption) ([]*RequesterGroup, bool, error) {
+	return hasNext(c.listRequesterGroups(ctx, lrgo))
+}
+
+func (c *Client) listRequesterGroups(ctx context.Context, lo ListOption) ([]*RequesterGroup, string, error) {
 	url := c.Endpoint("/requester_groups")
 	result := &requesterGroupsResult{}
-	next, err := c.DoList(ctx, url, lrgo, result)
+	next, err := c.DoListLink(ctx, url, lo, result)
 	return result.RequesterGroups, next, err
 }
 
 func (c *Client) IterRequesterGroups(ctx context.Context, lrgo *ListRequesterGroupsOption, irgf func(*RequesterGroup) error) error {
-	if lrgo == nil {
-		lrgo = &ListRequesterGroupsOption{}
-	}
-	if lrgo.Page < 1 {
-		lrgo.Page = 1
-	}
-	if lrgo.PerPage < 1 {
-		lrgo.PerPage = 100
-	}
+	return c.requesterGroupsPaginator().Iter(ctx, lrgo, irgf)
+}
 
-	for {
-		rgs, next, err := c.ListRequesterGroups(ctx, lrgo)
-		if err != nil {
-			return err
-		}
-		for _, rg := range rgs {
-			if err = irgf(rg); err != nil {
-				return err
-			}
-		}
-		if !next {
-			break
-		}
-		lrgo.Page++
-	}
-	return nil
+// AllRequesterGroups is like IterRequesterGroups but returns an iterator, the lrgo will not be modified.
+func (c *Client) AllRequesterGroups(ctx context.Context, lrgo *ListRequesterGroupsOption) iter.Seq2[*RequesterGroup, error] {
+	return c.requesterGroupsPaginator().All(ctx, lrgo)
+}
+
+func (c *Client) requesterGroupsPaginator() *fresh.Paginator[*RequesterGroup] {
+	return newPaginator(c.listRequesterGroups)
 }
 
 // Note:
@@ -134,39 +124,29 @@ func (c *Client) DeleteRequesterFromRequesterGroup(ctx context.Context, rgid, ri
 }
 
 func (c *Client) ListRequesterGroupMembers(ctx context.Context, rgid int64, lrgmo *ListRequesterGroupMembersOption) ([]*Requester, bool, error) {
+	return hasNext(c.listRequesterGroupMembers(ctx, rgid, lrgmo))
+}
+
+func (c *Client) listRequesterGroupMembers(ctx context.Context, rgid int64, lo ListOption) ([]*Requester, string, error) {
 	url := c.Endpoint("/requester_groups/%d/members", rgid)
 	result := &requestersResult{}
-	next, err := c.DoList(ctx, url, lrgmo, result)
+	next, err := c.DoListLink(ctx, url, lo, result)
 	return result.Requesters, next, err
 }
 
 func (c *Client) IterRequesterGroupMembers(ctx context.Context, rgid int64, lrgmo *ListRequesterGroupMembersOption, irgmf func(*Requester) error) error {
-	if lrgmo == nil {
-		lrgmo = &ListRequesterGroupMembersOption{}
-	}
-	if lrgmo.Page < 1 {
-		lrgmo.Page = 1
-	}
-	if lrgmo.PerPage < 1 {
-		lrgmo.PerPage = 100
-	}
+	return c.requesterGroupMembersPaginator(rgid).Iter(ctx, lrgmo, irgmf)
+}
 
-	for {
-		rs, next, err := c.ListRequesterGroupMembers(ctx, rgid, lrgmo)
-		if err != nil {
-			return err
-		}
-		for _, r := range rs {
-			if err = irgmf(r); err != nil {
-				return err
-			}
-		}
-		if !next {
-			break
-		}
-		lrgmo.Page++
-	}
-	return nil
+// AllRequesterGroupMembers is like IterRequesterGroupMembers but returns an iterator, the lrgmo will not be modified.
+func (c *Client) AllRequesterGroupMembers(ctx context.Context, rgid int64, lrgmo *ListRequesterGroupMembersOption) iter.Seq2[*Requester, error] {
+	return c.requesterGroupMembersPaginator(rgid).All(ctx, lrgmo)
+}
+
+func (c *Client) requesterGroupMembersPaginator(rgid int64) *fresh.Paginator[*Requester] {
+	return newPaginator(func(ctx context.Context, lo ListOption) ([]*Requester, string, error) {
+		return c.listRequesterGroupMembers(ctx, rgid, lo)
+	})
 }
 
 func (c *Client) CreateRequester(ctx context.Context, requester *RequesterCreate) (*Requester, error) {
@@ -227,39 +207,67 @@ func (c *Client) GetRequester(ctx context.Context, id int64) (*Requester, error)
 // Date	date
 // Phone number	string
 func (c *Client) ListRequesters(ctx context.Context, lro *ListRequestersOption) ([]*Requester, bool, error) {
+	return hasNext(c.listRequesters(ctx, lro))
+}
+
+func (c *Client) listRequesters(ctx context.Context, lo ListOption) ([]*Requester, string, error) {
 	url := c.Endpoint("/requesters")
 	result := &requestersResult{}
-	next, err := c.DoList(ctx, url, lro, result)
+	next, err := c.DoListLink(ctx, url, lo, result)
 	return result.Requesters, next, err
 }
 
 func (c *Client) IterRequesters(ctx context.Context, lro *ListRequestersOption, irf func(*Requester) error) error {
-	if lro == nil {
-		lro = &ListRequestersOption{}
-	}
-	if lro.Page < 1 {
-		lro.Page = 1
+	return c.requestersPaginator().Iter(ctx, lro, irf)
+}
+
+// AllRequesters is like IterRequesters but returns an iterator, the lro will not be modified.
+func (c *Client) AllRequesters(ctx context.Context, lro *ListRequestersOption) iter.Seq2[*Requester, error] {
+	return c.requestersPaginator().All(ctx, lro)
+}
+
+func (c *Client) requestersPaginator() *fresh.Paginator[*Requester] {
+	return newPaginator(c.listRequesters)
+}
+
+// AllRequesterPages returns an iterator over all the pages of requesters, the lro will not be modified.
+// The Page.Next can be saved to resume the iteration by AllRequesterPagesFrom.
+func (c *Client) AllRequesterPages(ctx context.Context, lro *ListRequestersOption) iter.Seq2[*Page[*Requester], error] {
+	return c.requestersPaginator().Pages(ctx, lro)
+}
+
+// AllRequesterPagesFrom returns an iterator over the pages of requesters starting from the next URL (Page.Next).
+func (c *Client) AllRequesterPagesFrom(ctx context.Context, next string) iter.Seq2[*Page[*Requester], error] {
+	return c.requestersPaginator().PagesFrom(ctx, next)
+}
+
+// IterRequestersConcurrently iterates the requesters by concurrent page requests, the requesters are delivered in order.
+// The Freshservice requesters api does not support ordering by updated_at, so the cco.Window is not used.
+// The lro.Page is ignored, and the lro will not be modified.
+func (c *Client) IterRequestersConcurrently(ctx context.Context, lro *ListRequestersOption, cco *ConcurrentOption, irf func(*Requester) error) error {
+	return newConcurrent[*Requester](cco, 0).Iter(ctx, c.requestersQueries(lro), irf)
+}
+
+// AllRequestersConcurrently is like IterRequestersConcurrently but returns an iterator.
+func (c *Client) AllRequestersConcurrently(ctx context.Context, lro *ListRequestersOption, cco *ConcurrentOption) iter.Seq2[*Requester, error] {
+	return newConcurrent[*Requester](cco, 0).All(ctx, c.requestersQueries(lro))
+}
+
+func (c *Client) requestersQueries(lro *ListRequestersOption) []fresh.QueryPageFunc[*Requester] {
+	base := ListRequestersOption{PerPage: 100}
+	if lro != nil {
+		base = *lro
 	}
-	if lro.PerPage < 1 {
-		lro.PerPage = 100
+	if base.PerPage < 1 {
+		base.PerPage = 100
 	}
 
-	for {
-		rs, next, err := c.ListRequesters(ctx, lro)
-		if err != nil {
-			return err
-		}
-		for _, r := range rs {
-			if err = irf(r); err != nil {
-				return err
-			}
-		}
-		if !next {
-			break
-		}
-		lro.Page++
+	qp := func(ctx context.Context, page int) ([]*Requester, bool, error) {
+		o := base
+		o.Page = page
+		return c.ListRequesters(ctx, &o)
 	}
-	return nil
+	return []fresh.QueryPageFunc[*Requester]{qp}
 }
 
 func (c *Client) GetRequesterFields(ctx context.Context, include ...string) ([]*RequesterField, error) {
@@ -1,6 +1,11 @@
 package freshservice
 
-import "context"
+import (
+	"context"
+	"iter"
+
+	"github.com/askasoft/gofresh/fresh"
+)
 
 func (c *Client) GetServiceItem(ctx context.Context, displayID int64) (*ServiceItem, error) {
 	url := c.Endpoint("/service_catalog/items/%d", displayID)
@@ -32,39 +37,27 @@ func (lio *ListServiceItemsOption) Values() Values {
 }
 
 func (c *Client) ListServiceItems(ctx context.Context, lio *ListServiceItemsOption) ([]*ServiceItem, bool, error) {
+	return hasNext(c.listServiceItems(ctx, lio))
+}
+
+func (c *Client) listServiceItems(ctx context.Context, lo ListOption) ([]*ServiceItem, string, error) {
 	url := c.Endpoint("/service_catalog/items")
 	result := &serviceItemsResult{}
-	next, err := c.DoList(ctx, url, lio, result)
+	next, err := c.DoListLink(ctx, url, lo, result)
 	return result.ServiceItems, next, err
 }
 
 func (c *Client) IterServiceItems(ctx context.Context, lio *ListServiceItemsOption, iif func(*ServiceItem) error) error {
-	if lio == nil {
-		lio = &ListServiceItemsOption{}
-	}
-	if lio.Page < 1 {
-		lio.Page = 1
-	}
-	if lio.PerPage < 1 {
-		lio.PerPage = 30
-	}
+	return c.serviceItemsPaginator().Iter(ctx, lio, iif)
+}
 
-	for {
-		items, next, err := c.ListServiceItems(ctx, lio)
-		if err != nil {
-			return err
-		}
-		for _, item := range items {
-			if err = iif(item); err != nil {
-				return err
-			}
-		}
-		if !next {
-			break
-		}
-		lio.Page++
-	}
-	return nil
+// AllServiceItems is like IterServiceItems but returns an iterator, the lio will not be modified.
+func (c *Client) AllServiceItems(ctx context.Context, lio *ListServiceItemsOption) iter.Seq2[*ServiceItem, error] {
+	return c.serviceItemsPaginator().All(ctx, lio)
+}
+
+func (c *Client) serviceItemsPaginator() *fresh.Paginator[*ServiceItem] {
+	return newPaginator(c.listServiceItems)
 }
 
 type SearchServiceItemsOption struct {
@@ -136,37 +129,25 @@ func (lco *ListServiceCategoriesOption) Values() Values {
 }
 
 func (c *Client) ListServiceCategories(ctx context.Context, lco *ListServiceCategoriesOption) ([]*ServiceCategory, bool, error) {
+	return hasNext(c.listServiceCategories(ctx, lco))
+}
+
+func (c *Client) listServiceCategories(ctx context.Context, lo ListOption) ([]*ServiceCategory, string, error) {
 	url := c.Endpoint("/service_catalog/categories")
 	result := &serviceCategoriesResult{}
-	next, err := c.DoList(ctx, url, lco, result)
+	next, err := c.DoListLink(ctx, url, lo, result)
 	return result.ServiceCategories, next, err
 }
 
 func (c *Client) IterServiceCategories(ctx context.Context, lco *ListServiceCategoriesOption, icf func(*ServiceCategory) error) error {
-	if lco == nil {
-		lco = &ListServiceCategoriesOption{}
-	}
-	if lco.Page < 1 {
-		lco.Page = 1
-	}
-	if lco.PerPage < 1 {
-		lco.PerPage = 30
-	}
+	return c.serviceCategoriesPaginator().Iter(ctx, lco, icf)
+}
 
-	for {
-		scs, next, err := c.ListServiceCategories(ctx, lco)
-		if err != nil {
-			return err
-		}
-		for _, c := range scs {
-			if err = icf(c); err != nil {
-				return err
-			}
-		}
-		if !next {
-			break
-		}
-		lco.Page++
-	}
-	return nil
+// AllServiceCategories is like IterServiceCategories but returns an iterator, the lco will not be modified.
+func (c *Client) AllServiceCategories(ctx context.Context, lco *ListServiceCategoriesOption) iter.Seq2[*ServiceCategory, error] {
+	return c.serviceCategoriesPaginator().All(ctx, lco)
+}
+
+func (c *Client) serviceCategoriesPaginator() *fresh.Paginator[*ServiceCategory] {
+	return newPaginator(c.listServiceCategories)
 }
@@ -2,7 +2,9 @@ package freshservice
 
 import (
 	"context"
+	"iter"
 
+	"github.com/askasoft/gofresh/fresh"
 	"github.com/askasoft/pango/asg"
 )
 
@@ -124,39 +126,27 @@ func (c *Client) GetCategory(ctx context.Context, cid int64) (*Category, error)
 }
 
 func (c *Client) ListCategories(ctx context.Context, lco *ListCategoriesOption) ([]*Category, bool, error) {
+	return hasNext(c.listCategories(ctx, lco))
+}
+
+func (c *Client) listCategories(ctx context.Context, lo ListOption) ([]*Category, string, error) {
 	url := c.Endpoint("/solutions/categories")
 	result := &categoriesResult{}
-	next, err := c.DoList(ctx, url, lco, result)
+	next, err := c.DoListLink(ctx, url, lo, result)
 	return result.Categories, next, err
 }
 
 func (c *Client) IterCategories(ctx context.Context, lco *ListCategoriesOption, icf func(*Category) error) error {
-	if lco == nil {
-		lco = &ListCategoriesOption{}
-	}
-	if lco.Page < 1 {
-		lco.Page = 1
-	}
-	if lco.PerPage < 1 {
-		lco.PerPage = 100
-	}
+	return c.categoriesPaginator().Iter(ctx, lco, icf)
+}
 
-	for {
-		categories, next, err := c.ListCategories(ctx, lco)
-		if err != nil {
-			return err
-		}
-		for _, c := range categories {
-			if err = icf(c); err != nil {
-				return err
-			}
-		}
-		if !next {
-			break
-		}
-		lco.Page++
-	}
-	return nil
+// AllCategories is like IterCategories but returns an iterator, the lco will not be modified.
+func (c *Client) AllCategories(ctx context.Context, lco *ListCategoriesOption) iter.Seq2[*Category, error] {
+	return c.categoriesPaginator().All(ctx, lco)
+}
+
+func (c *Client) categoriesPaginator() *fresh.Paginator[*Category] {
+	return newPaginator(c.listCategories)
 }
 
 func (c *Client) DeleteCategory(ctx context.Context, cid int64) error {
@@ -200,39 +190,27 @@ func (c *Client) GetFolder(ctx context.Context, fid int64) (*Folder, error) {
 }
 
 func (c *Client) ListFolders(ctx context.Context, lfo *ListFoldersOption) ([]*Folder, bool, error) {
+	return hasNext(c.listFolders(ctx, lfo))
+}
+
+func (c *Client) listFolders(ctx context.Context, lo ListOption) ([]*Folder, string, error) {
 	url := c.Endpoint("/solutions/folders")
 	result := &foldersResult{}
-	next, err := c.DoList(ctx, url, lfo, result)
+	next, err := c.DoListLink(ctx, url, lo, result)
 	return result.Folders, next, err
 }
 
 func (c *Client) IterFolders(ctx context.Context, lfo *ListFoldersOption, iff func(*Folder) error) error {
-	if lfo == nil {
-		lfo = &ListFoldersOption{}
-	}
-	if lfo.Page < 1 {
-		lfo.Page = 1
-	}
-	if lfo.PerPage < 1 {
-		lfo.PerPage = 100
-	}
+	return c.foldersPaginator().Iter(ctx, lfo, iff)
+}
 
-	for {
-		folders, next, err := c.ListFolders(ctx, lfo)
-		if err != nil {
-			return err
-		}
-		for _, f := range folders {
-			if err = iff(f); err != nil {
-				return err
-			}
-		}
-		if !next {
-			break
-		}
-		lfo.Page++
-	}
-	return nil
+// AllFolders is like IterFolders but returns an iterator, the lfo will not be modified.
+func (c *Client) AllFolders(ctx context.Context, lfo *ListFoldersOption) iter.Seq2[*Folder, error] {
+	return c.foldersPaginator().All(ctx, lfo)
+}
+
+func (c *Client) foldersPaginator() *fresh.Paginator[*Folder] {
+	return newPaginator(c.listFolders)
 }
 
 func (c *Client) DeleteFolder(ctx context.Context, fid int64) error {
@@ -285,9 +263,13 @@ func (c *Client) GetArticle(ctx context.Context, aid int64) (*Article, error) {
 }
 
 func (c *Client) ListArticles(ctx context.Context, lao *ListArticlesOption) ([]*ArticleInfo, bool, error) {
+	return hasNext(c.listArticles(ctx, lao))
+}
+
+func (c *Client) listArticles(ctx context.Context, lo ListOption) ([]*ArticleInfo, string, error) {
 	url := c.Endpoint("/solutions/articles")
 	result := &articlesResult{}
-	next, err := c.DoList(ctx, url, lao, result)
+	next, err := c.DoListLink(ctx, url, lo, result)
 	for _, ai := range result.Articles {
 		ai.normalize()
 	}
@@ -295,32 +277,16 @@ func (c *Client) ListArticles(ctx context.Context, lao *ListArticlesOption) ([]*
 }
 
 func (c *Client) IterArticles(ctx context.Context, lao *ListArticlesOption, iaf func(*ArticleInfo) error) error {
-	if lao == nil {
-		lao = &ListArticlesOption{}
-	}
-	if lao.Page < 1 {
-		lao.Page = 1
-	}
-	if lao.PerPage < 1 {
-		lao.PerPage = 100
-	}
+	return c.articlesPaginator().Iter(ctx, lao, iaf)
+}
 
-	for {
-		articles, next, err := c.ListArticles(ctx, lao)
-		if err != nil {
-			return err
-		}
-		for _, a := range articles {
-			if err = iaf(a); err != nil {
-				return err
-			}
-		}
-		if !next {
-			break
-		}
-		lao.Page++
-	}
-	return nil
+// AllArticles is like IterArticles but returns an iterator, the lao will not be modified.
+func (c *Client) AllArticles(ctx context.Context, lao *ListArticlesOption) iter.Seq2[*ArticleInfo, error] {
+	return c.articlesPaginator().All(ctx, lao)
+}
+
+func (c *Client) articlesPaginator() *fresh.Paginator[*ArticleInfo] {
+	return newPaginator(c.listArticles)
 }
 
 func (c *Client) DeleteArticle(ctx context.Context, aid int64) error {
@@ -0,0 +1,93 @@
+package freshservice
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/askasoft/gofresh/fresh/freshsync"
+	"github.com/askasoft/gofresh/freshservice/fstest"
+)
+
+func testSyncChanges[T any](t *testing.T, s *freshsync.Syncer[T], want ...string) {
+	t.Helper()
+
+	var cs []string
+	err := s.Sync(ctxbg, func(ctx context.Context, c *freshsync.Change[T]) error {
+		cs = append(cs, fmt.Sprintf("%s %s %d", c.Source, c.Type, c.ID))
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	if !slices.Equal(cs, want) {
+		t.Fatalf("Sync() = %q, want %q", cs, want)
+	}
+}
+
+func TestTicketSyncer(t *testing.T) {
+	fs := fstest.NewServer()
+	defer fs.Close()
+
+	now := time.Date(2024, 5, 6, 10, 0, 0, 0, time.UTC)
+	fs.SetNow(func() time.Time { return now })
+
+	fsv := &Client{Domain: fs.Domain, APIKey: fs.APIKey, BaseURL: fs.URL}
+
+	var tids []int64
+	for i := 0; i < 2; i++ {
+		ticket, err := fsv.CreateTicket(ctxbg, &TicketCreate{Email: "sync@example.com", Subject: "sync", Description: "sync"})
+		if err != nil {
+			t.Fatalf("ERROR: %v", err)
+		}
+		tids = append(tids, ticket.ID)
+	}
+
+	s := fsv.NewTicketSyncer(nil, &ListTicketsOption{OrderType: OrderAsc})
+	testSyncChanges(t, s, fmt.Sprintf("tickets created %d", tids[0]), fmt.Sprintf("tickets created %d", tids[1]))
+
+	now = now.Add(time.Second)
+	if _, err := fsv.UpdateTicket(ctxbg, tids[0], &TicketUpdate{Subject: "updated"}); err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	if err := fsv.DeleteTicket(ctxbg, tids[1]); err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+
+	testSyncChanges(t, s, fmt.Sprintf("tickets updated %d", tids[0]), fmt.Sprintf("deleted deleted %d", tids[1]))
+	testSyncChanges(t, s)
+}
+
+func TestRequesterSyncer(t *testing.T) {
+	fs := fstest.NewServer()
+	defer fs.Close()
+
+	now := time.Date(2024, 5, 6, 10, 0, 0, 0, time.UTC)
+	fs.SetNow(func() time.Time { return now })
+
+	fsv := &Client{Domain: fs.Domain, APIKey: fs.APIKey, BaseURL: fs.URL}
+
+	r1, err := fsv.CreateRequester(ctxbg, &RequesterCreate{FirstName: "Sync", PrimaryEmail: "sync1@example.com"})
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+
+	s := fsv.NewRequesterSyncer(freshsync.NewFileStore(t.TempDir()), nil)
+	testSyncChanges(t, s, fmt.Sprintf("requesters created %d", r1.ID))
+
+	// same timestamp as the checkpoint
+	r2, err := fsv.CreateRequester(ctxbg, &RequesterCreate{FirstName: "Sync", PrimaryEmail: "sync2@example.com"})
+	if err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	testSyncChanges(t, s, fmt.Sprintf("requesters created %d", r2.ID))
+
+	now = now.Add(24 * time.Hour)
+	if err = fsv.DeactivateRequester(ctxbg, r1.ID); err != nil {
+		t.Fatalf("ERROR: %v", err)
+	}
+	testSyncChanges(t, s, fmt.Sprintf("requesters updated %d", r1.ID))
+	testSyncChanges(t, s)
+}
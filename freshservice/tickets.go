@@ -2,7 +2,11 @@ package freshservice
 
 import (
 	"context"
+	"iter"
 	"strings"
+
+	"github.com/askasoft/gofresh/fresh"
+	"github.com/askasoft/gofresh/fresh/query"
 )
 
 // ---------------------------------------------------
@@ -59,6 +63,31 @@ func (lto *ListTicketsOption) Values() Values {
 
 type FilterTicketsOption = FilterOption
 
+// QueryTicketStatus returns a filter query condition that matches any of the ticket statuses.
+func QueryTicketStatus(tss ...TicketStatus) query.Cond {
+	return query.In(query.Number("status"), tss...)
+}
+
+// QueryTicketPriority returns a filter query condition that matches any of the ticket priorities.
+func QueryTicketPriority(tps ...TicketPriority) query.Cond {
+	return query.In(query.Number("priority"), tps...)
+}
+
+// QueryTicketUrgency returns a filter query condition that matches any of the ticket urgencies.
+func QueryTicketUrgency(tus ...TicketUrgency) query.Cond {
+	return query.In(query.Number("urgency"), tus...)
+}
+
+// QueryTicketImpact returns a filter query condition that matches any of the ticket impacts.
+func QueryTicketImpact(tis ...TicketImpact) query.Cond {
+	return query.In(query.Number("impact"), tis...)
+}
+
+// QueryTicketCreated returns a filter query condition that matches the tickets created in the date range [since, until].
+func QueryTicketCreated(since, until Date) query.Cond {
+	return query.Between(query.Date("created_at"), since, until)
+}
+
 // PerPage: 1 ~ 100, default: 30
 type ListConversationsOption = PageOption
 
@@ -132,39 +161,27 @@ func (c *Client) GetTicket(ctx context.Context, tid int64, include ...string) (*
 // 3. Date and date_time fields to be enclosed in single quotes('yyyy-mm-dd')
 // 4. only :> and :< are supported for date and date_time fields. Both fields expect input in the same format as 'yyyy-mm-dd'
 func (c *Client) FilterTickets(ctx context.Context, fto *FilterTicketsOption) ([]*Ticket, bool, error) {
+	return hasNext(c.filterTickets(ctx, fto))
+}
+
+func (c *Client) filterTickets(ctx context.Context, lo ListOption) ([]*Ticket, string, error) {
 	url := c.Endpoint("/tickets/filter")
 	result := &ticketResult{}
-	next, err := c.DoList(ctx, url, fto, result)
+	next, err := c.DoListLink(ctx, url, lo, result)
 	return result.Tickets, next, err
 }
 
 func (c *Client) IterFilterTickets(ctx context.Context, fto *FilterTicketsOption, itf func(*Ticket) error) error {
-	if fto == nil {
-		fto = &FilterTicketsOption{}
-	}
-	if fto.Page < 1 {
-		fto.Page = 1
-	}
-	if fto.PerPage < 1 {
-		fto.PerPage = 100
-	}
+	return c.filterTicketsPaginator().Iter(ctx, fto, itf)
+}
 
-	for {
-		tickets, next, err := c.FilterTickets(ctx, fto)
-		if err != nil {
-			return err
-		}
-		for _, t := range tickets {
-			if err = itf(t); err != nil {
-				return err
-			}
-		}
-		if !next {
-			break
-		}
-		fto.Page++
-	}
-	return nil
+// AllFilterTickets is like IterFilterTickets but returns an iterator, the fto will not be modified.
+func (c *Client) AllFilterTickets(ctx context.Context, fto *FilterTicketsOption) iter.Seq2[*Ticket, error] {
+	return c.filterTicketsPaginator().All(ctx, fto)
+}
+
+func (c *Client) filterTicketsPaginator() *fresh.Paginator[*Ticket] {
+	return newPaginator(c.filterTickets)
 }
 
 // List of Tickets
@@ -174,39 +191,67 @@ func (c *Client) IterFilterTickets(ctx context.Context, fto *FilterTicketsOption
 // 2. Use 'include' to embed additional details in the response. Each include will consume an additional 2 credits. For example if you embed the stats information you will be charged a total of 3 API credits (1 credit for the API call, and 2 credits for the additional stats embedding).
 // 3. By default, only tickets from the primary workspace will be returned for accounts with the 'Workspaces' feature enabled. For tickets from other workspaces, use the workspace_id filter.
 func (c *Client) ListTickets(ctx context.Context, lto *ListTicketsOption) ([]*Ticket, bool, error) {
+	return hasNext(c.listTickets(ctx, lto))
+}
+
+func (c *Client) listTickets(ctx context.Context, lo ListOption) ([]*Ticket, string, error) {
 	url := c.Endpoint("/tickets")
 	result := &ticketResult{}
-	next, err := c.DoList(ctx, url, lto, result)
+	next, err := c.DoListLink(ctx, url, lo, result)
 	return result.Tickets, next, err
 }
 
 func (c *Client) IterTickets(ctx context.Context, lto *ListTicketsOption, itf func(*Ticket) error) error {
-	if lto == nil {
-		lto = &ListTicketsOption{}
-	}
-	if lto.Page < 1 {
-		lto.Page = 1
+	return c.ticketsPaginator().Iter(ctx, lto, itf)
+}
+
+// AllTickets is like IterTickets but returns an iterator, the lto will not be modified.
+func (c *Client) AllTickets(ctx context.Context, lto *ListTicketsOption) iter.Seq2[*Ticket, error] {
+	return c.ticketsPaginator().All(ctx, lto)
+}
+
+func (c *Client) ticketsPaginator() *fresh.Paginator[*Ticket] {
+	return newPaginator(c.listTickets)
+}
+
+// AllTicketPages returns an iterator over all the pages of tickets, the lto will not be modified.
+// The Page.Next can be saved to resume the iteration by AllTicketPagesFrom.
+func (c *Client) AllTicketPages(ctx context.Context, lto *ListTicketsOption) iter.Seq2[*Page[*Ticket], error] {
+	return c.ticketsPaginator().Pages(ctx, lto)
+}
+
+// AllTicketPagesFrom returns an iterator over the pages of tickets starting from the next URL (Page.Next).
+func (c *Client) AllTicketPagesFrom(ctx context.Context, next string) iter.Seq2[*Page[*Ticket], error] {
+	return c.ticketsPaginator().PagesFrom(ctx, next)
+}
+
+// IterTicketsConcurrently iterates the tickets by concurrent page requests, the tickets are delivered in order.
+// The Freshservice api does not support ordering by updated_at, so the cco.Window is not used.
+// The lto.Page is ignored, and the lto will not be modified.
+func (c *Client) IterTicketsConcurrently(ctx context.Context, lto *ListTicketsOption, cco *ConcurrentOption, itf func(*Ticket) error) error {
+	return newConcurrent[*Ticket](cco, 0).Iter(ctx, c.ticketsQueries(lto), itf)
+}
+
+// AllTicketsConcurrently is like IterTicketsConcurrently but returns an iterator.
+func (c *Client) AllTicketsConcurrently(ctx context.Context, lto *ListTicketsOption, cco *ConcurrentOption) iter.Seq2[*Ticket, error] {
+	return newConcurrent[*Ticket](cco, 0).All(ctx, c.ticketsQueries(lto))
+}
+
+func (c *Client) ticketsQueries(lto *ListTicketsOption) []fresh.QueryPageFunc[*Ticket] {
+	base := ListTicketsOption{PerPage: 100}
+	if lto != nil {
+		base = *lto
 	}
-	if lto.PerPage < 1 {
-		lto.PerPage = 100
+	if base.PerPage < 1 {
+		base.PerPage = 100
 	}
 
-	for {
-		tickets, next, err := c.ListTickets(ctx, lto)
-		if err != nil {
-			return err
-		}
-		for _, t := range tickets {
-			if err = itf(t); err != nil {
-				return err
-			}
-		}
-		if !next {
-			break
-		}
-		lto.Page++
+	qp := func(ctx context.Context, page int) ([]*Ticket, bool, error) {
+		o := base
+		o.Page = page
+		return c.ListTickets(ctx, &o)
 	}
-	return nil
+	return []fresh.QueryPageFunc[*Ticket]{qp}
 }
 
 // Update a Ticket
@@ -315,39 +360,29 @@ func (c *Client) DeleteConversationAttachment(ctx context.Context, cid, aid int6
 }
 
 func (c *Client) ListTicketConversations(ctx context.Context, tid int64, lco *ListConversationsOption) ([]*Conversation, bool, error) {
+	return hasNext(c.listTicketConversations(ctx, tid, lco))
+}
+
+func (c *Client) listTicketConversations(ctx context.Context, tid int64, lo ListOption) ([]*Conversation, string, error) {
 	url := c.Endpoint("/tickets/%d/conversations", tid)
 	result := &conversationsResult{}
-	next, err := c.DoList(ctx, url, lco, result)
+	next, err := c.DoListLink(ctx, url, lo, result)
 	return result.Conversations, next, err
 }
 
 func (c *Client) IterTicketConversations(ctx context.Context, tid int64, lco *ListConversationsOption, icf func(*Conversation) error) error {
-	if lco == nil {
-		lco = &ListConversationsOption{}
-	}
-	if lco.Page < 1 {
-		lco.Page = 1
-	}
-	if lco.PerPage < 1 {
-		lco.PerPage = 100
-	}
+	return c.ticketConversationsPaginator(tid).Iter(ctx, lco, icf)
+}
 
-	for {
-		conversations, next, err := c.ListTicketConversations(ctx, tid, lco)
-		if err != nil {
-			return err
-		}
-		for _, c := range conversations {
-			if err = icf(c); err != nil {
-				return err
-			}
-		}
-		if !next {
-			break
-		}
-		lco.Page++
-	}
-	return nil
+// AllTicketConversations is like IterTicketConversations but returns an iterator, the lco will not be modified.
+func (c *Client) AllTicketConversations(ctx context.Context, tid int64, lco *ListConversationsOption) iter.Seq2[*Conversation, error] {
+	return c.ticketConversationsPaginator(tid).All(ctx, lco)
+}
+
+func (c *Client) ticketConversationsPaginator(tid int64) *fresh.Paginator[*Conversation] {
+	return newPaginator(func(ctx context.Context, lo ListOption) ([]*Conversation, string, error) {
+		return c.listTicketConversations(ctx, tid, lo)
+	})
 }
 
 // ---------------------------------------------------
@@ -397,41 +432,31 @@ func (c *Client) RequestTicketApproval(ctx context.Context, tid int64, approval
 }
 
 func (c *Client) ListTicketApprovals(ctx context.Context, tid int64, lao *ListTicketApprovalsOption) ([]*Approval, bool, error) {
+	return hasNext(c.listTicketApprovals(ctx, tid, lao))
+}
+
+func (c *Client) listTicketApprovals(ctx context.Context, tid int64, lo ListOption) ([]*Approval, string, error) {
 	url := c.Endpoint("/tickets/%d/approvals", tid)
 	result := &approvalsResult{}
-	next, err := c.DoList(ctx, url, lao, result)
+	next, err := c.DoListLink(ctx, url, lo, result)
 	return result.Approvals, next, err
 }
 
 type ListTicketApprovalsOption = PageOption
 
 func (c *Client) IterTicketApprovals(ctx context.Context, tid int64, lao *ListTicketApprovalsOption, iaf func(*Approval) error) error {
-	if lao == nil {
-		lao = &ListTicketApprovalsOption{}
-	}
-	if lao.Page < 1 {
-		lao.Page = 1
-	}
-	if lao.PerPage < 1 {
-		lao.PerPage = 100
-	}
+	return c.ticketApprovalsPaginator(tid).Iter(ctx, lao, iaf)
+}
 
-	for {
-		approvals, next, err := c.ListTicketApprovals(ctx, tid, lao)
-		if err != nil {
-			return err
-		}
-		for _, a := range approvals {
-			if err = iaf(a); err != nil {
-				return err
-			}
-		}
-		if !next {
-			break
-		}
-		lao.Page++
-	}
-	return nil
+// AllTicketApprovals is like IterTicketApprovals but returns an iterator, the lao will not be modified.
+func (c *Client) AllTicketApprovals(ctx context.Context, tid int64, lao *ListTicketApprovalsOption) iter.Seq2[*Approval, error] {
+	return c.ticketApprovalsPaginator(tid).All(ctx, lao)
+}
+
+func (c *Client) ticketApprovalsPaginator(tid int64) *fresh.Paginator[*Approval] {
+	return newPaginator(func(ctx context.Context, lo ListOption) ([]*Approval, string, error) {
+		return c.listTicketApprovals(ctx, tid, lo)
+	})
 }
 
 func (c *Client) GetTicketApproval(ctx context.Context, tid, aid int64) (*Approval, error) {
@@ -1,6 +1,11 @@
 package freshservice
 
-import "context"
+import (
+	"context"
+	"iter"
+
+	"github.com/askasoft/gofresh/fresh"
+)
 
 // ---------------------------------------------------
 // Time Entries
@@ -37,39 +42,29 @@ func (c *Client) GetTimeEntry(ctx context.Context, tid, teid int64) (*TimeEntry,
 // This API helps to view all time entries of a particular ticket.
 // GET  /api/v2/tickets/[ticket_id]/time_entries
 func (c *Client) ListTicketTimeEntries(ctx context.Context, tid int64, lteo *ListTimeEntriesOption) ([]*TimeEntry, bool, error) {
+	return hasNext(c.listTicketTimeEntries(ctx, tid, lteo))
+}
+
+func (c *Client) listTicketTimeEntries(ctx context.Context, tid int64, lo ListOption) ([]*TimeEntry, string, error) {
 	url := c.Endpoint("/tickets/%d/time_entries", tid)
 	result := &timeEntriesResult{}
-	next, err := c.DoList(ctx, url, lteo, result)
+	next, err := c.DoListLink(ctx, url, lo, result)
 	return result.TimeEntries, next, err
 }
 
 func (c *Client) IterTicketTimeEntries(ctx context.Context, tid int64, lteo *ListTimeEntriesOption, itef func(*TimeEntry) error) error {
-	if lteo == nil {
-		lteo = &ListTimeEntriesOption{}
-	}
-	if lteo.Page < 1 {
-		lteo.Page = 1
-	}
-	if lteo.PerPage < 1 {
-		lteo.PerPage = 100
-	}
+	return c.ticketTimeEntriesPaginator(tid).Iter(ctx, lteo, itef)
+}
 
-	for {
-		tms, next, err := c.ListTicketTimeEntries(ctx, tid, lteo)
-		if err != nil {
-			return err
-		}
-		for _, t := range tms {
-			if err = itef(t); err != nil {
-				return err
-			}
-		}
-		if !next {
-			break
-		}
-		lteo.Page++
-	}
-	return nil
+// AllTicketTimeEntries is like IterTicketTimeEntries but returns an iterator, the lteo will not be modified.
+func (c *Client) AllTicketTimeEntries(ctx context.Context, tid int64, lteo *ListTimeEntriesOption) iter.Seq2[*TimeEntry, error] {
+	return c.ticketTimeEntriesPaginator(tid).All(ctx, lteo)
+}
+
+func (c *Client) ticketTimeEntriesPaginator(tid int64) *fresh.Paginator[*TimeEntry] {
+	return newPaginator(func(ctx context.Context, lo ListOption) ([]*TimeEntry, string, error) {
+		return c.listTicketTimeEntries(ctx, tid, lo)
+	})
 }
 
 // Update a Time Entry
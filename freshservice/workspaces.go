@@ -1,6 +1,11 @@
 package freshservice
 
-import "context"
+import (
+	"context"
+	"iter"
+
+	"github.com/askasoft/gofresh/fresh"
+)
 
 // ---------------------------------------------------
 // Workspace
@@ -15,37 +20,25 @@ func (c *Client) GetWorkspace(ctx context.Context, id int64) (*Workspace, error)
 }
 
 func (c *Client) ListWorkspaces(ctx context.Context, lwo *ListWorkspacesOption) ([]*Workspace, bool, error) {
+	return hasNext(c.listWorkspaces(ctx, lwo))
+}
+
+func (c *Client) listWorkspaces(ctx context.Context, lo ListOption) ([]*Workspace, string, error) {
 	url := c.Endpoint("/workspaces")
 	result := &workspacesResult{}
-	next, err := c.DoList(ctx, url, lwo, result)
+	next, err := c.DoListLink(ctx, url, lo, result)
 	return result.Workspaces, next, err
 }
 
 func (c *Client) IterWorkspaces(ctx context.Context, lwo *ListWorkspacesOption, iwf func(*Workspace) error) error {
-	if lwo == nil {
-		lwo = &ListWorkspacesOption{}
-	}
-	if lwo.Page < 1 {
-		lwo.Page = 1
-	}
-	if lwo.PerPage < 1 {
-		lwo.PerPage = 100
-	}
-
-	for {
-		ws, next, err := c.ListWorkspaces(ctx, lwo)
-		if err != nil {
-			return err
-		}
-		for _, ag := range ws {
-			if err = iwf(ag); err != nil {
-				return err
-			}
-		}
-		if !next {
-			break
-		}
-		lwo.Page++
-	}
-	return nil
+	return c.workspacesPaginator().Iter(ctx, lwo, iwf)
+}
+
+// AllWorkspaces is like IterWorkspaces but returns an iterator, the lwo will not be modified.
+func (c *Client) AllWorkspaces(ctx context.Context, lwo *ListWorkspacesOption) iter.Seq2[*Workspace, error] {
+	return c.workspacesPaginator().All(ctx, lwo)
+}
+
+func (c *Client) workspacesPaginator() *fresh.Paginator[*Workspace] {
+	return newPaginator(c.listWorkspaces)
 }
@@ -2,4 +2,8 @@ module github.com/askasoft/gofresh
 
 go 1.25.0
 
-require github.com/askasoft/pango v1.2.17
+require (
+	github.com/askasoft/pango v1.2.17
+	github.com/yuin/goldmark v1.8.6
+	golang.org/x/net v0.56.0
+)